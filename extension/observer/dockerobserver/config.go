@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines configuration for docker observer.
+type Config struct {
+	config.ExtensionSettings `mapstructure:",squash"`
+
+	// Endpoint is the URL of the docker server. Default is "unix:///var/run/docker.sock".
+	Endpoint string `mapstructure:"endpoint"`
+
+	// Timeout is the maximum amount of time to wait for docker API responses. Default is 5s.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// CacheSyncInterval determines how often the full list of running containers is
+	// synchronized, in addition to the container events stream. Default is 60m.
+	CacheSyncInterval time.Duration `mapstructure:"cache_sync_interval"`
+
+	// UseHostBindings determines whether endpoints target the host address and port
+	// that a container port is published to, when published, instead of the container
+	// address and exposed port. Default is false.
+	UseHostBindings bool `mapstructure:"use_host_bindings"`
+}
+
+// Validate checks the Config is valid.
+func (config Config) Validate() error {
+	if config.Endpoint == "" {
+		return errors.New("endpoint must be specified")
+	}
+	if config.CacheSyncInterval <= 0 {
+		return errors.New("cache_sync_interval must be a positive duration")
+	}
+	return nil
+}
@@ -0,0 +1,350 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	dtypes "github.com/docker/docker/api/types"
+	devents "github.com/docker/docker/api/types/events"
+	dfilters "github.com/docker/docker/api/types/filters"
+	docker "github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+const (
+	dockerAPIVersion = "v1.22"
+	userAgent        = "OpenTelemetry-Collector Docker Observer/v0.0.1"
+)
+
+var _ component.Extension = (*dockerObserver)(nil)
+var _ observer.Observable = (*dockerObserver)(nil)
+
+// dockerObserver keeps track of the running containers through the docker events stream,
+// periodically re-synchronized with the full container list, and notifies its listeners
+// of the endpoints of their ports.
+type dockerObserver struct {
+	logger *zap.Logger
+	config *Config
+	client *docker.Client
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	sync.Mutex
+	// containers are the inspected running containers by ID.
+	containers map[string]*dtypes.ContainerJSON
+
+	observer.EndpointsNotifier
+}
+
+func newObserver(logger *zap.Logger, config *Config) (component.Extension, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := docker.NewClientWithOpts(
+		docker.WithHost(config.Endpoint),
+		docker.WithVersion(dockerAPIVersion),
+		docker.WithHTTPHeaders(map[string]string{"User-Agent": userAgent}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create docker client: %w", err)
+	}
+
+	return &dockerObserver{
+		logger:     logger,
+		config:     config,
+		client:     client,
+		containers: map[string]*dtypes.ContainerJSON{},
+	}, nil
+}
+
+func (d *dockerObserver) Start(context.Context, component.Host) error {
+	var ctx context.Context
+	ctx, d.cancel = context.WithCancel(context.Background())
+	d.done = make(chan struct{})
+	go func() {
+		defer close(d.done)
+		d.watch(ctx)
+	}()
+	return nil
+}
+
+// Shutdown stops watching the containers and waits for the watch to complete.
+func (d *dockerObserver) Shutdown(context.Context) error {
+	if d.cancel != nil {
+		d.cancel()
+		<-d.done
+	}
+	return d.client.Close()
+}
+
+// watch synchronizes the running containers and follows the container events until ctx is done.
+// The container list is synchronized again every CacheSyncInterval and whenever the events
+// stream has to be resumed after an error.
+func (d *dockerObserver) watch(ctx context.Context) {
+	filters := dfilters.NewArgs([]dfilters.KeyValuePair{
+		{Key: "type", Value: "container"},
+		{Key: "event", Value: "destroy"},
+		{Key: "event", Value: "die"},
+		{Key: "event", Value: "pause"},
+		{Key: "event", Value: "rename"},
+		{Key: "event", Value: "stop"},
+		{Key: "event", Value: "start"},
+		{Key: "event", Value: "unpause"},
+		{Key: "event", Value: "update"},
+	}...)
+
+	ticker := time.NewTicker(d.config.CacheSyncInterval)
+	defer ticker.Stop()
+
+EVENT_LOOP:
+	for {
+		// Subscribe to the events since before the synchronization so no change is missed.
+		since := time.Now()
+		d.syncContainers(ctx)
+
+		options := dtypes.EventsOptions{
+			Filters: filters,
+			Since:   since.Format(time.RFC3339Nano),
+		}
+		eventCh, errCh := d.client.Events(ctx, options)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.syncContainers(ctx)
+			case event := <-eventCh:
+				d.handleEvent(ctx, event)
+			case err := <-errCh:
+				// We are only interested when the context hasn't been canceled since requests made
+				// with a closed context are guaranteed to fail.
+				if ctx.Err() != nil {
+					return
+				}
+				d.logger.Error("Error watching docker container events", zap.Error(err))
+				select {
+				case <-time.After(3 * time.Second):
+					continue EVENT_LOOP
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}
+}
+
+// syncContainers replaces the tracked containers with the currently running ones.
+func (d *dockerObserver) syncContainers(ctx context.Context) {
+	listCtx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	filters := dfilters.NewArgs()
+	filters.Add("status", "running")
+	containers, err := d.client.ContainerList(listCtx, dtypes.ContainerListOptions{Filters: filters})
+	if err != nil {
+		d.logger.Error("Could not list docker containers", zap.Error(err))
+		return
+	}
+
+	d.Lock()
+	known := make(map[string]*dtypes.ContainerJSON, len(d.containers))
+	for id, c := range d.containers {
+		known[id] = c
+	}
+	d.Unlock()
+
+	running := make(map[string]*dtypes.ContainerJSON, len(containers))
+	for _, c := range containers {
+		if container, ok := known[c.ID]; ok {
+			running[c.ID] = container
+		} else if container := d.inspectContainer(ctx, c.ID); container != nil {
+			running[c.ID] = container
+		}
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	d.containers = running
+	d.updateEndpoints()
+}
+
+func (d *dockerObserver) handleEvent(ctx context.Context, event devents.Message) {
+	d.logger.Debug("Docker container update", zap.String("id", event.ID), zap.String("action", event.Action))
+
+	var container *dtypes.ContainerJSON
+	switch event.Action {
+	case "destroy", "die", "pause", "stop":
+	default:
+		container = d.inspectContainer(ctx, event.ID)
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	if container != nil {
+		d.containers[event.ID] = container
+	} else {
+		delete(d.containers, event.ID)
+	}
+	d.updateEndpoints()
+}
+
+// inspectContainer returns the inspected container, or nil if it isn't running or couldn't be inspected.
+func (d *dockerObserver) inspectContainer(ctx context.Context, id string) *dtypes.ContainerJSON {
+	inspectCtx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	container, err := d.client.ContainerInspect(inspectCtx, id)
+	if err != nil {
+		d.logger.Error("Could not inspect docker container", zap.String("id", id), zap.Error(err))
+		return nil
+	}
+	if container.State == nil || !container.State.Running || container.State.Paused {
+		return nil
+	}
+	return &container
+}
+
+// updateEndpoints notifies the listeners of the endpoints added, removed or changed since
+// the last update. It must be called with the lock held so updates are applied in order.
+func (d *dockerObserver) updateEndpoints() {
+	var endpoints []observer.Endpoint
+	for _, c := range d.containers {
+		endpoints = append(endpoints, d.containerEndpoints(c)...)
+	}
+	d.Update(endpoints)
+}
+
+// containerEndpoints returns an endpoint for each exposed port of the container.
+func (d *dockerObserver) containerEndpoints(c *dtypes.ContainerJSON) []observer.Endpoint {
+	if c.Config == nil {
+		return nil
+	}
+
+	ports := map[nat.Port][]nat.PortBinding{}
+	for port := range c.Config.ExposedPorts {
+		ports[port] = nil
+	}
+	if c.NetworkSettings != nil {
+		for port, bindings := range c.NetworkSettings.Ports {
+			ports[port] = bindings
+		}
+	}
+
+	portKeys := make([]nat.Port, 0, len(ports))
+	for port := range ports {
+		portKeys = append(portKeys, port)
+	}
+	sort.Slice(portKeys, func(i, j int) bool { return portKeys[i] < portKeys[j] })
+
+	image, tag := parseImage(c.Config.Image)
+	host := containerIP(c)
+	endpoints := make([]observer.Endpoint, 0, len(portKeys))
+	for _, port := range portKeys {
+		details := &observer.Container{
+			Name:        strings.TrimPrefix(c.Name, "/"),
+			Image:       image,
+			Tag:         tag,
+			Port:        uint16(port.Int()),
+			Command:     strings.Join(c.Config.Cmd, " "),
+			ContainerID: c.ID,
+			Host:        host,
+			Transport:   portProtoToTransport(port.Proto()),
+			Labels:      c.Config.Labels,
+		}
+
+		target := fmt.Sprintf("%s:%d", host, details.Port)
+		if bindings := ports[port]; len(bindings) > 0 {
+			hostPort, err := nat.ParsePort(bindings[0].HostPort)
+			if err == nil && hostPort > 0 {
+				details.AlternatePort = uint16(hostPort)
+				if d.config.UseHostBindings {
+					target = fmt.Sprintf("%s:%d", bindingIP(bindings[0].HostIP), details.AlternatePort)
+				}
+			}
+		}
+
+		endpoints = append(endpoints, observer.Endpoint{
+			ID:      observer.EndpointID(fmt.Sprintf("(%s)%s:%d/%s", d.config.ID().String(), c.ID, details.Port, details.Transport)),
+			Target:  target,
+			Details: details,
+		})
+	}
+	return endpoints
+}
+
+// parseImage splits an image reference into its name and tag, dropping any digest.
+func parseImage(image string) (string, string) {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+	return image, ""
+}
+
+// containerIP returns the IP address of the container on the default bridge network or,
+// if not attached to it, on the first of its networks. Containers without an address of
+// their own (e.g. using the host network) are reachable through localhost.
+func containerIP(c *dtypes.ContainerJSON) string {
+	if c.NetworkSettings != nil {
+		if c.NetworkSettings.IPAddress != "" {
+			return c.NetworkSettings.IPAddress
+		}
+
+		names := make([]string, 0, len(c.NetworkSettings.Networks))
+		for name := range c.NetworkSettings.Networks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if network := c.NetworkSettings.Networks[name]; network != nil && network.IPAddress != "" {
+				return network.IPAddress
+			}
+		}
+	}
+	return "127.0.0.1"
+}
+
+// bindingIP returns the address to reach a port published on the given host IP.
+func bindingIP(hostIP string) string {
+	// A host IP of 0.0.0.0 means it listens on all interfaces, including localhost.
+	if hostIP == "" || hostIP == "0.0.0.0" || hostIP == "::" {
+		return "127.0.0.1"
+	}
+	return hostIP
+}
+
+func portProtoToTransport(proto string) observer.Transport {
+	switch proto {
+	case "tcp":
+		return observer.ProtocolTCP
+	case "udp":
+		return observer.ProtocolUDP
+	}
+	return observer.ProtocolUnknown
+}
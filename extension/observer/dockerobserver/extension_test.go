@@ -0,0 +1,299 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	dtypes "github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	devents "github.com/docker/docker/api/types/events"
+	dnetwork "github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.uber.org/goleak"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+// mockDaemon serves the subset of the docker API used by the observer.
+type mockDaemon struct {
+	sync.Mutex
+	containers map[string]dtypes.ContainerJSON
+	events     chan devents.Message
+}
+
+func newMockDaemon(containers ...dtypes.ContainerJSON) *mockDaemon {
+	m := &mockDaemon{
+		containers: map[string]dtypes.ContainerJSON{},
+		events:     make(chan devents.Message, 10),
+	}
+	for _, c := range containers {
+		m.containers[c.ID] = c
+	}
+	return m
+}
+
+func (m *mockDaemon) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/containers/json"):
+		m.Lock()
+		list := []dtypes.Container{}
+		for id := range m.containers {
+			list = append(list, dtypes.Container{ID: id})
+		}
+		m.Unlock()
+		_ = json.NewEncoder(w).Encode(list)
+	case strings.HasSuffix(r.URL.Path, "/json") && strings.Contains(r.URL.Path, "/containers/"):
+		id := strings.TrimSuffix(r.URL.Path[strings.Index(r.URL.Path, "/containers/")+len("/containers/"):], "/json")
+		m.Lock()
+		c, ok := m.containers[id]
+		m.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "No such container"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(c)
+	case strings.HasSuffix(r.URL.Path, "/events"):
+		w.(http.Flusher).Flush()
+		encoder := json.NewEncoder(w)
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case event := <-m.events:
+				_ = encoder.Encode(event)
+				w.(http.Flusher).Flush()
+			}
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (m *mockDaemon) start(c dtypes.ContainerJSON) {
+	m.Lock()
+	m.containers[c.ID] = c
+	m.Unlock()
+	m.events <- devents.Message{Type: devents.ContainerEventType, Action: "start", ID: c.ID, TimeNano: time.Now().UnixNano()}
+}
+
+func (m *mockDaemon) die(id string) {
+	m.Lock()
+	delete(m.containers, id)
+	m.Unlock()
+	m.events <- devents.Message{Type: devents.ContainerEventType, Action: "die", ID: id, TimeNano: time.Now().UnixNano()}
+}
+
+func newContainer(id, name, image string, ports nat.PortMap) dtypes.ContainerJSON {
+	return dtypes.ContainerJSON{
+		ContainerJSONBase: &dtypes.ContainerJSONBase{
+			ID:    id,
+			Name:  "/" + name,
+			State: &dtypes.ContainerState{Running: true},
+		},
+		Config: &dcontainer.Config{
+			Image:  image,
+			Cmd:    []string{name, "--verbose"},
+			Labels: map[string]string{"app": name},
+		},
+		NetworkSettings: &dtypes.NetworkSettings{
+			NetworkSettingsBase: dtypes.NetworkSettingsBase{Ports: ports},
+			Networks: map[string]*dnetwork.EndpointSettings{
+				"bridge": {IPAddress: "172.17.0.2"},
+			},
+		},
+	}
+}
+
+type recordingNotify struct {
+	sync.Mutex
+	endpoints map[observer.EndpointID]observer.Endpoint
+}
+
+func (n *recordingNotify) OnAdd(added []observer.Endpoint) {
+	n.Lock()
+	defer n.Unlock()
+	for _, e := range added {
+		n.endpoints[e.ID] = e
+	}
+}
+
+func (n *recordingNotify) OnRemove(removed []observer.Endpoint) {
+	n.Lock()
+	defer n.Unlock()
+	for _, e := range removed {
+		delete(n.endpoints, e.ID)
+	}
+}
+
+func (n *recordingNotify) OnChange(changed []observer.Endpoint) {
+	n.OnAdd(changed)
+}
+
+func (n *recordingNotify) get(id observer.EndpointID) (observer.Endpoint, bool) {
+	n.Lock()
+	defer n.Unlock()
+	e, ok := n.endpoints[id]
+	return e, ok
+}
+
+func (n *recordingNotify) len() int {
+	n.Lock()
+	defer n.Unlock()
+	return len(n.endpoints)
+}
+
+func TestObserverListAndWatch(t *testing.T) {
+	daemon := newMockDaemon(newContainer("redis-id", "redis", "redis:6.2", nat.PortMap{
+		"6379/tcp": []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "16379"}},
+		"6380/udp": nil,
+	}))
+	srv := httptest.NewServer(daemon)
+	defer srv.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = srv.URL
+	ext, err := newObserver(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	obs := ext.(*dockerObserver)
+	require.NoError(t, obs.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, obs.Shutdown(context.Background())) }()
+
+	notify := &recordingNotify{endpoints: map[observer.EndpointID]observer.Endpoint{}}
+	obs.ListAndWatch(notify)
+
+	require.Eventually(t, func() bool { return notify.len() == 2 }, 5*time.Second, 10*time.Millisecond)
+	e, ok := notify.get("(docker_observer)redis-id:6379/TCP")
+	require.True(t, ok)
+	assert.Equal(t, observer.Endpoint{
+		ID:     "(docker_observer)redis-id:6379/TCP",
+		Target: "172.17.0.2:6379",
+		Details: &observer.Container{
+			Name:          "redis",
+			Image:         "redis",
+			Tag:           "6.2",
+			Port:          6379,
+			AlternatePort: 16379,
+			Command:       "redis --verbose",
+			ContainerID:   "redis-id",
+			Host:          "172.17.0.2",
+			Transport:     observer.ProtocolTCP,
+			Labels:        map[string]string{"app": "redis"},
+		},
+	}, e)
+	e, ok = notify.get("(docker_observer)redis-id:6380/UDP")
+	require.True(t, ok)
+	assert.Equal(t, "172.17.0.2:6380", e.Target)
+	assert.Equal(t, uint16(0), e.Details.(*observer.Container).AlternatePort)
+
+	daemon.start(newContainer("nginx-id", "nginx", "nginx", nat.PortMap{"80/tcp": nil}))
+	require.Eventually(t, func() bool { return notify.len() == 3 }, 5*time.Second, 10*time.Millisecond)
+	e, ok = notify.get("(docker_observer)nginx-id:80/TCP")
+	require.True(t, ok)
+	assert.Equal(t, "nginx", e.Details.(*observer.Container).Image)
+	assert.Equal(t, "", e.Details.(*observer.Container).Tag)
+
+	daemon.die("redis-id")
+	require.Eventually(t, func() bool { return notify.len() == 1 }, 5*time.Second, 10*time.Millisecond)
+	_, ok = notify.get("(docker_observer)nginx-id:80/TCP")
+	assert.True(t, ok)
+
+	// Listeners subscribing later are notified of the current endpoints.
+	late := &recordingNotify{endpoints: map[observer.EndpointID]observer.Endpoint{}}
+	obs.ListAndWatch(late)
+	assert.Equal(t, 1, late.len())
+
+	// Unsubscribed listeners are no longer notified.
+	obs.Unsubscribe(notify)
+	daemon.start(newContainer("mysql-id", "mysql", "mysql", nat.PortMap{"3306/tcp": nil}))
+	require.Eventually(t, func() bool { return late.len() == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, notify.len())
+}
+
+func TestShutdownStopsWatching(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	srv := httptest.NewServer(newMockDaemon())
+	defer srv.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = srv.URL
+	ext, err := newObserver(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+
+	notify := &recordingNotify{endpoints: map[observer.EndpointID]observer.Endpoint{}}
+	ext.(*dockerObserver).ListAndWatch(notify)
+	ext.(*dockerObserver).Unsubscribe(notify)
+	require.NoError(t, ext.Shutdown(context.Background()))
+}
+
+func TestContainerEndpointsUseHostBindings(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.UseHostBindings = true
+	obs := &dockerObserver{config: cfg}
+
+	c := newContainer("redis-id", "redis", "redis", nat.PortMap{
+		"6379/tcp": []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "16379"}},
+		"6380/tcp": []nat.PortBinding{{HostIP: "10.0.0.1", HostPort: "16380"}},
+		"6381/tcp": nil,
+	})
+	endpoints := obs.containerEndpoints(&c)
+	require.Len(t, endpoints, 3)
+	assert.Equal(t, "127.0.0.1:16379", endpoints[0].Target)
+	assert.Equal(t, "10.0.0.1:16380", endpoints[1].Target)
+	assert.Equal(t, "172.17.0.2:6381", endpoints[2].Target)
+
+	// Containers without an address of their own, e.g. on the host network, use localhost.
+	c.NetworkSettings = nil
+	c.Config.ExposedPorts = nat.PortSet{"8080/tcp": struct{}{}}
+	endpoints = obs.containerEndpoints(&c)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, "127.0.0.1:8080", endpoints[0].Target)
+}
+
+func TestParseImage(t *testing.T) {
+	tests := []struct {
+		image string
+		name  string
+		tag   string
+	}{
+		{image: "redis", name: "redis"},
+		{image: "redis:6.2", name: "redis", tag: "6.2"},
+		{image: "localhost:5000/redis", name: "localhost:5000/redis"},
+		{image: "localhost:5000/redis:6.2", name: "localhost:5000/redis", tag: "6.2"},
+		{image: "redis:6.2@sha256:0123456789abcdef", name: "redis", tag: "6.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			name, tag := parseImage(tt.image)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.tag, tag)
+		})
+	}
+}
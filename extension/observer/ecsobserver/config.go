@@ -80,6 +80,9 @@ func ExampleConfig() Config {
 		JobLabelName:    defaultJobLabelName,
 		Services: []ServiceConfig{
 			{
+				CommonExporterConfig: CommonExporterConfig{
+					MetricsPorts: []int{8080},
+				},
 				NamePattern: "^retail-.*$",
 			},
 		},
@@ -14,6 +14,17 @@
 
 package ecsobserver
 
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"go.uber.org/multierr"
+)
+
+const (
+	defaultMetricsPath = "/metrics"
+)
+
 // CommonExporterConfig should be embedded into filter config.
 // They set labels like job, metrics_path etc. that can override prometheus default.
 type CommonExporterConfig struct {
@@ -21,3 +32,95 @@ type CommonExporterConfig struct {
 	MetricsPath  string `mapstructure:"metrics_path" yaml:"metrics_path"`
 	MetricsPorts []int  `mapstructure:"metrics_ports" yaml:"metrics_ports"`
 }
+
+// validateMetricsPorts checks metrics_ports for filters exporting a target for each container port listed in it.
+func (c *CommonExporterConfig) validateMetricsPorts() error {
+	if len(c.MetricsPorts) == 0 {
+		return fmt.Errorf("metrics_ports is empty")
+	}
+	for _, port := range c.MetricsPorts {
+		if port <= 0 || port > 65535 {
+			return fmt.Errorf("invalid port %d in metrics_ports", port)
+		}
+	}
+	return nil
+}
+
+// taskExporter converts the matched containers of tasks into prometheus targets.
+type taskExporter struct {
+	cluster string
+}
+
+// exportTasks returns the targets of all the tasks. The returned error is not fatal,
+// it contains the tasks and containers whose address could not be found.
+func (e *taskExporter) exportTasks(tasks []*Task) ([]PrometheusECSTarget, error) {
+	var (
+		merr    error
+		targets []PrometheusECSTarget
+	)
+	for _, t := range tasks {
+		taskTargets, err := e.exportTask(t)
+		multierr.AppendInto(&merr, err)
+		targets = append(targets, taskTargets...)
+	}
+	return targets, merr
+}
+
+func (e *taskExporter) exportTask(task *Task) ([]PrometheusECSTarget, error) {
+	ip, err := task.PrivateIP()
+	if err != nil {
+		return nil, err
+	}
+
+	// Labels shared by all the targets of the task.
+	taskTarget := PrometheusECSTarget{
+		Source:                 aws.StringValue(task.Task.TaskArn),
+		ClusterName:            e.cluster,
+		TaskDefinitionFamily:   aws.StringValue(task.Definition.Family),
+		TaskDefinitionRevision: int(aws.Int64Value(task.Definition.Revision)),
+		TaskStartedBy:          aws.StringValue(task.Task.StartedBy),
+		TaskLaunchType:         aws.StringValue(task.Task.LaunchType),
+		TaskGroup:              aws.StringValue(task.Task.Group),
+		TaskTags:               task.TaskTags(),
+		HealthStatus:           aws.StringValue(task.Task.HealthStatus),
+	}
+	if task.Service != nil {
+		taskTarget.ServiceName = aws.StringValue(task.Service.ServiceName)
+	}
+	if task.EC2 != nil {
+		taskTarget.EC2InstanceID = aws.StringValue(task.EC2.InstanceId)
+		taskTarget.EC2InstanceType = aws.StringValue(task.EC2.InstanceType)
+		taskTarget.EC2Tags = task.EC2Tags()
+		taskTarget.EC2VpcID = aws.StringValue(task.EC2.VpcId)
+		taskTarget.EC2SubnetID = aws.StringValue(task.EC2.SubnetId)
+		taskTarget.EC2PrivateIP = aws.StringValue(task.EC2.PrivateIpAddress)
+		taskTarget.EC2PublicIP = aws.StringValue(task.EC2.PublicIpAddress)
+	}
+
+	var (
+		merr    error
+		targets []PrometheusECSTarget
+	)
+	for _, matched := range task.Matched {
+		def := task.Definition.ContainerDefinitions[matched.ContainerIndex]
+		containerTarget := taskTarget
+		containerTarget.ContainerName = aws.StringValue(def.Name)
+		containerTarget.ContainerLabels = task.ContainerLabels(matched.ContainerIndex)
+		for _, matchedTarget := range matched.Targets {
+			port, err := task.MappedPort(def, int64(matchedTarget.Port))
+			if err != nil {
+				multierr.AppendInto(&merr, err)
+				continue
+			}
+			target := containerTarget
+			target.Address = fmt.Sprintf("%s:%d", ip, port)
+			target.MetricsPath = matchedTarget.MetricsPath
+			if target.MetricsPath == "" {
+				target.MetricsPath = defaultMetricsPath
+			}
+			target.Job = matchedTarget.Job
+			targets = append(targets, target)
+		}
+	}
+	return targets, merr
+}
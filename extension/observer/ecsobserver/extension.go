@@ -16,20 +16,43 @@ package ecsobserver
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"strconv"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
 
 var _ component.Extension = (*ecsObserver)(nil)
+var _ observer.Observable = (*ecsObserver)(nil)
 
 // ecsObserver implements component.ServiceExtension interface.
+// The discovered targets are written to the result file and notified to the
+// listeners as observer.ECSTask endpoints.
 type ecsObserver struct {
-	logger *zap.Logger
-	sd     *ServiceDiscovery
+	logger       *zap.Logger
+	sd           *ServiceDiscovery
+	id           config.ComponentID
+	jobLabelName string
 
 	// for Shutdown
 	cancel func()
+	done   chan struct{}
+
+	observer.EndpointsNotifier
+}
+
+func newObserver(logger *zap.Logger, cfg *Config, sd *ServiceDiscovery) *ecsObserver {
+	return &ecsObserver{
+		logger:       logger,
+		sd:           sd,
+		id:           cfg.ID(),
+		jobLabelName: cfg.JobLabelName,
+	}
 }
 
 // Start runs the service discovery in backeground
@@ -38,8 +61,10 @@ func (e *ecsObserver) Start(_ context.Context, host component.Host) error {
 	// Ignore the ctx parameter as it is not for long running operation
 	ctx, cancel := context.WithCancel(context.Background())
 	e.cancel = cancel
+	e.done = make(chan struct{})
 	go func() {
-		if err := e.sd.RunAndWriteFile(ctx); err != nil {
+		defer close(e.done)
+		if err := e.sd.RunAndWriteFile(ctx, e.updateEndpoints); err != nil {
 			e.logger.Error("ECSDiscovery stopped by error", zap.Error(err))
 		}
 	}()
@@ -48,6 +73,50 @@ func (e *ecsObserver) Start(_ context.Context, host component.Host) error {
 
 func (e *ecsObserver) Shutdown(ctx context.Context) error {
 	e.logger.Info("Stopping ECSDiscovery")
-	e.cancel()
+	if e.cancel != nil {
+		e.cancel()
+		<-e.done
+	}
 	return nil
 }
+
+// updateEndpoints converts the discovered targets into endpoints and notifies the listeners of the changes.
+func (e *ecsObserver) updateEndpoints(targets []PrometheusECSTarget) {
+	endpoints := make([]observer.Endpoint, 0, len(targets))
+	for _, target := range targets {
+		endpoint, err := e.targetToEndpoint(target)
+		if err != nil {
+			e.logger.Warn("Invalid ECS target address", zap.String("address", target.Address), zap.Error(err))
+			continue
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	e.Update(endpoints)
+}
+
+// targetToEndpoint converts a target into an endpoint identified by its task, container, port and metrics path.
+func (e *ecsObserver) targetToEndpoint(target PrometheusECSTarget) (observer.Endpoint, error) {
+	_, portStr, err := net.SplitHostPort(target.Address)
+	if err != nil {
+		return observer.Endpoint{}, err
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return observer.Endpoint{}, err
+	}
+	return observer.Endpoint{
+		ID:     observer.EndpointID(fmt.Sprintf("%s/%s/%s:%d%s", e.id, target.Source, target.ContainerName, port, target.MetricsPath)),
+		Target: target.Address,
+		Details: &observer.ECSTask{
+			TaskARN:                target.Source,
+			TaskDefinitionFamily:   target.TaskDefinitionFamily,
+			TaskDefinitionRevision: target.TaskDefinitionRevision,
+			ContainerName:          target.ContainerName,
+			Port:                   uint16(port),
+			MetricsPath:            target.MetricsPath,
+			Job:                    target.Job,
+			JobLabelName:           e.jobLabelName,
+			DockerLabels:           target.ContainerLabels,
+		},
+	}, nil
+}
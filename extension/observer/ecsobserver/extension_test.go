@@ -16,20 +16,131 @@ package ecsobserver
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer/ecsobserver/internal/ecsmock"
 )
 
-// Simply start and stop, the actual test logic is in sd_test.go until we implement the ListWatcher interface.
-// In that case sd itself does not use timer and relies on caller to trigger List.
+// Simply start and stop, the discovery itself is tested in sd_test.go.
 func TestExtensionStartStop(t *testing.T) {
-	ext, err := createExtension(context.TODO(), component.ExtensionCreateParams{Logger: zap.NewExample()}, createDefaultConfig())
-	require.NoError(t, err)
-	require.IsType(t, &ecsObserver{}, ext)
+	cfg := testDiscoveryConfig()
+	ext := newObserver(zap.NewExample(), &cfg, newTestDiscovery(t, cfg, ecsmock.NewCluster()))
+	require.NoError(t, ext.Start(context.TODO(), componenttest.NewNopHost()))
+	require.NoError(t, ext.Shutdown(context.TODO()))
+}
+
+func TestExtensionListAndWatch(t *testing.T) {
+	cfg := testDiscoveryConfig()
+	c := ecsmock.NewCluster()
+	setTestTasks(c)
+	sd := newTestDiscovery(t, cfg, c)
+	ext := newObserver(zap.NewExample(), &cfg, sd)
+
+	notify := &recordingNotify{endpoints: map[observer.EndpointID]observer.Endpoint{}}
+	ext.ListAndWatch(notify)
 	require.NoError(t, ext.Start(context.TODO(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool { return notify.len() == 3 }, 5*time.Second, 10*time.Millisecond)
 	require.NoError(t, ext.Shutdown(context.TODO()))
+
+	e, ok := notify.get("ecs_observer/nginx-task-0/exporter:9113/metrics")
+	require.True(t, ok)
+	assert.Equal(t, observer.Endpoint{
+		ID:     "ecs_observer/nginx-task-0/exporter:9113/metrics",
+		Target: "10.0.0.1:9113",
+		Details: &observer.ECSTask{
+			TaskARN:                "nginx-task-0",
+			TaskDefinitionFamily:   "nginx",
+			TaskDefinitionRevision: 1,
+			ContainerName:          "exporter",
+			Port:                   9113,
+			MetricsPath:            "/metrics",
+			Job:                    "nginx",
+			JobLabelName:           defaultJobLabelName,
+			DockerLabels: map[string]string{
+				"ECS_PROMETHEUS_EXPORTER_PORT": "9113",
+				"ECS_PROMETHEUS_JOB_NAME":      "nginx",
+			},
+		},
+	}, e)
+	e, ok = notify.get("ecs_observer/app-task-0/app:32768/stats")
+	require.True(t, ok)
+	assert.Equal(t, "172.31.0.1:32768", e.Target)
+
+	// Listeners subscribing later are notified of the current endpoints.
+	late := &recordingNotify{endpoints: map[observer.EndpointID]observer.Endpoint{}}
+	ext.ListAndWatch(late)
+	assert.Equal(t, 3, late.len())
+
+	// Removed tasks and changed labels are notified, unsubscribed listeners are left alone.
+	ext.Unsubscribe(late)
+	c.SetTasks(ecsmock.GenTasks("nginx-task-", 1, func(_ int, task *ecs.Task) {
+		task.TaskDefinitionArn = aws.String("nginx:1")
+		task.Attachments = []*ecs.Attachment{
+			{
+				Type: aws.String("ElasticNetworkInterface"),
+				Details: []*ecs.KeyValuePair{
+					{Name: aws.String("privateIPv4Address"), Value: aws.String("10.0.0.1")},
+				},
+			},
+		}
+	}))
+	targets, err := sd.Discover(context.TODO())
+	require.NoError(t, err)
+	ext.updateEndpoints(targets)
+	assert.Equal(t, 1, notify.len())
+	assert.Equal(t, 3, late.len())
+}
+
+type recordingNotify struct {
+	sync.Mutex
+	endpoints map[observer.EndpointID]observer.Endpoint
+}
+
+var _ observer.Notify = (*recordingNotify)(nil)
+
+func (n *recordingNotify) OnAdd(added []observer.Endpoint) {
+	n.Lock()
+	defer n.Unlock()
+	for _, e := range added {
+		n.endpoints[e.ID] = e
+	}
+}
+
+func (n *recordingNotify) OnRemove(removed []observer.Endpoint) {
+	n.Lock()
+	defer n.Unlock()
+	for _, e := range removed {
+		delete(n.endpoints, e.ID)
+	}
+}
+
+func (n *recordingNotify) OnChange(changed []observer.Endpoint) {
+	n.Lock()
+	defer n.Unlock()
+	for _, e := range changed {
+		n.endpoints[e.ID] = e
+	}
+}
+
+func (n *recordingNotify) get(id observer.EndpointID) (observer.Endpoint, bool) {
+	n.Lock()
+	defer n.Unlock()
+	e, ok := n.endpoints[id]
+	return e, ok
+}
+
+func (n *recordingNotify) len() int {
+	n.Lock()
+	defer n.Unlock()
+	return len(n.endpoints)
 }
@@ -46,8 +46,5 @@ func createExtension(ctx context.Context, params component.ExtensionCreateParams
 	if err != nil {
 		return nil, err
 	}
-	return &ecsObserver{
-		logger: params.Logger,
-		sd:     sd,
-	}, nil
+	return newObserver(params.Logger, sdCfg, sd), nil
 }
@@ -20,20 +20,44 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"go.uber.org/zap"
 )
 
+const (
+	// describeContainerInstanceLimit is the max number of container instances in a single DescribeContainerInstances request.
+	describeContainerInstanceLimit = 100
+	// describeServiceLimit is the max number of services in a single DescribeServices request.
+	describeServiceLimit = 10
+)
+
 // ecsClient includes API required by taskFetcher.
 type ecsClient interface {
 	ListTasksWithContext(ctx context.Context, input *ecs.ListTasksInput, opts ...request.Option) (*ecs.ListTasksOutput, error)
 	DescribeTasksWithContext(ctx context.Context, input *ecs.DescribeTasksInput, opts ...request.Option) (*ecs.DescribeTasksOutput, error)
+	DescribeTaskDefinitionWithContext(ctx context.Context, input *ecs.DescribeTaskDefinitionInput, opts ...request.Option) (*ecs.DescribeTaskDefinitionOutput, error)
+	DescribeContainerInstancesWithContext(ctx context.Context, input *ecs.DescribeContainerInstancesInput, opts ...request.Option) (*ecs.DescribeContainerInstancesOutput, error)
+	ListServicesWithContext(ctx context.Context, input *ecs.ListServicesInput, opts ...request.Option) (*ecs.ListServicesOutput, error)
+	DescribeServicesWithContext(ctx context.Context, input *ecs.DescribeServicesInput, opts ...request.Option) (*ecs.DescribeServicesOutput, error)
+}
+
+// ec2Client includes API required by taskFetcher.
+type ec2Client interface {
+	DescribeInstancesWithContext(ctx context.Context, input *ec2.DescribeInstancesInput, opts ...request.Option) (*ec2.DescribeInstancesOutput, error)
 }
 
 type taskFetcher struct {
 	logger  *zap.Logger
 	ecs     ecsClient
+	ec2     ec2Client
 	cluster string
+
+	// taskDefCache caches task definitions by arn, a task definition revision never changes.
+	taskDefCache map[string]*ecs.TaskDefinition
+	// ec2Cache caches EC2 instances by container instance arn.
+	ec2Cache map[string]*ec2.Instance
 }
 
 type taskFetcherOptions struct {
@@ -43,19 +67,55 @@ type taskFetcherOptions struct {
 
 	// test overrides
 	ecsOverride ecsClient
+	ec2Override ec2Client
 }
 
 func newTaskFetcher(opts taskFetcherOptions) (*taskFetcher, error) {
 	fetcher := taskFetcher{
-		logger:  opts.Logger,
-		ecs:     opts.ecsOverride,
-		cluster: opts.Cluster,
+		logger:       opts.Logger,
+		ecs:          opts.ecsOverride,
+		ec2:          opts.ec2Override,
+		cluster:      opts.Cluster,
+		taskDefCache: map[string]*ecs.TaskDefinition{},
+		ec2Cache:     map[string]*ec2.Instance{},
 	}
 	// Return early if clients are mocked
-	if fetcher.ecs != nil {
+	if fetcher.ecs != nil && fetcher.ec2 != nil {
 		return &fetcher, nil
 	}
-	return nil, fmt.Errorf("actual aws init logic not implemented")
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(opts.Region)})
+	if err != nil {
+		return nil, fmt.Errorf("create aws session failed: %w", err)
+	}
+	if fetcher.ecs == nil {
+		fetcher.ecs = ecs.New(sess)
+	}
+	if fetcher.ec2 == nil {
+		fetcher.ec2 = ec2.New(sess)
+	}
+	return &fetcher, nil
+}
+
+// FetchAndDecorate fetches all the running tasks and attaches their task definition,
+// the service that started them and, for tasks running on container instances, the underlying EC2 instance.
+func (f *taskFetcher) FetchAndDecorate(ctx context.Context) ([]*Task, error) {
+	rawTasks, err := f.GetAllTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := f.AttachTaskDefinitions(ctx, rawTasks)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.AttachContainerInstances(ctx, tasks); err != nil {
+		return nil, err
+	}
+	services, err := f.GetAllServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	attachServices(tasks, services)
+	return tasks, nil
 }
 
 // GetAllTasks get arns of all running tasks and describe those tasks.
@@ -86,3 +146,164 @@ func (f *taskFetcher) GetAllTasks(ctx context.Context) ([]*ecs.Task, error) {
 	}
 	return tasks, nil
 }
+
+// AttachTaskDefinitions converts ecs.Task into Task with its task definition.
+// Task definitions are cached, only the ones of the given tasks are kept.
+func (f *taskFetcher) AttachTaskDefinitions(ctx context.Context, rawTasks []*ecs.Task) ([]*Task, error) {
+	cache := make(map[string]*ecs.TaskDefinition, len(f.taskDefCache))
+	tasks := make([]*Task, 0, len(rawTasks))
+	for _, rawTask := range rawTasks {
+		arn := aws.StringValue(rawTask.TaskDefinitionArn)
+		def, ok := cache[arn]
+		if !ok {
+			def, ok = f.taskDefCache[arn]
+		}
+		if !ok {
+			res, err := f.ecs.DescribeTaskDefinitionWithContext(ctx, &ecs.DescribeTaskDefinitionInput{
+				TaskDefinition: rawTask.TaskDefinitionArn,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("ecs.DescribeTaskDefinition failed: %w", err)
+			}
+			def = res.TaskDefinition
+		}
+		cache[arn] = def
+		tasks = append(tasks, &Task{
+			Task:       rawTask,
+			Definition: def,
+		})
+	}
+	f.taskDefCache = cache
+	return tasks, nil
+}
+
+// AttachContainerInstances attaches the EC2 instance to tasks running on a container instance.
+// Fargate tasks have no container instance and are left untouched.
+// EC2 instances are cached by container instance, only the ones of the given tasks are kept.
+func (f *taskFetcher) AttachContainerInstances(ctx context.Context, tasks []*Task) error {
+	cache := make(map[string]*ec2.Instance, len(f.ec2Cache))
+	var missing []*string
+	for _, t := range tasks {
+		arn := aws.StringValue(t.Task.ContainerInstanceArn)
+		if arn == "" {
+			continue
+		}
+		if _, ok := cache[arn]; ok {
+			continue
+		}
+		if instance, ok := f.ec2Cache[arn]; ok {
+			cache[arn] = instance
+			continue
+		}
+		cache[arn] = nil
+		missing = append(missing, t.Task.ContainerInstanceArn)
+	}
+
+	// Describe container instances in batches to find their EC2 instance ids.
+	containerInstanceByEC2ID := map[string]string{}
+	for start := 0; start < len(missing); start += describeContainerInstanceLimit {
+		end := start + describeContainerInstanceLimit
+		if end > len(missing) {
+			end = len(missing)
+		}
+		res, err := f.ecs.DescribeContainerInstancesWithContext(ctx, &ecs.DescribeContainerInstancesInput{
+			Cluster:            aws.String(f.cluster),
+			ContainerInstances: missing[start:end],
+		})
+		if err != nil {
+			return fmt.Errorf("ecs.DescribeContainerInstances failed: %w", err)
+		}
+		for _, ci := range res.ContainerInstances {
+			containerInstanceByEC2ID[aws.StringValue(ci.Ec2InstanceId)] = aws.StringValue(ci.ContainerInstanceArn)
+		}
+	}
+
+	if len(containerInstanceByEC2ID) > 0 {
+		ids := make([]*string, 0, len(containerInstanceByEC2ID))
+		for id := range containerInstanceByEC2ID {
+			ids = append(ids, aws.String(id))
+		}
+		req := ec2.DescribeInstancesInput{InstanceIds: ids}
+		for {
+			res, err := f.ec2.DescribeInstancesWithContext(ctx, &req)
+			if err != nil {
+				return fmt.Errorf("ec2.DescribeInstances failed: %w", err)
+			}
+			for _, reservation := range res.Reservations {
+				for _, instance := range reservation.Instances {
+					if arn, ok := containerInstanceByEC2ID[aws.StringValue(instance.InstanceId)]; ok {
+						cache[arn] = instance
+					}
+				}
+			}
+			if res.NextToken == nil {
+				break
+			}
+			req.NextToken = res.NextToken
+		}
+	}
+
+	for _, t := range tasks {
+		if instance := cache[aws.StringValue(t.Task.ContainerInstanceArn)]; instance != nil {
+			t.EC2 = instance
+		}
+	}
+	// Don't cache container instances that could not be found so they are retried.
+	for arn, instance := range cache {
+		if instance == nil {
+			delete(cache, arn)
+		}
+	}
+	f.ec2Cache = cache
+	return nil
+}
+
+// GetAllServices lists the arns of all the services in the cluster and describes them in batches.
+func (f *taskFetcher) GetAllServices(ctx context.Context) ([]*ecs.Service, error) {
+	svc := f.ecs
+	cluster := aws.String(f.cluster)
+	req := ecs.ListServicesInput{Cluster: cluster}
+	var services []*ecs.Service
+	for {
+		listRes, err := svc.ListServicesWithContext(ctx, &req)
+		if err != nil {
+			return nil, fmt.Errorf("ecs.ListServices failed: %w", err)
+		}
+		arns := listRes.ServiceArns
+		for start := 0; start < len(arns); start += describeServiceLimit {
+			end := start + describeServiceLimit
+			if end > len(arns) {
+				end = len(arns)
+			}
+			descRes, err := svc.DescribeServicesWithContext(ctx, &ecs.DescribeServicesInput{
+				Cluster:  cluster,
+				Services: arns[start:end],
+			})
+			if err != nil {
+				return nil, fmt.Errorf("ecs.DescribeServices failed: %w", err)
+			}
+			services = append(services, descRes.Services...)
+		}
+		if listRes.NextToken == nil {
+			break
+		}
+		req.NextToken = listRes.NextToken
+	}
+	return services, nil
+}
+
+// attachServices attaches the service to the tasks it started.
+// A task started by a service has its startedBy set to the id of one of the service's deployments.
+func attachServices(tasks []*Task, services []*ecs.Service) {
+	serviceByDeploymentID := map[string]*ecs.Service{}
+	for _, svc := range services {
+		for _, deployment := range svc.Deployments {
+			serviceByDeploymentID[aws.StringValue(deployment.Id)] = svc
+		}
+	}
+	for _, t := range tasks {
+		if svc, ok := serviceByDeploymentID[aws.StringValue(t.Task.StartedBy)]; ok {
+			t.Service = svc
+		}
+	}
+}
@@ -16,27 +16,105 @@ package ecsobserver
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer/ecsobserver/internal/ecsmock"
 )
 
 func TestFetcher_GetAllTasks(t *testing.T) {
 	c := ecsmock.NewCluster()
-	f, err := newTaskFetcher(taskFetcherOptions{
-		Logger:      zap.NewExample(),
-		Cluster:     "not used",
-		Region:      "not used",
-		ecsOverride: c,
-	})
-	require.NoError(t, err)
-	c.SetTasks(ecsmock.GenTasks("p", 203))
+	f := newMockFetcher(t, c)
+	c.SetTasks(ecsmock.GenTasks("p", 203, nil))
 	ctx := context.Background()
 	tasks, err := f.GetAllTasks(ctx)
 	require.NoError(t, err)
 	assert.Equal(t, 203, len(tasks))
 }
+
+func TestFetcher_GetAllServices(t *testing.T) {
+	c := ecsmock.NewCluster()
+	f := newMockFetcher(t, c)
+	c.SetServices(ecsmock.GenServices("s", 23, nil))
+	services, err := f.GetAllServices(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 23, len(services))
+}
+
+func TestFetcher_AttachServices(t *testing.T) {
+	c := ecsmock.NewCluster()
+	f := newMockFetcher(t, c)
+	setTestTasks(c)
+	c.SetServices(ecsmock.GenServices("s", 2, func(i int, s *ecs.Service) {
+		s.Deployments = []*ecs.Deployment{
+			{Id: aws.String(fmt.Sprintf("ecs-svc/%d-primary", i)), Status: aws.String("PRIMARY")},
+			{Id: aws.String(fmt.Sprintf("ecs-svc/%d-active", i)), Status: aws.String("ACTIVE")},
+		}
+	}))
+	ctx := context.Background()
+	// The mock returns the tasks it stores, so they can be updated in place.
+	tasks, err := f.GetAllTasks(ctx)
+	require.NoError(t, err)
+	tasks[0].StartedBy = aws.String("ecs-svc/0-primary")
+	tasks[1].StartedBy = aws.String("ecs-svc/1-active")
+	tasks[2].StartedBy = aws.String("user")
+
+	decorated, err := f.FetchAndDecorate(ctx)
+	require.NoError(t, err)
+	require.Len(t, decorated, 3)
+	require.NotNil(t, decorated[0].Service)
+	assert.Equal(t, "0", aws.StringValue(decorated[0].Service.ServiceName))
+	require.NotNil(t, decorated[1].Service)
+	assert.Equal(t, "1", aws.StringValue(decorated[1].Service.ServiceName))
+	assert.Nil(t, decorated[2].Service)
+}
+
+func TestFetcher_FetchAndDecorate(t *testing.T) {
+	c := ecsmock.NewCluster()
+	f := newMockFetcher(t, c)
+	setTestTasks(c)
+	ctx := context.Background()
+
+	tasks, err := f.FetchAndDecorate(ctx)
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+	for _, task := range tasks[:2] {
+		assert.Equal(t, "nginx", aws.StringValue(task.Definition.Family))
+		assert.Nil(t, task.EC2)
+	}
+	assert.Equal(t, "app", aws.StringValue(tasks[2].Definition.Family))
+	require.NotNil(t, tasks[2].EC2)
+	assert.Equal(t, "i-0", aws.StringValue(tasks[2].EC2.InstanceId))
+	assert.Len(t, f.taskDefCache, 2)
+	assert.Len(t, f.ec2Cache, 1)
+	assert.Nil(t, tasks[0].Service)
+
+	// Cached task definitions and instances are used even if they are gone from the API.
+	c.SetTaskDefinitions(nil)
+	c.SetContainerInstances(nil)
+	tasks, err = f.FetchAndDecorate(ctx)
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+	assert.NotNil(t, tasks[2].EC2)
+
+	// Caches only keep the entries of running tasks.
+	c.SetTasks(ecsmock.GenTasks("p", 1, func(_ int, task *ecs.Task) {
+		task.TaskDefinitionArn = aws.String("nginx:1")
+	}))
+	_, err = f.FetchAndDecorate(ctx)
+	require.NoError(t, err)
+	assert.Len(t, f.taskDefCache, 1)
+	assert.Len(t, f.ec2Cache, 0)
+
+	// Task definitions not found fail the fetch.
+	c.SetTasks(ecsmock.GenTasks("p", 1, func(_ int, task *ecs.Task) {
+		task.TaskDefinitionArn = aws.String("nginx:2")
+	}))
+	_, err = f.FetchAndDecorate(ctx)
+	require.Error(t, err)
+}
@@ -0,0 +1,57 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsobserver
+
+import (
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// taskFilter applies all the matchers to the tasks and keeps the ones with matched containers.
+type taskFilter struct {
+	logger   *zap.Logger
+	matchers map[MatcherType][]Matcher
+}
+
+func newTaskFilter(logger *zap.Logger, matchers map[MatcherType][]Matcher) *taskFilter {
+	return &taskFilter{
+		logger:   logger,
+		matchers: matchers,
+	}
+}
+
+// filter attaches the matched containers to the tasks and returns the tasks having at least one.
+// The returned error is not fatal, it contains the unexpected errors of the matchers, e.g. invalid docker label values.
+func (f *taskFilter) filter(tasks []*Task) ([]*Task, error) {
+	var merr error
+	// Match in a fixed order so the first matcher wins when targets are merged.
+	for _, tpe := range []MatcherType{MatcherTypeService, MatcherTypeTaskDefinition, MatcherTypeDockerLabel} {
+		for index, matcher := range f.matchers[tpe] {
+			res, err := matchContainers(tasks, matcher, index)
+			multierr.AppendInto(&merr, err)
+			for _, container := range res.Containers {
+				tasks[container.TaskIndex].AddMatchedContainer(container)
+			}
+		}
+	}
+
+	var matched []*Task
+	for _, t := range tasks {
+		if len(t.Matched) > 0 {
+			matched = append(matched, t)
+		}
+	}
+	return matched, merr
+}
@@ -4,8 +4,12 @@ go 1.15
 
 require (
 	github.com/aws/aws-sdk-go v1.38.45
+	github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer v0.0.0-00010101000000-000000000000
 	github.com/stretchr/testify v1.7.0
 	go.opentelemetry.io/collector v0.27.1-0.20210524201935-86ea0a131fb2
 	go.uber.org/multierr v1.6.0
 	go.uber.org/zap v1.16.0
+	gopkg.in/yaml.v2 v2.4.0
 )
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer => ../
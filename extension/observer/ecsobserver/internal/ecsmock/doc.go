@@ -12,6 +12,6 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package ecsmock implements mock server for ECS service API.
+// Package ecsmock implements mock server for ECS and EC2 service API.
 // Currently it is only used by ecsobserver extension for unit test.
 package ecsmock
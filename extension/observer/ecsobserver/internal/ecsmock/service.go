@@ -22,6 +22,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ecs"
 )
 
@@ -48,16 +49,25 @@ func DefaultPageLimit() PageLimit {
 
 // Cluster implements both ECS and EC2 API for a single cluster.
 type Cluster struct {
-	taskList []*ecs.Task
-	taskMap  map[string]*ecs.Task
-	limit    PageLimit
+	taskList             []*ecs.Task
+	taskMap              map[string]*ecs.Task
+	serviceList          []*ecs.Service
+	serviceMap           map[string]*ecs.Service
+	defMap               map[string]*ecs.TaskDefinition
+	containerInstanceMap map[string]*ecs.ContainerInstance
+	ec2Map               map[string]*ec2.Instance
+	limit                PageLimit
 }
 
 // NewCluster creates a mock ECS cluster with default limits.
 func NewCluster() *Cluster {
 	return &Cluster{
-		taskMap: make(map[string]*ecs.Task),
-		limit:   DefaultPageLimit(),
+		taskMap:              make(map[string]*ecs.Task),
+		serviceMap:           make(map[string]*ecs.Service),
+		defMap:               make(map[string]*ecs.TaskDefinition),
+		containerInstanceMap: make(map[string]*ecs.ContainerInstance),
+		ec2Map:               make(map[string]*ec2.Instance),
+		limit:                DefaultPageLimit(),
 	}
 }
 
@@ -102,6 +112,96 @@ func (c *Cluster) DescribeTasksWithContext(_ context.Context, input *ecs.Describ
 	return &ecs.DescribeTasksOutput{Failures: failures, Tasks: tasks}, nil
 }
 
+func (c *Cluster) DescribeTaskDefinitionWithContext(_ context.Context, input *ecs.DescribeTaskDefinitionInput, _ ...request.Option) (*ecs.DescribeTaskDefinitionOutput, error) {
+	arn := aws.StringValue(input.TaskDefinition)
+	def, ok := c.defMap[arn]
+	if !ok {
+		return nil, fmt.Errorf("task definition not found %s", arn)
+	}
+	return &ecs.DescribeTaskDefinitionOutput{TaskDefinition: def}, nil
+}
+
+func (c *Cluster) DescribeContainerInstancesWithContext(_ context.Context, input *ecs.DescribeContainerInstancesInput, _ ...request.Option) (*ecs.DescribeContainerInstancesOutput, error) {
+	if len(input.ContainerInstances) > c.limit.DescribeContainerInstanceInput {
+		return nil, fmt.Errorf("too many container instances %d, limit %d", len(input.ContainerInstances), c.limit.DescribeContainerInstanceInput)
+	}
+	var (
+		failures  []*ecs.Failure
+		instances []*ecs.ContainerInstance
+	)
+	for _, instanceArn := range input.ContainerInstances {
+		arn := aws.StringValue(instanceArn)
+		instance, ok := c.containerInstanceMap[arn]
+		if !ok {
+			failures = append(failures, &ecs.Failure{
+				Arn:    instanceArn,
+				Detail: aws.String(fmt.Sprintf("container instance not found arn %s", arn)),
+				Reason: aws.String("container instance not found"),
+			})
+			continue
+		}
+		instances = append(instances, instance)
+	}
+	return &ecs.DescribeContainerInstancesOutput{Failures: failures, ContainerInstances: instances}, nil
+}
+
+func (c *Cluster) ListServicesWithContext(_ context.Context, input *ecs.ListServicesInput, _ ...request.Option) (*ecs.ListServicesOutput, error) {
+	page, err := getPage(pageInput{
+		nextToken: input.NextToken,
+		size:      len(c.serviceList),
+		limit:     c.limit.ListServiceOutput,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := c.serviceList[page.start:page.end]
+	return &ecs.ListServicesOutput{
+		ServiceArns: getArns(res, func(i int) *string {
+			return res[i].ServiceArn
+		}),
+		NextToken: page.nextToken,
+	}, nil
+}
+
+func (c *Cluster) DescribeServicesWithContext(_ context.Context, input *ecs.DescribeServicesInput, _ ...request.Option) (*ecs.DescribeServicesOutput, error) {
+	if len(input.Services) > c.limit.DescribeServiceInput {
+		return nil, fmt.Errorf("too many services %d, limit %d", len(input.Services), c.limit.DescribeServiceInput)
+	}
+	var (
+		failures []*ecs.Failure
+		services []*ecs.Service
+	)
+	for _, serviceArn := range input.Services {
+		arn := aws.StringValue(serviceArn)
+		svc, ok := c.serviceMap[arn]
+		if !ok {
+			failures = append(failures, &ecs.Failure{
+				Arn:    serviceArn,
+				Detail: aws.String(fmt.Sprintf("service not found arn %s", arn)),
+				Reason: aws.String("service not found"),
+			})
+			continue
+		}
+		services = append(services, svc)
+	}
+	return &ecs.DescribeServicesOutput{Failures: failures, Services: services}, nil
+}
+
+// DescribeInstancesWithContext returns the instances found by id in a single reservation, it does not paginate.
+func (c *Cluster) DescribeInstancesWithContext(_ context.Context, input *ec2.DescribeInstancesInput, _ ...request.Option) (*ec2.DescribeInstancesOutput, error) {
+	var instances []*ec2.Instance
+	for _, id := range input.InstanceIds {
+		instance, ok := c.ec2Map[aws.StringValue(id)]
+		if !ok {
+			return nil, fmt.Errorf("instance not found %s", aws.StringValue(id))
+		}
+		instances = append(instances, instance)
+	}
+	return &ec2.DescribeInstancesOutput{
+		Reservations: []*ec2.Reservation{{Instances: instances}},
+	}, nil
+}
+
 // API End
 
 // Hook Start
@@ -116,21 +216,99 @@ func (c *Cluster) SetTasks(tasks []*ecs.Task) {
 	c.taskMap = m
 }
 
+// SetServices updates both list and map.
+func (c *Cluster) SetServices(services []*ecs.Service) {
+	c.serviceList = services
+	m := make(map[string]*ecs.Service, len(services))
+	for _, s := range services {
+		m[aws.StringValue(s.ServiceArn)] = s
+	}
+	c.serviceMap = m
+}
+
+// SetTaskDefinitions updates the task definitions by arn.
+func (c *Cluster) SetTaskDefinitions(defs []*ecs.TaskDefinition) {
+	m := make(map[string]*ecs.TaskDefinition, len(defs))
+	for _, d := range defs {
+		m[aws.StringValue(d.TaskDefinitionArn)] = d
+	}
+	c.defMap = m
+}
+
+// SetContainerInstances updates the container instances by arn.
+func (c *Cluster) SetContainerInstances(instances []*ecs.ContainerInstance) {
+	m := make(map[string]*ecs.ContainerInstance, len(instances))
+	for _, i := range instances {
+		m[aws.StringValue(i.ContainerInstanceArn)] = i
+	}
+	c.containerInstanceMap = m
+}
+
+// SetEc2Instances updates the EC2 instances by id.
+func (c *Cluster) SetEc2Instances(instances []*ec2.Instance) {
+	m := make(map[string]*ec2.Instance, len(instances))
+	for _, i := range instances {
+		m[aws.StringValue(i.InstanceId)] = i
+	}
+	c.ec2Map = m
+}
+
 // Hook End
 
 // Util Start
 
 // GenTasks returns tasks with TaskArn set to arnPrefix+offset, where offset is [0, count).
-func GenTasks(arnPrefix string, count int) []*ecs.Task {
+// The optional modifier is called on each task after it is created.
+func GenTasks(arnPrefix string, count int, modifier func(i int, task *ecs.Task)) []*ecs.Task {
 	var tasks []*ecs.Task
 	for i := 0; i < count; i++ {
-		tasks = append(tasks, &ecs.Task{
+		task := &ecs.Task{
 			TaskArn: aws.String(arnPrefix + strconv.Itoa(i)),
-		})
+		}
+		if modifier != nil {
+			modifier(i, task)
+		}
+		tasks = append(tasks, task)
 	}
 	return tasks
 }
 
+// GenServices returns services with ServiceArn set to arnPrefix+offset and ServiceName set to offset,
+// where offset is [0, count). The optional modifier is called on each service after it is created.
+func GenServices(arnPrefix string, count int, modifier func(i int, s *ecs.Service)) []*ecs.Service {
+	var services []*ecs.Service
+	for i := 0; i < count; i++ {
+		svc := &ecs.Service{
+			ServiceArn:  aws.String(arnPrefix + strconv.Itoa(i)),
+			ServiceName: aws.String(strconv.Itoa(i)),
+		}
+		if modifier != nil {
+			modifier(i, svc)
+		}
+		services = append(services, svc)
+	}
+	return services
+}
+
+// GenTaskDefinitions returns count task definitions of family with revision starting from startRevision,
+// TaskDefinitionArn is set to family:revision. The optional modifier is called on each definition after it is created.
+func GenTaskDefinitions(family string, count int, startRevision int, modifier func(i int, def *ecs.TaskDefinition)) []*ecs.TaskDefinition {
+	var defs []*ecs.TaskDefinition
+	for i := 0; i < count; i++ {
+		revision := startRevision + i
+		def := &ecs.TaskDefinition{
+			TaskDefinitionArn: aws.String(fmt.Sprintf("%s:%d", family, revision)),
+			Family:            aws.String(family),
+			Revision:          aws.Int64(int64(revision)),
+		}
+		if modifier != nil {
+			modifier(i, def)
+		}
+		defs = append(defs, def)
+	}
+	return defs
+}
+
 // Util End
 
 // pagination Start
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -29,7 +30,7 @@ func TestCluster_ListTasksWithContext(t *testing.T) {
 	ctx := context.Background()
 	c := NewCluster()
 	count := DefaultPageLimit().ListTaskOutput*2 + 1
-	c.SetTasks(GenTasks("p", count))
+	c.SetTasks(GenTasks("p", count, nil))
 
 	t.Run("get all", func(t *testing.T) {
 		req := &ecs.ListTasksInput{}
@@ -60,7 +61,7 @@ func TestCluster_DescribeTasksWithContext(t *testing.T) {
 	ctx := context.Background()
 	c := NewCluster()
 	count := 10
-	c.SetTasks(GenTasks("p", count))
+	c.SetTasks(GenTasks("p", count, nil))
 
 	t.Run("exists", func(t *testing.T) {
 		req := &ecs.DescribeTasksInput{Tasks: []*string{aws.String("p0"), aws.String(fmt.Sprintf("p%d", count-1))}}
@@ -78,3 +79,112 @@ func TestCluster_DescribeTasksWithContext(t *testing.T) {
 		assert.Len(t, res.Failures, 1)
 	})
 }
+
+func TestCluster_ListServicesWithContext(t *testing.T) {
+	ctx := context.Background()
+	c := NewCluster()
+	count := DefaultPageLimit().ListServiceOutput*2 + 1
+	c.SetServices(GenServices("s", count, nil))
+
+	req := &ecs.ListServicesInput{}
+	listedServices := 0
+	pages := 0
+	for {
+		res, err := c.ListServicesWithContext(ctx, req)
+		require.NoError(t, err)
+		listedServices += len(res.ServiceArns)
+		pages++
+		if res.NextToken == nil {
+			break
+		}
+		req.NextToken = res.NextToken
+	}
+	assert.Equal(t, count, listedServices)
+	assert.Equal(t, 3, pages)
+}
+
+func TestCluster_DescribeServicesWithContext(t *testing.T) {
+	ctx := context.Background()
+	c := NewCluster()
+	count := DefaultPageLimit().DescribeServiceInput + 1
+	c.SetServices(GenServices("s", count, nil))
+
+	t.Run("exists and not found", func(t *testing.T) {
+		req := &ecs.DescribeServicesInput{Services: []*string{aws.String("s0"), aws.String(fmt.Sprintf("s%d", count))}}
+		res, err := c.DescribeServicesWithContext(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, res.Services, 1)
+		assert.Equal(t, "0", aws.StringValue(res.Services[0].ServiceName))
+		assert.Len(t, res.Failures, 1)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		var arns []*string
+		for i := 0; i < count; i++ {
+			arns = append(arns, aws.String(fmt.Sprintf("s%d", i)))
+		}
+		_, err := c.DescribeServicesWithContext(ctx, &ecs.DescribeServicesInput{Services: arns})
+		require.Error(t, err)
+	})
+}
+
+func TestCluster_DescribeTaskDefinitionWithContext(t *testing.T) {
+	ctx := context.Background()
+	c := NewCluster()
+	c.SetTaskDefinitions(GenTaskDefinitions("d", 2, 1, nil))
+
+	t.Run("exists", func(t *testing.T) {
+		req := &ecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String("d:2")}
+		res, err := c.DescribeTaskDefinitionWithContext(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, "d", aws.StringValue(res.TaskDefinition.Family))
+		assert.Equal(t, int64(2), aws.Int64Value(res.TaskDefinition.Revision))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		req := &ecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String("d:3")}
+		_, err := c.DescribeTaskDefinitionWithContext(ctx, req)
+		require.Error(t, err)
+	})
+}
+
+func TestCluster_DescribeContainerInstancesWithContext(t *testing.T) {
+	ctx := context.Background()
+	c := NewCluster()
+	c.SetContainerInstances([]*ecs.ContainerInstance{
+		{ContainerInstanceArn: aws.String("ci0"), Ec2InstanceId: aws.String("i-0")},
+	})
+
+	t.Run("exists and not found", func(t *testing.T) {
+		req := &ecs.DescribeContainerInstancesInput{ContainerInstances: []*string{aws.String("ci0"), aws.String("ci1")}}
+		res, err := c.DescribeContainerInstancesWithContext(ctx, req)
+		require.NoError(t, err)
+		assert.Len(t, res.ContainerInstances, 1)
+		assert.Len(t, res.Failures, 1)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		var arns []*string
+		for i := 0; i < DefaultPageLimit().DescribeContainerInstanceInput+1; i++ {
+			arns = append(arns, aws.String(fmt.Sprintf("ci%d", i)))
+		}
+		_, err := c.DescribeContainerInstancesWithContext(ctx, &ecs.DescribeContainerInstancesInput{ContainerInstances: arns})
+		require.Error(t, err)
+	})
+}
+
+func TestCluster_DescribeInstancesWithContext(t *testing.T) {
+	ctx := context.Background()
+	c := NewCluster()
+	c.SetEc2Instances([]*ec2.Instance{
+		{InstanceId: aws.String("i-0"), PrivateIpAddress: aws.String("10.0.0.1")},
+	})
+
+	res, err := c.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{InstanceIds: []*string{aws.String("i-0")}})
+	require.NoError(t, err)
+	require.Len(t, res.Reservations, 1)
+	assert.Equal(t, "10.0.0.1", aws.StringValue(res.Reservations[0].Instances[0].PrivateIpAddress))
+
+	_, err = c.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{InstanceIds: []*string{aws.String("i-1")}})
+	require.Error(t, err)
+}
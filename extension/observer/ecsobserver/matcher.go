@@ -16,7 +16,9 @@ package ecsobserver
 
 import (
 	"fmt"
+	"regexp"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -93,6 +95,40 @@ type MatchedTarget struct {
 // to help user debug. e.g. type ^ngix-*$ does not match nginx-service.
 var errNotMatched = fmt.Errorf("container not matched")
 
+// matchContainerPorts is shared by service and task definition matchers after the service or task definition
+// is matched. It returns a target for each port in metrics_ports that is exposed by the container,
+// and errNotMatched if the container name does not match the optional regex or no port is exposed.
+func matchContainerPorts(nameRegex *regexp.Regexp, cfg CommonExporterConfig, c *ecs.ContainerDefinition) ([]MatchedTarget, error) {
+	if nameRegex != nil && !nameRegex.MatchString(aws.StringValue(c.Name)) {
+		return nil, errNotMatched
+	}
+	var targets []MatchedTarget
+	for _, port := range cfg.MetricsPorts {
+		for _, portMapping := range c.PortMappings {
+			if aws.Int64Value(portMapping.ContainerPort) == int64(port) {
+				targets = append(targets, MatchedTarget{
+					Port:        port,
+					MetricsPath: cfg.MetricsPath,
+					Job:         cfg.JobName,
+				})
+				break
+			}
+		}
+	}
+	if len(targets) == 0 {
+		return nil, errNotMatched
+	}
+	return targets, nil
+}
+
+// compileOptionalRegex returns nil for an empty pattern.
+func compileOptionalRegex(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile(pattern)
+}
+
 // matchContainers apply one matcher to a list of tasks and returns MatchResult.
 // It does not modify the task in place, the attaching match result logic is
 // performed by TaskFilter at later stage.
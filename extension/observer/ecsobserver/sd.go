@@ -17,41 +17,151 @@ package ecsobserver
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"time"
 
 	"go.uber.org/zap"
 )
 
 type ServiceDiscovery struct {
-	logger *zap.Logger
-	cfg    Config
+	logger   *zap.Logger
+	cfg      Config
+	fetcher  *taskFetcher
+	filter   *taskFilter
+	exporter *taskExporter
 }
 
 type ServiceDiscoveryOptions struct {
 	Logger *zap.Logger
+
+	// test overrides
+	fetcherOverride *taskFetcher
 }
 
 func NewDiscovery(cfg Config, opts ServiceDiscoveryOptions) (*ServiceDiscovery, error) {
-	// NOTE: there are other init logic, currently removed to reduce pr size
+	matchers, err := newMatchers(cfg, MatcherOptions{Logger: opts.Logger})
+	if err != nil {
+		return nil, err
+	}
+	fetcher := opts.fetcherOverride
+	if fetcher == nil {
+		fetcher, err = newTaskFetcher(taskFetcherOptions{
+			Logger:  opts.Logger,
+			Cluster: cfg.ClusterName,
+			Region:  cfg.ClusterRegion,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
 	return &ServiceDiscovery{
-		logger: opts.Logger,
-		cfg:    cfg,
+		logger:   opts.Logger,
+		cfg:      cfg,
+		fetcher:  fetcher,
+		filter:   newTaskFilter(opts.Logger, matchers),
+		exporter: &taskExporter{cluster: cfg.ClusterName},
 	}, nil
 }
 
-// RunAndWriteFile writes the output to Config.ResultFile.
-func (s *ServiceDiscovery) RunAndWriteFile(ctx context.Context) error {
+// newMatchers validates the filters in config and creates their matchers.
+func newMatchers(cfg Config, opts MatcherOptions) (map[MatcherType][]Matcher, error) {
+	matchers := map[MatcherType][]Matcher{}
+	for i := range cfg.Services {
+		matcherCfg := &cfg.Services[i]
+		if err := matcherCfg.Init(); err != nil {
+			return nil, fmt.Errorf("invalid services[%d]: %w", i, err)
+		}
+		matcher, err := matcherCfg.NewMatcher(opts)
+		if err != nil {
+			return nil, err
+		}
+		matchers[MatcherTypeService] = append(matchers[MatcherTypeService], matcher)
+	}
+	for i := range cfg.TaskDefinitions {
+		matcherCfg := &cfg.TaskDefinitions[i]
+		if err := matcherCfg.Init(); err != nil {
+			return nil, fmt.Errorf("invalid task_definitions[%d]: %w", i, err)
+		}
+		matcher, err := matcherCfg.NewMatcher(opts)
+		if err != nil {
+			return nil, err
+		}
+		matchers[MatcherTypeTaskDefinition] = append(matchers[MatcherTypeTaskDefinition], matcher)
+	}
+	for i := range cfg.DockerLabels {
+		matcherCfg := &cfg.DockerLabels[i]
+		if err := matcherCfg.Init(); err != nil {
+			return nil, fmt.Errorf("invalid docker_labels[%d]: %w", i, err)
+		}
+		matcher, err := matcherCfg.NewMatcher(opts)
+		if err != nil {
+			return nil, err
+		}
+		matchers[MatcherTypeDockerLabel] = append(matchers[MatcherTypeDockerLabel], matcher)
+	}
+	return matchers, nil
+}
+
+// RunAndWriteFile discovers the targets right away and then every RefreshInterval until ctx is done.
+// The targets are written to Config.ResultFile, if set, and passed to onTargets, if not nil.
+func (s *ServiceDiscovery) RunAndWriteFile(ctx context.Context, onTargets func([]PrometheusECSTarget)) error {
 	ticker := time.NewTicker(s.cfg.RefreshInterval)
+	defer ticker.Stop()
 	for {
+		targets, err := s.Discover(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			s.logger.Error("Failed to discover ECS targets", zap.Error(err))
+		default:
+			if s.cfg.ResultFile != "" {
+				if err := s.writeFile(targets); err != nil {
+					s.logger.Error("Failed to write ECS targets", zap.String("result_file", s.cfg.ResultFile), zap.Error(err))
+				}
+			}
+			if onTargets != nil {
+				onTargets(targets)
+			}
+		}
+
 		select {
 		case <-ctx.Done():
 			return nil
 		case <-ticker.C:
-			// do actual work
 		}
 	}
 }
 
+// Discover fetches the running tasks, applies the filters and returns the targets of the matched containers.
+// Errors of single tasks and containers, e.g. an invalid port label, are logged and do not fail the discovery.
 func (s *ServiceDiscovery) Discover(ctx context.Context) ([]PrometheusECSTarget, error) {
-	return nil, fmt.Errorf("not implemented")
+	tasks, err := s.fetcher.FetchAndDecorate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matched, err := s.filter.filter(tasks)
+	if err != nil {
+		s.logger.Warn("Failed to match some ECS task containers", zap.Error(err))
+	}
+	targets, err := s.exporter.exportTasks(matched)
+	if err != nil {
+		s.logger.Warn("Failed to export some ECS targets", zap.Error(err))
+	}
+	return targets, nil
+}
+
+// writeFile replaces Config.ResultFile with the targets. The file is replaced atomically
+// so prometheus never reads a partially written file.
+func (s *ServiceDiscovery) writeFile(targets []PrometheusECSTarget) error {
+	b, err := TargetsToFileSDYAML(targets, s.cfg.JobLabelName)
+	if err != nil {
+		return err
+	}
+	tmpFile := s.cfg.ResultFile + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, s.cfg.ResultFile)
 }
@@ -16,22 +16,207 @@ package ecsobserver
 
 import (
 	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer/ecsobserver/internal/ecsmock"
 )
 
 func TestNewDiscovery(t *testing.T) {
-	t.Run("empty impl", func(t *testing.T) {
-		_, err := NewDiscovery(ExampleConfig(), ServiceDiscoveryOptions{})
+	t.Run("example config", func(t *testing.T) {
+		_, err := NewDiscovery(ExampleConfig(), ServiceDiscoveryOptions{
+			Logger:          zap.NewExample(),
+			fetcherOverride: newMockFetcher(t, ecsmock.NewCluster()),
+		})
 		require.NoError(t, err)
 	})
-	t.Run("for the coverage", func(t *testing.T) {
-		d := ServiceDiscovery{}
-		_, err := d.Discover(context.TODO())
+	t.Run("invalid docker label", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.DockerLabels = []DockerLabelConfig{{}}
+		_, err := NewDiscovery(cfg, ServiceDiscoveryOptions{Logger: zap.NewExample()})
+		require.Error(t, err)
+	})
+	t.Run("invalid service", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Services = []ServiceConfig{{NamePattern: "retail"}}
+		_, err := NewDiscovery(cfg, ServiceDiscoveryOptions{Logger: zap.NewExample()})
 		require.Error(t, err)
+		assert.Contains(t, err.Error(), "services[0]")
+	})
+	t.Run("invalid task definition", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.TaskDefinitions = []TaskDefinitionConfig{{ArnPattern: "*memcached"}}
+		_, err := NewDiscovery(cfg, ServiceDiscoveryOptions{Logger: zap.NewExample()})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "task_definitions[0]")
+	})
+}
+
+func TestServiceDiscovery_Discover(t *testing.T) {
+	sd := newTestDiscovery(t, testDiscoveryConfig(), ecsmock.NewCluster())
+	c := sd.fetcher.ecs.(*ecsmock.Cluster)
+
+	t.Run("empty", func(t *testing.T) {
+		targets, err := sd.Discover(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, targets)
 	})
+
+	setTestTasks(c)
+	t.Run("awsvpc and bridge", func(t *testing.T) {
+		targets, err := sd.Discover(context.Background())
+		require.NoError(t, err)
+		require.Len(t, targets, 3)
+		assert.Equal(t, PrometheusECSTarget{
+			Source:                 "nginx-task-0",
+			Address:                "10.0.0.1:9113",
+			MetricsPath:            "/metrics",
+			Job:                    "nginx",
+			ClusterName:            "ecs-test",
+			TaskDefinitionFamily:   "nginx",
+			TaskDefinitionRevision: 1,
+			TaskLaunchType:         ecs.LaunchTypeFargate,
+			ContainerName:          "exporter",
+			ContainerLabels: map[string]string{
+				"ECS_PROMETHEUS_EXPORTER_PORT": "9113",
+				"ECS_PROMETHEUS_JOB_NAME":      "nginx",
+			},
+		}, targets[0])
+		assert.Equal(t, "10.0.0.2:9113", targets[1].Address)
+		assert.Equal(t, PrometheusECSTarget{
+			Source:                 "app-task-0",
+			Address:                "172.31.0.1:32768",
+			MetricsPath:            "/stats",
+			ClusterName:            "ecs-test",
+			TaskDefinitionFamily:   "app",
+			TaskDefinitionRevision: 3,
+			TaskLaunchType:         ecs.LaunchTypeEc2,
+			ContainerName:          "app",
+			ContainerLabels: map[string]string{
+				"ECS_PROMETHEUS_EXPORTER_PORT": "8080",
+				"ECS_PROMETHEUS_METRICS_PATH":  "/stats",
+			},
+			EC2InstanceID:   "i-0",
+			EC2InstanceType: "t3.medium",
+			EC2VpcID:        "vpc-0",
+			EC2SubnetID:     "subnet-0",
+			EC2PrivateIP:    "172.31.0.1",
+		}, targets[2])
+	})
+
+	t.Run("skip invalid targets", func(t *testing.T) {
+		// The app container is no longer bound to a host port.
+		c.SetTasks(ecsmock.GenTasks("app-task-", 1, func(_ int, task *ecs.Task) {
+			task.TaskDefinitionArn = aws.String("app:3")
+			task.ContainerInstanceArn = aws.String("ci-0")
+		}))
+		targets, err := sd.Discover(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, targets)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		c.SetTasks(ecsmock.GenTasks("unknown-", 1, func(_ int, task *ecs.Task) {
+			task.TaskDefinitionArn = aws.String("unknown:1")
+		}))
+		_, err := sd.Discover(context.Background())
+		require.Error(t, err)
+	})
+}
+
+func TestServiceDiscovery_DiscoverServicesAndTaskDefinitions(t *testing.T) {
+	cfg := testDiscoveryConfig()
+	cfg.DockerLabels = nil
+	cfg.Services = []ServiceConfig{
+		{
+			NamePattern:          "^nginx-service$",
+			ContainerNamePattern: "^nginx$",
+			CommonExporterConfig: CommonExporterConfig{
+				JobName:      "nginx-service",
+				MetricsPorts: []int{80},
+			},
+		},
+	}
+	cfg.TaskDefinitions = []TaskDefinitionConfig{
+		{
+			ArnPattern: "^app:[0-9]+$",
+			CommonExporterConfig: CommonExporterConfig{
+				MetricsPath:  "/app/metrics",
+				MetricsPorts: []int{8080},
+			},
+		},
+	}
+	c := ecsmock.NewCluster()
+	setTestTasks(c)
+	c.SetServices(ecsmock.GenServices("nginx-service-", 1, func(_ int, s *ecs.Service) {
+		s.ServiceName = aws.String("nginx-service")
+		s.Deployments = []*ecs.Deployment{{Id: aws.String("ecs-svc/1")}}
+	}))
+	sd := newTestDiscovery(t, cfg, c)
+	tasks, err := sd.fetcher.GetAllTasks(context.Background())
+	require.NoError(t, err)
+	// Only the first nginx task is started by the service.
+	tasks[0].StartedBy = aws.String("ecs-svc/1")
+
+	targets, err := sd.Discover(context.Background())
+	require.NoError(t, err)
+	require.Len(t, targets, 2)
+	assert.Equal(t, "10.0.0.1:80", targets[0].Address)
+	assert.Equal(t, "nginx-service", targets[0].Job)
+	assert.Equal(t, "nginx-service", targets[0].ServiceName)
+	assert.Equal(t, "nginx", targets[0].ContainerName)
+	assert.Equal(t, "172.31.0.1:32768", targets[1].Address)
+	assert.Equal(t, "/app/metrics", targets[1].MetricsPath)
+	assert.Empty(t, targets[1].ServiceName)
+}
+
+func TestServiceDiscovery_RunAndWriteFile(t *testing.T) {
+	cfg := testDiscoveryConfig()
+	cfg.ResultFile = filepath.Join(t.TempDir(), "ecs_sd_targets.yaml")
+	c := ecsmock.NewCluster()
+	setTestTasks(c)
+	sd := newTestDiscovery(t, cfg, c)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	discovered := make(chan []PrometheusECSTarget, 1)
+	done := make(chan error)
+	go func() {
+		done <- sd.RunAndWriteFile(ctx, func(targets []PrometheusECSTarget) {
+			select {
+			case discovered <- targets:
+			default:
+			}
+		})
+	}()
+
+	// The first discovery happens without waiting for the refresh interval.
+	select {
+	case targets := <-discovered:
+		assert.Len(t, targets, 3)
+	case <-time.After(5 * time.Second):
+		t.Fatal("targets were not discovered")
+	}
+	cancel()
+	require.NoError(t, <-done)
+
+	b, err := ioutil.ReadFile(cfg.ResultFile)
+	require.NoError(t, err)
+	var groups []fileSDTarget
+	require.NoError(t, yaml.Unmarshal(b, &groups))
+	require.Len(t, groups, 3)
+	assert.Equal(t, []string{"10.0.0.1:9113"}, groups[0].Targets)
+	assert.Equal(t, "nginx", groups[0].Labels[defaultJobLabelName])
 }
 
 // Util Start
@@ -56,4 +241,119 @@ func testMatcherOptions() MatcherOptions {
 	}
 }
 
+func testDiscoveryConfig() Config {
+	cfg := DefaultConfig()
+	cfg.ClusterName = "ecs-test"
+	cfg.ResultFile = ""
+	cfg.RefreshInterval = time.Hour
+	cfg.DockerLabels = []DockerLabelConfig{
+		{
+			PortLabel:        "ECS_PROMETHEUS_EXPORTER_PORT",
+			JobNameLabel:     "ECS_PROMETHEUS_JOB_NAME",
+			MetricsPathLabel: "ECS_PROMETHEUS_METRICS_PATH",
+		},
+	}
+	return cfg
+}
+
+func newTestDiscovery(t *testing.T, cfg Config, c *ecsmock.Cluster) *ServiceDiscovery {
+	sd, err := NewDiscovery(cfg, ServiceDiscoveryOptions{
+		Logger:          zap.NewExample(),
+		fetcherOverride: newMockFetcher(t, c),
+	})
+	require.NoError(t, err)
+	return sd
+}
+
+func newMockFetcher(t *testing.T, c *ecsmock.Cluster) *taskFetcher {
+	f, err := newTaskFetcher(taskFetcherOptions{
+		Logger:      zap.NewExample(),
+		Cluster:     "not used",
+		Region:      "not used",
+		ecsOverride: c,
+		ec2Override: c,
+	})
+	require.NoError(t, err)
+	return f
+}
+
+// setTestTasks sets two awsvpc nginx tasks with an exporter sidecar and one app task in bridge
+// mode on an EC2 container instance.
+func setTestTasks(c *ecsmock.Cluster) {
+	nginxDefs := ecsmock.GenTaskDefinitions("nginx", 1, 1, func(_ int, def *ecs.TaskDefinition) {
+		def.NetworkMode = aws.String(ecs.NetworkModeAwsvpc)
+		def.ContainerDefinitions = []*ecs.ContainerDefinition{
+			{
+				Name:         aws.String("nginx"),
+				PortMappings: []*ecs.PortMapping{{ContainerPort: aws.Int64(80), HostPort: aws.Int64(80)}},
+			},
+			{
+				Name: aws.String("exporter"),
+				DockerLabels: map[string]*string{
+					"ECS_PROMETHEUS_EXPORTER_PORT": aws.String("9113"),
+					"ECS_PROMETHEUS_JOB_NAME":      aws.String("nginx"),
+				},
+				PortMappings: []*ecs.PortMapping{{ContainerPort: aws.Int64(9113), HostPort: aws.Int64(9113)}},
+			},
+		}
+	})
+	appDefs := ecsmock.GenTaskDefinitions("app", 1, 3, func(_ int, def *ecs.TaskDefinition) {
+		def.NetworkMode = aws.String(ecs.NetworkModeBridge)
+		def.ContainerDefinitions = []*ecs.ContainerDefinition{
+			{
+				Name: aws.String("app"),
+				DockerLabels: map[string]*string{
+					"ECS_PROMETHEUS_EXPORTER_PORT": aws.String("8080"),
+					"ECS_PROMETHEUS_METRICS_PATH":  aws.String("/stats"),
+				},
+				PortMappings: []*ecs.PortMapping{{ContainerPort: aws.Int64(8080)}},
+			},
+		}
+	})
+	c.SetTaskDefinitions(append(nginxDefs, appDefs...))
+
+	nginxTasks := ecsmock.GenTasks("nginx-task-", 2, func(i int, task *ecs.Task) {
+		task.TaskDefinitionArn = aws.String("nginx:1")
+		task.LaunchType = aws.String(ecs.LaunchTypeFargate)
+		task.Attachments = []*ecs.Attachment{
+			{
+				Type: aws.String("ElasticNetworkInterface"),
+				Details: []*ecs.KeyValuePair{
+					{
+						Name:  aws.String("privateIPv4Address"),
+						Value: aws.String(fmt.Sprintf("10.0.0.%d", i+1)),
+					},
+				},
+			},
+		}
+	})
+	appTasks := ecsmock.GenTasks("app-task-", 1, func(_ int, task *ecs.Task) {
+		task.TaskDefinitionArn = aws.String("app:3")
+		task.LaunchType = aws.String(ecs.LaunchTypeEc2)
+		task.ContainerInstanceArn = aws.String("ci-0")
+		task.Containers = []*ecs.Container{
+			{
+				Name: aws.String("app"),
+				NetworkBindings: []*ecs.NetworkBinding{
+					{ContainerPort: aws.Int64(8080), HostPort: aws.Int64(32768)},
+				},
+			},
+		}
+	})
+	c.SetTasks(append(nginxTasks, appTasks...))
+
+	c.SetContainerInstances([]*ecs.ContainerInstance{
+		{ContainerInstanceArn: aws.String("ci-0"), Ec2InstanceId: aws.String("i-0")},
+	})
+	c.SetEc2Instances([]*ec2.Instance{
+		{
+			InstanceId:       aws.String("i-0"),
+			InstanceType:     aws.String("t3.medium"),
+			PrivateIpAddress: aws.String("172.31.0.1"),
+			VpcId:            aws.String("vpc-0"),
+			SubnetId:         aws.String("subnet-0"),
+		},
+	})
+}
+
 // Util End
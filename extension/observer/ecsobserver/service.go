@@ -14,6 +14,15 @@
 
 package ecsobserver
 
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"go.uber.org/zap"
+)
+
 type ServiceConfig struct {
 	CommonExporterConfig `mapstructure:",squash" yaml:",inline"`
 
@@ -23,3 +32,52 @@ type ServiceConfig struct {
 	// Otherwise both service and container name petterns need to metch.
 	ContainerNamePattern string `mapstructure:"container_name_pattern" yaml:"container_name_pattern"`
 }
+
+func (s *ServiceConfig) Init() error {
+	if s.NamePattern == "" {
+		return fmt.Errorf("name_pattern is empty")
+	}
+	if _, err := regexp.Compile(s.NamePattern); err != nil {
+		return fmt.Errorf("invalid name_pattern %q: %w", s.NamePattern, err)
+	}
+	if _, err := regexp.Compile(s.ContainerNamePattern); err != nil {
+		return fmt.Errorf("invalid container_name_pattern %q: %w", s.ContainerNamePattern, err)
+	}
+	return s.validateMetricsPorts()
+}
+
+func (s *ServiceConfig) NewMatcher(options MatcherOptions) (Matcher, error) {
+	nameRegex, err := regexp.Compile(s.NamePattern)
+	if err != nil {
+		return nil, err
+	}
+	containerNameRegex, err := compileOptionalRegex(s.ContainerNamePattern)
+	if err != nil {
+		return nil, err
+	}
+	return &serviceMatcher{
+		logger:             options.Logger,
+		cfg:                *s,
+		nameRegex:          nameRegex,
+		containerNameRegex: containerNameRegex,
+	}, nil
+}
+
+type serviceMatcher struct {
+	logger             *zap.Logger
+	cfg                ServiceConfig
+	nameRegex          *regexp.Regexp
+	containerNameRegex *regexp.Regexp
+}
+
+func (s *serviceMatcher) Type() MatcherType {
+	return MatcherTypeService
+}
+
+func (s *serviceMatcher) MatchTargets(t *Task, c *ecs.ContainerDefinition) ([]MatchedTarget, error) {
+	// Tasks not started by a service, e.g. batch jobs, never match.
+	if t.Service == nil || !s.nameRegex.MatchString(aws.StringValue(t.Service.ServiceName)) {
+		return nil, errNotMatched
+	}
+	return matchContainerPorts(s.containerNameRegex, s.cfg.CommonExporterConfig, c)
+}
@@ -0,0 +1,148 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsobserver
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceMatcher_Match(t *testing.T) {
+	t.Run("must set name pattern", func(t *testing.T) {
+		cfg := ServiceConfig{CommonExporterConfig: CommonExporterConfig{MetricsPorts: []int{2112}}}
+		require.Error(t, cfg.Init())
+	})
+
+	t.Run("must set metrics ports", func(t *testing.T) {
+		cfg := ServiceConfig{NamePattern: "nginx"}
+		require.Error(t, cfg.Init())
+	})
+
+	t.Run("invalid regex", func(t *testing.T) {
+		cfg := ServiceConfig{
+			NamePattern:          "*nginx",
+			CommonExporterConfig: CommonExporterConfig{MetricsPorts: []int{2112}},
+		}
+		require.Error(t, cfg.Init())
+		cfg.NamePattern = "nginx"
+		cfg.ContainerNamePattern = "(exporter"
+		require.Error(t, cfg.Init())
+	})
+
+	genTasks := func() []*Task {
+		return []*Task{
+			{
+				Service: &ecs.Service{ServiceName: aws.String("retail-web")},
+				Definition: &ecs.TaskDefinition{
+					ContainerDefinitions: []*ecs.ContainerDefinition{
+						{
+							Name: aws.String("web"),
+							PortMappings: []*ecs.PortMapping{
+								{ContainerPort: aws.Int64(80)},
+								{ContainerPort: aws.Int64(2112)},
+							},
+						},
+						{
+							Name: aws.String("exporter"),
+							PortMappings: []*ecs.PortMapping{
+								{ContainerPort: aws.Int64(9113)},
+							},
+						},
+					},
+				},
+			},
+			{
+				Service: &ecs.Service{ServiceName: aws.String("game")},
+				Definition: &ecs.TaskDefinition{
+					ContainerDefinitions: []*ecs.ContainerDefinition{
+						{
+							Name:         aws.String("exporter"),
+							PortMappings: []*ecs.PortMapping{{ContainerPort: aws.Int64(9113)}},
+						},
+					},
+				},
+			},
+			{
+				// Not started by a service.
+				Definition: &ecs.TaskDefinition{
+					ContainerDefinitions: []*ecs.ContainerDefinition{
+						{
+							Name:         aws.String("exporter"),
+							PortMappings: []*ecs.PortMapping{{ContainerPort: aws.Int64(9113)}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("name pattern", func(t *testing.T) {
+		cfg := ServiceConfig{
+			NamePattern: "^retail-.*$",
+			CommonExporterConfig: CommonExporterConfig{
+				JobName:      "retail",
+				MetricsPath:  "/v2/metrics",
+				MetricsPorts: []int{2112, 9113, 404},
+			},
+		}
+		res := newMatcherAndMatch(t, &cfg, genTasks())
+		assert.Equal(t, &MatchResult{
+			Tasks: []int{0},
+			Containers: []MatchedContainer{
+				{
+					TaskIndex:      0,
+					ContainerIndex: 0,
+					Targets: []MatchedTarget{
+						{
+							MatcherType: MatcherTypeService,
+							Port:        2112,
+							MetricsPath: "/v2/metrics",
+							Job:         "retail",
+						},
+					},
+				},
+				{
+					TaskIndex:      0,
+					ContainerIndex: 1,
+					Targets: []MatchedTarget{
+						{
+							MatcherType: MatcherTypeService,
+							Port:        9113,
+							MetricsPath: "/v2/metrics",
+							Job:         "retail",
+						},
+					},
+				},
+			},
+		}, res)
+	})
+
+	t.Run("container name pattern", func(t *testing.T) {
+		cfg := ServiceConfig{
+			NamePattern:          ".*",
+			ContainerNamePattern: "^exporter$",
+			CommonExporterConfig: CommonExporterConfig{MetricsPorts: []int{9113}},
+		}
+		res := newMatcherAndMatch(t, &cfg, genTasks())
+		assert.Equal(t, []int{0, 1}, res.Tasks)
+		require.Len(t, res.Containers, 2)
+		assert.Equal(t, 1, res.Containers[0].ContainerIndex)
+		assert.Equal(t, 0, res.Containers[1].ContainerIndex)
+	})
+}
@@ -17,6 +17,8 @@ package ecsobserver
 import (
 	"regexp"
 	"strconv"
+
+	"gopkg.in/yaml.v2"
 )
 
 // target.go defines labels and structs in exported target.
@@ -109,6 +111,37 @@ func TargetToLabels(t PrometheusECSTarget) map[string]string {
 	return labels
 }
 
+// fileSDTarget is a target group in prometheus file discovery format.
+type fileSDTarget struct {
+	Targets []string          `yaml:"targets"`
+	Labels  map[string]string `yaml:"labels"`
+}
+
+// TargetsToFileSDYAML converts targets into prometheus file discovery format in YAML.
+// Labels with empty values are omitted and the job is exported using jobLabelName,
+// see Config.JobLabelName.
+func TargetsToFileSDYAML(targets []PrometheusECSTarget, jobLabelName string) ([]byte, error) {
+	groups := make([]fileSDTarget, 0, len(targets))
+	for _, t := range targets {
+		labels := TargetToLabels(t)
+		delete(labels, labelAddress)
+		for k, v := range labels {
+			if v == "" {
+				delete(labels, k)
+			}
+		}
+		if job, ok := labels[labelJob]; ok && jobLabelName != "" {
+			delete(labels, labelJob)
+			labels[jobLabelName] = job
+		}
+		groups = append(groups, fileSDTarget{
+			Targets: []string{t.Address},
+			Labels:  labels,
+		})
+	}
+	return yaml.Marshal(groups)
+}
+
 // addTagsToLabels merge tags (from ecs, ec2 etc.) into existing labels.
 // tag key are prefixed with labelNamePrefix and sanitize with sanitizeLabelName.
 func addTagsToLabels(tags map[string]string, labelNamePrefix string, labels map[string]string) {
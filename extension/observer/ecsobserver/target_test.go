@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTargetToLabels(t *testing.T) {
@@ -33,3 +34,38 @@ func TestTargetToLabels(t *testing.T) {
 		assert.Equal(t, "same", m["__meta_ecs_task_tags_ab"])
 	})
 }
+
+func TestTargetsToFileSDYAML(t *testing.T) {
+	targets := []PrometheusECSTarget{
+		{
+			Source:                 "arn:task:1",
+			Address:                "10.0.0.1:9113",
+			MetricsPath:            "/metrics",
+			Job:                    "nginx",
+			TaskDefinitionFamily:   "nginx",
+			TaskDefinitionRevision: 2,
+		},
+		{
+			Source:      "arn:task:2",
+			Address:     "10.0.0.2:8080",
+			MetricsPath: "/stats",
+		},
+	}
+	b, err := TargetsToFileSDYAML(targets, "prometheus_job")
+	require.NoError(t, err)
+	assert.Equal(t, `- targets:
+  - 10.0.0.1:9113
+  labels:
+    __meta_ecs_source: arn:task:1
+    __meta_ecs_task_definition_family: nginx
+    __meta_ecs_task_definition_revision: "2"
+    __metrics_path__: /metrics
+    prometheus_job: nginx
+- targets:
+  - 10.0.0.2:8080
+  labels:
+    __meta_ecs_source: arn:task:2
+    __meta_ecs_task_definition_revision: "0"
+    __metrics_path__: /stats
+`, string(b))
+}
@@ -14,6 +14,15 @@
 
 package ecsobserver
 
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"go.uber.org/zap"
+)
+
 type TaskDefinitionConfig struct {
 	CommonExporterConfig `mapstructure:",squash" yaml:",inline"`
 
@@ -23,3 +32,51 @@ type TaskDefinitionConfig struct {
 	// Otherwise both service and container name petterns need to metch.
 	ContainerNamePattern string `mapstructure:"container_name_pattern" yaml:"container_name_pattern"`
 }
+
+func (t *TaskDefinitionConfig) Init() error {
+	if t.ArnPattern == "" {
+		return fmt.Errorf("arn_pattern is empty")
+	}
+	if _, err := regexp.Compile(t.ArnPattern); err != nil {
+		return fmt.Errorf("invalid arn_pattern %q: %w", t.ArnPattern, err)
+	}
+	if _, err := regexp.Compile(t.ContainerNamePattern); err != nil {
+		return fmt.Errorf("invalid container_name_pattern %q: %w", t.ContainerNamePattern, err)
+	}
+	return t.validateMetricsPorts()
+}
+
+func (t *TaskDefinitionConfig) NewMatcher(options MatcherOptions) (Matcher, error) {
+	arnRegex, err := regexp.Compile(t.ArnPattern)
+	if err != nil {
+		return nil, err
+	}
+	containerNameRegex, err := compileOptionalRegex(t.ContainerNamePattern)
+	if err != nil {
+		return nil, err
+	}
+	return &taskDefinitionMatcher{
+		logger:             options.Logger,
+		cfg:                *t,
+		arnRegex:           arnRegex,
+		containerNameRegex: containerNameRegex,
+	}, nil
+}
+
+type taskDefinitionMatcher struct {
+	logger             *zap.Logger
+	cfg                TaskDefinitionConfig
+	arnRegex           *regexp.Regexp
+	containerNameRegex *regexp.Regexp
+}
+
+func (m *taskDefinitionMatcher) Type() MatcherType {
+	return MatcherTypeTaskDefinition
+}
+
+func (m *taskDefinitionMatcher) MatchTargets(t *Task, c *ecs.ContainerDefinition) ([]MatchedTarget, error) {
+	if !m.arnRegex.MatchString(aws.StringValue(t.Definition.TaskDefinitionArn)) {
+		return nil, errNotMatched
+	}
+	return matchContainerPorts(m.containerNameRegex, m.cfg.CommonExporterConfig, c)
+}
@@ -0,0 +1,114 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsobserver
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskDefinitionMatcher_Match(t *testing.T) {
+	t.Run("must set arn pattern", func(t *testing.T) {
+		cfg := TaskDefinitionConfig{CommonExporterConfig: CommonExporterConfig{MetricsPorts: []int{2112}}}
+		require.Error(t, cfg.Init())
+	})
+
+	t.Run("must set valid metrics ports", func(t *testing.T) {
+		cfg := TaskDefinitionConfig{ArnPattern: "nginx"}
+		require.Error(t, cfg.Init())
+		cfg.MetricsPorts = []int{0}
+		require.Error(t, cfg.Init())
+	})
+
+	t.Run("invalid regex", func(t *testing.T) {
+		cfg := TaskDefinitionConfig{
+			ArnPattern:           "*memcached.*",
+			CommonExporterConfig: CommonExporterConfig{MetricsPorts: []int{2112}},
+		}
+		require.Error(t, cfg.Init())
+	})
+
+	genTasks := func() []*Task {
+		return []*Task{
+			{
+				Definition: &ecs.TaskDefinition{
+					TaskDefinitionArn: aws.String("arn:aws:ecs:us-west-2:123456789012:task-definition/nginx:1"),
+					ContainerDefinitions: []*ecs.ContainerDefinition{
+						{
+							Name:         aws.String("nginx"),
+							PortMappings: []*ecs.PortMapping{{ContainerPort: aws.Int64(80)}},
+						},
+						{
+							Name:         aws.String("exporter"),
+							PortMappings: []*ecs.PortMapping{{ContainerPort: aws.Int64(9113)}},
+						},
+					},
+				},
+			},
+			{
+				Definition: &ecs.TaskDefinition{
+					TaskDefinitionArn: aws.String("arn:aws:ecs:us-west-2:123456789012:task-definition/memcached:3"),
+					ContainerDefinitions: []*ecs.ContainerDefinition{
+						{
+							Name:         aws.String("memcached"),
+							PortMappings: []*ecs.PortMapping{{ContainerPort: aws.Int64(9113)}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("arn pattern", func(t *testing.T) {
+		cfg := TaskDefinitionConfig{
+			ArnPattern: ".*:task-definition/nginx:[0-9]+",
+			CommonExporterConfig: CommonExporterConfig{
+				JobName:      "task_def_1",
+				MetricsPorts: []int{9113, 9090},
+			},
+		}
+		res := newMatcherAndMatch(t, &cfg, genTasks())
+		assert.Equal(t, &MatchResult{
+			Tasks: []int{0},
+			Containers: []MatchedContainer{
+				{
+					TaskIndex:      0,
+					ContainerIndex: 1,
+					Targets: []MatchedTarget{
+						{
+							MatcherType: MatcherTypeTaskDefinition,
+							Port:        9113,
+							Job:         "task_def_1",
+						},
+					},
+				},
+			},
+		}, res)
+	})
+
+	t.Run("container name pattern", func(t *testing.T) {
+		cfg := TaskDefinitionConfig{
+			ArnPattern:           "task-definition",
+			ContainerNamePattern: "^memcached$",
+			CommonExporterConfig: CommonExporterConfig{MetricsPorts: []int{9113}},
+		}
+		res := newMatcherAndMatch(t, &cfg, genTasks())
+		assert.Equal(t, []int{1}, res.Tasks)
+	})
+}
@@ -35,12 +35,24 @@ const (
 	PodType EndpointType = "pod"
 	// HostPortType is a hostport endpoint.
 	HostPortType EndpointType = "hostport"
+	// ContainerType is a container endpoint.
+	ContainerType EndpointType = "container"
+	// ECSTaskType is an ECS task container port endpoint.
+	ECSTaskType EndpointType = "ecs_task"
+	// K8sNodeType is a Kubernetes Node endpoint.
+	K8sNodeType EndpointType = "k8s.node"
+	// K8sServiceType is a Kubernetes Service endpoint.
+	K8sServiceType EndpointType = "k8s.service"
 )
 
 var (
 	_ EndpointDetails = (*Pod)(nil)
 	_ EndpointDetails = (*Port)(nil)
 	_ EndpointDetails = (*HostPort)(nil)
+	_ EndpointDetails = (*Container)(nil)
+	_ EndpointDetails = (*ECSTask)(nil)
+	_ EndpointDetails = (*K8sNode)(nil)
+	_ EndpointDetails = (*K8sService)(nil)
 )
 
 // EndpointDetails provides additional context about an endpoint such as a Pod or Port.
@@ -143,6 +155,9 @@ type HostPort struct {
 	Transport Transport
 	// IsIPv6 indicates whether or not the Endpoint is IPv6.
 	IsIPv6 bool
+	// ContainerID is the id of the container running the process using the
+	// Endpoint. Empty if the process doesn't run in a container.
+	ContainerID string
 }
 
 func (h *HostPort) Env() EndpointEnv {
@@ -152,9 +167,199 @@ func (h *HostPort) Env() EndpointEnv {
 		"is_ipv6":      h.IsIPv6,
 		"port":         h.Port,
 		"transport":    h.Transport,
+		"container_id": h.ContainerID,
 	}
 }
 
 func (h *HostPort) Type() EndpointType {
 	return HostPortType
 }
+
+// Container is a discovered container port.
+type Container struct {
+	// Name is the primary name of the container.
+	Name string
+	// Image is the container image name, without its tag.
+	Image string
+	// Tag is the container image tag, if any.
+	Tag string
+	// Port is the exposed port of the container.
+	Port uint16
+	// AlternatePort is the port the exposed port is mapped to on the host,
+	// or 0 if it isn't published.
+	AlternatePort uint16
+	// Command used to invoke the process using the Endpoint.
+	Command string
+	// ContainerID is the id of the container exposing the Endpoint.
+	ContainerID string
+	// Host is the IP address of the container.
+	Host string
+	// Transport is the transport protocol used by the Endpoint. (TCP or UDP).
+	Transport Transport
+	// Labels is a map of user-specified metadata on the container.
+	Labels map[string]string
+}
+
+func (c *Container) Env() EndpointEnv {
+	return map[string]interface{}{
+		"name":           c.Name,
+		"image":          c.Image,
+		"tag":            c.Tag,
+		"port":           c.Port,
+		"alternate_port": c.AlternatePort,
+		"command":        c.Command,
+		"container_id":   c.ContainerID,
+		"host":           c.Host,
+		"transport":      c.Transport,
+		"labels":         c.Labels,
+	}
+}
+
+func (c *Container) Type() EndpointType {
+	return ContainerType
+}
+
+// ECSTask is a discovered port of a container in a running Amazon ECS task.
+type ECSTask struct {
+	// TaskARN is the ARN of the task.
+	TaskARN string
+	// TaskDefinitionFamily is the family of the task definition.
+	TaskDefinitionFamily string
+	// TaskDefinitionRevision is the revision of the task definition.
+	TaskDefinitionRevision int
+	// ContainerName is the name of the container in the task definition.
+	ContainerName string
+	// Port is the port of the Endpoint as reachable from the observer, e.g.
+	// the host port for containers in bridge network mode.
+	Port uint16
+	// MetricsPath is the metrics path of the matched target, if any.
+	MetricsPath string
+	// Job is the job name of the matched target, if any.
+	Job string
+	// JobLabelName is the label name the job is exported with.
+	JobLabelName string
+	// DockerLabels is a map of docker labels set on the container.
+	DockerLabels map[string]string
+}
+
+func (t *ECSTask) Env() EndpointEnv {
+	return map[string]interface{}{
+		"task_arn":                 t.TaskARN,
+		"task_definition_family":   t.TaskDefinitionFamily,
+		"task_definition_revision": t.TaskDefinitionRevision,
+		"container_name":           t.ContainerName,
+		"port":                     t.Port,
+		"metrics_path":             t.MetricsPath,
+		"job":                      t.Job,
+		"job_label_name":           t.JobLabelName,
+		"docker_labels":            t.DockerLabels,
+	}
+}
+
+func (t *ECSTask) Type() EndpointType {
+	return ECSTaskType
+}
+
+// K8sNode is a discovered Kubernetes Node.
+type K8sNode struct {
+	// Name is the name of the node.
+	Name string
+	// UID is the unique ID in the cluster for the node.
+	UID string
+	// Hostname is the hostname address of the node, if reported.
+	Hostname string
+	// InternalIP is the internal IP address of the node, if reported.
+	InternalIP string
+	// ExternalIP is the external IP address of the node, if reported.
+	ExternalIP string
+	// InternalDNS is the internal DNS name of the node, if reported.
+	InternalDNS string
+	// ExternalDNS is the external DNS name of the node, if reported.
+	ExternalDNS string
+	// KubeletEndpointPort is the port the kubelet listens on.
+	KubeletEndpointPort uint16
+	// Labels is a map of user-specified metadata on the node.
+	Labels map[string]string
+	// Annotations is a map of unstructured metadata on the node.
+	Annotations map[string]string
+}
+
+func (n *K8sNode) Env() EndpointEnv {
+	return map[string]interface{}{
+		"name":                  n.Name,
+		"uid":                   n.UID,
+		"hostname":              n.Hostname,
+		"internal_ip":           n.InternalIP,
+		"external_ip":           n.ExternalIP,
+		"internal_dns":          n.InternalDNS,
+		"external_dns":          n.ExternalDNS,
+		"kubelet_endpoint_port": n.KubeletEndpointPort,
+		"labels":                n.Labels,
+		"annotations":           n.Annotations,
+	}
+}
+
+func (n *K8sNode) Type() EndpointType {
+	return K8sNodeType
+}
+
+// K8sService is a discovered Kubernetes Service with a cluster IP.
+type K8sService struct {
+	// Name is the name of the service.
+	Name string
+	// UID is the unique ID in the cluster for the service.
+	UID string
+	// Namespace is the namespace of the service.
+	Namespace string
+	// ClusterIP is the cluster IP address of the service.
+	ClusterIP string
+	// ServiceType is the type of the service, e.g. ClusterIP or NodePort.
+	ServiceType string
+	// Ports are the ports exposed by the service.
+	Ports []K8sServicePort
+	// Selector is the pod label selector of the service.
+	Selector map[string]string
+	// Labels is a map of user-specified metadata on the service.
+	Labels map[string]string
+	// Annotations is a map of unstructured metadata on the service.
+	Annotations map[string]string
+}
+
+// K8sServicePort is a port exposed by a Kubernetes Service.
+type K8sServicePort struct {
+	// Name of the port, it may be empty if the service has a single port.
+	Name string
+	// Port is the port exposed by the service.
+	Port uint16
+	// TargetPort is the number or name of the port targeted on the pods.
+	TargetPort string
+	// Transport is the transport protocol used by the port. (TCP or UDP).
+	Transport Transport
+}
+
+func (s *K8sService) Env() EndpointEnv {
+	ports := make([]map[string]interface{}, 0, len(s.Ports))
+	for _, p := range s.Ports {
+		ports = append(ports, map[string]interface{}{
+			"name":        p.Name,
+			"port":        p.Port,
+			"target_port": p.TargetPort,
+			"transport":   p.Transport,
+		})
+	}
+	return map[string]interface{}{
+		"name":         s.Name,
+		"uid":          s.UID,
+		"namespace":    s.Namespace,
+		"cluster_ip":   s.ClusterIP,
+		"service_type": s.ServiceType,
+		"ports":        ports,
+		"selector":     s.Selector,
+		"labels":       s.Labels,
+		"annotations":  s.Annotations,
+	}
+}
+
+func (s *K8sService) Type() EndpointType {
+	return K8sServiceType
+}
@@ -111,6 +111,7 @@ func TestEndpointEnv(t *testing.T) {
 					Port:        2379,
 					Transport:   ProtocolUDP,
 					IsIPv6:      true,
+					ContainerID: "abcdef",
 				},
 			},
 			want: EndpointEnv{
@@ -121,6 +122,153 @@ func TestEndpointEnv(t *testing.T) {
 				"is_ipv6":      true,
 				"port":         uint16(2379),
 				"transport":    ProtocolUDP,
+				"container_id": "abcdef",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Container",
+			endpoint: Endpoint{
+				ID:     EndpointID("container_endpoint_id"),
+				Target: "127.0.0.1:2379",
+				Details: &Container{
+					Name:          "otel-collector",
+					Image:         "otel/collector",
+					Tag:           "1.0.0",
+					Port:          2379,
+					AlternatePort: 2380,
+					Command:       "./cmd --config config.yaml",
+					ContainerID:   "abcdef",
+					Host:          "172.17.0.2",
+					Transport:     ProtocolTCP,
+					Labels: map[string]string{
+						"label_key": "label_val",
+					},
+				},
+			},
+			want: EndpointEnv{
+				"type":           "container",
+				"endpoint":       "127.0.0.1:2379",
+				"name":           "otel-collector",
+				"image":          "otel/collector",
+				"tag":            "1.0.0",
+				"port":           uint16(2379),
+				"alternate_port": uint16(2380),
+				"command":        "./cmd --config config.yaml",
+				"container_id":   "abcdef",
+				"host":           "172.17.0.2",
+				"transport":      ProtocolTCP,
+				"labels": map[string]string{
+					"label_key": "label_val",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ECS task",
+			endpoint: Endpoint{
+				ID:     EndpointID("ecs_task_endpoint_id"),
+				Target: "10.0.0.1:9113",
+				Details: &ECSTask{
+					TaskARN:                "arn:aws:ecs:us-west-2:123456789012:task/cluster/abcdef",
+					TaskDefinitionFamily:   "nginx",
+					TaskDefinitionRevision: 3,
+					ContainerName:          "nginx-exporter",
+					Port:                   9113,
+					MetricsPath:            "/metrics",
+					Job:                    "nginx",
+					JobLabelName:           "prometheus_job",
+					DockerLabels: map[string]string{
+						"ECS_PROMETHEUS_EXPORTER_PORT": "9113",
+					},
+				},
+			},
+			want: EndpointEnv{
+				"type":                     "ecs_task",
+				"endpoint":                 "10.0.0.1:9113",
+				"task_arn":                 "arn:aws:ecs:us-west-2:123456789012:task/cluster/abcdef",
+				"task_definition_family":   "nginx",
+				"task_definition_revision": 3,
+				"container_name":           "nginx-exporter",
+				"port":                     uint16(9113),
+				"metrics_path":             "/metrics",
+				"job":                      "nginx",
+				"job_label_name":           "prometheus_job",
+				"docker_labels": map[string]string{
+					"ECS_PROMETHEUS_EXPORTER_PORT": "9113",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "K8s node",
+			endpoint: Endpoint{
+				ID:     EndpointID("k8s_node_endpoint_id"),
+				Target: "10.0.0.1",
+				Details: &K8sNode{
+					Name:                "node-1",
+					UID:                 "node-1-uid",
+					Hostname:            "node-1.local",
+					InternalIP:          "10.0.0.1",
+					ExternalIP:          "1.2.3.4",
+					InternalDNS:         "node-1.internal",
+					ExternalDNS:         "node-1.example.com",
+					KubeletEndpointPort: 10250,
+					Labels:              map[string]string{"label_key": "label_val"},
+					Annotations:         map[string]string{"annotation_key": "annotation_val"},
+				},
+			},
+			want: EndpointEnv{
+				"type":                  "k8s.node",
+				"endpoint":              "10.0.0.1",
+				"name":                  "node-1",
+				"uid":                   "node-1-uid",
+				"hostname":              "node-1.local",
+				"internal_ip":           "10.0.0.1",
+				"external_ip":           "1.2.3.4",
+				"internal_dns":          "node-1.internal",
+				"external_dns":          "node-1.example.com",
+				"kubelet_endpoint_port": uint16(10250),
+				"labels":                map[string]string{"label_key": "label_val"},
+				"annotations":           map[string]string{"annotation_key": "annotation_val"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "K8s service",
+			endpoint: Endpoint{
+				ID:     EndpointID("k8s_service_endpoint_id"),
+				Target: "10.96.0.10",
+				Details: &K8sService{
+					Name:        "kube-dns",
+					UID:         "kube-dns-uid",
+					Namespace:   "kube-system",
+					ClusterIP:   "10.96.0.10",
+					ServiceType: "ClusterIP",
+					Ports: []K8sServicePort{
+						{Name: "dns", Port: 53, TargetPort: "53", Transport: ProtocolUDP},
+						{Name: "metrics", Port: 9153, TargetPort: "metrics", Transport: ProtocolTCP},
+					},
+					Selector:    map[string]string{"k8s-app": "kube-dns"},
+					Labels:      map[string]string{"label_key": "label_val"},
+					Annotations: map[string]string{"annotation_key": "annotation_val"},
+				},
+			},
+			want: EndpointEnv{
+				"type":         "k8s.service",
+				"endpoint":     "10.96.0.10",
+				"name":         "kube-dns",
+				"uid":          "kube-dns-uid",
+				"namespace":    "kube-system",
+				"cluster_ip":   "10.96.0.10",
+				"service_type": "ClusterIP",
+				"ports": []map[string]interface{}{
+					{"name": "dns", "port": uint16(53), "target_port": "53", "transport": ProtocolUDP},
+					{"name": "metrics", "port": uint16(9153), "target_port": "metrics", "transport": ProtocolTCP},
+				},
+				"selector":    map[string]string{"k8s-app": "kube-dns"},
+				"labels":      map[string]string{"label_key": "label_val"},
+				"annotations": map[string]string{"annotation_key": "annotation_val"},
 			},
 			wantErr: false,
 		},
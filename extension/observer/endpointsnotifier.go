@@ -0,0 +1,164 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observer
+
+import (
+	"reflect"
+	"sync"
+)
+
+// EndpointsNotifier keeps track of the endpoints of an observer and fans
+// their changes out to the subscribed listeners. Observers either pass the
+// latest set of endpoints to Update, which notifies the differences with the
+// previous set, or report the changes themselves through its Notify methods.
+// Embedding it in an observer provides the Observable methods. The zero value
+// is ready to use.
+type EndpointsNotifier struct {
+	mu        sync.Mutex
+	endpoints map[EndpointID]Endpoint
+	listeners map[Notify]struct{}
+}
+
+var _ Observable = (*EndpointsNotifier)(nil)
+var _ Notify = (*EndpointsNotifier)(nil)
+
+// ListAndWatch subscribes listener and notifies it of the current endpoints.
+// Subscribing the same listener again is a no-op.
+func (n *EndpointsNotifier) ListAndWatch(listener Notify) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, ok := n.listeners[listener]; ok {
+		return
+	}
+	if n.listeners == nil {
+		n.listeners = map[Notify]struct{}{}
+	}
+	n.listeners[listener] = struct{}{}
+
+	if len(n.endpoints) > 0 {
+		endpoints := make([]Endpoint, 0, len(n.endpoints))
+		for _, e := range n.endpoints {
+			endpoints = append(endpoints, e)
+		}
+		listener.OnAdd(endpoints)
+	}
+}
+
+// Unsubscribe stops notifying listener. Listeners are notified while holding
+// the lock so no callback is in flight once it is released here.
+func (n *EndpointsNotifier) Unsubscribe(listener Notify) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.listeners, listener)
+}
+
+// UnsubscribeAll stops notifying all the listeners.
+func (n *EndpointsNotifier) UnsubscribeAll() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.listeners = nil
+}
+
+// ListenerCount returns the number of subscribed listeners.
+func (n *EndpointsNotifier) ListenerCount() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.listeners)
+}
+
+// Update replaces the endpoints with latest and notifies the listeners of the
+// endpoints removed, added or changed since the previous update.
+func (n *EndpointsNotifier) Update(latest []Endpoint) {
+	latestEndpoints := make(map[EndpointID]Endpoint, len(latest))
+	for _, e := range latest {
+		latestEndpoints[e.ID] = e
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var removedEndpoints, addedEndpoints, updatedEndpoints []Endpoint
+	for id, e := range latestEndpoints {
+		if existingEndpoint, ok := n.endpoints[id]; !ok {
+			addedEndpoints = append(addedEndpoints, e)
+		} else if !reflect.DeepEqual(existingEndpoint, e) {
+			updatedEndpoints = append(updatedEndpoints, e)
+		}
+	}
+	for id, e := range n.endpoints {
+		if _, ok := latestEndpoints[id]; !ok {
+			removedEndpoints = append(removedEndpoints, e)
+		}
+	}
+	n.endpoints = latestEndpoints
+
+	for listener := range n.listeners {
+		if len(removedEndpoints) > 0 {
+			listener.OnRemove(removedEndpoints)
+		}
+		if len(addedEndpoints) > 0 {
+			listener.OnAdd(addedEndpoints)
+		}
+		if len(updatedEndpoints) > 0 {
+			listener.OnChange(updatedEndpoints)
+		}
+	}
+}
+
+// OnAdd records the added endpoints and notifies the listeners.
+func (n *EndpointsNotifier) OnAdd(added []Endpoint) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.endpoints == nil {
+		n.endpoints = map[EndpointID]Endpoint{}
+	}
+	for _, e := range added {
+		n.endpoints[e.ID] = e
+	}
+	for listener := range n.listeners {
+		listener.OnAdd(added)
+	}
+}
+
+// OnRemove forgets the removed endpoints and notifies the listeners.
+func (n *EndpointsNotifier) OnRemove(removed []Endpoint) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, e := range removed {
+		delete(n.endpoints, e.ID)
+	}
+	for listener := range n.listeners {
+		listener.OnRemove(removed)
+	}
+}
+
+// OnChange records the changed endpoints and notifies the listeners.
+func (n *EndpointsNotifier) OnChange(changed []Endpoint) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.endpoints == nil {
+		n.endpoints = map[EndpointID]Endpoint{}
+	}
+	for _, e := range changed {
+		n.endpoints[e.ID] = e
+	}
+	for listener := range n.listeners {
+		listener.OnChange(changed)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointsNotifierUpdate(t *testing.T) {
+	var n EndpointsNotifier
+	mn := &mockNotifier{}
+	n.ListAndWatch(mn)
+
+	n.Update([]Endpoint{{ID: "0"}, {ID: "1"}})
+	assert.ElementsMatch(t, []Endpoint{{ID: "0"}, {ID: "1"}}, mn.added)
+
+	n.Update([]Endpoint{{ID: "1", Target: "updated_target"}, {ID: "2"}})
+	assert.ElementsMatch(t, []Endpoint{{ID: "0"}, {ID: "1"}, {ID: "2"}}, mn.added)
+	assert.Equal(t, []Endpoint{{ID: "0"}}, mn.removed)
+	assert.Equal(t, []Endpoint{{ID: "1", Target: "updated_target"}}, mn.changed)
+
+	// An unchanged set notifies nothing.
+	n.Update([]Endpoint{{ID: "2"}, {ID: "1", Target: "updated_target"}})
+	assert.Len(t, mn.added, 3)
+	assert.Len(t, mn.removed, 1)
+	assert.Len(t, mn.changed, 1)
+}
+
+func TestEndpointsNotifierNotify(t *testing.T) {
+	var n EndpointsNotifier
+	mn := &mockNotifier{}
+	n.ListAndWatch(mn)
+
+	n.OnAdd([]Endpoint{{ID: "0"}, {ID: "1"}})
+	n.OnChange([]Endpoint{{ID: "1", Target: "updated_target"}})
+	n.OnRemove([]Endpoint{{ID: "0"}})
+
+	assert.Equal(t, []Endpoint{{ID: "0"}, {ID: "1"}}, mn.added)
+	assert.Equal(t, []Endpoint{{ID: "1", Target: "updated_target"}}, mn.changed)
+	assert.Equal(t, []Endpoint{{ID: "0"}}, mn.removed)
+	assert.Equal(t, map[EndpointID]Endpoint{"1": {ID: "1", Target: "updated_target"}}, n.endpoints)
+}
+
+func TestEndpointsNotifierSubscriptions(t *testing.T) {
+	var n EndpointsNotifier
+	n.Update([]Endpoint{{ID: "0"}})
+
+	// Late listeners are sent the current endpoints once.
+	first := &mockNotifier{}
+	n.ListAndWatch(first)
+	n.ListAndWatch(first)
+	assert.Equal(t, []Endpoint{{ID: "0"}}, first.added)
+
+	second := &mockNotifier{}
+	n.ListAndWatch(second)
+	assert.Equal(t, 2, n.ListenerCount())
+
+	n.Unsubscribe(first)
+	n.Update([]Endpoint{{ID: "0"}, {ID: "1"}})
+	assert.Equal(t, []Endpoint{{ID: "0"}}, first.added)
+	assert.Equal(t, []Endpoint{{ID: "0"}, {ID: "1"}}, second.added)
+
+	// Unsubscribing an unknown listener is a no-op.
+	n.Unsubscribe(&mockNotifier{})
+	assert.Equal(t, 1, n.ListenerCount())
+
+	n.UnsubscribeAll()
+	assert.Zero(t, n.ListenerCount())
+	n.Update(nil)
+	assert.Empty(t, second.removed)
+}
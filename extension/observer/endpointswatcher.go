@@ -15,7 +15,7 @@
 package observer
 
 import (
-	"reflect"
+	"sync"
 	"time"
 )
 
@@ -23,89 +23,116 @@ import (
 // RefreshInterval and report any new or removed endpoints using Notify
 // passed into ListAndWatch. Any observer that lists endpoints can make
 // use of EndpointsWatcher to poll for endpoints by embedding this struct
-// in the observer struct.
+// in the observer struct. Polling starts with the first listener and stops
+// once the last listener unsubscribes or StopListAndWatch is called.
 type EndpointsWatcher struct {
-	Endpointslister   EndpointsLister
-	RefreshInterval   time.Duration
-	existingEndpoints map[EndpointID]Endpoint
-	stop              chan struct{}
+	Endpointslister EndpointsLister
+	RefreshInterval time.Duration
+
+	// mu serializes subscriptions with starting and stopping the poller.
+	mu       sync.Mutex
+	notifier EndpointsNotifier
+	stop     chan struct{}
+	done     chan struct{}
 }
 
 // ListAndWatch runs ListEndpoints on a regular interval and keeps the list.
+// Listeners subscribing while polling is already running are sent the
+// currently known endpoints.
 func (ew *EndpointsWatcher) ListAndWatch(listener Notify) {
-	ew.existingEndpoints = map[EndpointID]Endpoint{}
+	ew.mu.Lock()
+	defer ew.mu.Unlock()
+
+	if ew.stop != nil {
+		ew.notifier.ListAndWatch(listener)
+		return
+	}
+
+	// Forget the endpoints of a previous polling before the first listener subscribes.
+	ew.notifier.Update(nil)
+	ew.notifier.ListAndWatch(listener)
 	ew.stop = make(chan struct{})
+	ew.done = make(chan struct{})
 
 	// Do the initial listing immediately so that services can be monitored ASAP.
-	ew.refreshEndpoints(listener)
+	ew.refreshEndpoints()
+
+	go ew.watch(ew.stop, ew.done)
+}
+
+func (ew *EndpointsWatcher) watch(stop, done chan struct{}) {
+	defer close(done)
 
-	go func() {
-		ticker := time.NewTicker(ew.RefreshInterval)
-		defer ticker.Stop()
+	ticker := time.NewTicker(ew.RefreshInterval)
+	defer ticker.Stop()
 
-		for {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ew.mu.Lock()
+			// Stopping may have been requested while waiting for the lock.
 			select {
-			case <-ew.stop:
+			case <-stop:
+				ew.mu.Unlock()
 				return
-			case <-ticker.C:
-				ew.refreshEndpoints(listener)
+			default:
 			}
+			ew.refreshEndpoints()
+			ew.mu.Unlock()
 		}
-	}()
+	}
 }
 
-// refreshEndpoints updates the listener with the latest list
-// of active endpoints.
-func (ew *EndpointsWatcher) refreshEndpoints(listener Notify) {
-	latestEndpoints := ew.Endpointslister.ListEndpoints()
-
-	// Create map from ID to endpoint for lookup.
-	latestEndpointsMap := make(map[EndpointID]bool, len(latestEndpoints))
-	for _, e := range latestEndpoints {
-		latestEndpointsMap[e.ID] = true
-	}
+// refreshEndpoints updates the listeners with the latest list
+// of active endpoints. It must be called with ew.mu held.
+func (ew *EndpointsWatcher) refreshEndpoints() {
+	ew.notifier.Update(ew.Endpointslister.ListEndpoints())
+}
 
-	var removedEndpoints, addedEndpoints, updatedEndpoints []Endpoint
-	// Iterate over the latest endpoints obtained. An endpoint needs
-	// to be added in case it is not already available in existingEndpoints.
-	for _, e := range latestEndpoints {
-		if existingEndpoint, ok := ew.existingEndpoints[e.ID]; !ok {
-			ew.existingEndpoints[e.ID] = e
-			addedEndpoints = append(addedEndpoints, e)
-		} else {
-			// Collect updated endpoints.
-			if !reflect.DeepEqual(existingEndpoint, e) {
-				ew.existingEndpoints[e.ID] = e
-				updatedEndpoints = append(updatedEndpoints, e)
-			}
-		}
+// Unsubscribe stops notifying listener. Polling is stopped once there are
+// no listeners left.
+func (ew *EndpointsWatcher) Unsubscribe(listener Notify) {
+	ew.mu.Lock()
+	ew.notifier.Unsubscribe(listener)
+	if ew.notifier.ListenerCount() > 0 {
+		ew.mu.Unlock()
+		return
 	}
+	stop, done := ew.detach()
+	ew.mu.Unlock()
 
-	// If endpoint present in existingEndpoints does not exist in the latest
-	// list, it needs to be removed.
-	for id, e := range ew.existingEndpoints {
-		if !latestEndpointsMap[e.ID] {
-			delete(ew.existingEndpoints, id)
-			removedEndpoints = append(removedEndpoints, e)
-		}
-	}
+	ew.wait(stop, done)
+}
 
-	if len(removedEndpoints) > 0 {
-		listener.OnRemove(removedEndpoints)
-	}
+// StopListAndWatch polling the ListEndpoints and unsubscribes all listeners.
+func (ew *EndpointsWatcher) StopListAndWatch() {
+	ew.mu.Lock()
+	ew.notifier.UnsubscribeAll()
+	stop, done := ew.detach()
+	ew.mu.Unlock()
 
-	if len(addedEndpoints) > 0 {
-		listener.OnAdd(addedEndpoints)
-	}
+	ew.wait(stop, done)
+}
 
-	if len(updatedEndpoints) > 0 {
-		listener.OnChange(updatedEndpoints)
-	}
+// detach resets the polling state and returns the channels of the running
+// poller, if any. It must be called with ew.mu held.
+func (ew *EndpointsWatcher) detach() (stop, done chan struct{}) {
+	stop, done = ew.stop, ew.done
+	ew.stop, ew.done = nil, nil
+	return stop, done
 }
 
-// StopListAndWatch polling the ListEndpoints.
-func (ew *EndpointsWatcher) StopListAndWatch() {
-	close(ew.stop)
+// wait stops the poller owning stop and done and waits for it to exit.
+// It must be called without ew.mu held since the poller may be waiting
+// on it.
+func (ew *EndpointsWatcher) wait(stop, done chan struct{}) {
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
 }
 
 // EndpointsLister that provides a list of endpoints.
@@ -20,7 +20,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
 )
 
 func TestRefreshEndpointsOnStartup(t *testing.T) {
@@ -33,63 +35,153 @@ func TestRefreshEndpointsOnStartup(t *testing.T) {
 	// Endpoints available before the ListAndWatch call should be
 	// readily discovered.
 	expected := map[EndpointID]Endpoint{"0": {ID: "0"}}
-	require.Equal(t, expected, ew.existingEndpoints)
+	require.Equal(t, expected, ew.notifier.endpoints)
+	require.Len(t, mn.added, 1)
 }
 
 func TestRefreshEndpoints(t *testing.T) {
 	ml, ew, mn := setup()
+	ew.notifier.ListAndWatch(mn)
 
 	ml.addEndpoint(0)
-	ew.refreshEndpoints(mn)
+	ew.refreshEndpoints()
 
 	expected := map[EndpointID]Endpoint{"0": {ID: "0"}}
-	require.Equal(t, expected, ew.existingEndpoints)
+	require.Equal(t, expected, ew.notifier.endpoints)
 
 	ml.addEndpoint(1)
 	ml.addEndpoint(2)
 	ml.removeEndpoint(0)
-	ew.refreshEndpoints(mn)
+	ew.refreshEndpoints()
 
 	expected["1"] = Endpoint{ID: "1"}
 	expected["2"] = Endpoint{ID: "2"}
 	delete(expected, "0")
-	require.Equal(t, expected, ew.existingEndpoints)
+	require.Equal(t, expected, ew.notifier.endpoints)
 
 	ml.updateEndpoint(2, "updated_target")
-	ew.refreshEndpoints(mn)
+	ew.refreshEndpoints()
 
 	expected["2"] = Endpoint{ID: "2", Target: "updated_target"}
-	require.Equal(t, expected, ew.existingEndpoints)
+	require.Equal(t, expected, ew.notifier.endpoints)
+
+	assert.Equal(t, []Endpoint{{ID: "0"}}, mn.removed)
+	assert.Equal(t, []Endpoint{{ID: "2", Target: "updated_target"}}, mn.changed)
+}
+
+func TestListAndWatchLateListener(t *testing.T) {
+	ml, ew, first := setup()
+	ml.addEndpoint(0)
+
+	ew.ListAndWatch(first)
+	defer ew.StopListAndWatch()
+
+	second := &mockNotifier{}
+	ew.ListAndWatch(second)
+	// Subscribing twice must not resend the endpoints.
+	ew.ListAndWatch(second)
+
+	assert.Equal(t, []Endpoint{{ID: "0"}}, first.added)
+	assert.Equal(t, []Endpoint{{ID: "0"}}, second.added)
+}
+
+func TestUnsubscribe(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ml, ew, first := setup()
+	ew.RefreshInterval = time.Millisecond
+	second := &mockNotifier{}
+
+	ew.ListAndWatch(first)
+	ew.ListAndWatch(second)
+
+	ml.addEndpoint(0)
+	require.Eventually(t, func() bool {
+		return len(first.getAdded()) == 1 && len(second.getAdded()) == 1
+	}, 5*time.Second, time.Millisecond)
+
+	ew.Unsubscribe(first)
+	ml.addEndpoint(1)
+	require.Eventually(t, func() bool {
+		return len(second.getAdded()) == 2
+	}, 5*time.Second, time.Millisecond)
+	assert.Len(t, first.getAdded(), 1)
+
+	// Unsubscribing the last listener stops polling.
+	ew.Unsubscribe(second)
+	assert.Nil(t, ew.stop)
+	ml.addEndpoint(2)
+	time.Sleep(10 * time.Millisecond)
+	assert.Len(t, second.getAdded(), 2)
+
+	// Unsubscribing an unknown listener is a no-op.
+	ew.Unsubscribe(&mockNotifier{})
 }
 
-func setup() (*mockEndpointsLister, EndpointsWatcher, mockNotifier) {
+func TestStopListAndWatch(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	_, ew, mn := setup()
+
+	// Stopping without listeners must not block or panic.
+	ew.StopListAndWatch()
+
+	ew.ListAndWatch(mn)
+	ew.ListAndWatch(&mockNotifier{})
+	ew.StopListAndWatch()
+	assert.Zero(t, ew.notifier.ListenerCount())
+
+	// The watcher can be reused after being stopped.
+	ew.ListAndWatch(mn)
+	ew.StopListAndWatch()
+}
+
+func setup() (*mockEndpointsLister, *EndpointsWatcher, *mockNotifier) {
 	ml := &mockEndpointsLister{
 		endpointsMap: map[EndpointID]Endpoint{},
 	}
 
-	ew := EndpointsWatcher{
-		Endpointslister:   ml,
-		RefreshInterval:   2 * time.Second,
-		existingEndpoints: map[EndpointID]Endpoint{},
+	ew := &EndpointsWatcher{
+		Endpointslister: ml,
+		RefreshInterval: 2 * time.Second,
 	}
 
-	mn := mockNotifier{}
+	mn := &mockNotifier{}
 
 	return ml, ew, mn
 }
 
 type mockNotifier struct {
+	sync.Mutex
+	added   []Endpoint
+	removed []Endpoint
+	changed []Endpoint
 }
 
 var _ Notify = (*mockNotifier)(nil)
 
-func (m mockNotifier) OnAdd([]Endpoint) {
+func (m *mockNotifier) OnAdd(added []Endpoint) {
+	m.Lock()
+	defer m.Unlock()
+	m.added = append(m.added, added...)
 }
 
-func (m mockNotifier) OnRemove([]Endpoint) {
+func (m *mockNotifier) OnRemove(removed []Endpoint) {
+	m.Lock()
+	defer m.Unlock()
+	m.removed = append(m.removed, removed...)
 }
 
-func (m mockNotifier) OnChange([]Endpoint) {
+func (m *mockNotifier) OnChange(changed []Endpoint) {
+	m.Lock()
+	defer m.Unlock()
+	m.changed = append(m.changed, changed...)
+}
+
+func (m *mockNotifier) getAdded() []Endpoint {
+	m.Lock()
+	defer m.Unlock()
+	return append([]Endpoint(nil), m.added...)
 }
 
 type mockEndpointsLister struct {
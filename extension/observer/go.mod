@@ -2,4 +2,7 @@ module github.com/open-telemetry/opentelemetry-collector-contrib/extension/obser
 
 go 1.15
 
-require github.com/stretchr/testify v1.7.0
+require (
+	github.com/stretchr/testify v1.7.0
+	go.uber.org/goleak v1.1.10
+)
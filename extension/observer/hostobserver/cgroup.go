@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostobserver
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// containerIDRe matches the last cgroup path element of a container process, e.g.
+// "<id>" (docker, kubernetes with the cgroupfs driver), "docker-<id>.scope",
+// "cri-containerd-<id>.scope", "crio-<id>.scope" or "libpod-<id>.scope"
+// (systemd driver).
+var containerIDRe = regexp.MustCompile(`^(?:[a-z-]+-)?([0-9a-f]{64})(?:\.scope)?$`)
+
+// getContainerID returns the id of the container running the process from
+// /proc/<pid>/cgroup or an empty string if the process doesn't run in a container
+// or its cgroups cannot be read (e.g. on non linux hosts).
+func getContainerID(pid int32) string {
+	procRoot := os.Getenv("HOST_PROC")
+	if procRoot == "" {
+		procRoot = "/proc"
+	}
+
+	f, err := os.Open(filepath.Join(procRoot, strconv.Itoa(int(pid)), "cgroup"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	return parseContainerID(f)
+}
+
+// parseContainerID returns the container id of the first line of r, in the
+// /proc/<pid>/cgroup "hierarchy-ID:controller-list:cgroup-path" format, whose
+// cgroup path identifies a container.
+func parseContainerID(r io.Reader) string {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// The controller list may be empty but the cgroup path always follows the second colon.
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if m := containerIDRe.FindStringSubmatch(path.Base(parts[2])); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
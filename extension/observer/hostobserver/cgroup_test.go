@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+package hostobserver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testContainerID = "8ae4b6f4e0b9b8f1a0c5c6d3c1e3b4a5f6e7d8c9b0a1f2e3d4c5b6a7f8e9d0c1"
+
+func TestParseContainerID(t *testing.T) {
+	tests := []struct {
+		name   string
+		cgroup string
+		want   string
+	}{
+		{
+			name:   "docker cgroup v1",
+			cgroup: "12:pids:/docker/" + testContainerID + "\n11:cpuset:/docker/" + testContainerID,
+			want:   testContainerID,
+		},
+		{
+			name:   "kubernetes cgroupfs driver",
+			cgroup: "4:memory:/kubepods/burstable/pod0d2d2e4a-6c3b-4b0e-9d3f-1a2b3c4d5e6f/" + testContainerID,
+			want:   testContainerID,
+		},
+		{
+			name:   "systemd driver",
+			cgroup: "0::/system.slice/docker-" + testContainerID + ".scope",
+			want:   testContainerID,
+		},
+		{
+			name: "containerd systemd driver",
+			cgroup: "0::/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod0d2d2e4a.slice/cri-containerd-" +
+				testContainerID + ".scope",
+			want: testContainerID,
+		},
+		{
+			name:   "host process",
+			cgroup: "12:pids:/user.slice/user-1000.slice/session-2.scope\n0::/init.scope",
+			want:   "",
+		},
+		{
+			name:   "malformed",
+			cgroup: "not a cgroup line\n",
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseContainerID(strings.NewReader(tt.cgroup)))
+		})
+	}
+}
+
+func TestGetContainerID(t *testing.T) {
+	procRoot, err := ioutil.TempDir("", "proc")
+	require.NoError(t, err)
+	defer os.RemoveAll(procRoot)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(procRoot, "42"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(procRoot, "42", "cgroup"),
+		[]byte("1:name=systemd:/docker/"+testContainerID+"\n"), 0600))
+
+	os.Setenv("HOST_PROC", procRoot)
+	defer os.Unsetenv("HOST_PROC")
+
+	assert.Equal(t, testContainerID, getContainerID(42))
+	assert.Equal(t, "", getContainerID(43))
+}
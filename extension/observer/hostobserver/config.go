@@ -27,4 +27,30 @@ type Config struct {
 	// RefreshInterval determines how frequency at which the observer
 	// needs to poll for collecting information about new processes.
 	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+
+	// Include restricts the reported endpoints to the ones owned by a process
+	// matching the filter. Endpoints whose owning process is unknown are not
+	// reported when Include is set.
+	Include ProcessFilter `mapstructure:"include"`
+	// Exclude drops the endpoints owned by a process matching the filter.
+	// It is evaluated after Include.
+	Exclude ProcessFilter `mapstructure:"exclude"`
+}
+
+// ProcessFilter matches processes by regular expressions. A process matches the
+// filter if any of the expressions of any of the fields matches.
+type ProcessFilter struct {
+	// Names are matched against the process name.
+	Names []string `mapstructure:"names"`
+	// Users are matched against the name of the user owning the process, or
+	// its numeric uid if the name cannot be looked up.
+	Users []string `mapstructure:"users"`
+	// Cmdlines are matched against the full command line of the process.
+	Cmdlines []string `mapstructure:"cmdlines"`
+}
+
+// Validate checks if the extension configuration is valid
+func (cfg *Config) Validate() error {
+	_, err := newProcessFilter(cfg)
+	return err
 }
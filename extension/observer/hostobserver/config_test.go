@@ -47,6 +47,24 @@ func TestLoadConfig(t *testing.T) {
 		&Config{
 			ExtensionSettings: config.NewExtensionSettings(config.NewIDWithName(typeStr, "all_settings")),
 			RefreshInterval:   20 * time.Second,
+			Include: ProcessFilter{
+				Names: []string{"^redis-server$", "^nginx"},
+				Users: []string{"^www-data$"},
+			},
+			Exclude: ProcessFilter{
+				Cmdlines: []string{"--debug"},
+			},
 		},
 		ext1)
 }
+
+func TestValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	require.NoError(t, cfg.Validate())
+
+	cfg.Include.Names = []string{"^redis"}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Exclude.Users = []string{"(root"}
+	assert.EqualError(t, cfg.Validate(), "invalid exclude filter: users: error parsing regexp: missing closing ): `(root`")
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"strconv"
 	"syscall"
 
 	"github.com/shirou/gopsutil/net"
@@ -35,6 +36,7 @@ type hostObserver struct {
 type endpointsLister struct {
 	logger       *zap.Logger
 	observerName string
+	filter       processFilter
 
 	// For testing
 	getConnections        func() ([]net.ConnectionStat, error)
@@ -43,14 +45,21 @@ type endpointsLister struct {
 }
 
 var _ component.Extension = (*hostObserver)(nil)
+var _ observer.Observable = (*hostObserver)(nil)
 
 func newObserver(logger *zap.Logger, config *Config) (component.Extension, error) {
+	filter, err := newProcessFilter(config)
+	if err != nil {
+		return nil, err
+	}
+
 	h := &hostObserver{
 		EndpointsWatcher: observer.EndpointsWatcher{
 			RefreshInterval: config.RefreshInterval,
 			Endpointslister: endpointsLister{
 				logger:                logger,
 				observerName:          config.ID().String(),
+				filter:                filter,
 				getConnections:        getConnections,
 				getProcess:            process.NewProcess,
 				collectProcessDetails: collectProcessDetails,
@@ -95,31 +104,35 @@ func getConnections() (conns []net.ConnectionStat, err error) {
 
 func (e endpointsLister) collectEndpoints(conns []net.ConnectionStat) []observer.Endpoint {
 	endpoints := make([]observer.Endpoint, 0, len(conns))
+	// The same address can be reported for several sockets, e.g. UDP sockets
+	// bound with SO_REUSEPORT, only its first endpoint is reported.
+	seen := make(map[observer.EndpointID]bool)
 	connsByPID := make(map[int32][]*net.ConnectionStat)
 	for i := range conns {
 		c := conns[i]
-		isIPSocket := c.Family == syscall.AF_INET || c.Family == syscall.AF_INET6
-		isTCPOrUDP := c.Type == syscall.SOCK_STREAM || c.Type == syscall.SOCK_DGRAM
-		// UDP doesn't have any status
-		isUDPOrListening := c.Type == syscall.SOCK_DGRAM || c.Status == "LISTEN"
-		// UDP is "listening" when it has a remote port of 0
-		isTCPOrHasNoRemotePort := c.Type == syscall.SOCK_STREAM || c.Raddr.Port == 0
-
-		if !isIPSocket || !isTCPOrUDP || !isUDPOrListening || !isTCPOrHasNoRemotePort {
+		if !isListener(&c) {
 			continue
 		}
 
 		// PID of 0 means that the listening file descriptor couldn't be mapped
 		// back to a process's set of open file descriptors in /proc. Collect these
 		// endpoints even though there's no process metadata available so users can
-		// still do discovery rules on such sockets.
+		// still do discovery rules on such sockets, unless an include filter is set.
 		if c.Pid == 0 {
+			if !e.filter.keep(nil) {
+				continue
+			}
+
 			cd := collectConnectionDetails(&c)
 			id := observer.EndpointID(
 				fmt.Sprintf(
 					"(%s)%s-%d-%s", e.observerName, cd.ip, cd.port, cd.transport,
 				),
 			)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
 
 			endpoints = append(endpoints, observer.Endpoint{
 				ID:     id,
@@ -153,6 +166,11 @@ func (e endpointsLister) collectEndpoints(conns []net.ConnectionStat) []observer
 			continue
 		}
 
+		// Filter out processes before creating any of their endpoints.
+		if !e.filter.keep(pd) {
+			continue
+		}
+
 		for _, c := range conns {
 			cd := collectConnectionDetails(c)
 
@@ -162,6 +180,10 @@ func (e endpointsLister) collectEndpoints(conns []net.ConnectionStat) []observer
 					e.observerName, cd.ip, cd.port, cd.transport, pid,
 				),
 			)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
 
 			e := observer.Endpoint{
 				ID:     id,
@@ -169,6 +191,7 @@ func (e endpointsLister) collectEndpoints(conns []net.ConnectionStat) []observer
 				Details: &observer.HostPort{
 					ProcessName: pd.name,
 					Command:     pd.args,
+					ContainerID: pd.containerID,
 					Port:        cd.port,
 					Transport:   cd.transport,
 					// TODO: Move this field to observer.Endpoint and
@@ -183,6 +206,23 @@ func (e endpointsLister) collectEndpoints(conns []net.ConnectionStat) []observer
 	return endpoints
 }
 
+// isListener returns whether the connection is a TCP or UDP socket over IPv4 or IPv6
+// accepting traffic from any peer. TCP sockets are listening when in the LISTEN state.
+// UDP sockets have no state and are considered listening when they are not connected
+// to a remote address, i.e. when their remote port is 0.
+func isListener(c *net.ConnectionStat) bool {
+	if c.Family != syscall.AF_INET && c.Family != syscall.AF_INET6 {
+		return false
+	}
+	switch c.Type {
+	case syscall.SOCK_STREAM:
+		return c.Status == "LISTEN"
+	case syscall.SOCK_DGRAM:
+		return c.Raddr.Port == 0 && c.Laddr.Port != 0
+	}
+	return false
+}
+
 type connectionDetails struct {
 	ip        string
 	isIPv6    bool
@@ -221,8 +261,10 @@ func collectConnectionDetails(c *net.ConnectionStat) connectionDetails {
 }
 
 type processDetails struct {
-	name string
-	args string
+	name        string
+	args        string
+	user        string
+	containerID string
 }
 
 func collectProcessDetails(proc *process.Process) (*processDetails, error) {
@@ -237,11 +279,26 @@ func collectProcessDetails(proc *process.Process) (*processDetails, error) {
 	}
 
 	return &processDetails{
-		name: name,
-		args: args,
+		name:        name,
+		args:        args,
+		user:        getUser(proc),
+		containerID: getContainerID(proc.Pid),
 	}, nil
 }
 
+// getUser returns the name of the user owning the process, its real uid if the
+// name cannot be looked up (e.g. the user only exists in a container) or an
+// empty string if neither is available.
+func getUser(proc *process.Process) string {
+	if username, err := proc.Username(); err == nil {
+		return username
+	}
+	if uids, err := proc.Uids(); err == nil && len(uids) > 0 {
+		return strconv.Itoa(int(uids[0]))
+	}
+	return ""
+}
+
 func portTypeToProtocol(t uint32) observer.Transport {
 	switch t {
 	case syscall.SOCK_STREAM:
@@ -32,6 +32,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.uber.org/goleak"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
@@ -195,7 +196,7 @@ func startAndStopObserver(
 
 	ctx := context.Background()
 	require.NoError(t, h.Start(ctx, componenttest.NewNopHost()))
-	h.ListAndWatch(mn)
+	h.ListAndWatch(&mn)
 
 	time.Sleep(2 * time.Second) // Wait a bit to sync endpoints once.
 	require.NoError(t, h.Shutdown(ctx))
@@ -443,6 +444,7 @@ func TestCollectEndpoints(t *testing.T) {
 		conns       []psnet.ConnectionStat
 		newProc     func(pid int32) (*process.Process, error)
 		procDetails func(proc *process.Process) (*processDetails, error)
+		filter      *Config
 		want        []observer.Endpoint
 	}{
 		{
@@ -529,6 +531,80 @@ func TestCollectEndpoints(t *testing.T) {
 			},
 			want: []observer.Endpoint{},
 		},
+		{
+			name: "Listening UDP sockets",
+			conns: []psnet.ConnectionStat{
+				udpConn("0.0.0.0", 53, 0, 9999),
+				// Bound with SO_REUSEPORT by the same process.
+				udpConn("0.0.0.0", 53, 0, 9999),
+				// Connected to a remote address.
+				udpConn("10.0.0.1", 41000, 53, 9999),
+			},
+			newProc:     fakeProcess,
+			procDetails: fakeProcessDetails(map[int32]*processDetails{9999: {name: "dnsmasq", containerID: "abcdef"}}),
+			want: []observer.Endpoint{
+				{
+					ID:     observer.EndpointID("()127.0.0.1-53-UDP-9999"),
+					Target: "127.0.0.1:53",
+					Details: &observer.HostPort{
+						ProcessName: "dnsmasq",
+						Port:        53,
+						Transport:   observer.ProtocolUDP,
+						ContainerID: "abcdef",
+					},
+				},
+			},
+		},
+		{
+			name: "Include filter",
+			conns: []psnet.ConnectionStat{
+				udpConn("0.0.0.0", 53, 0, 9999),
+				udpConn("0.0.0.0", 8125, 0, 8888),
+				udpConn("0.0.0.0", 161, 0, 0),
+			},
+			newProc: fakeProcess,
+			procDetails: fakeProcessDetails(map[int32]*processDetails{
+				9999: {name: "dnsmasq", user: "nobody", args: "dnsmasq -k"},
+				8888: {name: "statsd", user: "statsd", args: "statsd --port 8125"},
+			}),
+			filter: &Config{Include: ProcessFilter{Users: []string{"^statsd$"}}},
+			want: []observer.Endpoint{
+				{
+					ID:     observer.EndpointID("()127.0.0.1-8125-UDP-8888"),
+					Target: "127.0.0.1:8125",
+					Details: &observer.HostPort{
+						ProcessName: "statsd",
+						Command:     "statsd --port 8125",
+						Port:        8125,
+						Transport:   observer.ProtocolUDP,
+					},
+				},
+			},
+		},
+		{
+			name: "Exclude filter",
+			conns: []psnet.ConnectionStat{
+				udpConn("0.0.0.0", 53, 0, 9999),
+				udpConn("0.0.0.0", 8125, 0, 8888),
+				udpConn("0.0.0.0", 161, 0, 0),
+			},
+			newProc: fakeProcess,
+			procDetails: fakeProcessDetails(map[int32]*processDetails{
+				9999: {name: "dnsmasq", user: "nobody", args: "dnsmasq -k"},
+				8888: {name: "statsd", user: "statsd", args: "statsd --port 8125"},
+			}),
+			filter: &Config{Exclude: ProcessFilter{Names: []string{"^dns"}, Cmdlines: []string{"--port 8125"}}},
+			want: []observer.Endpoint{
+				{
+					ID:     observer.EndpointID("()127.0.0.1-161-UDP"),
+					Target: "127.0.0.1:161",
+					Details: &observer.HostPort{
+						Port:      161,
+						Transport: observer.ProtocolUDP,
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -538,6 +614,12 @@ func TestCollectEndpoints(t *testing.T) {
 				collectProcessDetails: collectProcessDetails,
 			}
 
+			if tt.filter != nil {
+				filter, err := newProcessFilter(tt.filter)
+				require.NoError(t, err)
+				e.filter = filter
+			}
+
 			if tt.procDetails != nil {
 				e.collectProcessDetails = tt.procDetails
 			}
@@ -555,3 +637,70 @@ func TestCollectEndpoints(t *testing.T) {
 		})
 	}
 }
+
+func udpConn(ip string, port, remotePort uint32, pid int32) psnet.ConnectionStat {
+	return psnet.ConnectionStat{
+		Family: syscall.AF_INET,
+		Type:   syscall.SOCK_DGRAM,
+		Laddr:  psnet.Addr{IP: ip, Port: port},
+		Raddr:  psnet.Addr{IP: "0.0.0.0", Port: remotePort},
+		Status: "NONE",
+		Pid:    pid,
+	}
+}
+
+func fakeProcess(pid int32) (*process.Process, error) {
+	return &process.Process{Pid: pid}, nil
+}
+
+func fakeProcessDetails(details map[int32]*processDetails) func(proc *process.Process) (*processDetails, error) {
+	return func(proc *process.Process) (*processDetails, error) {
+		return details[proc.Pid], nil
+	}
+}
+
+func TestIsListener(t *testing.T) {
+	tcp := psnet.ConnectionStat{Family: syscall.AF_INET6, Type: syscall.SOCK_STREAM, Status: "LISTEN"}
+	assert.True(t, isListener(&tcp))
+	tcp.Status = "ESTABLISHED"
+	assert.False(t, isListener(&tcp))
+
+	udp := udpConn("0.0.0.0", 53, 0, 1)
+	assert.True(t, isListener(&udp))
+	udp.Raddr.Port = 53
+	assert.False(t, isListener(&udp))
+
+	unix := psnet.ConnectionStat{Family: syscall.AF_UNIX, Type: syscall.SOCK_DGRAM}
+	assert.False(t, isListener(&unix))
+}
+
+func TestUnsubscribe(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	h := &hostObserver{
+		EndpointsWatcher: observer.EndpointsWatcher{
+			RefreshInterval: time.Millisecond,
+			Endpointslister: endpointsLister{
+				logger: zap.NewNop(),
+				getConnections: func() ([]psnet.ConnectionStat, error) {
+					return nil, nil
+				},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	require.NoError(t, h.Start(ctx, componenttest.NewNopHost()))
+
+	first := &mockNotifier{map[observer.EndpointID]observer.Endpoint{}}
+	second := &mockNotifier{map[observer.EndpointID]observer.Endpoint{}}
+	h.ListAndWatch(first)
+	h.ListAndWatch(second)
+
+	// Polling continues for the remaining listener and stops with the last one.
+	h.Unsubscribe(first)
+	h.Unsubscribe(second)
+
+	h.ListAndWatch(first)
+	require.NoError(t, h.Shutdown(ctx))
+}
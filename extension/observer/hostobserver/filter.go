@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostobserver
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// processMatcher is the compiled form of a ProcessFilter.
+type processMatcher struct {
+	names    []*regexp.Regexp
+	users    []*regexp.Regexp
+	cmdlines []*regexp.Regexp
+}
+
+func newProcessMatcher(filter ProcessFilter) (*processMatcher, error) {
+	var err error
+	m := &processMatcher{}
+	if m.names, err = compileAll("names", filter.Names); err != nil {
+		return nil, err
+	}
+	if m.users, err = compileAll("users", filter.Users); err != nil {
+		return nil, err
+	}
+	if m.cmdlines, err = compileAll("cmdlines", filter.Cmdlines); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func compileAll(field string, exprs []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(exprs))
+	for _, expr := range exprs {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", field, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// isEmpty returns whether the matcher has no expression.
+func (m *processMatcher) isEmpty() bool {
+	return len(m.names) == 0 && len(m.users) == 0 && len(m.cmdlines) == 0
+}
+
+// matches returns whether any of the expressions matches the process.
+func (m *processMatcher) matches(pd *processDetails) bool {
+	return matchesAny(m.names, pd.name) || matchesAny(m.users, pd.user) || matchesAny(m.cmdlines, pd.args)
+}
+
+func matchesAny(res []*regexp.Regexp, s string) bool {
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// processFilter decides which processes have their endpoints reported.
+type processFilter struct {
+	include *processMatcher
+	exclude *processMatcher
+}
+
+func newProcessFilter(cfg *Config) (processFilter, error) {
+	include, err := newProcessMatcher(cfg.Include)
+	if err != nil {
+		return processFilter{}, fmt.Errorf("invalid include filter: %v", err)
+	}
+	exclude, err := newProcessMatcher(cfg.Exclude)
+	if err != nil {
+		return processFilter{}, fmt.Errorf("invalid exclude filter: %v", err)
+	}
+	return processFilter{include: include, exclude: exclude}, nil
+}
+
+// keep returns whether the endpoints of the process should be reported. pd is nil
+// when the process owning the endpoint is unknown.
+func (f processFilter) keep(pd *processDetails) bool {
+	if pd == nil {
+		return f.include == nil || f.include.isEmpty()
+	}
+	if f.include != nil && !f.include.isEmpty() && !f.include.matches(pd) {
+		return false
+	}
+	return f.exclude == nil || !f.exclude.matches(pd)
+}
@@ -15,6 +15,8 @@
 package k8sobserver
 
 import (
+	"errors"
+
 	"go.opentelemetry.io/collector/config"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
@@ -36,9 +38,22 @@ type Config struct {
 	//
 	// Then set this value to ${K8S_NODE_NAME} in the configuration.
 	Node string `mapstructure:"node"`
+
+	// ObservePods determines whether to report pod and port endpoints. If Node is set only
+	// the pods of that node are reported. Defaults to true.
+	ObservePods bool `mapstructure:"observe_pods"`
+	// ObserveNodes determines whether to report k8s.node endpoints. If Node is set only
+	// that node is reported. Defaults to false.
+	ObserveNodes bool `mapstructure:"observe_nodes"`
+	// ObserveServices determines whether to report k8s.service endpoints for the services
+	// of all namespaces having a cluster IP. Defaults to false.
+	ObserveServices bool `mapstructure:"observe_services"`
 }
 
 // Validate checks if the extension configuration is valid
 func (cfg *Config) Validate() error {
+	if !cfg.ObservePods && !cfg.ObserveNodes && !cfg.ObserveServices {
+		return errors.New("one of observe_pods, observe_nodes and observe_services must be enabled")
+	}
 	return cfg.APIConfig.Validate()
 }
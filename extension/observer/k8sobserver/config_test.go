@@ -49,6 +49,9 @@ func TestLoadConfig(t *testing.T) {
 			ExtensionSettings: config.NewExtensionSettings(config.NewIDWithName(typeStr, "1")),
 			Node:              "node-1",
 			APIConfig:         k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeKubeConfig},
+			ObservePods:       true,
+			ObserveNodes:      true,
+			ObserveServices:   true,
 		},
 		ext1)
 }
@@ -58,11 +61,20 @@ func TestValidate(t *testing.T) {
 		ExtensionSettings: config.NewExtensionSettings(config.NewIDWithName(typeStr, "1")),
 		Node:              "node-1",
 		APIConfig:         k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeKubeConfig},
+		ObservePods:       true,
 	}
 
 	err := cfg.Validate()
 	require.Nil(t, err)
 
+	cfg.ObservePods = false
+	err = cfg.Validate()
+	require.EqualError(t, err, "one of observe_pods, observe_nodes and observe_services must be enabled")
+
+	cfg.ObserveNodes = true
+	err = cfg.Validate()
+	require.Nil(t, err)
+
 	cfg.APIConfig.AuthType = "invalid"
 	err = cfg.Validate()
 	require.NotNil(t, err)
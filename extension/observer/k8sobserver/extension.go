@@ -16,41 +16,99 @@ package k8sobserver
 
 import (
 	"context"
+	"sync"
 
 	"go.opentelemetry.io/collector/component"
 	"go.uber.org/zap"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
 
 type k8sObserver struct {
-	logger   *zap.Logger
-	informer cache.SharedInformer
-	stop     chan struct{}
-	config   *Config
+	logger    *zap.Logger
+	informers []cache.SharedInformer
+	notifier  *observer.EndpointsNotifier
+	stop      chan struct{}
+	done      chan struct{}
+	config    *Config
 }
 
 func (k *k8sObserver) Start(ctx context.Context, host component.Host) error {
-	go k.informer.Run(k.stop)
+	k.done = make(chan struct{})
+	var wg sync.WaitGroup
+	for _, informer := range k.informers {
+		wg.Add(1)
+		go func(informer cache.SharedInformer) {
+			defer wg.Done()
+			informer.Run(k.stop)
+		}(informer)
+	}
+	go func() {
+		wg.Wait()
+		close(k.done)
+	}()
 	return nil
 }
 
+// Shutdown stops the informers and waits for them to complete.
 func (k *k8sObserver) Shutdown(ctx context.Context) error {
 	close(k.stop)
+	if k.done != nil {
+		<-k.done
+	}
 	return nil
 }
 
 var _ (component.Extension) = (*k8sObserver)(nil)
+var _ observer.Observable = (*k8sObserver)(nil)
 
 // ListAndWatch notifies watcher with the current state and sends subsequent state changes.
 func (k *k8sObserver) ListAndWatch(listener observer.Notify) {
-	k.informer.AddEventHandler(&handler{watcher: listener, idNamespace: k.config.ID().String()})
+	k.notifier.ListAndWatch(listener)
 }
 
-// newObserver creates a new k8s observer extension.
-func newObserver(logger *zap.Logger, config *Config, listWatch cache.ListerWatcher) (component.Extension, error) {
-	informer := cache.NewSharedInformer(listWatch, &v1.Pod{}, 0)
-	return &k8sObserver{logger: logger, informer: informer, stop: make(chan struct{}), config: config}, nil
+// Unsubscribe stops sending state changes to listener.
+func (k *k8sObserver) Unsubscribe(listener observer.Notify) {
+	k.notifier.Unsubscribe(listener)
+}
+
+// newObserver creates a new k8s observer extension. An informer is created for each
+// of the pods, nodes and services ListerWatcher that is not nil.
+func newObserver(
+	logger *zap.Logger,
+	config *Config,
+	podListerWatcher, nodeListerWatcher, serviceListerWatcher cache.ListerWatcher,
+) (component.Extension, error) {
+	// A single handler is registered with each informer since handlers cannot be removed
+	// from them, the notifier takes care of the listeners coming and going.
+	n := &observer.EndpointsNotifier{}
+	h := &handler{watcher: n, idNamespace: config.ID().String()}
+
+	var informers []cache.SharedInformer
+	for _, lw := range []struct {
+		listerWatcher cache.ListerWatcher
+		objType       runtime.Object
+	}{
+		{podListerWatcher, &v1.Pod{}},
+		{nodeListerWatcher, &v1.Node{}},
+		{serviceListerWatcher, &v1.Service{}},
+	} {
+		if lw.listerWatcher == nil {
+			continue
+		}
+		informer := cache.NewSharedInformer(lw.listerWatcher, lw.objType, 0)
+		informer.AddEventHandler(h)
+		informers = append(informers, informer)
+	}
+
+	return &k8sObserver{
+		logger:    logger,
+		informers: informers,
+		notifier:  n,
+		stop:      make(chan struct{}),
+		config:    config,
+	}, nil
 }
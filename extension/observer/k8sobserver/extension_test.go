@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.uber.org/goleak"
 	"go.uber.org/zap"
 	framework "k8s.io/client-go/tools/cache/testing"
 
@@ -30,7 +31,7 @@ import (
 func TestNewExtension(t *testing.T) {
 	listWatch := framework.NewFakeControllerSource()
 	factory := &Factory{}
-	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), listWatch)
+	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), listWatch, nil, nil)
 	require.NoError(t, err)
 	require.NotNil(t, ext)
 }
@@ -38,7 +39,7 @@ func TestNewExtension(t *testing.T) {
 func TestExtensionObserve(t *testing.T) {
 	listWatch := framework.NewFakeControllerSource()
 	factory := &Factory{}
-	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), listWatch)
+	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), listWatch, nil, nil)
 	require.NoError(t, err)
 	require.NotNil(t, ext)
 	obs := ext.(*k8sObserver)
@@ -89,3 +90,81 @@ func TestExtensionObserve(t *testing.T) {
 
 	require.NoError(t, ext.Shutdown(context.Background()))
 }
+
+func TestExtensionObserveNodesAndServices(t *testing.T) {
+	nodeListWatch := framework.NewFakeControllerSource()
+	serviceListWatch := framework.NewFakeControllerSource()
+	factory := &Factory{}
+	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), nil, nodeListWatch, serviceListWatch)
+	require.NoError(t, err)
+	obs := ext.(*k8sObserver)
+	require.Len(t, obs.informers, 2)
+
+	nodeListWatch.Add(node1V1)
+	serviceListWatch.Add(service1V1)
+	serviceListWatch.Add(headlessService)
+
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+
+	sink := &endpointSink{}
+	obs.ListAndWatch(sink)
+
+	assertSink(t, sink, func() bool {
+		return len(sink.added) == 2
+	})
+
+	sink.Lock()
+	ids := []observer.EndpointID{sink.added[0].ID, sink.added[1].ID}
+	sink.Unlock()
+	assert.ElementsMatch(t, []observer.EndpointID{"k8s_observer/node1-UID", "k8s_observer/service1-UID"}, ids)
+
+	nodeListWatch.Delete(node1V2)
+	serviceListWatch.Delete(service1V2)
+
+	assertSink(t, sink, func() bool {
+		return len(sink.removed) == 2
+	})
+
+	require.NoError(t, ext.Shutdown(context.Background()))
+}
+
+func TestExtensionUnsubscribe(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	listWatch := framework.NewFakeControllerSource()
+	factory := &Factory{}
+	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), listWatch, nil, nil)
+	require.NoError(t, err)
+	obs := ext.(*k8sObserver)
+
+	listWatch.Add(pod1V1)
+
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+
+	first := &endpointSink{}
+	obs.ListAndWatch(first)
+	assertSink(t, first, func() bool {
+		return len(first.added) == 1
+	})
+
+	// Listeners subscribing later are notified of the known endpoints.
+	second := &endpointSink{}
+	obs.ListAndWatch(second)
+	assertSink(t, second, func() bool {
+		return len(second.added) == 1
+	})
+
+	obs.Unsubscribe(first)
+	listWatch.Delete(pod1V2)
+	assertSink(t, second, func() bool {
+		return len(second.removed) == 1
+	})
+
+	first.Lock()
+	assert.Empty(t, first.removed)
+	first.Unlock()
+
+	obs.Unsubscribe(second)
+	require.NoError(t, ext.Shutdown(context.Background()))
+	listWatch.Shutdown()
+}
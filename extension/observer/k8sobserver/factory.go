@@ -52,6 +52,7 @@ func (f *Factory) CreateDefaultConfig() config.Extension {
 	return &Config{
 		ExtensionSettings: config.NewExtensionSettings(config.NewID(typeStr)),
 		APIConfig:         k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount},
+		ObservePods:       true,
 	}
 }
 
@@ -68,11 +69,24 @@ func (f *Factory) CreateExtension(
 		return nil, err
 	}
 
-	listWatch := cache.NewListWatchFromClient(
-		clientset.CoreV1().RESTClient(), "pods", v1.NamespaceAll,
-		fields.OneTermEqualSelector("spec.nodeName", config.Node))
+	restClient := clientset.CoreV1().RESTClient()
+	var podListerWatcher, nodeListerWatcher, serviceListerWatcher cache.ListerWatcher
+	if config.ObservePods {
+		podListerWatcher = cache.NewListWatchFromClient(restClient, "pods", v1.NamespaceAll,
+			fields.OneTermEqualSelector("spec.nodeName", config.Node))
+	}
+	if config.ObserveNodes {
+		nodeSelector := fields.Everything()
+		if config.Node != "" {
+			nodeSelector = fields.OneTermEqualSelector("metadata.name", config.Node)
+		}
+		nodeListerWatcher = cache.NewListWatchFromClient(restClient, "nodes", v1.NamespaceAll, nodeSelector)
+	}
+	if config.ObserveServices {
+		serviceListerWatcher = cache.NewListWatchFromClient(restClient, "services", v1.NamespaceAll, fields.Everything())
+	}
 
-	return newObserver(params.Logger, config, listWatch)
+	return newObserver(params.Logger, config, podListerWatcher, nodeListerWatcher, serviceListerWatcher)
 }
 
 // NewFactory should be called to create a factory with default values.
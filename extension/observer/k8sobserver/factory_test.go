@@ -45,6 +45,7 @@ func TestFactory_CreateDefaultConfig(t *testing.T) {
 	assert.Equal(t, &Config{
 		ExtensionSettings: config.NewExtensionSettings(config.NewID(typeStr)),
 		APIConfig:         k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount},
+		ObservePods:       true,
 	},
 		cfg)
 
@@ -63,6 +64,22 @@ func TestFactory_CreateExtension(t *testing.T) {
 	require.NotNil(t, ext)
 }
 
+func TestFactory_CreateExtensionInformers(t *testing.T) {
+	factory := Factory{createK8sClientset: nilClient}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.ObserveNodes = true
+	cfg.ObserveServices = true
+
+	ext, err := factory.CreateExtension(context.Background(), component.ExtensionCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	assert.Len(t, ext.(*k8sObserver).informers, 3)
+
+	cfg.ObservePods = false
+	ext, err = factory.CreateExtension(context.Background(), component.ExtensionCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	assert.Len(t, ext.(*k8sObserver).informers, 2)
+}
+
 func TestNewFactory(t *testing.T) {
 	f := NewFactory()
 	require.IsType(t, f, &Factory{})
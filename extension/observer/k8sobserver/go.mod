@@ -15,6 +15,7 @@ require (
 	github.com/pelletier/go-toml v1.8.0 // indirect
 	github.com/stretchr/testify v1.7.0
 	go.opentelemetry.io/collector v0.27.1-0.20210524201935-86ea0a131fb2
+	go.uber.org/goleak v1.1.10
 	go.uber.org/zap v1.16.0
 	gopkg.in/ini.v1 v1.57.0 // indirect
 	k8s.io/api v0.21.1
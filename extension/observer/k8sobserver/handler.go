@@ -24,7 +24,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
 
-// handler handles k8s cache informer callbacks.
+// handler handles k8s cache informer callbacks for pods, nodes and services.
 type handler struct {
 	// idNamespace should be some unique token to distinguish multiple handler instances.
 	idNamespace string
@@ -32,13 +32,25 @@ type handler struct {
 	watcher observer.Notify
 }
 
-// OnAdd is called in response to a pod being added.
+// OnAdd is called in response to a pod, node or service being added.
 func (h *handler) OnAdd(obj interface{}) {
-	pod, ok := obj.(*v1.Pod)
-	if !ok {
-		return
+	if endpoints := h.convertToEndpoints(obj); len(endpoints) > 0 {
+		h.watcher.OnAdd(endpoints)
 	}
-	h.watcher.OnAdd(h.convertPodToEndpoints(pod))
+}
+
+// convertToEndpoints converts a pod, node or service into its endpoints.
+// Objects of other types are ignored.
+func (h *handler) convertToEndpoints(obj interface{}) []observer.Endpoint {
+	switch o := obj.(type) {
+	case *v1.Pod:
+		return h.convertPodToEndpoints(o)
+	case *v1.Node:
+		return h.convertNodeToEndpoints(o)
+	case *v1.Service:
+		return h.convertServiceToEndpoints(o)
+	}
+	return nil
 }
 
 // convertPodToEndpoints converts a pod instance into a slice of endpoints. The endpoints
@@ -109,32 +121,23 @@ func getTransport(protocol v1.Protocol) observer.Transport {
 	return observer.ProtocolUnknown
 }
 
-// OnUpdate is called in response to an existing pod changing.
+// OnUpdate is called in response to an existing pod, node or service changing.
 func (h *handler) OnUpdate(oldObj, newObj interface{}) {
-	oldPod, ok := oldObj.(*v1.Pod)
-	if !ok {
-		return
-	}
-	newPod, ok := newObj.(*v1.Pod)
-	if !ok {
-		return
-	}
-
 	oldEndpoints := map[observer.EndpointID]observer.Endpoint{}
 	newEndpoints := map[observer.EndpointID]observer.Endpoint{}
 
-	// Convert pods to endpoints and map by ID for easier lookup.
-	for _, e := range h.convertPodToEndpoints(oldPod) {
+	// Convert objects to endpoints and map by ID for easier lookup.
+	for _, e := range h.convertToEndpoints(oldObj) {
 		oldEndpoints[e.ID] = e
 	}
-	for _, e := range h.convertPodToEndpoints(newPod) {
+	for _, e := range h.convertToEndpoints(newObj) {
 		newEndpoints[e.ID] = e
 	}
 
 	var removedEndpoints, updatedEndpoints, addedEndpoints []observer.Endpoint
 
-	// Find endpoints that are present in oldPod and newPod and see if they've
-	// changed. Otherwise if it wasn't in oldPod it's a new endpoint.
+	// Find endpoints that are present in oldObj and newObj and see if they've
+	// changed. Otherwise if it wasn't in oldObj it's a new endpoint.
 	for _, e := range newEndpoints {
 		if existing, ok := oldEndpoints[e.ID]; ok {
 			if !reflect.DeepEqual(existing, e) {
@@ -145,7 +148,7 @@ func (h *handler) OnUpdate(oldObj, newObj interface{}) {
 		}
 	}
 
-	// If an endpoint is present in the oldPod but not in the newPod then
+	// If an endpoint is present in the oldObj but not in the newObj then
 	// send as removed.
 	for _, e := range oldEndpoints {
 		if _, ok := newEndpoints[e.ID]; !ok {
@@ -171,19 +174,93 @@ func (h *handler) OnUpdate(oldObj, newObj interface{}) {
 	// they are all cleaned up.
 }
 
-// OnDelete is called in response to a pod being deleted.
+// OnDelete is called in response to a pod, node or service being deleted.
 func (h *handler) OnDelete(obj interface{}) {
-	var pod *v1.Pod
+	// Assuming we never saw the object state where new endpoints would have been created
+	// to begin with it seems that we can't leak endpoints here.
 	switch o := obj.(type) {
+	case cache.DeletedFinalStateUnknown:
+		obj = o.Obj
 	case *cache.DeletedFinalStateUnknown:
-		// Assuming we never saw the pod state where new endpoints would have been created
-		// to begin with it seems that we can't leak endpoints here.
-		pod = o.Obj.(*v1.Pod)
-	case *v1.Pod:
-		pod = o
+		obj = o.Obj
+	}
+	if endpoints := h.convertToEndpoints(obj); len(endpoints) > 0 {
+		h.watcher.OnRemove(endpoints)
+	}
+}
+
+// convertNodeToEndpoints converts a node instance into its endpoint. The endpoint
+// target is the first address reported among the internal IP, internal DNS, hostname,
+// external IP and external DNS addresses.
+func (h *handler) convertNodeToEndpoints(node *v1.Node) []observer.Endpoint {
+	details := observer.K8sNode{
+		Name:                node.Name,
+		UID:                 string(node.UID),
+		KubeletEndpointPort: uint16(node.Status.DaemonEndpoints.KubeletEndpoint.Port),
+		Labels:              node.Labels,
+		Annotations:         node.Annotations,
 	}
-	if pod == nil {
-		return
+	for _, address := range node.Status.Addresses {
+		switch address.Type {
+		case v1.NodeHostName:
+			details.Hostname = address.Address
+		case v1.NodeInternalIP:
+			details.InternalIP = address.Address
+		case v1.NodeExternalIP:
+			details.ExternalIP = address.Address
+		case v1.NodeInternalDNS:
+			details.InternalDNS = address.Address
+		case v1.NodeExternalDNS:
+			details.ExternalDNS = address.Address
+		}
+	}
+
+	var target string
+	for _, address := range []string{details.InternalIP, details.InternalDNS, details.Hostname, details.ExternalIP, details.ExternalDNS} {
+		if address != "" {
+			target = address
+			break
+		}
 	}
-	h.watcher.OnRemove(h.convertPodToEndpoints(pod))
+
+	return []observer.Endpoint{{
+		ID:      observer.EndpointID(fmt.Sprintf("%s/%s", h.idNamespace, node.UID)),
+		Target:  target,
+		Details: &details,
+	}}
+}
+
+// convertServiceToEndpoints converts a service instance into its endpoint targeting the
+// cluster IP. Headless and ExternalName services have no cluster IP and no endpoint.
+func (h *handler) convertServiceToEndpoints(service *v1.Service) []observer.Endpoint {
+	clusterIP := service.Spec.ClusterIP
+	if clusterIP == "" || clusterIP == v1.ClusterIPNone {
+		return nil
+	}
+
+	ports := make([]observer.K8sServicePort, 0, len(service.Spec.Ports))
+	for _, port := range service.Spec.Ports {
+		ports = append(ports, observer.K8sServicePort{
+			Name:       port.Name,
+			Port:       uint16(port.Port),
+			TargetPort: port.TargetPort.String(),
+			Transport:  getTransport(port.Protocol),
+		})
+	}
+
+	return []observer.Endpoint{{
+		ID:     observer.EndpointID(fmt.Sprintf("%s/%s", h.idNamespace, service.UID)),
+		Target: clusterIP,
+		Details: &observer.K8sService{
+			Name:        service.Name,
+			UID:         string(service.UID),
+			Namespace:   service.Namespace,
+			ClusterIP:   clusterIP,
+			ServiceType: string(service.Spec.Type),
+			Ports:       ports,
+			Selector:    service.Spec.Selector,
+			Labels:      service.Labels,
+			Annotations: service.Annotations,
+		},
+	}}
 }
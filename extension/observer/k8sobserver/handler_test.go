@@ -18,6 +18,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
@@ -146,3 +149,107 @@ func TestEndpointsChanged(t *testing.T) {
 				Transport: observer.ProtocolTCP}},
 	}, sink.changed)
 }
+
+var node1Endpoint = observer.Endpoint{
+	ID:     "test-1/node1-UID",
+	Target: "10.0.0.1",
+	Details: &observer.K8sNode{
+		Name:                "node1",
+		UID:                 "node1-UID",
+		Hostname:            "localhost",
+		InternalIP:          "10.0.0.1",
+		ExternalIP:          "34.0.0.1",
+		KubeletEndpointPort: 10250,
+		Labels:              map[string]string{"env": "prod"},
+	},
+}
+
+var service1Endpoint = observer.Endpoint{
+	ID:     "test-1/service1-UID",
+	Target: "10.96.0.10",
+	Details: &observer.K8sService{
+		Name:        "service1",
+		UID:         "service1-UID",
+		Namespace:   "default",
+		ClusterIP:   "10.96.0.10",
+		ServiceType: "ClusterIP",
+		Ports: []observer.K8sServicePort{
+			{Name: "redis", Port: 6379, TargetPort: "redis-port", Transport: observer.ProtocolTCP},
+			{Name: "dns", Port: 53, TargetPort: "5353", Transport: observer.ProtocolUDP},
+		},
+		Selector: map[string]string{"app": "redis"},
+		Labels:   map[string]string{"env": "prod"},
+	},
+}
+
+func TestNodeEndpoints(t *testing.T) {
+	sink := endpointSink{}
+	h := handler{
+		idNamespace: "test-1",
+		watcher:     &sink,
+	}
+
+	h.OnAdd(node1V1)
+	assert.Equal(t, []observer.Endpoint{node1Endpoint}, sink.added)
+
+	h.OnUpdate(node1V1, node1V2)
+	require.Len(t, sink.changed, 1)
+	assert.Equal(t, map[string]string{"env": "prod", "node-version": "2"},
+		sink.changed[0].Details.(*observer.K8sNode).Labels)
+
+	h.OnDelete(cache.DeletedFinalStateUnknown{Key: "node1", Obj: node1V1})
+	assert.Equal(t, []observer.Endpoint{node1Endpoint}, sink.removed)
+}
+
+func TestNodeEndpointTarget(t *testing.T) {
+	h := handler{idNamespace: "test-1"}
+
+	node := node1V1.DeepCopy()
+	node.Status.Addresses = []v1.NodeAddress{
+		{Type: v1.NodeExternalDNS, Address: "node1.example.com"},
+		{Type: v1.NodeHostName, Address: "node1"},
+	}
+	endpoints := h.convertToEndpoints(node)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, "node1", endpoints[0].Target)
+}
+
+func TestServiceEndpoints(t *testing.T) {
+	sink := endpointSink{}
+	h := handler{
+		idNamespace: "test-1",
+		watcher:     &sink,
+	}
+
+	h.OnAdd(service1V1)
+	assert.Equal(t, []observer.Endpoint{service1Endpoint}, sink.added)
+
+	h.OnUpdate(service1V1, service1V2)
+	require.Len(t, sink.changed, 1)
+	assert.Equal(t, map[string]string{"env": "prod", "service-version": "2"},
+		sink.changed[0].Details.(*observer.K8sService).Labels)
+
+	h.OnDelete(service1V1)
+	assert.Equal(t, []observer.Endpoint{service1Endpoint}, sink.removed)
+}
+
+func TestHeadlessServiceIgnored(t *testing.T) {
+	sink := endpointSink{}
+	h := handler{
+		idNamespace: "test-1",
+		watcher:     &sink,
+	}
+
+	h.OnAdd(headlessService)
+	h.OnUpdate(headlessService, headlessService)
+	h.OnDelete(headlessService)
+	assert.Nil(t, sink.added)
+	assert.Nil(t, sink.changed)
+	assert.Nil(t, sink.removed)
+
+	// Switching to headless removes the endpoint.
+	toHeadless := service1V1.DeepCopy()
+	toHeadless.Spec.ClusterIP = v1.ClusterIPNone
+	h.OnUpdate(service1V1, toHeadless)
+	assert.Equal(t, []observer.Endpoint{service1Endpoint}, sink.removed)
+}
@@ -18,6 +18,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // NewPod is a helper function for creating Pods for testing.
@@ -113,3 +114,67 @@ var podWithNamedPorts = func() *v1.Pod {
 func pointerBool(val bool) *bool {
 	return &val
 }
+
+// NewNode is a helper function for creating Nodes for testing.
+func NewNode(name, hostname string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			UID:  types.UID(name + "-UID"),
+			Labels: map[string]string{
+				"env": "prod",
+			},
+		},
+		Status: v1.NodeStatus{
+			Addresses: []v1.NodeAddress{
+				{Type: v1.NodeHostName, Address: hostname},
+				{Type: v1.NodeInternalIP, Address: "10.0.0.1"},
+				{Type: v1.NodeExternalIP, Address: "34.0.0.1"},
+			},
+			DaemonEndpoints: v1.NodeDaemonEndpoints{
+				KubeletEndpoint: v1.DaemonEndpoint{Port: 10250},
+			},
+		},
+	}
+}
+
+var node1V1 = NewNode("node1", "localhost")
+var node1V2 = func() *v1.Node {
+	node := node1V1.DeepCopy()
+	node.Labels["node-version"] = "2"
+	return node
+}()
+
+// NewService is a helper function for creating Services for testing.
+func NewService(name, clusterIP string) *v1.Service {
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			UID:       types.UID(name + "-UID"),
+			Labels: map[string]string{
+				"env": "prod",
+			},
+		},
+		Spec: v1.ServiceSpec{
+			Type:      v1.ServiceTypeClusterIP,
+			ClusterIP: clusterIP,
+			Selector: map[string]string{
+				"app": "redis",
+			},
+			Ports: []v1.ServicePort{
+				{Name: "redis", Port: 6379, TargetPort: intstr.FromString("redis-port"), Protocol: v1.ProtocolTCP},
+				{Name: "dns", Port: 53, TargetPort: intstr.FromInt(5353), Protocol: v1.ProtocolUDP},
+			},
+		},
+	}
+}
+
+var service1V1 = NewService("service1", "10.96.0.10")
+var service1V2 = func() *v1.Service {
+	service := service1V1.DeepCopy()
+	service.Labels["service-version"] = "2"
+	return service
+}()
+
+var headlessService = NewService("headless", v1.ClusterIPNone)
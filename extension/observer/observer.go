@@ -36,12 +36,16 @@ const (
 
 // Observable is an interface that provides notification of endpoint changes.
 type Observable interface {
-	// TODO: Stopping.
 	// ListAndWatch provides initial state sync as well as change notification.
 	// notify.OnAdd will be called one or more times if there are endpoints discovered.
 	// (It would not be called if there are no endpoints present.) The endpoint synchronization
-	// happens asynchronously to this call.
+	// happens asynchronously to this call. notify is used as a map key to identify the
+	// subscription so it must be comparable (e.g. a pointer).
 	ListAndWatch(notify Notify)
+	// Unsubscribe stops notifications to a notify previously passed to ListAndWatch.
+	// Once Unsubscribe returns no further callbacks will be made to notify. It must not
+	// be called from within a notify callback.
+	Unsubscribe(notify Notify)
 }
 
 // Notify is the callback for Observer events.
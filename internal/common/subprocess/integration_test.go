@@ -23,6 +23,7 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
 )
 
@@ -71,9 +73,9 @@ func (suite *SubprocessIntegrationSuite) TearDownSuite() {
 // prepareSubprocess will create a Subprocess based on a temporary script.
 // It returns a pointer to the pointer to psutil process info and a closure to set its
 // value from the running process once started.
-func (suite *SubprocessIntegrationSuite) prepareSubprocess(conf *Config) (*Subprocess, **process.Process, func() bool) {
+func (suite *SubprocessIntegrationSuite) prepareSubprocess(conf *Config) (*Subprocess, **process.Process, func() bool, *observer.ObservedLogs) {
 	t := suite.T()
-	logCore, _ := observer.New(zap.DebugLevel)
+	logCore, logObserver := observer.New(zap.DebugLevel)
 	logger := zap.New(logCore)
 
 	conf.ExecutablePath = suite.scriptPath
@@ -104,23 +106,26 @@ func (suite *SubprocessIntegrationSuite) prepareSubprocess(conf *Config) (*Subpr
 		return true
 	}
 
-	return subprocess, &procInfo, findProcessInfo
+	return subprocess, &procInfo, findProcessInfo, logObserver
 }
 
-func requireDesiredStdout(t *testing.T, subprocess *Subprocess, desired string) {
-	timer := time.After(5 * time.Second)
-loop:
-	for {
-		select {
-		case <-timer:
-			break loop
-		case out := <-subprocess.Stdout:
-			if out == desired {
-				return
+// requireDesiredOutput waits for a line of output of the subprocess satisfying matches
+// to be logged at the given level.
+func requireDesiredOutput(t *testing.T, logObserver *observer.ObservedLogs, level zapcore.Level, matches func(string) bool) {
+	require.Eventually(t, func() bool {
+		for _, entry := range logObserver.FilterMessage("subprocess output line").All() {
+			if entry.Level == level && matches(entry.ContextMap()["output"].(string)) {
+				return true
 			}
 		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond, "Failed to receive desired output")
+}
+
+func equals(desired string) func(string) bool {
+	return func(output string) bool {
+		return output == desired
 	}
-	t.Fatal("Failed to receive desired stdout")
 }
 
 func (suite *SubprocessIntegrationSuite) TestHappyPath() {
@@ -128,7 +133,7 @@ func (suite *SubprocessIntegrationSuite) TestHappyPath() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	subprocess, procInfo, findProcessInfo := suite.prepareSubprocess(&Config{})
+	subprocess, procInfo, findProcessInfo, _ := suite.prepareSubprocess(&Config{})
 	subprocess.Start(ctx)
 	defer subprocess.Shutdown(ctx)
 
@@ -145,7 +150,7 @@ func (suite *SubprocessIntegrationSuite) TestWithArgs() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	subprocess, procInfo, findProcessInfo := suite.prepareSubprocess(&Config{Args: []string{"myArgs"}})
+	subprocess, procInfo, findProcessInfo, _ := suite.prepareSubprocess(&Config{Args: []string{"myArgs"}})
 	subprocess.Start(ctx)
 	defer subprocess.Shutdown(ctx)
 
@@ -169,16 +174,17 @@ func (suite *SubprocessIntegrationSuite) TestWithEnvVars() {
 		},
 	}
 
-	subprocess, procInfo, findProcessInfo := suite.prepareSubprocess(config)
+	subprocess, procInfo, findProcessInfo, logObserver := suite.prepareSubprocess(config)
 	subprocess.Start(ctx)
 	defer subprocess.Shutdown(ctx)
 	require.Eventually(t, findProcessInfo, 5*time.Second, 10*time.Millisecond)
 	require.NotNil(t, *procInfo)
 
-	stdout := <-subprocess.Stdout
-	require.NotEmpty(t, stdout)
-	require.Contains(t, stdout, "MyEnv1=MyVal1")
-	require.Contains(t, stdout, "MyEnv2=MyVal2")
+	requireDesiredOutput(t, logObserver, zapcore.InfoLevel, func(output string) bool {
+		return strings.HasPrefix(output, "Env:") &&
+			strings.Contains(output, "MyEnv1=MyVal1") &&
+			strings.Contains(output, "MyEnv2=MyVal2")
+	})
 }
 
 func (suite *SubprocessIntegrationSuite) TestWithAutoRestart() {
@@ -187,7 +193,7 @@ func (suite *SubprocessIntegrationSuite) TestWithAutoRestart() {
 	defer cancel()
 
 	restartDelay := 100 * time.Millisecond
-	subprocess, procInfo, findProcessInfo := suite.prepareSubprocess(&Config{RestartOnError: true, RestartDelay: &restartDelay})
+	subprocess, procInfo, findProcessInfo, logObserver := suite.prepareSubprocess(&Config{RestartOnError: true, RestartDelay: &restartDelay})
 	subprocess.Start(ctx)
 	defer subprocess.Shutdown(ctx)
 
@@ -206,6 +212,90 @@ func (suite *SubprocessIntegrationSuite) TestWithAutoRestart() {
 	require.Eventually(t, func() bool {
 		return findProcessInfo() && *procInfo != nil && (*procInfo).Pid != oldProcPid
 	}, restartDelay+5*time.Second, 10*time.Millisecond)
+	require.Len(t, logObserver.FilterMessage("restarting subprocess").All(), 1)
+}
+
+func (suite *SubprocessIntegrationSuite) TestHooks() {
+	t := suite.T()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	restartDelay := 10 * time.Millisecond
+	var mu sync.Mutex
+	var starts, exits int
+	config := &Config{
+		ExecutablePath: "sh",
+		RestartOnError: true,
+		RestartDelay:   &restartDelay,
+		BeforeStart: func(conf *Config) error {
+			mu.Lock()
+			defer mu.Unlock()
+			starts++
+			conf.Args = []string{"-c", fmt.Sprintf("echo run %d", starts)}
+			return nil
+		},
+		AfterExit: func() {
+			mu.Lock()
+			defer mu.Unlock()
+			exits++
+		},
+	}
+	logCore, logObserver := observer.New(zap.DebugLevel)
+	subprocess := NewSubprocess(config, zap.New(logCore))
+	subprocess.Start(ctx)
+
+	requireDesiredOutput(t, logObserver, zapcore.InfoLevel, equals("run 3"))
+	require.NoError(t, subprocess.Shutdown(ctx))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, starts, exits)
+}
+
+func (suite *SubprocessIntegrationSuite) TestKillAfterShutdownTimeout() {
+	t := suite.T()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	shutdownTimeout := 100 * time.Millisecond
+	logCore, logObserver := observer.New(zap.DebugLevel)
+	subprocess := NewSubprocess(&Config{
+		ExecutablePath:  "sh",
+		Args:            []string{"-c", "trap '' TERM; echo ready; sleep 60"},
+		ShutdownTimeout: &shutdownTimeout,
+	}, zap.New(logCore))
+	subprocess.Start(ctx)
+
+	requireDesiredOutput(t, logObserver, zapcore.InfoLevel, equals("ready"))
+	require.NoError(t, subprocess.Shutdown(ctx))
+
+	_, ok := <-subprocess.shutdownSignal
+	require.False(t, ok)
+	require.Len(t, logObserver.FilterMessage("subprocess didn't exit after SIGTERM, killing it").All(), 1)
+}
+
+func (suite *SubprocessIntegrationSuite) TestStderrLogLevel() {
+	t := suite.T()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logCore, logObserver := observer.New(zap.DebugLevel)
+	subprocess := NewSubprocess(&Config{
+		ExecutablePath: "sh",
+		Args:           []string{"-c", "echo out; echo WARN err >&2; echo err >&2; sleep 60"},
+		LogLevel: func(line string, isStderr bool) zapcore.Level {
+			if strings.HasPrefix(line, "WARN") {
+				return zapcore.WarnLevel
+			}
+			return DefaultLogLevel(line, isStderr)
+		},
+	}, zap.New(logCore))
+	subprocess.Start(ctx)
+	defer subprocess.Shutdown(ctx)
+
+	requireDesiredOutput(t, logObserver, zapcore.InfoLevel, equals("out"))
+	requireDesiredOutput(t, logObserver, zapcore.WarnLevel, equals("WARN err"))
+	requireDesiredOutput(t, logObserver, zapcore.ErrorLevel, equals("err"))
 }
 
 func (suite *SubprocessIntegrationSuite) TestSendingStdin() {
@@ -213,14 +303,14 @@ func (suite *SubprocessIntegrationSuite) TestSendingStdin() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	subprocess, procInfo, findProcessInfo := suite.prepareSubprocess(&Config{StdInContents: "mystdincontents"})
+	subprocess, procInfo, findProcessInfo, logObserver := suite.prepareSubprocess(&Config{StdInContents: "mystdincontents"})
 	subprocess.Start(ctx)
 	defer subprocess.Shutdown(ctx)
 
 	require.Eventually(t, findProcessInfo, 5*time.Second, 10*time.Millisecond)
 	require.NotNil(t, *procInfo)
 
-	requireDesiredStdout(t, subprocess, "Stdin: mystdincontents")
+	requireDesiredOutput(t, logObserver, zapcore.InfoLevel, equals("Stdin: mystdincontents"))
 }
 
 func (suite *SubprocessIntegrationSuite) TestSendingStdinFails() {
@@ -274,10 +364,8 @@ func (suite *SubprocessIntegrationSuite) TestSubprocessSuccessfullyReturns() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// There is a race condition between writing from the stdout scanner and the closing of the stdout channel on
-	// process exit. Here we sleep before returning, but this will need to be addressed if short lived processes
-	// become an intended use case without forcing users to read stdout before closing.
-	subprocess := NewSubprocess(&Config{ExecutablePath: "sh", Args: []string{"-c", "echo finished; sleep .1"}}, zap.NewNop())
+	logCore, logObserver := observer.New(zap.DebugLevel)
+	subprocess := NewSubprocess(&Config{ExecutablePath: "sh", Args: []string{"-c", "echo finished"}}, zap.New(logCore))
 	subprocess.Start(ctx)
 	defer subprocess.Shutdown(ctx)
 
@@ -287,7 +375,7 @@ func (suite *SubprocessIntegrationSuite) TestSubprocessSuccessfullyReturns() {
 	}
 
 	require.Eventually(t, matched, 10*time.Second, 10*time.Millisecond)
-	requireDesiredStdout(t, subprocess, "finished")
+	requireDesiredOutput(t, logObserver, zapcore.InfoLevel, equals("finished"))
 }
 
 func TestShutdownBeforeStartIntegration(t *testing.T) {
@@ -0,0 +1,31 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subprocess
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// LogLevelFunc returns the level a line of output of the process is logged at.
+type LogLevelFunc func(line string, isStderr bool) zapcore.Level
+
+// DefaultLogLevel logs the lines written to stdout at info level and the ones
+// written to stderr at error level.
+func DefaultLogLevel(_ string, isStderr bool) zapcore.Level {
+	if isStderr {
+		return zapcore.ErrorLevel
+	}
+	return zapcore.InfoLevel
+}
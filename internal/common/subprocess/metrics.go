@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subprocess
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func init() {
+	view.Register(
+		viewRestarts,
+		viewRunning,
+	)
+}
+
+// tagKeyReceiver is the key of the receiver tag set by obsreport.ReceiverContext.
+var tagKeyReceiver = tag.MustNewKey("receiver")
+
+var (
+	mRestarts = stats.Int64("otelcol/subprocess/restarts", "Number of restarts of the subprocess after an unexpected exit", "1")
+	mRunning  = stats.Int64("otelcol/subprocess/running", "Whether the subprocess is running (1) or not (0)", "1")
+)
+
+var viewRestarts = &view.View{
+	Name:        mRestarts.Name(),
+	Description: mRestarts.Description(),
+	Measure:     mRestarts,
+	TagKeys:     []tag.Key{tagKeyReceiver},
+	Aggregation: view.Sum(),
+}
+
+var viewRunning = &view.View{
+	Name:        mRunning.Name(),
+	Description: mRunning.Description(),
+	Measure:     mRunning,
+	TagKeys:     []tag.Key{tagKeyReceiver},
+	Aggregation: view.LastValue(),
+}
+
+func recordRestart(ctx context.Context) {
+	stats.Record(ctx, mRestarts.M(1))
+}
+
+func recordRunning(ctx context.Context, running bool) {
+	var value int64
+	if running {
+		value = 1
+	}
+	stats.Record(ctx, mRunning.M(value))
+}
@@ -17,6 +17,7 @@ package subprocess
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -25,33 +26,52 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 )
 
 const (
 	defaultRestartDelay    = 5 * time.Second
+	defaultMaxRestartDelay = 5 * time.Minute
 	defaultShutdownTimeout = 5 * time.Second
 	noPid                  = -1
 )
 
-// exported to be used by jmx metric receiver
+// Config is the configuration of a Subprocess, shared by the receivers running
+// a process such as jmx and prometheus_exec.
 type Config struct {
 	ExecutablePath       string            `mapstructure:"executable_path"`
 	Args                 []string          `mapstructure:"args"`
 	EnvironmentVariables map[string]string `mapstructure:"environment_variables"`
 	StdInContents        string            `mapstructure:"stdin_contents"`
-	RestartOnError       bool              `mapstructure:"restart_on_error"`
-	RestartDelay         *time.Duration    `mapstructure:"restart_delay"`
-	ShutdownTimeout      *time.Duration    `mapstructure:"shutdown_timeout"`
+	// RestartOnError restarts the process when it exits before Shutdown is called.
+	RestartOnError bool `mapstructure:"restart_on_error"`
+	// RestartDelay is the delay before the first restart. It is doubled on each
+	// consecutive restart up to MaxRestartDelay, with a 50% jitter, and is reset
+	// once the process ran for longer than MaxRestartDelay.
+	RestartDelay    *time.Duration `mapstructure:"restart_delay"`
+	MaxRestartDelay *time.Duration `mapstructure:"max_restart_delay"`
+	// ShutdownTimeout is how long to wait for the process to exit after being sent
+	// a SIGTERM before killing it.
+	ShutdownTimeout *time.Duration `mapstructure:"shutdown_timeout"`
+
+	// LogLevel maps the lines of output of the process to the level they are logged
+	// at. DefaultLogLevel is used if not set.
+	LogLevel LogLevelFunc `mapstructure:"-"`
+	// BeforeStart, if set, is called before each start of the process. It can update
+	// the config of the process, e.g. to use a new port. An error fails the start.
+	BeforeStart func(config *Config) error `mapstructure:"-"`
+	// AfterExit, if set, is called after each exit of a process successfully prepared
+	// by BeforeStart, including when it fails to start.
+	AfterExit func() `mapstructure:"-"`
 }
 
-// exported to be used by jmx metric receiver
+// Subprocess runs and supervises a process, restarting it on unexpected exits if
+// configured to.
 type Subprocess struct {
-	Stdout         chan string
 	cancel         context.CancelFunc
 	config         *Config
-	envVars        []string
 	logger         *zap.Logger
 	pid            pid
 	shutdownSignal chan struct{}
@@ -76,6 +96,7 @@ func (p *pid) getPid() int {
 	return p.pid
 }
 
+// Pid returns the pid of the running process or -1 if it isn't running.
 func (subprocess *Subprocess) Pid() int {
 	pid := subprocess.pid.getPid()
 	if pid == 0 {
@@ -84,19 +105,25 @@ func (subprocess *Subprocess) Pid() int {
 	return pid
 }
 
-// exported to be used by jmx metric receiver
+// NewSubprocess creates a Subprocess, setting the unset durations of conf to their default.
 func NewSubprocess(conf *Config, logger *zap.Logger) *Subprocess {
 	if conf.RestartDelay == nil {
 		restartDelay := defaultRestartDelay
 		conf.RestartDelay = &restartDelay
 	}
+	if conf.MaxRestartDelay == nil {
+		maxRestartDelay := defaultMaxRestartDelay
+		conf.MaxRestartDelay = &maxRestartDelay
+	}
 	if conf.ShutdownTimeout == nil {
 		shutdownTimeout := defaultShutdownTimeout
 		conf.ShutdownTimeout = &shutdownTimeout
 	}
+	if conf.LogLevel == nil {
+		conf.LogLevel = DefaultLogLevel
+	}
 
 	return &Subprocess{
-		Stdout:         make(chan string),
 		pid:            pid{pid: noPid, pidLock: sync.Mutex{}},
 		config:         conf,
 		logger:         logger,
@@ -114,15 +141,12 @@ const (
 	Errored      = "Errored"
 )
 
+// Start starts the process in the background. The self-metrics of the process are
+// recorded with the tags of ctx, see obsreport.ReceiverContext.
 func (subprocess *Subprocess) Start(ctx context.Context) error {
 	var cancelCtx context.Context
 	cancelCtx, subprocess.cancel = context.WithCancel(ctx)
 
-	for k, v := range subprocess.config.EnvironmentVariables {
-		joined := fmt.Sprintf("%v=%v", k, v)
-		subprocess.envVars = append(subprocess.envVars, joined)
-	}
-
 	go func() {
 		subprocess.run(cancelCtx) // will block for lifetime of process
 		close(subprocess.shutdownSignal)
@@ -135,12 +159,15 @@ func (subprocess *Subprocess) Shutdown(ctx context.Context) error {
 	if subprocess.cancel == nil {
 		return fmt.Errorf("no subprocess.cancel().  Has it been started properly?")
 	}
+	subprocess.cancel()
 
+	// Leave time to the process to exit before being killed.
 	timeout := defaultShutdownTimeout
 	if subprocess.config.ShutdownTimeout != nil {
-		timeout = *subprocess.config.ShutdownTimeout
+		timeout = 2 * *subprocess.config.ShutdownTimeout
 	}
 	t := time.NewTimer(timeout)
+	defer t.Stop()
 
 	// Wait for the subprocess to exit or the timeout period to elapse
 	select {
@@ -193,7 +220,20 @@ func (subprocess *Subprocess) run(ctx context.Context) {
 	var cmd *exec.Cmd
 	var err error
 	var stdin io.WriteCloser
-	var stdout io.ReadCloser
+	var stdout, stderr io.ReadCloser // closed by collectOutput
+	var startTime time.Time
+	// prepared is true between a successful BeforeStart and its AfterExit.
+	var prepared bool
+
+	restartBackoff := newRestartBackoff(subprocess.config)
+
+	afterExit := func() {
+		recordRunning(ctx, false)
+		if prepared && subprocess.config.AfterExit != nil {
+			subprocess.config.AfterExit()
+		}
+		prepared = false
+	}
 
 	// writer is signalWhenProcessReturned() and closer is this loop, so we need synchronization
 	processReturned := newProcessReturned()
@@ -204,21 +244,34 @@ func (subprocess *Subprocess) run(ctx context.Context) {
 
 		switch state {
 		case Starting:
-			cmd, stdin, stdout = createCommand(
+			if subprocess.config.BeforeStart != nil {
+				if err = subprocess.config.BeforeStart(subprocess.config); err != nil {
+					err = fmt.Errorf("failed preparing subprocess: %w", err)
+					state = Errored
+					continue
+				}
+			}
+			prepared = true
+
+			cmd, stdin, stdout, stderr = createCommand(
 				subprocess.config.ExecutablePath,
 				subprocess.config.Args,
-				subprocess.envVars,
+				envVars(subprocess.config.EnvironmentVariables),
 			)
 
-			go collectStdout(bufio.NewScanner(stdout), subprocess.Stdout, subprocess.logger)
+			go collectOutput(stdout, false, subprocess.config.LogLevel, subprocess.logger)
+			go collectOutput(stderr, true, subprocess.config.LogLevel, subprocess.logger)
 
 			subprocess.logger.Debug("starting subprocess", zap.String("command", cmd.String()))
 			err = cmd.Start()
+			closeChildPipes(cmd)
 			if err != nil {
 				state = Errored
 				continue
 			}
 			subprocess.pid.setPid(cmd.Process.Pid)
+			startTime = time.Now()
+			recordRunning(ctx, true)
 
 			go signalWhenProcessReturned(cmd, processReturned)
 
@@ -227,15 +280,19 @@ func (subprocess *Subprocess) run(ctx context.Context) {
 			err = subprocess.sendToStdIn(subprocess.config.StdInContents, stdin)
 			stdin.Close()
 			if err != nil {
+				subprocess.terminate(cmd, processReturned)
 				state = Errored
 				continue
 			}
 
 			select {
 			case err = <-processReturned.ReturnedChan:
-				if err != nil && ctx.Err() == nil {
-					err = fmt.Errorf("unexpected shutdown: %w", err)
+				if ctx.Err() == nil {
 					// We aren't supposed to shutdown yet so this is an error state.
+					if err == nil {
+						err = errors.New("process exited")
+					}
+					err = fmt.Errorf("unexpected shutdown: %w", err)
 					state = Errored
 					continue
 				}
@@ -247,8 +304,9 @@ func (subprocess *Subprocess) run(ctx context.Context) {
 			}
 		case Errored:
 			subprocess.logger.Error("subprocess died", zap.Error(err))
-			if subprocess.config.RestartOnError {
-				subprocess.pid.setPid(-1)
+			subprocess.pid.setPid(noPid)
+			afterExit()
+			if subprocess.config.RestartOnError && ctx.Err() == nil {
 				state = Restarting
 			} else {
 				// We must close this channel or can wait indefinitely at ShuttingDown
@@ -256,38 +314,84 @@ func (subprocess *Subprocess) run(ctx context.Context) {
 				state = ShuttingDown
 			}
 		case ShuttingDown:
-			if cmd.Process != nil {
-				cmd.Process.Signal(syscall.SIGTERM)
+			if cmd != nil && cmd.Process != nil {
+				subprocess.terminate(cmd, processReturned)
 			}
-			<-processReturned.ReturnedChan
-			stdout.Close()
-			subprocess.pid.setPid(-1)
+			closeAll(stdin)
+			subprocess.pid.setPid(noPid)
+			afterExit()
 			state = Stopped
 		case Restarting:
-			stdout.Close()
-			stdin.Close()
-			time.Sleep(*subprocess.config.RestartDelay)
-			state = Starting
+			closeAll(stdin)
+			// A process that ran long enough is considered healthy, its crash
+			// isn't related to the previous ones.
+			if !startTime.IsZero() && time.Since(startTime) > *subprocess.config.MaxRestartDelay {
+				restartBackoff.Reset()
+			}
+			startTime = time.Time{}
+			delay := restartBackoff.NextBackOff()
+			subprocess.logger.Info("restarting subprocess", zap.Duration("delay", delay))
+			recordRestart(ctx)
+
+			t := time.NewTimer(delay)
+			select {
+			case <-t.C:
+				state = Starting
+			case <-ctx.Done():
+				t.Stop()
+				// The process already exited, nothing will be signaled.
+				processReturned.close()
+				state = ShuttingDown
+			}
 		case Stopped:
 			return
 		}
 	}
 }
 
+// terminate sends a SIGTERM to the process and kills it if it doesn't exit within
+// the shutdown timeout.
+func (subprocess *Subprocess) terminate(cmd *exec.Cmd, pr *processReturned) {
+	cmd.Process.Signal(syscall.SIGTERM)
+	t := time.NewTimer(*subprocess.config.ShutdownTimeout)
+	defer t.Stop()
+	select {
+	case <-pr.ReturnedChan:
+	case <-t.C:
+		subprocess.logger.Warn("subprocess didn't exit after SIGTERM, killing it")
+		cmd.Process.Kill()
+		<-pr.ReturnedChan
+	}
+}
+
+func newRestartBackoff(conf *Config) backoff.BackOff {
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = *conf.RestartDelay
+	b.MaxInterval = *conf.MaxRestartDelay
+	// Restart forever.
+	b.MaxElapsedTime = 0
+	b.Reset()
+	return b
+}
+
 func signalWhenProcessReturned(cmd *exec.Cmd, pr *processReturned) {
 	err := cmd.Wait()
 	pr.signal(err)
 }
 
-func collectStdout(stdoutScanner *bufio.Scanner, stdoutChan chan<- string, logger *zap.Logger) {
-	for stdoutScanner.Scan() {
-		text := stdoutScanner.Text()
-		if text != "" {
-			stdoutChan <- text
-			logger.Debug(text)
+func collectOutput(output io.ReadCloser, isStderr bool, logLevel LogLevelFunc, logger *zap.Logger) {
+	defer output.Close()
+	scanner := bufio.NewScanner(output)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if text == "" {
+			continue
+		}
+		if ce := logger.Check(logLevel(text, isStderr), "subprocess output line"); ce != nil {
+			ce.Write(zap.String("output", text))
 		}
 	}
-	// Returns when stdout is closed when the process ends
+	// Returns when the output is closed by the process, usually when it exits
 }
 
 func sendToStdIn(contents string, writer io.Writer) error {
@@ -299,7 +403,29 @@ func sendToStdIn(contents string, writer io.Writer) error {
 	return err
 }
 
-func createCommand(execPath string, args, envVars []string) (*exec.Cmd, io.WriteCloser, io.ReadCloser) {
+func envVars(env map[string]string) []string {
+	var joined []string
+	for k, v := range env {
+		joined = append(joined, fmt.Sprintf("%v=%v", k, v))
+	}
+	return joined
+}
+
+func closeAll(closers ...io.Closer) {
+	for _, c := range closers {
+		if c != nil {
+			c.Close()
+		}
+	}
+}
+
+// closeChildPipes closes the pipe ends used by the process once it was started, so
+// that its output is closed when it exits.
+func closeChildPipes(cmd *exec.Cmd) {
+	closeAll(cmd.Stdin.(io.Closer), cmd.Stdout.(io.Closer), cmd.Stderr.(io.Closer))
+}
+
+func createCommand(execPath string, args, envVars []string) (*exec.Cmd, io.WriteCloser, io.ReadCloser, io.ReadCloser) {
 	cmd := exec.Command(execPath, args...)
 
 	var env []string
@@ -318,9 +444,14 @@ func createCommand(execPath string, args, envVars []string) (*exec.Cmd, io.Write
 		panic("Output pipe could not be created for subprocess")
 	}
 	cmd.Stdout = outWriter
-	cmd.Stderr = outWriter
+
+	errReader, errWriter, err := os.Pipe()
+	if err != nil {
+		panic("Error pipe could not be created for subprocess")
+	}
+	cmd.Stderr = errWriter
 
 	applyOSSpecificCmdModifications(cmd)
 
-	return cmd, inWriter, outReader
+	return cmd, inWriter, outReader, errReader
 }
@@ -21,6 +21,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 func TestSubprocessAndConfig(t *testing.T) {
@@ -30,21 +31,46 @@ func TestSubprocessAndConfig(t *testing.T) {
 	require.NotNil(t, subprocess)
 	require.Same(t, config, subprocess.config)
 	require.Same(t, logger, subprocess.logger)
-	require.NotNil(t, subprocess.Stdout)
 
 	require.Equal(t, *config.ShutdownTimeout, 5*time.Second)
 	require.Equal(t, *config.RestartDelay, 5*time.Second)
+	require.Equal(t, *config.MaxRestartDelay, 5*time.Minute)
+	require.NotNil(t, config.LogLevel)
 }
 
 func TestConfigDurations(t *testing.T) {
 	logger := zap.NewNop()
 	restartDelay := 100 * time.Second
+	maxRestartDelay := 150 * time.Second
 	shutdownTimeout := 200 * time.Second
-	config := &Config{RestartDelay: &restartDelay, ShutdownTimeout: &shutdownTimeout}
+	config := &Config{RestartDelay: &restartDelay, MaxRestartDelay: &maxRestartDelay, ShutdownTimeout: &shutdownTimeout}
 	subprocess := NewSubprocess(config, logger)
 	require.NotNil(t, subprocess)
 	require.Equal(t, *config.ShutdownTimeout, shutdownTimeout)
 	require.Equal(t, *config.RestartDelay, restartDelay)
+	require.Equal(t, *config.MaxRestartDelay, maxRestartDelay)
+}
+
+func TestRestartBackoff(t *testing.T) {
+	restartDelay := 100 * time.Millisecond
+	maxRestartDelay := time.Second
+	b := newRestartBackoff(&Config{RestartDelay: &restartDelay, MaxRestartDelay: &maxRestartDelay})
+
+	// The delay grows exponentially up to the max delay, with a 50% jitter.
+	first := b.NextBackOff()
+	require.True(t, first >= 50*time.Millisecond && first <= 150*time.Millisecond, "first delay %v", first)
+	for i := 0; i < 10; i++ {
+		require.LessOrEqual(t, int64(b.NextBackOff()), int64(maxRestartDelay*3/2))
+	}
+	require.GreaterOrEqual(t, int64(b.NextBackOff()), int64(maxRestartDelay/2))
+
+	b.Reset()
+	require.LessOrEqual(t, int64(b.NextBackOff()), int64(150*time.Millisecond))
+}
+
+func TestDefaultLogLevel(t *testing.T) {
+	require.Equal(t, zapcore.InfoLevel, DefaultLogLevel("starting", false))
+	require.Equal(t, zapcore.ErrorLevel, DefaultLogLevel("failed", true))
 }
 
 func TestShutdownTimeout(t *testing.T) {
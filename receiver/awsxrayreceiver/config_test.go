@@ -43,7 +43,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 3)
+	assert.Equal(t, len(cfg.Receivers), 4)
 
 	// ensure default configurations are generated when users provide
 	// nothing.
@@ -100,4 +100,22 @@ func TestLoadConfig(t *testing.T) {
 			},
 		},
 		r2)
+
+	// ensure sampling rules can be served from a local file
+	r3 := cfg.Receivers[config.NewIDWithName(awsxray.TypeStr, "local_sampling")].(*Config)
+	assert.Equal(t,
+		&Config{
+			ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName(awsxray.TypeStr, "local_sampling")),
+			NetAddr: confignet.NetAddr{
+				Endpoint:  "0.0.0.0:2000",
+				Transport: "udp",
+			},
+			ProxyServer: &proxy.Config{
+				TCPAddr: confignet.TCPAddr{
+					Endpoint: "0.0.0.0:2000",
+				},
+				SamplingRulesFile: "/etc/xray/sampling-rules.json",
+			},
+		},
+		r3)
 }
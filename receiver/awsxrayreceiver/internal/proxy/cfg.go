@@ -49,6 +49,12 @@ type Config struct {
 	// will be called or not. Set to `true` to skip EC2 instance
 	// metadata check.
 	LocalMode bool `mapstructure:"local_mode"`
+
+	// SamplingRulesFile is the path to a JSON file of sampling rules, in the
+	// format of the output of `aws xray get-sampling-rules`. If set, the local
+	// TCP server serves the sampling rules and targets itself instead of
+	// forwarding requests to the AWS X-Ray backend.
+	SamplingRulesFile string `mapstructure:"sampling_rules_file"`
 }
 
 func DefaultConfig() *Config {
@@ -0,0 +1,361 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRuleName = "Default"
+
+	// targetInterval is how often SDKs are told to report statistics and
+	// refresh their sampling targets.
+	targetInterval = 10 * time.Second
+	// reservoirQuotaTTL is longer than targetInterval so that quotas don't
+	// lapse between two polls of a client.
+	reservoirQuotaTTL = 2 * targetInterval
+	// clientTTL is how long a client counts towards the split of a rule's
+	// reservoir after its last report.
+	clientTTL = 3 * targetInterval
+
+	getSamplingRulesPath   = "/GetSamplingRules"
+	getSamplingTargetsPath = "/SamplingTargets"
+)
+
+// samplingRule mirrors the SamplingRule shape of the X-Ray API.
+type samplingRule struct {
+	RuleName      string            `json:"RuleName"`
+	RuleARN       string            `json:"RuleARN,omitempty"`
+	ResourceARN   string            `json:"ResourceARN"`
+	Priority      int64             `json:"Priority"`
+	FixedRate     float64           `json:"FixedRate"`
+	ReservoirSize int64             `json:"ReservoirSize"`
+	ServiceName   string            `json:"ServiceName"`
+	ServiceType   string            `json:"ServiceType"`
+	Host          string            `json:"Host"`
+	HTTPMethod    string            `json:"HTTPMethod"`
+	URLPath       string            `json:"URLPath"`
+	Version       int64             `json:"Version"`
+	Attributes    map[string]string `json:"Attributes,omitempty"`
+}
+
+// samplingRulesFile is the content of a local sampling rules file. It has the
+// same shape as the output of `aws xray get-sampling-rules`, so rules can be
+// exported from an AWS account as-is.
+type samplingRulesFile struct {
+	SamplingRuleRecords []struct {
+		SamplingRule samplingRule `json:"SamplingRule"`
+	} `json:"SamplingRuleRecords"`
+}
+
+type samplingRuleRecord struct {
+	SamplingRule samplingRule `json:"SamplingRule"`
+	CreatedAt    float64      `json:"CreatedAt"`
+	ModifiedAt   float64      `json:"ModifiedAt"`
+}
+
+type getSamplingRulesOutput struct {
+	SamplingRuleRecords []samplingRuleRecord `json:"SamplingRuleRecords"`
+}
+
+type samplingStatisticsDocument struct {
+	RuleName     string  `json:"RuleName"`
+	ClientID     string  `json:"ClientID"`
+	Timestamp    float64 `json:"Timestamp"`
+	RequestCount int64   `json:"RequestCount"`
+	SampledCount int64   `json:"SampledCount"`
+	BorrowCount  int64   `json:"BorrowCount"`
+}
+
+type getSamplingTargetsInput struct {
+	SamplingStatisticsDocuments []samplingStatisticsDocument `json:"SamplingStatisticsDocuments"`
+}
+
+type samplingTargetDocument struct {
+	RuleName          string  `json:"RuleName"`
+	FixedRate         float64 `json:"FixedRate"`
+	ReservoirQuota    int64   `json:"ReservoirQuota"`
+	ReservoirQuotaTTL float64 `json:"ReservoirQuotaTTL"`
+	Interval          int64   `json:"Interval"`
+}
+
+type unprocessedStatistics struct {
+	RuleName  string `json:"RuleName"`
+	ErrorCode string `json:"ErrorCode"`
+	Message   string `json:"Message"`
+}
+
+type getSamplingTargetsOutput struct {
+	SamplingTargetDocuments []samplingTargetDocument `json:"SamplingTargetDocuments"`
+	LastRuleModification    float64                  `json:"LastRuleModification"`
+	UnprocessedStatistics   []unprocessedStatistics  `json:"UnprocessedStatistics"`
+}
+
+// clientStats is what a rule remembers about a client to split its
+// reservoir.
+type clientStats struct {
+	lastSeen time.Time
+	// requestRate is the number of requests per second the client matched
+	// against the rule during its last reporting interval.
+	requestRate float64
+}
+
+type localRule struct {
+	samplingRule
+	clients map[string]*clientStats
+}
+
+// localSampler serves sampling rules loaded from a file and computes
+// sampling targets the way the X-Ray service does: every rule has a fixed
+// rate and a reservoir of traces per second, which is split between the
+// clients that recently reported matching requests, proportionally to their
+// request rates.
+type localSampler struct {
+	logger     *zap.Logger
+	modifiedAt time.Time
+	now        func() time.Time
+
+	mu    sync.Mutex
+	rules []*localRule
+}
+
+func newLocalSampler(path string, logger *zap.Logger) (*localSampler, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read sampling rules file: %w", err)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read sampling rules file: %w", err)
+	}
+
+	rules, err := parseSamplingRules(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sampling rules file %s: %w", path, err)
+	}
+
+	s := &localSampler{
+		logger:     logger,
+		modifiedAt: info.ModTime(),
+		now:        time.Now,
+	}
+	for _, r := range rules {
+		s.rules = append(s.rules, &localRule{
+			samplingRule: r,
+			clients:      map[string]*clientStats{},
+		})
+	}
+	return s, nil
+}
+
+func parseSamplingRules(content []byte) ([]samplingRule, error) {
+	var file samplingRulesFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+
+	var rules []samplingRule
+	names := map[string]bool{}
+	for _, record := range file.SamplingRuleRecords {
+		r := record.SamplingRule
+		if r.RuleName == "" {
+			return nil, errors.New("a rule has no RuleName")
+		}
+		if names[r.RuleName] {
+			return nil, fmt.Errorf("rule %q is defined more than once", r.RuleName)
+		}
+		names[r.RuleName] = true
+		if r.FixedRate < 0 || r.FixedRate > 1 {
+			return nil, fmt.Errorf("rule %q: FixedRate must be between 0 and 1", r.RuleName)
+		}
+		if r.ReservoirSize < 0 {
+			return nil, fmt.Errorf("rule %q: ReservoirSize must not be negative", r.RuleName)
+		}
+		if r.RuleName == defaultRuleName {
+			r.Priority = 10000
+		} else if r.Priority < 1 || r.Priority > 9999 {
+			return nil, fmt.Errorf("rule %q: Priority must be between 1 and 9999", r.RuleName)
+		}
+		setRuleDefaults(&r)
+		rules = append(rules, r)
+	}
+
+	// The X-Ray service always has a Default rule, and SDKs rely on it to
+	// match requests no other rule matches.
+	if !names[defaultRuleName] {
+		r := samplingRule{
+			RuleName:      defaultRuleName,
+			Priority:      10000,
+			FixedRate:     0.05,
+			ReservoirSize: 1,
+		}
+		setRuleDefaults(&r)
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// setRuleDefaults fills the matching fields SDKs expect to always be set.
+func setRuleDefaults(r *samplingRule) {
+	for _, field := range []*string{&r.ResourceARN, &r.ServiceName, &r.ServiceType, &r.Host, &r.HTTPMethod, &r.URLPath} {
+		if *field == "" {
+			*field = "*"
+		}
+	}
+	if r.Version == 0 {
+		r.Version = 1
+	}
+}
+
+func (s *localSampler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.logger.Debug("Received request on X-Ray receiver local sampling server", zap.String("URL", req.URL.String()))
+
+	if req.Method != http.MethodPost {
+		writeSamplingError(w, http.StatusMethodNotAllowed, "InvalidRequestException", "method not allowed")
+		return
+	}
+
+	switch req.URL.Path {
+	case getSamplingRulesPath:
+		writeSamplingResponse(w, s.getSamplingRules())
+	case getSamplingTargetsPath:
+		var input getSamplingTargetsInput
+		if err := json.NewDecoder(req.Body).Decode(&input); err != nil {
+			writeSamplingError(w, http.StatusBadRequest, "InvalidRequestException", err.Error())
+			return
+		}
+		writeSamplingResponse(w, s.getSamplingTargets(&input))
+	default:
+		writeSamplingError(w, http.StatusNotFound, "UnknownOperationException", "unknown operation "+req.URL.Path)
+	}
+}
+
+func (s *localSampler) getSamplingRules() *getSamplingRulesOutput {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := &getSamplingRulesOutput{
+		SamplingRuleRecords: make([]samplingRuleRecord, 0, len(s.rules)),
+	}
+	for _, r := range s.rules {
+		out.SamplingRuleRecords = append(out.SamplingRuleRecords, samplingRuleRecord{
+			SamplingRule: r.samplingRule,
+			CreatedAt:    toEpochSeconds(s.modifiedAt),
+			ModifiedAt:   toEpochSeconds(s.modifiedAt),
+		})
+	}
+	return out
+}
+
+func (s *localSampler) getSamplingTargets(input *getSamplingTargetsInput) *getSamplingTargetsOutput {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	out := &getSamplingTargetsOutput{
+		SamplingTargetDocuments: []samplingTargetDocument{},
+		LastRuleModification:    toEpochSeconds(s.modifiedAt),
+		UnprocessedStatistics:   []unprocessedStatistics{},
+	}
+
+	for _, doc := range input.SamplingStatisticsDocuments {
+		rule := s.findRule(doc.RuleName)
+		if rule == nil {
+			out.UnprocessedStatistics = append(out.UnprocessedStatistics, unprocessedStatistics{
+				RuleName:  doc.RuleName,
+				ErrorCode: "400",
+				Message:   "unknown rule",
+			})
+			continue
+		}
+
+		rule.clients[doc.ClientID] = &clientStats{
+			lastSeen:    now,
+			requestRate: float64(doc.RequestCount) / targetInterval.Seconds(),
+		}
+
+		out.SamplingTargetDocuments = append(out.SamplingTargetDocuments, samplingTargetDocument{
+			RuleName:          rule.RuleName,
+			FixedRate:         rule.FixedRate,
+			ReservoirQuota:    rule.reservoirQuota(doc.ClientID, now),
+			ReservoirQuotaTTL: toEpochSeconds(now.Add(reservoirQuotaTTL)),
+			Interval:          int64(targetInterval.Seconds()),
+		})
+	}
+	return out
+}
+
+func (s *localSampler) findRule(name string) *localRule {
+	for _, r := range s.rules {
+		if r.RuleName == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// reservoirQuota returns the share of the reservoir of the rule given to the
+// client. Quotas are rounded down so the clients never sample more than the
+// reservoir size in total; clients with no share rely on the fixed rate.
+func (r *localRule) reservoirQuota(clientID string, now time.Time) int64 {
+	var totalRate float64
+	for id, c := range r.clients {
+		if now.Sub(c.lastSeen) > clientTTL {
+			delete(r.clients, id)
+			continue
+		}
+		totalRate += c.requestRate
+	}
+
+	if totalRate == 0 {
+		return r.ReservoirSize / int64(len(r.clients))
+	}
+	share := r.clients[clientID].requestRate / totalRate
+	return int64(math.Floor(float64(r.ReservoirSize) * share))
+}
+
+func toEpochSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
+
+func writeSamplingResponse(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// writeSamplingError writes an error the way the X-Ray service does, so that
+// SDKs can surface it.
+func writeSamplingError(w http.ResponseWriter, status int, errorType string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Amzn-ErrorType", errorType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"__type":  errorType,
+		"message": message,
+	})
+}
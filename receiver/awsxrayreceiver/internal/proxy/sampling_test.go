@@ -0,0 +1,278 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+)
+
+func newTestSampler(t *testing.T, now *time.Time) *localSampler {
+	s, err := newLocalSampler(filepath.Join("testdata", "samplingrules.json"), zap.NewNop())
+	require.NoError(t, err)
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func postSampling(t *testing.T, handler http.Handler, path string, body string, out interface{}) *http.Response {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if out != nil {
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(out))
+	}
+	return rec.Result()
+}
+
+func TestLocalSamplingRules(t *testing.T) {
+	now := time.Unix(1621504800, 0)
+	s := newTestSampler(t, &now)
+
+	var out getSamplingRulesOutput
+	resp := postSampling(t, s, getSamplingRulesPath, `{"NextToken": null}`, &out)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.Len(t, out.SamplingRuleRecords, 2)
+	assert.Equal(t, samplingRule{
+		RuleName:      "checkout",
+		ResourceARN:   "*",
+		Priority:      1,
+		FixedRate:     0.5,
+		ReservoirSize: 10,
+		ServiceName:   "checkout-service",
+		ServiceType:   "*",
+		Host:          "*",
+		HTTPMethod:    "POST",
+		URLPath:       "/checkout/*",
+		Version:       1,
+	}, out.SamplingRuleRecords[0].SamplingRule)
+	assert.Equal(t, "Default", out.SamplingRuleRecords[1].SamplingRule.RuleName)
+	assert.Equal(t, int64(2), out.SamplingRuleRecords[1].SamplingRule.ReservoirSize)
+	assert.Equal(t, toEpochSeconds(s.modifiedAt), out.SamplingRuleRecords[0].ModifiedAt)
+}
+
+func TestLocalSamplingTargets(t *testing.T) {
+	now := time.Unix(1621504800, 0)
+	s := newTestSampler(t, &now)
+
+	// A single client gets the whole reservoir.
+	var out getSamplingTargetsOutput
+	postSampling(t, s, getSamplingTargetsPath, `{"SamplingStatisticsDocuments": [
+		{"RuleName": "checkout", "ClientID": "client-a", "Timestamp": 1621504800, "RequestCount": 30, "SampledCount": 12, "BorrowCount": 1},
+		{"RuleName": "unknown", "ClientID": "client-a", "Timestamp": 1621504800, "RequestCount": 1}
+	]}`, &out)
+
+	assert.Equal(t, []samplingTargetDocument{{
+		RuleName:          "checkout",
+		FixedRate:         0.5,
+		ReservoirQuota:    10,
+		ReservoirQuotaTTL: 1621504820,
+		Interval:          10,
+	}}, out.SamplingTargetDocuments)
+	assert.Equal(t, []unprocessedStatistics{{
+		RuleName:  "unknown",
+		ErrorCode: "400",
+		Message:   "unknown rule",
+	}}, out.UnprocessedStatistics)
+	assert.Equal(t, toEpochSeconds(s.modifiedAt), out.LastRuleModification)
+
+	// A second client with three times the traffic gets three quarters of
+	// the reservoir, rounded down.
+	now = now.Add(5 * time.Second)
+	out = getSamplingTargetsOutput{}
+	postSampling(t, s, getSamplingTargetsPath, `{"SamplingStatisticsDocuments": [
+		{"RuleName": "checkout", "ClientID": "client-b", "Timestamp": 1621504805, "RequestCount": 90}
+	]}`, &out)
+	require.Len(t, out.SamplingTargetDocuments, 1)
+	assert.Equal(t, int64(7), out.SamplingTargetDocuments[0].ReservoirQuota)
+
+	now = now.Add(5 * time.Second)
+	out = getSamplingTargetsOutput{}
+	postSampling(t, s, getSamplingTargetsPath, `{"SamplingStatisticsDocuments": [
+		{"RuleName": "checkout", "ClientID": "client-a", "Timestamp": 1621504810, "RequestCount": 30}
+	]}`, &out)
+	require.Len(t, out.SamplingTargetDocuments, 1)
+	assert.Equal(t, int64(2), out.SamplingTargetDocuments[0].ReservoirQuota)
+
+	// Once client-a stops reporting, client-b gets the whole reservoir back.
+	now = now.Add(clientTTL + time.Second)
+	out = getSamplingTargetsOutput{}
+	postSampling(t, s, getSamplingTargetsPath, `{"SamplingStatisticsDocuments": [
+		{"RuleName": "checkout", "ClientID": "client-b", "Timestamp": 1621504851, "RequestCount": 90}
+	]}`, &out)
+	require.Len(t, out.SamplingTargetDocuments, 1)
+	assert.Equal(t, int64(10), out.SamplingTargetDocuments[0].ReservoirQuota)
+}
+
+func TestLocalSamplingTargetsNoTraffic(t *testing.T) {
+	now := time.Unix(1621504800, 0)
+	s := newTestSampler(t, &now)
+
+	// Without any traffic, the reservoir is split evenly.
+	for _, client := range []string{"client-a", "client-b"} {
+		var out getSamplingTargetsOutput
+		postSampling(t, s, getSamplingTargetsPath, `{"SamplingStatisticsDocuments": [
+			{"RuleName": "Default", "ClientID": "`+client+`", "Timestamp": 1621504800, "RequestCount": 0}
+		]}`, &out)
+		require.Len(t, out.SamplingTargetDocuments, 1)
+		if client == "client-a" {
+			assert.Equal(t, int64(2), out.SamplingTargetDocuments[0].ReservoirQuota)
+		} else {
+			assert.Equal(t, int64(1), out.SamplingTargetDocuments[0].ReservoirQuota)
+		}
+	}
+}
+
+func TestLocalSamplingInvalidRequests(t *testing.T) {
+	now := time.Now()
+	s := newTestSampler(t, &now)
+
+	resp := postSampling(t, s, getSamplingTargetsPath, `not json`, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "InvalidRequestException", resp.Header.Get("X-Amzn-ErrorType"))
+
+	resp = postSampling(t, s, "/TraceSegments", `{}`, nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	req := httptest.NewRequest(http.MethodGet, getSamplingRulesPath, nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestParseSamplingRules(t *testing.T) {
+	rules, err := parseSamplingRules([]byte(`{"SamplingRuleRecords": []}`))
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, samplingRule{
+		RuleName:      "Default",
+		ResourceARN:   "*",
+		Priority:      10000,
+		FixedRate:     0.05,
+		ReservoirSize: 1,
+		ServiceName:   "*",
+		ServiceType:   "*",
+		Host:          "*",
+		HTTPMethod:    "*",
+		URLPath:       "*",
+		Version:       1,
+	}, rules[0])
+
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{
+			name:    "invalid json",
+			content: `{`,
+			errMsg:  "unexpected end of JSON input",
+		},
+		{
+			name:    "no name",
+			content: `{"SamplingRuleRecords": [{"SamplingRule": {"Priority": 1}}]}`,
+			errMsg:  "a rule has no RuleName",
+		},
+		{
+			name:    "duplicate",
+			content: `{"SamplingRuleRecords": [{"SamplingRule": {"RuleName": "a", "Priority": 1}}, {"SamplingRule": {"RuleName": "a", "Priority": 2}}]}`,
+			errMsg:  `rule "a" is defined more than once`,
+		},
+		{
+			name:    "fixed rate",
+			content: `{"SamplingRuleRecords": [{"SamplingRule": {"RuleName": "a", "Priority": 1, "FixedRate": 1.5}}]}`,
+			errMsg:  `rule "a": FixedRate must be between 0 and 1`,
+		},
+		{
+			name:    "reservoir size",
+			content: `{"SamplingRuleRecords": [{"SamplingRule": {"RuleName": "a", "Priority": 1, "ReservoirSize": -1}}]}`,
+			errMsg:  `rule "a": ReservoirSize must not be negative`,
+		},
+		{
+			name:    "priority",
+			content: `{"SamplingRuleRecords": [{"SamplingRule": {"RuleName": "a", "Priority": 10000}}]}`,
+			errMsg:  `rule "a": Priority must be between 1 and 9999`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSamplingRules([]byte(tt.content))
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestNewServerWithSamplingRulesFile(t *testing.T) {
+	logger, _ := logSetup()
+
+	// No AWS region or credentials are needed to serve local rules.
+	env := stashEnv()
+	defer restoreEnv(env)
+
+	cfg := DefaultConfig()
+	tcpAddr := testutil.GetAvailableLocalAddress(t)
+	cfg.TCPAddr.Endpoint = tcpAddr
+	cfg.SamplingRulesFile = filepath.Join("testdata", "samplingrules.json")
+	srv, err := NewServer(cfg, logger)
+	require.NoError(t, err)
+	go srv.ListenAndServe()
+	defer srv.Close()
+
+	var resp *http.Response
+	require.Eventually(t, func() bool {
+		resp, err = http.Post("http://"+tcpAddr+getSamplingRulesPath, "application/json", strings.NewReader(`{}`))
+		return err == nil
+	}, 10*time.Second, 5*time.Millisecond)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `"RuleName":"checkout"`)
+}
+
+func TestNewServerWithInvalidSamplingRulesFile(t *testing.T) {
+	logger, _ := logSetup()
+
+	dir, err := ioutil.TempDir("", "samplingrules")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := DefaultConfig()
+	cfg.TCPAddr.Endpoint = testutil.GetAvailableLocalAddress(t)
+
+	cfg.SamplingRulesFile = filepath.Join(dir, "missing.json")
+	_, err = NewServer(cfg, logger)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to read sampling rules file")
+
+	cfg.SamplingRulesFile = filepath.Join(dir, "invalid.json")
+	require.NoError(t, ioutil.WriteFile(cfg.SamplingRulesFile, []byte(`{`), 0600))
+	_, err = NewServer(cfg, logger)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid sampling rules file")
+}
@@ -45,12 +45,26 @@ type Server interface {
 }
 
 // NewServer returns a local TCP server that proxies requests to AWS
-// backend using the given credentials.
+// backend using the given credentials, or that serves sampling rules and
+// targets itself if a sampling rules file is configured.
 func NewServer(cfg *Config, logger *zap.Logger) (Server, error) {
 	_, err := net.ResolveTCPAddr("tcp", cfg.Endpoint)
 	if err != nil {
 		return nil, err
 	}
+
+	if cfg.SamplingRulesFile != "" {
+		sampler, err := newLocalSampler(cfg.SamplingRulesFile, logger)
+		if err != nil {
+			return nil, err
+		}
+		logger.Debug("Serving sampling rules from file", zap.String("file", cfg.SamplingRulesFile))
+		return &http.Server{
+			Addr:    cfg.Endpoint,
+			Handler: sampler,
+		}, nil
+	}
+
 	if cfg.ProxyAddress != "" {
 		logger.Debug("Using remote proxy", zap.String("address", cfg.ProxyAddress))
 	}
@@ -40,7 +40,7 @@ type Config struct {
 	confignet.NetAddr `mapstructure:",squash"`
 
 	// TCPIdleTimeout is the timout for idle TCP connections, it is ignored
-	// if transport being used is UDP.
+	// if transport being used is UDP. The "pickle" transport uses TCP.
 	TCPIdleTimeout time.Duration `mapstructure:"tcp_idle_timeout"`
 
 	// Parser specifies a parser and the respective configuration to be used
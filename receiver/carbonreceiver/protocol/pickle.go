@@ -0,0 +1,487 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Pickle opcodes, see https://github.com/python/cpython/blob/main/Lib/pickletools.py.
+// Only the opcodes needed to decode lists of tuples holding strings and
+// numbers are supported, any opcode that could build arbitrary Python objects
+// (GLOBAL, REDUCE, BUILD, etc) is rejected.
+const (
+	opMark           = '('
+	opStop           = '.'
+	opInt            = 'I'
+	opBinInt         = 'J'
+	opBinInt1        = 'K'
+	opBinInt2        = 'M'
+	opLong           = 'L'
+	opNone           = 'N'
+	opFloat          = 'F'
+	opBinFloat       = 'G'
+	opString         = 'S'
+	opBinString      = 'T'
+	opShortBinString = 'U'
+	opUnicode        = 'V'
+	opBinUnicode     = 'X'
+	opBinBytes       = 'B'
+	opShortBinBytes  = 'C'
+	opAppend         = 'a'
+	opAppends        = 'e'
+	opList           = 'l'
+	opEmptyList      = ']'
+	opTuple          = 't'
+	opEmptyTuple     = ')'
+	opGet            = 'g'
+	opBinGet         = 'h'
+	opLongBinGet     = 'j'
+	opPut            = 'p'
+	opBinPut         = 'q'
+	opLongBinPut     = 'r'
+	opProto          = 0x80
+	opTuple1         = 0x85
+	opTuple2         = 0x86
+	opTuple3         = 0x87
+	opNewTrue        = 0x88
+	opNewFalse       = 0x89
+	opLong1          = 0x8a
+	opLong4          = 0x8b
+	opShortBinUni    = 0x8c
+	opBinUnicode8    = 0x8d
+	opBinBytes8      = 0x8e
+	opMemoize        = 0x94
+	opFrame          = 0x95
+)
+
+var (
+	errPickleUnexpectedEnd = errors.New("unexpected end of pickle data")
+	errPickleStackUnderrun = errors.New("pickle stack underrun")
+	errPickleNoMark        = errors.New("pickle mark not found")
+)
+
+// pickleMark is pushed to the stack by the MARK opcode.
+type pickleMark struct{}
+
+// pickleList is a pointer so the memo and the stack share the same list when
+// items are appended to it.
+type pickleList struct {
+	items []interface{}
+}
+
+// pickleTuple is an immutable sequence of values.
+type pickleTuple []interface{}
+
+// unpickler is a minimal and safe decoder of pickled data, it only builds
+// strings, numbers, booleans, None, lists and tuples.
+type unpickler struct {
+	r     *bytes.Reader
+	stack []interface{}
+	memo  map[int]interface{}
+}
+
+// unpickle decodes the pickled data and returns the value left on the stack
+// by the STOP opcode.
+func unpickle(data []byte) (interface{}, error) {
+	u := &unpickler{
+		r:    bytes.NewReader(data),
+		memo: make(map[int]interface{}),
+	}
+	for {
+		op, err := u.r.ReadByte()
+		if err != nil {
+			return nil, errPickleUnexpectedEnd
+		}
+		if op == opStop {
+			v, err := u.pop()
+			if err != nil {
+				return nil, err
+			}
+			if _, isMark := v.(pickleMark); isMark {
+				return nil, errPickleStackUnderrun
+			}
+			return v, nil
+		}
+		if err := u.execute(op); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (u *unpickler) execute(op byte) error {
+	switch op {
+	case opProto:
+		_, err := u.readN(1)
+		return err
+	case opFrame:
+		// Frames only hint at the buffering of the data, nothing to do.
+		_, err := u.readN(8)
+		return err
+	case opMark:
+		u.push(pickleMark{})
+	case opNone:
+		u.push(nil)
+	case opNewTrue:
+		u.push(true)
+	case opNewFalse:
+		u.push(false)
+	case opInt:
+		return u.loadInt()
+	case opBinInt:
+		b, err := u.readN(4)
+		if err != nil {
+			return err
+		}
+		u.push(int64(int32(binary.LittleEndian.Uint32(b))))
+	case opBinInt1:
+		b, err := u.readN(1)
+		if err != nil {
+			return err
+		}
+		u.push(int64(b[0]))
+	case opBinInt2:
+		b, err := u.readN(2)
+		if err != nil {
+			return err
+		}
+		u.push(int64(binary.LittleEndian.Uint16(b)))
+	case opLong:
+		line, err := u.readLine()
+		if err != nil {
+			return err
+		}
+		v, err := strconv.ParseInt(strings.TrimSuffix(line, "L"), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid pickle long: %v", err)
+		}
+		u.push(v)
+	case opLong1:
+		b, err := u.readN(1)
+		if err != nil {
+			return err
+		}
+		return u.loadLong(int(b[0]))
+	case opLong4:
+		n, err := u.readLength(4)
+		if err != nil {
+			return err
+		}
+		return u.loadLong(n)
+	case opFloat:
+		line, err := u.readLine()
+		if err != nil {
+			return err
+		}
+		v, err := strconv.ParseFloat(line, 64)
+		if err != nil {
+			return fmt.Errorf("invalid pickle float: %v", err)
+		}
+		u.push(v)
+	case opBinFloat:
+		b, err := u.readN(8)
+		if err != nil {
+			return err
+		}
+		u.push(math.Float64frombits(binary.BigEndian.Uint64(b)))
+	case opString:
+		line, err := u.readLine()
+		if err != nil {
+			return err
+		}
+		v, err := unquotePickleString(line)
+		if err != nil {
+			return err
+		}
+		u.push(v)
+	case opUnicode:
+		line, err := u.readLine()
+		if err != nil {
+			return err
+		}
+		u.push(line)
+	case opShortBinString, opShortBinUni, opShortBinBytes:
+		return u.loadString(1)
+	case opBinString, opBinUnicode, opBinBytes:
+		return u.loadString(4)
+	case opBinUnicode8, opBinBytes8:
+		return u.loadString(8)
+	case opEmptyList:
+		u.push(&pickleList{})
+	case opList:
+		items, err := u.popMark()
+		if err != nil {
+			return err
+		}
+		u.push(&pickleList{items: items})
+	case opAppend:
+		v, err := u.pop()
+		if err != nil {
+			return err
+		}
+		return u.appendToList(v)
+	case opAppends:
+		items, err := u.popMark()
+		if err != nil {
+			return err
+		}
+		return u.appendToList(items...)
+	case opEmptyTuple:
+		u.push(pickleTuple{})
+	case opTuple:
+		items, err := u.popMark()
+		if err != nil {
+			return err
+		}
+		u.push(pickleTuple(items))
+	case opTuple1, opTuple2, opTuple3:
+		n := int(op-opTuple1) + 1
+		if len(u.stack) < n {
+			return errPickleStackUnderrun
+		}
+		items := make(pickleTuple, n)
+		copy(items, u.stack[len(u.stack)-n:])
+		u.stack = u.stack[:len(u.stack)-n]
+		u.push(items)
+	case opPut:
+		line, err := u.readLine()
+		if err != nil {
+			return err
+		}
+		idx, err := strconv.Atoi(line)
+		if err != nil {
+			return fmt.Errorf("invalid pickle memo index: %v", err)
+		}
+		return u.memoize(idx)
+	case opBinPut:
+		b, err := u.readN(1)
+		if err != nil {
+			return err
+		}
+		return u.memoize(int(b[0]))
+	case opLongBinPut:
+		idx, err := u.readLength(4)
+		if err != nil {
+			return err
+		}
+		return u.memoize(idx)
+	case opMemoize:
+		return u.memoize(len(u.memo))
+	case opGet:
+		line, err := u.readLine()
+		if err != nil {
+			return err
+		}
+		idx, err := strconv.Atoi(line)
+		if err != nil {
+			return fmt.Errorf("invalid pickle memo index: %v", err)
+		}
+		return u.loadMemo(idx)
+	case opBinGet:
+		b, err := u.readN(1)
+		if err != nil {
+			return err
+		}
+		return u.loadMemo(int(b[0]))
+	case opLongBinGet:
+		idx, err := u.readLength(4)
+		if err != nil {
+			return err
+		}
+		return u.loadMemo(idx)
+	default:
+		return fmt.Errorf("unsupported pickle opcode 0x%02x", op)
+	}
+	return nil
+}
+
+func (u *unpickler) push(v interface{}) {
+	u.stack = append(u.stack, v)
+}
+
+func (u *unpickler) pop() (interface{}, error) {
+	if len(u.stack) == 0 {
+		return nil, errPickleStackUnderrun
+	}
+	v := u.stack[len(u.stack)-1]
+	u.stack = u.stack[:len(u.stack)-1]
+	return v, nil
+}
+
+// popMark pops all the values pushed after the last mark, and the mark itself.
+func (u *unpickler) popMark() ([]interface{}, error) {
+	for i := len(u.stack) - 1; i >= 0; i-- {
+		if _, isMark := u.stack[i].(pickleMark); isMark {
+			items := make([]interface{}, len(u.stack)-i-1)
+			copy(items, u.stack[i+1:])
+			u.stack = u.stack[:i]
+			return items, nil
+		}
+	}
+	return nil, errPickleNoMark
+}
+
+func (u *unpickler) appendToList(items ...interface{}) error {
+	if len(u.stack) == 0 {
+		return errPickleStackUnderrun
+	}
+	list, ok := u.stack[len(u.stack)-1].(*pickleList)
+	if !ok {
+		return errors.New("pickle append target is not a list")
+	}
+	list.items = append(list.items, items...)
+	return nil
+}
+
+func (u *unpickler) memoize(idx int) error {
+	if len(u.stack) == 0 {
+		return errPickleStackUnderrun
+	}
+	u.memo[idx] = u.stack[len(u.stack)-1]
+	return nil
+}
+
+func (u *unpickler) loadMemo(idx int) error {
+	v, ok := u.memo[idx]
+	if !ok {
+		return fmt.Errorf("pickle memo index %d not found", idx)
+	}
+	u.push(v)
+	return nil
+}
+
+func (u *unpickler) loadInt() error {
+	line, err := u.readLine()
+	if err != nil {
+		return err
+	}
+	// Protocol 0 encodes booleans as INT opcodes.
+	switch line {
+	case "00":
+		u.push(false)
+		return nil
+	case "01":
+		u.push(true)
+		return nil
+	}
+	v, err := strconv.ParseInt(line, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid pickle int: %v", err)
+	}
+	u.push(v)
+	return nil
+}
+
+// loadLong decodes a little-endian two's complement integer of n bytes, only
+// values fitting in an int64 are supported.
+func (u *unpickler) loadLong(n int) error {
+	if n > 8 {
+		return fmt.Errorf("pickle long of %d bytes is too large", n)
+	}
+	b, err := u.readN(n)
+	if err != nil {
+		return err
+	}
+	var v uint64
+	for i := n - 1; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+	if n > 0 && n < 8 && b[n-1]&0x80 != 0 {
+		// Sign extension of negative values.
+		v |= math.MaxUint64 << (8 * uint(n))
+	}
+	u.push(int64(v))
+	return nil
+}
+
+func (u *unpickler) loadString(lengthSize int) error {
+	n, err := u.readLength(lengthSize)
+	if err != nil {
+		return err
+	}
+	b, err := u.readN(n)
+	if err != nil {
+		return err
+	}
+	u.push(string(b))
+	return nil
+}
+
+// readLength reads a little-endian unsigned length of the given size.
+func (u *unpickler) readLength(size int) (int, error) {
+	b, err := u.readN(size)
+	if err != nil {
+		return 0, err
+	}
+	var n uint64
+	for i := size - 1; i >= 0; i-- {
+		n = n<<8 | uint64(b[i])
+	}
+	// Anything larger than the data left is invalid, checking it here avoids
+	// allocating buffers for bogus lengths.
+	if n > uint64(u.r.Len()) {
+		return 0, errPickleUnexpectedEnd
+	}
+	return int(n), nil
+}
+
+func (u *unpickler) readN(n int) ([]byte, error) {
+	if n > u.r.Len() {
+		return nil, errPickleUnexpectedEnd
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(u.r, b); err != nil {
+		return nil, errPickleUnexpectedEnd
+	}
+	return b, nil
+}
+
+func (u *unpickler) readLine() (string, error) {
+	var sb strings.Builder
+	for {
+		c, err := u.r.ReadByte()
+		if err != nil {
+			return "", errPickleUnexpectedEnd
+		}
+		if c == '\n' {
+			return sb.String(), nil
+		}
+		sb.WriteByte(c)
+	}
+}
+
+// unquotePickleString removes the quotes of a protocol 0 STRING opcode
+// argument, which uses the Python repr of the string.
+func unquotePickleString(s string) (string, error) {
+	if len(s) < 2 || (s[0] != '\'' && s[0] != '"') || s[len(s)-1] != s[0] {
+		return "", fmt.Errorf("invalid pickle string %q", s)
+	}
+	if s[0] == '\'' {
+		// Go only accepts double quoted strings.
+		s = `"` + strings.ReplaceAll(strings.ReplaceAll(s[1:len(s)-1], `\'`, `'`), `"`, `\"`) + `"`
+	}
+	v, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid pickle string %q: %v", s, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MaxPickleBatchSize is the maximum size accepted for a pickled batch, it is
+// the same limit used by Carbon, see
+// https://github.com/graphite-project/carbon/blob/master/lib/carbon/protocols.py.
+const MaxPickleBatchSize = 1 << 20
+
+var errPickleNotASequence = errors.New("pickled batch is not a list of metrics")
+
+// DecodePickleBatch decodes a batch received via the Carbon pickle protocol,
+// see https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-pickle-protocol.
+// The batch is a pickled list of tuples in the following format:
+//
+// 	[(<metric_path>, (<metric_timestamp>, <metric_value>)), ...]
+//
+// The batch is returned as Carbon plaintext lines so they can be handled by
+// any Parser, in particular by the same path parsers used for the plaintext
+// protocol. Duplicate points, ie.: with the same path and timestamp, are
+// aggregated keeping the last value received, the same as Carbon does when
+// storing them.
+func DecodePickleBatch(data []byte) ([]string, error) {
+	if len(data) > MaxPickleBatchSize {
+		return nil, fmt.Errorf("pickled batch of %d bytes exceeds the maximum of %d bytes", len(data), MaxPickleBatchSize)
+	}
+
+	v, err := unpickle(data)
+	if err != nil {
+		return nil, err
+	}
+	items, ok := pickleSequence(v)
+	if !ok {
+		return nil, errPickleNotASequence
+	}
+
+	lines := make([]string, 0, len(items))
+	// Index of the line of each path and timestamp, used to aggregate
+	// duplicate points.
+	pointIdx := make(map[string]int, len(items))
+	for i, item := range items {
+		path, timestamp, value, err := decodePickledPoint(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pickled metric at index %d: %v", i, err)
+		}
+
+		key := path + " " + timestamp
+		line := path + " " + value + " " + timestamp
+		if idx, ok := pointIdx[key]; ok {
+			lines[idx] = line
+			continue
+		}
+		pointIdx[key] = len(lines)
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// decodePickledPoint decodes a (<metric_path>, (<metric_timestamp>, <metric_value>))
+// tuple to its textual representation on the plaintext protocol.
+func decodePickledPoint(item interface{}) (path, timestamp, value string, err error) {
+	metric, ok := pickleSequence(item)
+	if !ok || len(metric) != 2 {
+		return "", "", "", errors.New("expected (path, (timestamp, value)) tuple")
+	}
+	path, ok = metric[0].(string)
+	if !ok || path == "" || strings.ContainsAny(path, " \t\r\n") {
+		return "", "", "", fmt.Errorf("invalid metric path %v", metric[0])
+	}
+
+	point, ok := pickleSequence(metric[1])
+	if !ok || len(point) != 2 {
+		return "", "", "", fmt.Errorf("expected (timestamp, value) tuple for metric path %q", path)
+	}
+
+	switch ts := point[0].(type) {
+	case int64:
+		timestamp = strconv.FormatInt(ts, 10)
+	case float64:
+		// Carbon timestamps are in seconds, fractions are dropped.
+		if math.IsNaN(ts) || math.IsInf(ts, 0) || ts > math.MaxInt64 || ts < math.MinInt64 {
+			return "", "", "", fmt.Errorf("invalid timestamp %v for metric path %q", ts, path)
+		}
+		timestamp = strconv.FormatInt(int64(ts), 10)
+	case string:
+		timestamp = strings.TrimSpace(ts)
+	default:
+		return "", "", "", fmt.Errorf("invalid timestamp %v for metric path %q", point[0], path)
+	}
+
+	switch v := point[1].(type) {
+	case int64:
+		value = strconv.FormatInt(v, 10)
+	case float64:
+		value = strconv.FormatFloat(v, 'g', -1, 64)
+		if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+			// Keep the value as a double when it has no fractional part.
+			value += ".0"
+		}
+	case bool:
+		value = "0"
+		if v {
+			value = "1"
+		}
+	case string:
+		value = strings.TrimSpace(v)
+	default:
+		return "", "", "", fmt.Errorf("invalid value %v for metric path %q", point[1], path)
+	}
+	if timestamp == "" || value == "" || strings.ContainsAny(timestamp+value, " \t\r\n") {
+		return "", "", "", fmt.Errorf("invalid point for metric path %q", path)
+	}
+
+	return path, timestamp, value, nil
+}
+
+// pickleSequence returns the items of a pickled list or tuple.
+func pickleSequence(v interface{}) ([]interface{}, bool) {
+	switch s := v.(type) {
+	case *pickleList:
+		return s.items, true
+	case pickleTuple:
+		return s, true
+	}
+	return nil, false
+}
@@ -0,0 +1,186 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Batches pickled by Python with the different protocols for:
+//
+// 	[('tst.int', (1582230020, 1)),
+// 	 ('tst.dbl;k0=v0', (1582230020.5, 3.14)),
+// 	 ('tst.int', (1582230020, 2)),
+// 	 ('tst.big', (1582230021, 2**40))]
+var pickledBatches = map[string]string{
+	"protocol_0": "(lp0\n(Vtst.int\np1\n(I1582230020\nI1\ntp2\ntp3\na(Vtst.dbl;k0=v0\np4\n(F1582230020.5\nF3.14\ntp5\ntp6\na(g1\n(I1582230020\nI2\ntp7\ntp8\na(Vtst.big\np9\n(I1582230021\nL1099511627776L\ntp10\ntp11\na.",
+	"protocol_1": "]q\x00((X\x07\x00\x00\x00tst.intq\x01(J\x04\xeaN^K\x01tq\x02tq\x03(X\r\x00\x00\x00tst.dbl;k0=v0q\x04(GA\xd7\x93\xba\x81 \x00\x00G@\t\x1e\xb8Q\xeb\x85\x1ftq\x05tq\x06(h\x01(J\x04\xeaN^K\x02tq\x07tq\x08(X\x07\x00\x00\x00tst.bigq\t(J\x05\xeaN^L1099511627776L\ntq\ntq\x0be.",
+	"protocol_2": "\x80\x02]q\x00(X\x07\x00\x00\x00tst.intq\x01J\x04\xeaN^K\x01\x86q\x02\x86q\x03X\r\x00\x00\x00tst.dbl;k0=v0q\x04GA\xd7\x93\xba\x81 \x00\x00G@\t\x1e\xb8Q\xeb\x85\x1f\x86q\x05\x86q\x06h\x01J\x04\xeaN^K\x02\x86q\x07\x86q\x08X\x07\x00\x00\x00tst.bigq\tJ\x05\xeaN^\x8a\x06\x00\x00\x00\x00\x00\x01\x86q\n\x86q\x0be.",
+	"protocol_4": "\x80\x04\x95h\x00\x00\x00\x00\x00\x00\x00]\x94(\x8c\x07tst.int\x94J\x04\xeaN^K\x01\x86\x94\x86\x94\x8c\rtst.dbl;k0=v0\x94GA\xd7\x93\xba\x81 \x00\x00G@\t\x1e\xb8Q\xeb\x85\x1f\x86\x94\x86\x94h\x01J\x04\xeaN^K\x02\x86\x94\x86\x94\x8c\x07tst.big\x94J\x05\xeaN^\x8a\x06\x00\x00\x00\x00\x00\x01\x86\x94\x86\x94e.",
+}
+
+func TestDecodePickleBatch(t *testing.T) {
+	want := []string{
+		"tst.int 2 1582230020",
+		"tst.dbl;k0=v0 3.14 1582230020",
+		"tst.big 1099511627776 1582230021",
+	}
+	for name, batch := range pickledBatches {
+		t.Run(name, func(t *testing.T) {
+			got, err := DecodePickleBatch([]byte(batch))
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestDecodePickleBatch_Python2(t *testing.T) {
+	tests := []struct {
+		name  string
+		batch string
+		want  []string
+	}{
+		{
+			// pickle.dumps([('tst.str', (1582230020, 1.0))], protocol=0)
+			name:  "protocol_0",
+			batch: "(lp0\n(S'tst.str'\np1\n(I1582230020\nF1.0\ntp2\ntp3\na.",
+			want:  []string{"tst.str 1.0 1582230020"},
+		},
+		{
+			// pickle.dumps([('tst.str', (1582230020, -1))], protocol=2)
+			name:  "protocol_2",
+			batch: "\x80\x02]q\x00U\x07tst.strq\x01J\x04\xeaN^J\xff\xff\xff\xff\x86q\x02\x86q\x03a.",
+			want:  []string{"tst.str -1 1582230020"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodePickleBatch([]byte(tt.batch))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDecodePickleBatch_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		batch string
+	}{
+		{
+			name:  "empty",
+			batch: "",
+		},
+		{
+			name:  "no_stop",
+			batch: "\x80\x02]q\x00",
+		},
+		{
+			name:  "not_a_list",
+			batch: "\x80\x02K\x01.",
+		},
+		{
+			// pickle.dumps([(b'bytes.path', (1, -5))], protocol=2) from Python 3
+			// uses GLOBAL and REDUCE opcodes.
+			name:  "global_and_reduce",
+			batch: "\x80\x02]q\x00c_codecs\nencode\nq\x01X\n\x00\x00\x00bytes.pathq\x02X\x06\x00\x00\x00latin1q\x03\x86q\x04Rq\x05K\x01J\xfb\xff\xff\xff\x86q\x06\x86q\x07a.",
+		},
+		{
+			name:  "bogus_string_length",
+			batch: "\x80\x02]X\xff\xff\xff\x7fabc.",
+		},
+		{
+			name:  "missing_point",
+			batch: "\x80\x02]X\x03\x00\x00\x00abc\x85a.",
+		},
+		{
+			name:  "path_with_space",
+			batch: "\x80\x02]X\x03\x00\x00\x00a cK\x01K\x02\x86\x86a.",
+		},
+		{
+			name:  "none_value",
+			batch: "\x80\x02]X\x03\x00\x00\x00abcK\x01N\x86\x86a.",
+		},
+		{
+			name:  "stack_underrun",
+			batch: "\x80\x02\x86.",
+		},
+		{
+			name:  "append_without_list",
+			batch: "\x80\x02K\x01K\x02a.",
+		},
+		{
+			name:  "appends_without_mark",
+			batch: "\x80\x02]e.",
+		},
+		{
+			name:  "memo_not_found",
+			batch: "\x80\x02h\x01.",
+		},
+		{
+			name:  "long_too_large",
+			batch: "\x80\x02\x8a\x09\x00\x00\x00\x00\x00\x00\x00\x00\x01.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodePickleBatch([]byte(tt.batch))
+			assert.Error(t, err)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestDecodePickleBatch_TooLarge(t *testing.T) {
+	_, err := DecodePickleBatch(make([]byte, MaxPickleBatchSize+1))
+	assert.Error(t, err)
+}
+
+// TestDecodePickleBatch_Fuzz checks that malformed payloads, generated by
+// randomly mutating valid ones, never cause a panic.
+func TestDecodePickleBatch_Fuzz(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for name, batch := range pickledBatches {
+		for i := 0; i < 10000; i++ {
+			data := []byte(batch)
+			switch rnd.Intn(3) {
+			case 0:
+				// Truncate the payload.
+				data = data[:rnd.Intn(len(data))]
+			case 1:
+				// Flip some bytes.
+				for j := rnd.Intn(4); j >= 0; j-- {
+					data[rnd.Intn(len(data))] = byte(rnd.Intn(256))
+				}
+			case 2:
+				// Duplicate a slice of the payload in a random position.
+				start := rnd.Intn(len(data))
+				end := start + rnd.Intn(len(data)-start)
+				pos := rnd.Intn(len(data))
+				mutated := append([]byte{}, data[:pos]...)
+				mutated = append(mutated, data[start:end]...)
+				data = append(mutated, data[pos:]...)
+			}
+
+			require.NotPanics(t, func() {
+				_, _ = DecodePickleBatch(data)
+			}, "%s: payload %q", name, data)
+		}
+	}
+}
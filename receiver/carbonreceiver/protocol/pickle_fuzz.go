@@ -1,4 +1,4 @@
-// Copyright 2020, OpenTelemetry Authors
+// Copyright 2021, OpenTelemetry Authors
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -12,18 +12,15 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package main
+// +build gofuzz
 
-import (
-	"os"
-	"time"
-)
+package protocol
 
-const defaultSleepTime = 2 * time.Millisecond
-
-// This program is simply a test program that does nothing but crash after a certain time, with a non-zero exit code, used in
-// subprocessmanager tests
-func main() {
-	time.Sleep(defaultSleepTime)
-	os.Exit(2)
+// Fuzz is the entry point for https://github.com/dvyukov/go-fuzz, it can be
+// used to look for malformed pickled batches that are not properly handled.
+func Fuzz(data []byte) int {
+	if _, err := DecodePickleBatch(data); err != nil {
+		return 0
+	}
+	return 1
 }
@@ -34,8 +34,9 @@ var (
 	errEmptyEndpoint = errors.New("empty endpoint")
 )
 
-// carbonreceiver implements a component.MetricsReceiver for Carbon plaintext, aka "line", protocol.
-// see https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-plaintext-protocol.
+// carbonreceiver implements a component.MetricsReceiver for Carbon plaintext, aka "line", protocol,
+// and pickle protocol. See https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-plaintext-protocol
+// and https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-pickle-protocol.
 type carbonReceiver struct {
 	sync.Mutex
 	logger *zap.Logger
@@ -102,6 +103,8 @@ func buildTransportServer(config Config) (transport.Server, error) {
 		return transport.NewTCPServer(config.Endpoint, config.TCPIdleTimeout)
 	case "udp":
 		return transport.NewUDPServer(config.Endpoint)
+	case "pickle":
+		return transport.NewPickleServer(config.Endpoint, config.TCPIdleTimeout)
 	}
 
 	return nil, fmt.Errorf("unsupported transport %q for receiver %v", config.Transport, config.ID())
@@ -170,6 +170,7 @@ func Test_carbonreceiver_EndToEnd(t *testing.T) {
 		name     string
 		configFn func() *Config
 		clientFn func(t *testing.T) *client.Graphite
+		sendFn   func(c *client.Graphite, m client.Metric) error
 	}{
 		{
 			name: "default_config",
@@ -195,6 +196,22 @@ func Test_carbonreceiver_EndToEnd(t *testing.T) {
 				return c
 			},
 		},
+		{
+			name: "default_config_pickle",
+			configFn: func() *Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.Transport = "pickle"
+				return cfg
+			},
+			clientFn: func(t *testing.T) *client.Graphite {
+				c, err := client.NewGraphite(client.TCP, host, port)
+				require.NoError(t, err)
+				return c
+			},
+			sendFn: func(c *client.Graphite, m client.Metric) error {
+				return c.SendPickledMetrics([]client.Metric{m})
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -220,7 +237,11 @@ func Test_carbonreceiver_EndToEnd(t *testing.T) {
 				Value:     1.23,
 				Timestamp: ts,
 			}
-			err = snd.SendMetric(carbonMetric)
+			if tt.sendFn != nil {
+				err = tt.sendFn(snd, carbonMetric)
+			} else {
+				err = snd.SendMetric(carbonMetric)
+			}
 			require.NoError(t, err)
 
 			mr.WaitAllOnMetricsProcessedCalls()
@@ -0,0 +1,63 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// SendPickledMetrics sends the metrics as a single batch using the Carbon
+// pickle protocol, see https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-pickle-protocol.
+// The connection must use the TCP transport.
+func (g *Graphite) SendPickledMetrics(metrics []Metric) error {
+	batch := PickleMetrics(metrics)
+	msg := make([]byte, 4, 4+len(batch))
+	binary.BigEndian.PutUint32(msg, uint32(len(batch)))
+	msg = append(msg, batch...)
+	_, err := g.Conn.Write(msg)
+	return err
+}
+
+// PickleMetrics pickles the metrics the same way that Python does with
+// protocol 2 for a list of (path, (timestamp, value)) tuples.
+func PickleMetrics(metrics []Metric) []byte {
+	// PROTO 2, EMPTY_LIST and MARK.
+	b := []byte{0x80, 0x02, ']', '('}
+	for _, m := range metrics {
+		// BINUNICODE with the path.
+		b = append(b, 'X')
+		b = appendUint32(b, binary.LittleEndian, uint32(len(m.Name)))
+		b = append(b, m.Name...)
+		// BININT with the timestamp.
+		b = append(b, 'J')
+		b = appendUint32(b, binary.LittleEndian, uint32(m.Timestamp.Unix()))
+		// BINFLOAT with the value.
+		b = append(b, 'G')
+		var v [8]byte
+		binary.BigEndian.PutUint64(v[:], math.Float64bits(m.Value))
+		b = append(b, v[:]...)
+		// TUPLE2 for the point and TUPLE2 for the metric.
+		b = append(b, 0x86, 0x86)
+	}
+	// APPENDS and STOP.
+	return append(b, 'e', '.')
+}
+
+func appendUint32(b []byte, order binary.ByteOrder, v uint32) []byte {
+	var buf [4]byte
+	order.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
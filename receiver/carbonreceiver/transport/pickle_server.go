@@ -0,0 +1,136 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/translator/internaldata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+)
+
+// NewPickleServer creates a transport.Server for the Carbon pickle protocol,
+// see https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-pickle-protocol.
+// The protocol uses TCP as its transport and each message is a pickled batch
+// of metrics prefixed by its length as a 4 bytes unsigned big-endian integer.
+func NewPickleServer(
+	addr string,
+	idleTimeout time.Duration,
+) (Server, error) {
+	t, err := newTCPServer(addr, idleTimeout)
+	if err != nil {
+		return nil, err
+	}
+	t.connHandler = t.handlePickleConnection
+	return t, nil
+}
+
+func (t *tcpServer) handlePickleConnection(
+	p protocol.Parser,
+	nextConsumer consumer.Metrics,
+	conn net.Conn,
+) {
+	defer conn.Close()
+	var header [4]byte
+	for {
+		if err := conn.SetDeadline(time.Now().Add(t.idleTimeout)); err != nil {
+			t.reporter.OnDebugf(
+				"Pickle Transport (%s) - conn.SetDeadLine error: %v",
+				t.ln.Addr(),
+				err)
+			return
+		}
+
+		// Both reads below block until either all the data is read, the
+		// connection is closed or an idle timeout happens.
+		if _, err := io.ReadFull(conn, header[:]); err != nil {
+			if err != io.EOF {
+				t.reporter.OnDebugf(
+					"Pickle Transport (%s) - read error: %v",
+					t.ln.Addr(),
+					err)
+			}
+			return
+		}
+
+		size := binary.BigEndian.Uint32(header[:])
+		if size > protocol.MaxPickleBatchSize {
+			// There is no way to resynchronize with the client after a bogus
+			// length, the connection needs to be closed.
+			ctx := t.reporter.OnDataReceived(context.Background())
+			t.reporter.OnTranslationError(ctx, fmt.Errorf(
+				"pickled batch of %d bytes exceeds the maximum of %d bytes",
+				size,
+				protocol.MaxPickleBatchSize))
+			t.reporter.OnMetricsProcessed(ctx, 0, nil)
+			return
+		}
+
+		data := make([]byte, size)
+		if _, err := io.ReadFull(conn, data); err != nil {
+			t.reporter.OnDebugf(
+				"Pickle Transport (%s) - read error: %v",
+				t.ln.Addr(),
+				err)
+			return
+		}
+
+		if err := t.handlePickleBatch(p, nextConsumer, data); err != nil {
+			// The protocol doesn't account for returning errors, closing the
+			// connection is the way to report "error" back to the client, the
+			// same as for the plaintext protocol.
+			return
+		}
+	}
+}
+
+// handlePickleBatch parses and sends the batch to the next consumer. Only
+// errors from the next consumer are returned, translation errors are just
+// reported.
+func (t *tcpServer) handlePickleBatch(
+	p protocol.Parser,
+	nextConsumer consumer.Metrics,
+	data []byte,
+) error {
+	ctx := t.reporter.OnDataReceived(context.Background())
+	lines, err := protocol.DecodePickleBatch(data)
+	if err != nil {
+		t.reporter.OnTranslationError(ctx, err)
+		t.reporter.OnMetricsProcessed(ctx, 0, nil)
+		return nil
+	}
+
+	metrics := make([]*metricspb.Metric, 0, len(lines))
+	for _, line := range lines {
+		metric, err := p.Parse(line)
+		if err != nil {
+			t.reporter.OnTranslationError(ctx, err)
+			continue
+		}
+		metrics = append(metrics, metric)
+	}
+
+	err = nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(nil, nil, metrics))
+	t.reporter.OnMetricsProcessed(ctx, len(lines), err)
+	return err
+}
@@ -0,0 +1,140 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"encoding/binary"
+	"net"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.opentelemetry.io/collector/translator/internaldata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/transport/client"
+)
+
+func Test_PickleServer_ListenAndServe(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	svr, err := NewPickleServer(addr, 1*time.Second)
+	require.NoError(t, err)
+	require.NotNil(t, svr)
+
+	host, portStr, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	mc := new(consumertest.MetricsSink)
+	p, err := (&protocol.PlaintextConfig{}).BuildParser()
+	require.NoError(t, err)
+	// One call for the invalid batch and another for the valid one.
+	mr := NewMockReporter(2)
+
+	wgListenAndServe := sync.WaitGroup{}
+	wgListenAndServe.Add(1)
+	go func() {
+		defer wgListenAndServe.Done()
+		assert.Error(t, svr.ListenAndServe(p, mc, mr))
+	}()
+
+	runtime.Gosched()
+
+	gc, err := client.NewGraphite(client.TCP, host, port)
+	require.NoError(t, err)
+
+	// An invalid batch is dropped without closing the connection.
+	invalid := []byte{0, 0, 0, 1, '.'}
+	_, err = gc.Conn.Write(invalid)
+	require.NoError(t, err)
+
+	ts := time.Date(2020, 2, 20, 20, 20, 20, 20, time.UTC)
+	err = gc.SendPickledMetrics([]client.Metric{
+		{Name: "test.metric", Value: 1, Timestamp: ts},
+		{Name: "test.metric;k0=v0", Value: 2.5, Timestamp: ts},
+		{Name: "test.metric", Value: 3, Timestamp: ts},
+	})
+	assert.NoError(t, err)
+	runtime.Gosched()
+
+	mr.WaitAllOnMetricsProcessedCalls()
+
+	err = gc.Disconnect()
+	assert.NoError(t, err)
+
+	err = svr.Close()
+	assert.NoError(t, err)
+
+	wgListenAndServe.Wait()
+
+	mdd := mc.AllMetrics()
+	require.Len(t, mdd, 1)
+	_, _, metrics := internaldata.ResourceMetricsToOC(mdd[0].ResourceMetrics().At(0))
+	require.Len(t, metrics, 2)
+	assert.Equal(t, "test.metric", metrics[0].GetMetricDescriptor().GetName())
+	// Duplicate points keep the last value.
+	assert.Equal(t, 3.0, metrics[0].GetTimeseries()[0].GetPoints()[0].GetDoubleValue())
+	assert.Equal(t, "test.metric", metrics[1].GetMetricDescriptor().GetName())
+	assert.Equal(t, "k0", metrics[1].GetMetricDescriptor().GetLabelKeys()[0].GetKey())
+}
+
+func Test_PickleServer_BatchTooLarge(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	svr, err := NewPickleServer(addr, 1*time.Second)
+	require.NoError(t, err)
+
+	p, err := (&protocol.PlaintextConfig{}).BuildParser()
+	require.NoError(t, err)
+	mr := NewMockReporter(1)
+
+	wgListenAndServe := sync.WaitGroup{}
+	wgListenAndServe.Add(1)
+	go func() {
+		defer wgListenAndServe.Done()
+		assert.Error(t, svr.ListenAndServe(p, new(consumertest.MetricsSink), mr))
+	}()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], protocol.MaxPickleBatchSize+1)
+	_, err = conn.Write(header[:])
+	require.NoError(t, err)
+
+	mr.WaitAllOnMetricsProcessedCalls()
+
+	// The server closes the connection since it can't recover from a bogus
+	// length.
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Read(header[:])
+	assert.Error(t, err)
+	assert.False(t, isTimeout(err))
+
+	assert.NoError(t, svr.Close())
+	wgListenAndServe.Wait()
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
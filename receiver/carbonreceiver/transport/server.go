@@ -45,6 +45,17 @@ type Server interface {
 	Close() error
 }
 
+// LineConsumer can be implemented by a protocol.Parser for a line protocol
+// in which not every line is a metric, e.g. when spans are received on the
+// same connection. The TCP server then passes each line to ConsumeLine instead
+// of passing the result of Parse to the next consumer.
+type LineConsumer interface {
+	// ConsumeLine parses the line and passes the result to the consumer of
+	// its data type, reporting it as the Reporter would. Translation errors
+	// are not returned, an error from the next consumer closes the connection.
+	ConsumeLine(line string) error
+}
+
 // Reporter is used to report (via zPages, logs, metrics, etc) the events
 // happening when the Server is receiving and processing data.
 type Reporter interface {
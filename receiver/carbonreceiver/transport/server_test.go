@@ -111,3 +111,44 @@ func Test_Server_ListenAndServe(t *testing.T) {
 		})
 	}
 }
+
+// lineConsumerParser records the lines passed to ConsumeLine.
+type lineConsumerParser struct {
+	protocol.Parser
+	lines chan string
+}
+
+var _ LineConsumer = (*lineConsumerParser)(nil)
+
+func (p *lineConsumerParser) ConsumeLine(line string) error {
+	p.lines <- line
+	return nil
+}
+
+func Test_TCPServer_LineConsumer(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	svr, err := NewTCPServer(addr, 1*time.Second)
+	require.NoError(t, err)
+
+	p := &lineConsumerParser{lines: make(chan string, 2)}
+
+	wgListenAndServe := sync.WaitGroup{}
+	wgListenAndServe.Add(1)
+	go func() {
+		defer wgListenAndServe.Done()
+		// The line consumer doesn't need a next consumer.
+		assert.Error(t, svr.ListenAndServe(p, nil, NewMockReporter(0)))
+	}()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("first line\n\n  second line  \n"))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	assert.Equal(t, "first line", <-p.lines)
+	assert.Equal(t, "second line", <-p.lines)
+
+	require.NoError(t, svr.Close())
+	wgListenAndServe.Wait()
+}
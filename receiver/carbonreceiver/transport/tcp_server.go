@@ -42,6 +42,10 @@ type tcpServer struct {
 	wg          sync.WaitGroup
 	idleTimeout time.Duration
 	reporter    Reporter
+
+	// connHandler handles each accepted connection according to the protocol
+	// being served.
+	connHandler func(p protocol.Parser, nextConsumer consumer.Metrics, conn net.Conn)
 }
 
 var _ Server = (*tcpServer)(nil)
@@ -51,6 +55,18 @@ func NewTCPServer(
 	addr string,
 	idleTimeout time.Duration,
 ) (Server, error) {
+	t, err := newTCPServer(addr, idleTimeout)
+	if err != nil {
+		return nil, err
+	}
+	t.connHandler = t.handleConnection
+	return t, nil
+}
+
+func newTCPServer(
+	addr string,
+	idleTimeout time.Duration,
+) (*tcpServer, error) {
 	if idleTimeout < 0 {
 		return nil, fmt.Errorf("invalid idle timeout: %v", idleTimeout)
 	}
@@ -76,7 +92,9 @@ func (t *tcpServer) ListenAndServe(
 	nextConsumer consumer.Metrics,
 	reporter Reporter,
 ) error {
-	if parser == nil || nextConsumer == nil || reporter == nil {
+	// A LineConsumer passes the data to its own consumers.
+	_, isLineConsumer := parser.(LineConsumer)
+	if parser == nil || (nextConsumer == nil && !isLineConsumer) || reporter == nil {
 		return errNilListenAndServeParameters
 	}
 
@@ -93,7 +111,7 @@ func (t *tcpServer) ListenAndServe(
 			connMapMtx.Unlock()
 			t.wg.Add(1)
 			go func(c net.Conn) {
-				t.handleConnection(parser, nextConsumer, c)
+				t.connHandler(parser, nextConsumer, c)
 				connMapMtx.Lock()
 				delete(acceptedConnMap, c)
 				connMapMtx.Unlock()
@@ -172,21 +190,15 @@ func (t *tcpServer) handleConnection(
 		bytes, err := reader.ReadBytes((byte)('\n'))
 
 		// It is possible to have new data in bytes and err to be io.EOF
-		ctx := t.reporter.OnDataReceived(context.Background())
-		var numReceivedMetricPoints int
 		line := strings.TrimSpace(string(bytes))
 		if line != "" {
-			numReceivedMetricPoints++
-			var metric *metricspb.Metric
-			metric, err = p.Parse(line)
-			if err != nil {
-				t.reporter.OnTranslationError(ctx, err)
-				continue
+			var consumeErr error
+			if lc, ok := p.(LineConsumer); ok {
+				consumeErr = lc.ConsumeLine(line)
+			} else {
+				consumeErr = t.consumeLine(p, nextConsumer, line)
 			}
-
-			err = nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(nil, nil, []*metricspb.Metric{metric}))
-			t.reporter.OnMetricsProcessed(ctx, numReceivedMetricPoints, err)
-			if err != nil {
+			if consumeErr != nil {
 				// The protocol doesn't account for returning errors.
 				// Since this is a TCP connection it seems reasonable to close the
 				// connection as a way to report "error" back to client and minimize
@@ -218,3 +230,22 @@ func (t *tcpServer) handleConnection(
 		}
 	}
 }
+
+// consumeLine parses line and passes the metric to nextConsumer. Translation
+// errors are only reported, the error of nextConsumer is returned.
+func (t *tcpServer) consumeLine(
+	p protocol.Parser,
+	nextConsumer consumer.Metrics,
+	line string,
+) error {
+	ctx := t.reporter.OnDataReceived(context.Background())
+	metric, err := p.Parse(line)
+	if err != nil {
+		t.reporter.OnTranslationError(ctx, err)
+		return nil
+	}
+
+	err = nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(nil, nil, []*metricspb.Metric{metric}))
+	t.reporter.OnMetricsProcessed(ctx, 1, err)
+	return err
+}
@@ -0,0 +1,426 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectdreceiver
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha1" // #nosec G505 -- SHA-1 is mandated by the collectd network protocol.
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Part types of the collectd binary network protocol, see
+// https://github.com/collectd/collectd/wiki/Binary-protocol.
+const (
+	partTypeHost           = 0x0000
+	partTypeTime           = 0x0001
+	partTypePlugin         = 0x0002
+	partTypePluginInstance = 0x0003
+	partTypeType           = 0x0004
+	partTypeTypeInstance   = 0x0005
+	partTypeValues         = 0x0006
+	partTypeInterval       = 0x0007
+	partTypeTimeHR         = 0x0008
+	partTypeIntervalHR     = 0x0009
+	partTypeMessage        = 0x0100
+	partTypeSeverity       = 0x0101
+	partTypeSignature      = 0x0200
+	partTypeEncryption     = 0x0210
+
+	partHeaderLength = 4
+)
+
+// Data source types of the values part.
+const (
+	dsTypeCounter  = 0
+	dsTypeGauge    = 1
+	dsTypeDerive   = 2
+	dsTypeAbsolute = 3
+)
+
+var dsTypeNames = map[byte]string{
+	dsTypeCounter:  collectDMetricCounter,
+	dsTypeGauge:    collectDMetricGauge,
+	dsTypeDerive:   collectDMetricDerive,
+	dsTypeAbsolute: collectDMetricAbsolute,
+}
+
+// Security levels of the collectd network plugin, see
+// https://collectd.org/documentation/manpages/collectd.conf.5.shtml#plugin_network.
+const (
+	securityLevelNone    = "none"
+	securityLevelSign    = "sign"
+	securityLevelEncrypt = "encrypt"
+)
+
+// typesDataSources has the data source names of the multi-value types of the
+// default collectd types.db. The binary protocol doesn't carry the data source
+// names, values of types not listed here are named by their index.
+var typesDataSources = map[string][]string{
+	"load":           {"shortterm", "midterm", "longterm"},
+	"if_octets":      {"rx", "tx"},
+	"if_packets":     {"rx", "tx"},
+	"if_errors":      {"rx", "tx"},
+	"if_dropped":     {"rx", "tx"},
+	"io_octets":      {"rx", "tx"},
+	"io_packets":     {"rx", "tx"},
+	"node_octets":    {"rx", "tx"},
+	"disk_octets":    {"read", "write"},
+	"disk_ops":       {"read", "write"},
+	"disk_time":      {"read", "write"},
+	"disk_merged":    {"read", "write"},
+	"disk_io_time":   {"io_time", "weighted_io_time"},
+	"ps_count":       {"processes", "threads"},
+	"ps_cputime":     {"user", "syst"},
+	"ps_disk_octets": {"read", "write"},
+	"ps_disk_ops":    {"read", "write"},
+	"ps_pagefaults":  {"minflt", "majflt"},
+}
+
+var (
+	errPartTooShort         = errors.New("collectd part shorter than its header")
+	errPartTruncated        = errors.New("collectd part longer than the packet")
+	errUnsignedData         = errors.New("collectd packet is not signed or encrypted as required by the security level")
+	errUnencryptedData      = errors.New("collectd packet is not encrypted as required by the security level")
+	errInvalidSignature     = errors.New("collectd packet signature is invalid")
+	errInvalidEncryption    = errors.New("collectd packet could not be decrypted")
+	errValuesTypesMismatch  = errors.New("collectd values part has an invalid number of values")
+	errStringNotTerminated  = errors.New("collectd string part is not null terminated")
+	errNumericPartMalformed = errors.New("collectd numeric part must have 8 bytes")
+)
+
+// binaryParser decodes packets of the collectd binary network protocol into
+// the same records used for the JSON format, so both are converted to metrics
+// in the same way.
+type binaryParser struct {
+	securityLevel string
+	// passwords has the password of each user from the auth file.
+	passwords map[string]string
+}
+
+// packetState holds the values set by the parts of a packet. Each values part
+// uses the values set by the parts before it.
+type packetState struct {
+	host           string
+	plugin         string
+	pluginInstance string
+	typeS          string
+	typeInstance   string
+	time           float64
+	interval       float64
+}
+
+// parse decodes a packet and returns the records for each values part, the
+// number of notifications found is also returned.
+func (p *binaryParser) parse(packet []byte) ([]collectDRecord, int, error) {
+	var records []collectDRecord
+	var notifications int
+	state := &packetState{}
+	if err := p.parseParts(packet, state, false, false, &records, &notifications); err != nil {
+		return nil, 0, err
+	}
+	return records, notifications, nil
+}
+
+// parseParts decodes the parts of the packet. signed and encrypted tell if
+// the data was verified by a signature part or decrypted from an encryption
+// part.
+func (p *binaryParser) parseParts(
+	data []byte,
+	state *packetState,
+	signed bool,
+	encrypted bool,
+	records *[]collectDRecord,
+	notifications *int,
+) error {
+	for len(data) > 0 {
+		if len(data) < partHeaderLength {
+			return errPartTooShort
+		}
+		partType := binary.BigEndian.Uint16(data[0:2])
+		partLength := int(binary.BigEndian.Uint16(data[2:4]))
+		if partLength < partHeaderLength {
+			return errPartTooShort
+		}
+		if partLength > len(data) {
+			return errPartTruncated
+		}
+		payload := data[partHeaderLength:partLength]
+
+		switch partType {
+		case partTypeSignature:
+			// The signature covers the rest of the packet.
+			verified, err := p.verifySignature(payload, data[partLength:])
+			if err != nil {
+				return err
+			}
+			return p.parseParts(data[partLength:], state, signed || verified, encrypted, records, notifications)
+		case partTypeEncryption:
+			// The encrypted data is the rest of the part, any data after it
+			// is ignored as collectd does.
+			plaintext, err := p.decrypt(payload)
+			if err != nil {
+				return err
+			}
+			if plaintext == nil {
+				// Unknown user when encryption is not required.
+				return nil
+			}
+			return p.parseParts(plaintext, state, true, true, records, notifications)
+		}
+
+		if err := p.checkSecurityLevel(signed, encrypted); err != nil {
+			return err
+		}
+
+		var err error
+		switch partType {
+		case partTypeHost:
+			state.host, err = parseStringPart(payload)
+		case partTypePlugin:
+			state.plugin, err = parseStringPart(payload)
+		case partTypePluginInstance:
+			state.pluginInstance, err = parseStringPart(payload)
+		case partTypeType:
+			state.typeS, err = parseStringPart(payload)
+		case partTypeTypeInstance:
+			state.typeInstance, err = parseStringPart(payload)
+		case partTypeTime, partTypeInterval:
+			var v uint64
+			if v, err = parseNumericPart(payload); err == nil {
+				if partType == partTypeTime {
+					state.time = float64(v)
+				} else {
+					state.interval = float64(v)
+				}
+			}
+		case partTypeTimeHR, partTypeIntervalHR:
+			var v uint64
+			if v, err = parseNumericPart(payload); err == nil {
+				if partType == partTypeTimeHR {
+					state.time = highResolutionSeconds(v)
+				} else {
+					state.interval = highResolutionSeconds(v)
+				}
+			}
+		case partTypeValues:
+			var record collectDRecord
+			if record, err = state.parseValues(payload); err == nil {
+				*records = append(*records, record)
+			}
+		case partTypeMessage:
+			// Notifications are not converted to metrics, as for the JSON
+			// format.
+			*notifications++
+		default:
+			// Unknown parts, and the severity part, are skipped as collectd
+			// does.
+		}
+		if err != nil {
+			return fmt.Errorf("invalid collectd part 0x%04x: %v", partType, err)
+		}
+
+		data = data[partLength:]
+	}
+	return nil
+}
+
+func (p *binaryParser) checkSecurityLevel(signed, encrypted bool) error {
+	switch p.securityLevel {
+	case securityLevelSign:
+		if !signed {
+			return errUnsignedData
+		}
+	case securityLevelEncrypt:
+		if !encrypted {
+			return errUnencryptedData
+		}
+	}
+	return nil
+}
+
+// verifySignature checks the HMAC-SHA256 signature of the data, the payload
+// of the signature part has the 32 bytes signature followed by the user name.
+// It returns false, without error, if the signature can't be verified because
+// the user is unknown and signatures are not required.
+func (p *binaryParser) verifySignature(payload []byte, data []byte) (bool, error) {
+	if len(payload) <= sha256.Size {
+		return false, errInvalidSignature
+	}
+	signature, username := payload[:sha256.Size], payload[sha256.Size:]
+	password, ok := p.passwords[string(username)]
+	if !ok {
+		if p.securityLevel == securityLevelNone || p.securityLevel == "" {
+			return false, nil
+		}
+		return false, fmt.Errorf("unknown collectd user %q", username)
+	}
+
+	mac := hmac.New(sha256.New, []byte(password))
+	mac.Write(username)
+	mac.Write(data)
+	if !hmac.Equal(mac.Sum(nil), signature) {
+		return false, errInvalidSignature
+	}
+	return true, nil
+}
+
+// decrypt decrypts the payload of an encryption part. The payload has the
+// user name length, the user name, a 16 bytes initialization vector, and the
+// data encrypted with AES-256 in OFB mode using the SHA-256 of the password as
+// key. The decrypted data starts with the SHA-1 of the rest of the data.
+// It returns nil, without error, if the data can't be decrypted because the
+// user is unknown and encryption is not required.
+func (p *binaryParser) decrypt(payload []byte) ([]byte, error) {
+	if len(payload) < 2 {
+		return nil, errInvalidEncryption
+	}
+	usernameLength := int(binary.BigEndian.Uint16(payload[0:2]))
+	payload = payload[2:]
+	if len(payload) < usernameLength+aes.BlockSize+sha1.Size {
+		return nil, errInvalidEncryption
+	}
+	username := string(payload[:usernameLength])
+	iv := payload[usernameLength : usernameLength+aes.BlockSize]
+	encrypted := payload[usernameLength+aes.BlockSize:]
+
+	password, ok := p.passwords[username]
+	if !ok {
+		if p.securityLevel == securityLevelNone || p.securityLevel == "" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unknown collectd user %q", username)
+	}
+
+	key := sha256.Sum256([]byte(password))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(encrypted))
+	cipher.NewOFB(block, iv).XORKeyStream(plaintext, encrypted)
+
+	checksum, data := plaintext[:sha1.Size], plaintext[sha1.Size:]
+	sum := sha1.Sum(data) // #nosec G401 -- SHA-1 is mandated by the collectd network protocol.
+	if !hmac.Equal(checksum, sum[:]) {
+		return nil, errInvalidEncryption
+	}
+	return data, nil
+}
+
+// parseValues decodes a values part: the number of values, the data source
+// type of each value and then the values.
+func (s *packetState) parseValues(payload []byte) (collectDRecord, error) {
+	if len(payload) < 2 {
+		return collectDRecord{}, errValuesTypesMismatch
+	}
+	count := int(binary.BigEndian.Uint16(payload[0:2]))
+	payload = payload[2:]
+	if len(payload) != count*9 {
+		return collectDRecord{}, errValuesTypesMismatch
+	}
+	dsTypes, values := payload[:count], payload[count:]
+
+	record := collectDRecord{
+		Host:           stringPtr(s.host),
+		Plugin:         stringPtr(s.plugin),
+		PluginInstance: stringPtr(s.pluginInstance),
+		TypeS:          stringPtr(s.typeS),
+		TypeInstance:   stringPtr(s.typeInstance),
+		Time:           float64Ptr(s.time),
+		Interval:       float64Ptr(s.interval),
+		Dsnames:        make([]*string, count),
+		Dstypes:        make([]*string, count),
+		Values:         make([]*json.Number, count),
+	}
+
+	dsNames := typesDataSources[s.typeS]
+	for i := 0; i < count; i++ {
+		dsName := "value"
+		switch {
+		case len(dsNames) == count:
+			dsName = dsNames[i]
+		case count > 1:
+			dsName = strconv.Itoa(i)
+		}
+		record.Dsnames[i] = stringPtr(dsName)
+
+		dsType, ok := dsTypeNames[dsTypes[i]]
+		if !ok {
+			return collectDRecord{}, fmt.Errorf("unknown data source type %d", dsTypes[i])
+		}
+		record.Dstypes[i] = stringPtr(dsType)
+
+		raw := values[i*8 : (i+1)*8]
+		var number string
+		switch dsTypes[i] {
+		case dsTypeCounter, dsTypeAbsolute:
+			number = strconv.FormatUint(binary.BigEndian.Uint64(raw), 10)
+		case dsTypeDerive:
+			number = strconv.FormatInt(int64(binary.BigEndian.Uint64(raw)), 10)
+		case dsTypeGauge:
+			// Gauges are the only values in little endian.
+			v := math.Float64frombits(binary.LittleEndian.Uint64(raw))
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				// Unknown values are skipped.
+				continue
+			}
+			number = strconv.FormatFloat(v, 'f', -1, 64)
+			if _, err := strconv.ParseInt(number, 10, 64); err == nil {
+				// Keep gauges as doubles even without fractional part.
+				number += ".0"
+			}
+		}
+		n := json.Number(number)
+		record.Values[i] = &n
+	}
+	return record, nil
+}
+
+func parseStringPart(payload []byte) (string, error) {
+	if len(payload) == 0 || payload[len(payload)-1] != 0 {
+		return "", errStringNotTerminated
+	}
+	return string(bytes.TrimRight(payload, "\x00")), nil
+}
+
+func parseNumericPart(payload []byte) (uint64, error) {
+	if len(payload) != 8 {
+		return 0, errNumericPartMalformed
+	}
+	return binary.BigEndian.Uint64(payload), nil
+}
+
+// highResolutionSeconds converts the collectd high resolution time, in units
+// of 2^-30 seconds, to seconds.
+func highResolutionSeconds(v uint64) float64 {
+	return float64(v) / (1 << 30)
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,208 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectdreceiver
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+// maxPacketSize is the largest UDP payload, collectd packets are much smaller
+// by default.
+const maxPacketSize = 65535
+
+var _ component.MetricsReceiver = (*collectdBinaryReceiver)(nil)
+
+// collectdBinaryReceiver implements the component.MetricsReceiver for the
+// binary protocol of the CollectD network plugin.
+type collectdBinaryReceiver struct {
+	sync.Mutex
+	logger       *zap.Logger
+	addr         string
+	authFile     string
+	parser       *binaryParser
+	nextConsumer consumer.Metrics
+
+	conn net.PacketConn
+	wg   sync.WaitGroup
+}
+
+// newCollectdBinaryReceiver creates the CollectD binary protocol receiver with
+// the given parameters.
+func newCollectdBinaryReceiver(
+	logger *zap.Logger,
+	addr string,
+	securityLevel string,
+	authFile string,
+	nextConsumer consumer.Metrics) (component.MetricsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	securityLevel = strings.ToLower(securityLevel)
+	switch securityLevel {
+	case "", securityLevelNone:
+	case securityLevelSign, securityLevelEncrypt:
+		if authFile == "" {
+			return nil, fmt.Errorf("CollectD security level %q requires an auth file", securityLevel)
+		}
+	default:
+		return nil, fmt.Errorf(
+			"CollectD security level %q is not supported, must be one of %q, %q or %q",
+			securityLevel, securityLevelNone, securityLevelSign, securityLevelEncrypt)
+	}
+
+	return &collectdBinaryReceiver{
+		logger:       logger,
+		addr:         addr,
+		authFile:     authFile,
+		parser:       &binaryParser{securityLevel: securityLevel},
+		nextConsumer: nextConsumer,
+	}, nil
+}
+
+// Start starts an UDP server that can process CollectD binary packets.
+func (cdr *collectdBinaryReceiver) Start(_ context.Context, host component.Host) error {
+	cdr.Lock()
+	defer cdr.Unlock()
+
+	if cdr.authFile != "" {
+		passwords, err := readAuthFile(cdr.authFile)
+		if err != nil {
+			return err
+		}
+		cdr.parser.passwords = passwords
+	}
+
+	conn, err := net.ListenPacket("udp", cdr.addr)
+	if err != nil {
+		return fmt.Errorf("error starting collectd receiver: %v", err)
+	}
+	cdr.conn = conn
+
+	cdr.wg.Add(1)
+	go func() {
+		defer cdr.wg.Done()
+		cdr.serve(host)
+	}()
+	return nil
+}
+
+// Shutdown stops the CollectD receiver.
+func (cdr *collectdBinaryReceiver) Shutdown(context.Context) error {
+	cdr.Lock()
+	defer cdr.Unlock()
+
+	if cdr.conn == nil {
+		return nil
+	}
+	err := cdr.conn.Close()
+	cdr.wg.Wait()
+	return err
+}
+
+func (cdr *collectdBinaryReceiver) serve(host component.Host) {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, _, err := cdr.conn.ReadFrom(buf)
+		if n > 0 {
+			cdr.handlePacket(buf[:n])
+		}
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+				continue
+			}
+			if !strings.Contains(err.Error(), "use of closed network connection") {
+				host.ReportFatalError(fmt.Errorf("error reading collectd packets: %v", err))
+			}
+			return
+		}
+	}
+}
+
+func (cdr *collectdBinaryReceiver) handlePacket(packet []byte) {
+	recordRequestReceived()
+
+	records, notifications, err := cdr.parser.parse(packet)
+	if err != nil {
+		recordRequestErrors()
+		cdr.logger.Debug("unable to decode collectd packet", zap.Error(err))
+		return
+	}
+	for i := 0; i < notifications; i++ {
+		recordEventsReceived()
+	}
+	if len(records) == 0 {
+		return
+	}
+
+	var metrics []*metricspb.Metric
+	for _, record := range records {
+		metrics, err = record.appendToMetrics(metrics, nil)
+		if err != nil {
+			recordRequestErrors()
+			cdr.logger.Debug("unable to process metrics", zap.Error(err))
+			return
+		}
+	}
+
+	err = cdr.nextConsumer.ConsumeMetrics(context.Background(), internaldata.OCToMetrics(nil, nil, metrics))
+	if err != nil {
+		recordRequestErrors()
+		cdr.logger.Debug("unable to process metrics", zap.Error(err))
+	}
+}
+
+// readAuthFile reads the users and passwords from the auth file, it uses the
+// same format as the AuthFile of the CollectD network plugin:
+//
+// 	<user>: <password>
+func readAuthFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open collectd auth file: %v", err)
+	}
+	defer f.Close()
+
+	passwords := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		idx := strings.IndexByte(entry, ':')
+		if idx < 1 {
+			return nil, fmt.Errorf("invalid collectd auth file entry on line %d", line)
+		}
+		passwords[strings.TrimSpace(entry[:idx])] = strings.TrimSpace(entry[idx+1:])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read collectd auth file: %v", err)
+	}
+	return passwords, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectdreceiver
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+)
+
+func TestNewBinaryReceiver(t *testing.T) {
+	_, err := newCollectdBinaryReceiver(zap.NewNop(), "localhost:0", "", "", nil)
+	assert.Error(t, err)
+
+	_, err = newCollectdBinaryReceiver(zap.NewNop(), "localhost:0", "sign", "", consumertest.NewNop())
+	assert.Error(t, err)
+
+	_, err = newCollectdBinaryReceiver(zap.NewNop(), "localhost:0", "unknown", "", consumertest.NewNop())
+	assert.Error(t, err)
+
+	r, err := newCollectdBinaryReceiver(zap.NewNop(), "localhost:0", "Encrypt", "passwd", consumertest.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, securityLevelEncrypt, r.(*collectdBinaryReceiver).parser.securityLevel)
+}
+
+func TestCollectDBinaryServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "collectd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	authFile := filepath.Join(dir, "passwd")
+	require.NoError(t, ioutil.WriteFile(authFile, []byte("# collectd users\nuser: secret\n"), 0600))
+
+	addr := testutil.GetAvailableLocalAddress(t)
+	sink := new(consumertest.MetricsSink)
+	r, err := newCollectdBinaryReceiver(zap.NewNop(), addr, securityLevelSign, authFile, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, r.Shutdown(context.Background()))
+	}()
+
+	conn, err := net.Dial("udp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The unsigned packet is dropped.
+	_, err = conn.Write(testPacket())
+	require.NoError(t, err)
+	_, err = conn.Write(signed(testPacket(), "user", "secret"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	// 2 load, 1 df, 2 if_octets and 2 custom metrics.
+	assert.Equal(t, 7, sink.MetricsCount())
+}
+
+func TestReadAuthFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "collectd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	authFile := filepath.Join(dir, "passwd")
+	require.NoError(t, ioutil.WriteFile(authFile, []byte("user0: secret0\n\n# comment\nuser1:secret:1\n"), 0600))
+	passwords, err := readAuthFile(authFile)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"user0": "secret0", "user1": "secret:1"}, passwords)
+
+	require.NoError(t, ioutil.WriteFile(authFile, []byte("no separator\n"), 0600))
+	_, err = readAuthFile(authFile)
+	assert.Error(t, err)
+
+	_, err = readAuthFile(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,335 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectdreceiver
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha1" // #nosec G505 -- SHA-1 is mandated by the collectd network protocol.
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// packetBuilder builds packets of the collectd binary network protocol the
+// same way that the network plugin does.
+type packetBuilder struct {
+	data []byte
+}
+
+func (b *packetBuilder) part(partType uint16, payload []byte) *packetBuilder {
+	header := make([]byte, partHeaderLength)
+	binary.BigEndian.PutUint16(header[0:2], partType)
+	binary.BigEndian.PutUint16(header[2:4], uint16(partHeaderLength+len(payload)))
+	b.data = append(b.data, header...)
+	b.data = append(b.data, payload...)
+	return b
+}
+
+func (b *packetBuilder) str(partType uint16, s string) *packetBuilder {
+	return b.part(partType, append([]byte(s), 0))
+}
+
+func (b *packetBuilder) number(partType uint16, v uint64) *packetBuilder {
+	payload := make([]byte, 8)
+	binary.BigEndian.PutUint64(payload, v)
+	return b.part(partType, payload)
+}
+
+type testValue struct {
+	dsType byte
+	value  float64
+}
+
+func (b *packetBuilder) values(values ...testValue) *packetBuilder {
+	payload := make([]byte, 2+9*len(values))
+	binary.BigEndian.PutUint16(payload[0:2], uint16(len(values)))
+	for i, v := range values {
+		payload[2+i] = v.dsType
+		raw := payload[2+len(values)+i*8 : 2+len(values)+(i+1)*8]
+		switch v.dsType {
+		case dsTypeGauge:
+			binary.LittleEndian.PutUint64(raw, math.Float64bits(v.value))
+		case dsTypeDerive:
+			binary.BigEndian.PutUint64(raw, uint64(int64(v.value)))
+		default:
+			binary.BigEndian.PutUint64(raw, uint64(v.value))
+		}
+	}
+	return b.part(partTypeValues, payload)
+}
+
+// signed returns the packet signed with HMAC-SHA256.
+func signed(data []byte, username, password string) []byte {
+	mac := hmac.New(sha256.New, []byte(password))
+	mac.Write([]byte(username))
+	mac.Write(data)
+	payload := append(mac.Sum(nil), username...)
+	return append((&packetBuilder{}).part(partTypeSignature, payload).data, data...)
+}
+
+// encrypted returns the packet encrypted with AES-256 in OFB mode.
+func encrypted(data []byte, username, password string) []byte {
+	sum := sha1.Sum(data) // #nosec G401 -- SHA-1 is mandated by the collectd network protocol.
+	plaintext := append(sum[:], data...)
+
+	key := sha256.Sum256([]byte(password))
+	block, _ := aes.NewCipher(key[:])
+	iv := make([]byte, aes.BlockSize)
+	for i := range iv {
+		iv[i] = byte(i)
+	}
+	ciphertext := make([]byte, len(plaintext))
+	cipher.NewOFB(block, iv).XORKeyStream(ciphertext, plaintext)
+
+	payload := make([]byte, 2)
+	binary.BigEndian.PutUint16(payload, uint16(len(username)))
+	payload = append(payload, username...)
+	payload = append(payload, iv...)
+	payload = append(payload, ciphertext...)
+	return (&packetBuilder{}).part(partTypeEncryption, payload).data
+}
+
+func testPacket() []byte {
+	b := &packetBuilder{}
+	b.str(partTypeHost, "i-b13d1e5f").
+		number(partTypeTimeHR, 1415062577<<30|1<<29).
+		number(partTypeIntervalHR, 10<<30).
+		str(partTypePlugin, "load").
+		str(partTypePluginInstance, "").
+		str(partTypeType, "load").
+		str(partTypeTypeInstance, "").
+		values(
+			testValue{dsTypeGauge, 0.37},
+			testValue{dsTypeGauge, 0.61},
+			testValue{dsTypeGauge, math.NaN()},
+		).
+		str(partTypePlugin, "df").
+		str(partTypePluginInstance, "dev").
+		str(partTypeType, "df_complex").
+		str(partTypeTypeInstance, "free").
+		values(testValue{dsTypeDerive, -5}).
+		str(partTypeType, "if_octets").
+		values(testValue{dsTypeCounter, 10}, testValue{dsTypeAbsolute, 20}).
+		number(partTypeTime, 1415062578).
+		str(partTypeType, "custom").
+		values(testValue{dsTypeGauge, 1}, testValue{dsTypeGauge, 2}).
+		str(partTypeMessage, "my message").
+		number(partTypeSeverity, 4)
+	return b.data
+}
+
+func TestBinaryParser(t *testing.T) {
+	p := &binaryParser{}
+	records, notifications, err := p.parse(testPacket())
+	require.NoError(t, err)
+	assert.Equal(t, 1, notifications)
+	require.Len(t, records, 4)
+
+	load := records[0]
+	assert.Equal(t, "i-b13d1e5f", *load.Host)
+	assert.Equal(t, "load", *load.Plugin)
+	assert.Equal(t, "", *load.PluginInstance)
+	assert.Equal(t, 1415062577.5, *load.Time)
+	assert.Equal(t, 10.0, *load.Interval)
+	require.Len(t, load.Values, 3)
+	assert.Equal(t, "shortterm", *load.Dsnames[0])
+	assert.Equal(t, "gauge", *load.Dstypes[0])
+	assert.Equal(t, "0.37", load.Values[0].String())
+	assert.Equal(t, "0.61", load.Values[1].String())
+	// NaN gauges are unknown values.
+	assert.Nil(t, load.Values[2])
+
+	df := records[1]
+	assert.Equal(t, "dev", *df.PluginInstance)
+	assert.Equal(t, "free", *df.TypeInstance)
+	assert.Equal(t, "value", *df.Dsnames[0])
+	assert.Equal(t, "derive", *df.Dstypes[0])
+	assert.Equal(t, "-5", df.Values[0].String())
+
+	ifOctets := records[2]
+	assert.Equal(t, "rx", *ifOctets.Dsnames[0])
+	assert.Equal(t, "counter", *ifOctets.Dstypes[0])
+	assert.Equal(t, "10", ifOctets.Values[0].String())
+	assert.Equal(t, "tx", *ifOctets.Dsnames[1])
+	assert.Equal(t, "absolute", *ifOctets.Dstypes[1])
+	assert.Equal(t, "20", ifOctets.Values[1].String())
+
+	custom := records[3]
+	assert.Equal(t, 1415062578.0, *custom.Time)
+	assert.Equal(t, "0", *custom.Dsnames[0])
+	assert.Equal(t, "1", *custom.Dsnames[1])
+	// Gauges remain doubles.
+	assert.Equal(t, "1.0", custom.Values[0].String())
+
+	metrics, err := load.appendToMetrics(nil, nil)
+	require.NoError(t, err)
+	require.Len(t, metrics, 2)
+	assert.Equal(t, "load.shortterm", metrics[0].GetMetricDescriptor().GetName())
+}
+
+func TestBinaryParser_Security(t *testing.T) {
+	passwords := map[string]string{"user": "secret"}
+	plain := testPacket()
+
+	tests := []struct {
+		name          string
+		securityLevel string
+		packet        []byte
+		wantErr       bool
+		wantRecords   int
+	}{
+		{
+			name:          "none_plain",
+			securityLevel: securityLevelNone,
+			packet:        plain,
+			wantRecords:   4,
+		},
+		{
+			name:          "none_signed",
+			securityLevel: securityLevelNone,
+			packet:        signed(plain, "user", "secret"),
+			wantRecords:   4,
+		},
+		{
+			name:          "none_signed_unknown_user",
+			securityLevel: securityLevelNone,
+			packet:        signed(plain, "unknown", "secret"),
+			wantRecords:   4,
+		},
+		{
+			name:          "none_encrypted_unknown_user",
+			securityLevel: securityLevelNone,
+			packet:        encrypted(plain, "unknown", "secret"),
+			wantRecords:   0,
+		},
+		{
+			name:          "none_invalid_signature",
+			securityLevel: securityLevelNone,
+			packet:        signed(plain, "user", "wrong"),
+			wantErr:       true,
+		},
+		{
+			name:          "sign_plain",
+			securityLevel: securityLevelSign,
+			packet:        plain,
+			wantErr:       true,
+		},
+		{
+			name:          "sign_signed",
+			securityLevel: securityLevelSign,
+			packet:        signed(plain, "user", "secret"),
+			wantRecords:   4,
+		},
+		{
+			name:          "sign_signed_unknown_user",
+			securityLevel: securityLevelSign,
+			packet:        signed(plain, "unknown", "secret"),
+			wantErr:       true,
+		},
+		{
+			name:          "sign_encrypted",
+			securityLevel: securityLevelSign,
+			packet:        encrypted(plain, "user", "secret"),
+			wantRecords:   4,
+		},
+		{
+			name:          "encrypt_signed",
+			securityLevel: securityLevelEncrypt,
+			packet:        signed(plain, "user", "secret"),
+			wantErr:       true,
+		},
+		{
+			name:          "encrypt_encrypted",
+			securityLevel: securityLevelEncrypt,
+			packet:        encrypted(plain, "user", "secret"),
+			wantRecords:   4,
+		},
+		{
+			name:          "encrypt_wrong_password",
+			securityLevel: securityLevelEncrypt,
+			packet:        encrypted(plain, "user", "wrong"),
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &binaryParser{securityLevel: tt.securityLevel, passwords: passwords}
+			records, _, err := p.parse(tt.packet)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, records, tt.wantRecords)
+		})
+	}
+}
+
+func TestBinaryParser_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		packet []byte
+	}{
+		{
+			name:   "short_header",
+			packet: []byte{0, 0, 0},
+		},
+		{
+			name:   "length_shorter_than_header",
+			packet: []byte{0, 0, 0, 2},
+		},
+		{
+			name:   "truncated",
+			packet: (&packetBuilder{}).str(partTypeHost, "host").data[:6],
+		},
+		{
+			name:   "string_not_terminated",
+			packet: (&packetBuilder{}).part(partTypeHost, []byte("host")).data,
+		},
+		{
+			name:   "numeric_too_short",
+			packet: (&packetBuilder{}).part(partTypeTime, []byte{1, 2, 3}).data,
+		},
+		{
+			name:   "values_count_mismatch",
+			packet: (&packetBuilder{}).part(partTypeValues, []byte{0, 2, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0}).data,
+		},
+		{
+			name:   "values_unknown_type",
+			packet: (&packetBuilder{}).values(testValue{dsType: 9}).data,
+		},
+		{
+			name:   "signature_too_short",
+			packet: (&packetBuilder{}).part(partTypeSignature, []byte{1, 2, 3}).data,
+		},
+		{
+			name:   "encryption_too_short",
+			packet: (&packetBuilder{}).part(partTypeEncryption, []byte{0, 1, 'u'}).data,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &binaryParser{}
+			_, _, err := p.parse(tt.packet)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -29,4 +29,11 @@ type Config struct {
 	Timeout          time.Duration `mapstructure:"timeout"`
 	AttributesPrefix string        `mapstructure:"attributes_prefix"`
 	Encoding         string        `mapstructure:"encoding"`
+
+	// SecurityLevel is the minimum security level of the packets accepted with
+	// the "binary" encoding: "none" (the default), "sign" or "encrypt".
+	SecurityLevel string `mapstructure:"security_level"`
+	// AuthFile is the file with the users and passwords used to verify signed
+	// packets and to decrypt encrypted packets with the "binary" encoding.
+	AuthFile string `mapstructure:"auth_file"`
 }
@@ -40,7 +40,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 2)
+	assert.Equal(t, len(cfg.Receivers), 3)
 
 	r0 := cfg.Receivers[config.NewID(typeStr)]
 	assert.Equal(t, r0, factory.CreateDefaultConfig())
@@ -55,5 +55,19 @@ func TestLoadConfig(t *testing.T) {
 			Timeout:          time.Second * 50,
 			AttributesPrefix: "dap_",
 			Encoding:         "command",
+			SecurityLevel:    "none",
+		})
+
+	r2 := cfg.Receivers[config.NewIDWithName(typeStr, "binary")].(*Config)
+	assert.Equal(t, r2,
+		&Config{
+			ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName(typeStr, "binary")),
+			TCPAddr: confignet.TCPAddr{
+				Endpoint: "localhost:25826",
+			},
+			Timeout:       time.Second * 30,
+			Encoding:      "binary",
+			SecurityLevel: "sign",
+			AuthFile:      "/etc/collectd/passwd",
 		})
 }
@@ -34,6 +34,8 @@ const (
 	defaultBindEndpoint   = "localhost:8081"
 	defaultTimeout        = time.Second * 30
 	defaultEncodingFormat = "json"
+	binaryEncodingFormat  = "binary"
+	defaultSecurityLevel  = securityLevelNone
 )
 
 // NewFactory creates a factory for collectd receiver.
@@ -49,8 +51,9 @@ func createDefaultConfig() config.Receiver {
 		TCPAddr: confignet.TCPAddr{
 			Endpoint: defaultBindEndpoint,
 		},
-		Timeout:  defaultTimeout,
-		Encoding: defaultEncodingFormat,
+		Timeout:       defaultTimeout,
+		Encoding:      defaultEncodingFormat,
+		SecurityLevel: defaultSecurityLevel,
 	}
 }
 
@@ -62,13 +65,16 @@ func createMetricsReceiver(
 ) (component.MetricsReceiver, error) {
 	c := cfg.(*Config)
 	c.Encoding = strings.ToLower(c.Encoding)
-	// CollectD receiver only supports JSON encoding. We expose a config option
-	// to make it explicit and obvious to the users.
-	if c.Encoding != defaultEncodingFormat {
-		return nil, fmt.Errorf(
-			"CollectD only support JSON encoding format. %s is not supported",
-			c.Encoding,
-		)
+	switch c.Encoding {
+	case defaultEncodingFormat:
+		// JSON from the write_http plugin over HTTP.
+		return newCollectdReceiver(params.Logger, c.Endpoint, c.Timeout, c.AttributesPrefix, nextConsumer)
+	case binaryEncodingFormat:
+		// Binary protocol from the network plugin over UDP.
+		return newCollectdBinaryReceiver(params.Logger, c.Endpoint, c.SecurityLevel, c.AuthFile, nextConsumer)
 	}
-	return newCollectdReceiver(params.Logger, c.Endpoint, c.Timeout, c.AttributesPrefix, nextConsumer)
+	return nil, fmt.Errorf(
+		"CollectD only support JSON and binary encoding formats. %s is not supported",
+		c.Encoding,
+	)
 }
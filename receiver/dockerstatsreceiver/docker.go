@@ -25,6 +25,7 @@ import (
 
 	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
 	dtypes "github.com/docker/docker/api/types"
+	devents "github.com/docker/docker/api/types/events"
 	dfilters "github.com/docker/docker/api/types/filters"
 	docker "github.com/docker/docker/client"
 	"go.uber.org/zap"
@@ -205,6 +206,30 @@ func (dc *dockerClient) ContainerEventLoop(ctx context.Context) {
 		{Key: "event", Value: "unpause"},
 		{Key: "event", Value: "update"},
 	}...)
+
+	dc.watchEvents(ctx, filters, func(event devents.Message) {
+		switch event.Action {
+		case "destroy":
+			dc.logger.Debug("Docker container was destroyed:", zap.String("id", event.ID))
+			dc.removeContainer(event.ID)
+		default:
+			dc.logger.Debug(
+				"Docker container update:",
+				zap.String("id", event.ID),
+				zap.String("action", event.Action),
+			)
+
+			if container, ok := dc.inspectedContainerIsOfInterest(ctx, event.ID); ok {
+				dc.persistContainer(container)
+			}
+		}
+	})
+}
+
+// watchEvents subscribes to the docker daemon events matching the provided filters and
+// invokes handle for each of them until ctx is done, resuming the subscription from the
+// last observed event after any decoding or connection error.
+func (dc *dockerClient) watchEvents(ctx context.Context, filters dfilters.Args, handle func(devents.Message)) {
 	lastTime := time.Now()
 
 EVENT_LOOP:
@@ -220,21 +245,7 @@ EVENT_LOOP:
 			case <-ctx.Done():
 				return
 			case event := <-eventCh:
-				switch event.Action {
-				case "destroy":
-					dc.logger.Debug("Docker container was destroyed:", zap.String("id", event.ID))
-					dc.removeContainer(event.ID)
-				default:
-					dc.logger.Debug(
-						"Docker container update:",
-						zap.String("id", event.ID),
-						zap.String("action", event.Action),
-					)
-
-					if container, ok := dc.inspectedContainerIsOfInterest(ctx, event.ID); ok {
-						dc.persistContainer(container)
-					}
-				}
+				handle(event)
 
 				if event.TimeNano > lastTime.UnixNano() {
 					lastTime = time.Unix(0, event.TimeNano)
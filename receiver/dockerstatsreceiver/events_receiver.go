@@ -0,0 +1,192 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerstatsreceiver
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	devents "github.com/docker/docker/api/types/events"
+	dfilters "github.com/docker/docker/api/types/filters"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+const (
+	healthStatusPrefix = "health_status:"
+)
+
+var severityText = map[pdata.SeverityNumber]string{
+	pdata.SeverityNumberINFO: "INFO",
+	pdata.SeverityNumberWARN: "WARN",
+}
+
+var _ component.LogsReceiver = (*eventsReceiver)(nil)
+
+// eventsReceiver subscribes to the docker daemon's container lifecycle events
+// and emits each of them as a log record.
+type eventsReceiver struct {
+	config       *Config
+	logger       *zap.Logger
+	nextConsumer consumer.Logs
+	client       *dockerClient
+	cancel       context.CancelFunc
+	obsCtx       context.Context
+	transport    string
+	obsrecv      *obsreport.Receiver
+}
+
+func newEventsReceiver(
+	logger *zap.Logger,
+	config *Config,
+	nextConsumer consumer.Logs,
+) (*eventsReceiver, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine receiver transport: %w", err)
+	}
+
+	return &eventsReceiver{
+		config:       config,
+		logger:       logger,
+		nextConsumer: nextConsumer,
+		transport:    parsed.Scheme,
+		obsrecv:      obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: config.ID(), Transport: parsed.Scheme}),
+	}, nil
+}
+
+func (r *eventsReceiver) Start(ctx context.Context, _ component.Host) error {
+	var err error
+	r.client, err = newDockerClient(r.config, r.logger)
+	if err != nil {
+		return err
+	}
+
+	r.obsCtx = obsreport.ReceiverContext(ctx, r.config.ID(), r.transport)
+
+	var eventsCtx context.Context
+	eventsCtx, r.cancel = context.WithCancel(context.Background())
+
+	filters := dfilters.NewArgs([]dfilters.KeyValuePair{
+		{Key: "type", Value: "container"},
+		{Key: "event", Value: "start"},
+		{Key: "event", Value: "stop"},
+		{Key: "event", Value: "die"},
+		{Key: "event", Value: "oom"},
+		// the daemon matches health_status filters against any "health_status: <status>" action.
+		{Key: "event", Value: "health_status"},
+	}...)
+
+	go r.client.watchEvents(eventsCtx, filters, func(event devents.Message) {
+		r.consumeEvent(eventsCtx, event)
+	})
+	return nil
+}
+
+func (r *eventsReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	return nil
+}
+
+func (r *eventsReceiver) consumeEvent(ctx context.Context, event devents.Message) {
+	if r.client.shouldBeExcluded(event.Actor.Attributes["image"]) {
+		return
+	}
+
+	c := r.obsrecv.StartLogsReceiveOp(r.obsCtx)
+	ld := eventToLogs(event, r.config)
+	err := r.nextConsumer.ConsumeLogs(ctx, ld)
+	if err != nil {
+		r.logger.Error("Failed to consume docker container event", zap.String("id", event.ID), zap.Error(err))
+	}
+	r.obsrecv.EndLogsReceiveOp(c, typeStr, ld.LogRecordCount(), err)
+}
+
+// eventToLogs converts a docker container event into a single log record whose resource
+// describes the container in the same manner as its stats metrics.
+func eventToLogs(event devents.Message, config *Config) pdata.Logs {
+	ld := pdata.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+
+	resourceAttrs := rl.Resource().Attributes()
+	resourceAttrs.UpsertString(conventions.AttributeContainerID, event.ID)
+	if image, ok := event.Actor.Attributes["image"]; ok {
+		resourceAttrs.UpsertString(conventions.AttributeContainerImage, image)
+	}
+	if name, ok := event.Actor.Attributes["name"]; ok {
+		resourceAttrs.UpsertString(conventions.AttributeContainerName, name)
+	}
+	// container labels are provided alongside the event's other actor attributes.
+	for k, label := range config.ContainerLabelsToMetricLabels {
+		if v := event.Actor.Attributes[k]; v != "" {
+			resourceAttrs.UpsertString(label, v)
+		}
+	}
+
+	lr := rl.InstrumentationLibraryLogs().AppendEmpty().Logs().AppendEmpty()
+	lr.SetTimestamp(pdata.TimestampFromTime(eventTime(event)))
+	lr.Body().SetStringVal(event.Action)
+
+	attrs := lr.Attributes()
+	action := event.Action
+	severity := pdata.SeverityNumberINFO
+	switch {
+	case strings.HasPrefix(action, healthStatusPrefix):
+		status := strings.TrimSpace(strings.TrimPrefix(action, healthStatusPrefix))
+		action = "health_status"
+		attrs.UpsertString("container.health.status", status)
+		if status == "unhealthy" {
+			severity = pdata.SeverityNumberWARN
+		}
+	case action == "oom":
+		severity = pdata.SeverityNumberWARN
+	case action == "die":
+		if code, err := strconv.ParseInt(event.Actor.Attributes["exitCode"], 10, 64); err == nil {
+			attrs.UpsertInt("container.exit_code", code)
+			if code != 0 {
+				severity = pdata.SeverityNumberWARN
+			}
+		}
+	}
+	attrs.UpsertString("docker.event.action", action)
+
+	lr.SetName("docker.container." + action)
+	lr.SetSeverityNumber(severity)
+	lr.SetSeverityText(severityText[severity])
+
+	return ld
+}
+
+func eventTime(event devents.Message) time.Time {
+	if event.TimeNano != 0 {
+		return time.Unix(0, event.TimeNano)
+	}
+	return time.Unix(event.Time, 0)
+}
@@ -0,0 +1,199 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !windows
+// TODO review if tests should succeed on Windows
+
+package dockerstatsreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	devents "github.com/docker/docker/api/types/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func containerEvent(action string, attributes map[string]string) devents.Message {
+	return devents.Message{
+		Type:   devents.ContainerEventType,
+		Action: action,
+		Actor: devents.Actor{
+			ID:         "a359c0fc87c546b42d2ad32db7c978627f1d89b49cb3827a7b19ba97a1febcce",
+			Attributes: attributes,
+		},
+		ID:       "a359c0fc87c546b42d2ad32db7c978627f1d89b49cb3827a7b19ba97a1febcce",
+		Time:     1622000000,
+		TimeNano: 1622000000123456789,
+	}
+}
+
+func TestEventToLogs(t *testing.T) {
+	config := &Config{
+		ContainerLabelsToMetricLabels: map[string]string{
+			"my.label": "my_label",
+		},
+	}
+
+	tests := []struct {
+		name         string
+		event        devents.Message
+		action       string
+		severity     pdata.SeverityNumber
+		exitCode     int64
+		healthStatus string
+	}{
+		{
+			name:     "start",
+			event:    containerEvent("start", map[string]string{"image": "redis", "name": "cache", "my.label": "value"}),
+			action:   "start",
+			severity: pdata.SeverityNumberINFO,
+		},
+		{
+			name:     "clean exit",
+			event:    containerEvent("die", map[string]string{"image": "redis", "name": "cache", "exitCode": "0"}),
+			action:   "die",
+			severity: pdata.SeverityNumberINFO,
+			exitCode: 0,
+		},
+		{
+			name:     "failed exit",
+			event:    containerEvent("die", map[string]string{"image": "redis", "name": "cache", "exitCode": "137"}),
+			action:   "die",
+			severity: pdata.SeverityNumberWARN,
+			exitCode: 137,
+		},
+		{
+			name:     "oom",
+			event:    containerEvent("oom", map[string]string{"image": "redis", "name": "cache"}),
+			action:   "oom",
+			severity: pdata.SeverityNumberWARN,
+		},
+		{
+			name:         "healthy",
+			event:        containerEvent("health_status: healthy", map[string]string{"image": "redis", "name": "cache"}),
+			action:       "health_status",
+			severity:     pdata.SeverityNumberINFO,
+			healthStatus: "healthy",
+		},
+		{
+			name:         "unhealthy",
+			event:        containerEvent("health_status: unhealthy", map[string]string{"image": "redis", "name": "cache"}),
+			action:       "health_status",
+			severity:     pdata.SeverityNumberWARN,
+			healthStatus: "unhealthy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ld := eventToLogs(tt.event, config)
+			require.Equal(t, 1, ld.LogRecordCount())
+
+			rl := ld.ResourceLogs().At(0)
+			resourceAttrs := rl.Resource().Attributes()
+			id, _ := resourceAttrs.Get("container.id")
+			assert.Equal(t, tt.event.ID, id.StringVal())
+			image, _ := resourceAttrs.Get("container.image.name")
+			assert.Equal(t, "redis", image.StringVal())
+			name, _ := resourceAttrs.Get("container.name")
+			assert.Equal(t, "cache", name.StringVal())
+			label, ok := resourceAttrs.Get("my_label")
+			assert.Equal(t, tt.event.Actor.Attributes["my.label"] != "", ok)
+			if ok {
+				assert.Equal(t, "value", label.StringVal())
+			}
+
+			lr := rl.InstrumentationLibraryLogs().At(0).Logs().At(0)
+			assert.Equal(t, "docker.container."+tt.action, lr.Name())
+			assert.Equal(t, tt.event.Action, lr.Body().StringVal())
+			assert.Equal(t, pdata.Timestamp(tt.event.TimeNano), lr.Timestamp())
+			assert.Equal(t, tt.severity, lr.SeverityNumber())
+
+			action, _ := lr.Attributes().Get("docker.event.action")
+			assert.Equal(t, tt.action, action.StringVal())
+
+			exitCode, ok := lr.Attributes().Get("container.exit_code")
+			assert.Equal(t, tt.action == "die", ok)
+			if ok {
+				assert.Equal(t, tt.exitCode, exitCode.IntVal())
+			}
+
+			healthStatus, ok := lr.Attributes().Get("container.health.status")
+			assert.Equal(t, tt.healthStatus != "", ok)
+			if ok {
+				assert.Equal(t, tt.healthStatus, healthStatus.StringVal())
+			}
+		})
+	}
+}
+
+func TestEventsReceiver(t *testing.T) {
+	events := []devents.Message{
+		containerEvent("start", map[string]string{"image": "undesired-container", "name": "excluded"}),
+		containerEvent("start", map[string]string{"image": "redis", "name": "cache"}),
+		containerEvent("die", map[string]string{"image": "redis", "name": "cache", "exitCode": "1"}),
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/events") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		for _, event := range events {
+			require.NoError(t, encoder.Encode(event))
+		}
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	config := &Config{
+		Endpoint:           srv.URL,
+		CollectionInterval: 1 * time.Second,
+		Timeout:            1 * time.Second,
+		ExcludedImages:     []string{"undesired-container"},
+	}
+
+	sink := new(consumertest.LogsSink)
+	receiver, err := newEventsReceiver(zap.NewNop(), config, sink)
+	require.NoError(t, err)
+	require.NoError(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	var actions []string
+	for _, ld := range sink.AllLogs() {
+		name, _ := ld.ResourceLogs().At(0).Resource().Attributes().Get("container.name")
+		assert.Equal(t, "cache", name.StringVal())
+		actions = append(actions, ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).Body().StringVal())
+	}
+	assert.Equal(t, []string{"start", "die"}, actions)
+
+	require.NoError(t, receiver.Shutdown(context.Background()))
+}
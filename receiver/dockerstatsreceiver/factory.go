@@ -32,7 +32,8 @@ func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
 }
 
 func createDefaultConfig() config.Receiver {
@@ -59,3 +60,19 @@ func createMetricsReceiver(
 
 	return dsr, nil
 }
+
+func createLogsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	config config.Receiver,
+	consumer consumer.Logs,
+) (component.LogsReceiver, error) {
+	dockerConfig := config.(*Config)
+
+	der, err := newEventsReceiver(params.Logger, dockerConfig, consumer)
+	if err != nil {
+		return nil, err
+	}
+
+	return der, nil
+}
@@ -15,7 +15,11 @@
 package dotnetdiagnosticsreceiver
 
 import (
+	"fmt"
+
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/dotnet"
 )
 
 type Config struct {
@@ -36,16 +40,55 @@ type Config struct {
 	// be displayed by the `dotnet-counters` tool:
 	// https://docs.microsoft.com/en-us/dotnet/core/diagnostics/dotnet-counters
 	Counters []string `mapstructure:"counters"`
+	// RuntimeEvents is an optional list of groups of runtime events to request
+	// from the dotnet process, in addition to the counters. "gc" and
+	// "contention" events are aggregated into histograms (GC pause durations,
+	// allocation sizes, and lock contention durations) and sent at the
+	// collection interval when the receiver is used in a metrics pipeline.
+	// "exceptions" events are converted to log records when the receiver is
+	// used in a logs pipeline. Defaults to none.
+	RuntimeEvents []string `mapstructure:"runtime_events"`
 
 	// LocalDebugDir takes an optional directory name where stream data can be written for
 	// offline analysis and troubleshooting. If LocalDebugDir is empty, no stream data is
 	// written. If it has a value, MaxLocalDebugFiles also needs to be set, and stream
 	// data will be written to disk at the specified location using the naming
 	// convention `msg.%d.bin` as each message is received, where %d is the current
-	// message number.
+	// message number. When the receiver is used in a logs pipeline, the stream
+	// of its separate connection is written to the "logs" subdirectory.
 	LocalDebugDir string `mapstructure:"local_debug_dir"`
 	// MaxLocalDebugFiles indicates the maximum number of files kept in LocalDebugDir. When a
 	// file is written, the oldest one will be deleted if necessary to keep the
 	// number of files in LocalDebugDir at the specified maximum.
 	MaxLocalDebugFiles int `mapstructure:"max_local_debug_files"`
 }
+
+// runtimeEventKeywords maps each runtime_events value to the keywords for the
+// runtime provider.
+var runtimeEventKeywords = map[string]dotnet.RuntimeKeywords{
+	"gc":         dotnet.RuntimeKeywordGC,
+	"contention": dotnet.RuntimeKeywordContention,
+	"exceptions": dotnet.RuntimeKeywordException,
+}
+
+func (c *Config) Validate() error {
+	for _, e := range c.RuntimeEvents {
+		if _, ok := runtimeEventKeywords[e]; !ok {
+			return fmt.Errorf(`runtime_events: unsupported value %q, must be one of "gc", "contention" or "exceptions"`, e)
+		}
+	}
+	return nil
+}
+
+// runtimeKeywords returns the combined keywords for the configured runtime
+// events that are among the passed-in ones.
+func (c *Config) runtimeKeywords(supported ...string) (k dotnet.RuntimeKeywords) {
+	for _, e := range c.RuntimeEvents {
+		for _, s := range supported {
+			if e == s {
+				k |= runtimeEventKeywords[e]
+			}
+		}
+	}
+	return
+}
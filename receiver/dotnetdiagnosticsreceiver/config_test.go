@@ -24,6 +24,8 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configtest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/dotnet"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -42,4 +44,15 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, 1234, cfg.PID)
 	assert.Equal(t, 2*time.Second, cfg.CollectionInterval)
 	assert.Equal(t, []string{"Foo", "Bar"}, cfg.Counters)
+	assert.Equal(t, []string{"gc", "exceptions"}, cfg.RuntimeEvents)
+	assert.Equal(t, dotnet.RuntimeKeywordGC, cfg.runtimeKeywords("gc", "contention"))
+	assert.Equal(t, dotnet.RuntimeKeywordException, cfg.runtimeKeywords("exceptions"))
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.RuntimeEvents = []string{"gc", "contention", "exceptions"}
+	assert.NoError(t, cfg.Validate())
+	cfg.RuntimeEvents = []string{"gc", "jit"}
+	assert.EqualError(t, cfg.Validate(), `runtime_events: unsupported value "jit", must be one of "gc", "contention" or "exceptions"`)
 }
@@ -25,3 +25,13 @@ func endObject(r network.MultiReader) error {
 	const tagEndObject = 6
 	return r.AssertNextByteEquals(tagEndObject)
 }
+
+// skipPayload moves the reader to the end of an event payload that started at
+// the passed-in position, skipping any bytes that were not parsed.
+func skipPayload(r network.MultiReader, start int, payloadSize int32) error {
+	remaining := start + int(payloadSize) - r.Pos()
+	if remaining > 0 {
+		return r.Seek(remaining)
+	}
+	return nil
+}
@@ -25,6 +25,9 @@ type eventHeader struct {
 	stackID           int32
 	payloadSize       int32
 	timestampDelta    int64
+	// timestamp is the sum of the deltas read so far, which (because the header
+	// is reset at the beginning of each block) is the event's QPC timestamp
+	timestamp int64
 }
 
 type headerFlags byte
@@ -46,9 +49,9 @@ func (f headerFlags) isSet(other headerFlags) bool {
 }
 
 // parseEventHeader is used by event parser (and by metadata parser for stream
-// alignment only) to get the metadata ID so that it can be correlated to the
-// extracted metadata. The rest of the extracted information is currently
-// unused.
+// alignment and payload sizes) to get the metadata ID so that it can be
+// correlated to the extracted metadata. The thread ID and timestamp are used to
+// decode runtime events.
 func parseEventHeader(r network.MultiReader, h *eventHeader) (err error) {
 	// EventPipeEventHeader.ReadFromFormatV4
 	var b byte
@@ -102,6 +105,7 @@ func parseEventHeader(r network.MultiReader, h *eventHeader) (err error) {
 	if err != nil {
 		return
 	}
+	h.timestamp += h.timestampDelta
 
 	const guidSize = 16
 	if f.isSet(headerFlagActivityID) {
@@ -18,13 +18,20 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/network"
 )
 
+// eventCountersEventName is the name of the events that carry counter values.
+// Providers may send other events (e.g. System.Runtime's ProcessorCount), which
+// are skipped.
+const eventCountersEventName = "EventCounters"
+
 // parseEventBlock parses an event block and returns a Metric slice containing
 // the raw representation of the metrics extracted from the event messages. It
 // uses the structure and names of the passed-in fieldMetadataMap (from
 // parseMetadataBlock) and the values extracted from the stream to build the
-// Metrics and their key-value pairs.
+// Metrics and their key-value pairs. Events from the runtime provider, whose
+// metadata does not describe their fields, are decoded by the passed-in
+// runtimeDecoder and returned as Events.
 // https://github.com/Microsoft/perfview/blob/main/src/TraceEvent/EventPipe/EventPipeFormat.md#the-eventblock-object
-func parseEventBlock(r network.MultiReader, fm fieldMetadataMap) (metrics []Metric, err error) {
+func parseEventBlock(r network.MultiReader, fm fieldMetadataMap, rd *runtimeDecoder) (metrics []Metric, events []Event, err error) {
 	var offset int32
 	err = r.Read(&offset)
 	if err != nil {
@@ -64,16 +71,36 @@ func parseEventBlock(r network.MultiReader, fm fieldMetadataMap) (metrics []Metr
 			return
 		}
 
-		m := Metric{}
+		start := r.Pos()
 		// here we correlate the metadata extracted from parseMetadataBlock to the events
 		// contained in this message
-		err = parseFieldValues(fm[int(header.metadataID)].fields, r, m)
+		md := fm[int(header.metadataID)]
+		if md.header.providerName == runtimeProviderName {
+			var e Event
+			var ok bool
+			e, ok, err = rd.decode(r, &header, md.header)
+			if err != nil {
+				return
+			}
+			if ok {
+				events = append(events, e)
+			}
+		} else if md.header.eventName == eventCountersEventName {
+			m := Metric{}
+			err = parseFieldValues(md.fields, r, m)
+			if err != nil {
+				return
+			}
+			if len(m) > 0 {
+				metrics = append(metrics, m)
+			}
+		}
+
+		// skip fields we don't decode, so that the next event header is aligned
+		err = skipPayload(r, start, header.payloadSize)
 		if err != nil {
 			return
 		}
-		if len(m) > 0 {
-			metrics = append(metrics, m)
-		}
 	}
 
 	return
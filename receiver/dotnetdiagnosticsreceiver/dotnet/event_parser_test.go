@@ -31,9 +31,10 @@ func TestEventParser(t *testing.T) {
 	reader := network.NewMultiReader(rw, &network.NopBlobWriter{})
 	err = reader.Seek(1131)
 	require.NoError(t, err)
-	metrics, err := parseEventBlock(reader, fms())
+	metrics, events, err := parseEventBlock(reader, fms(), &runtimeDecoder{})
 	require.NoError(t, err)
 	assert.Equal(t, 19, len(metrics))
+	assert.Empty(t, events)
 	testCPUUsage(t, metrics[0])
 	testAllocRate(t, metrics[16])
 }
@@ -77,7 +78,7 @@ func testEventParserError(t *testing.T, data [][]byte, i int) {
 	err := reader.Seek(1131)
 	rw.ErrOnRead(i)
 	require.NoError(t, err)
-	_, err = parseEventBlock(reader, fms())
+	_, _, err = parseEventBlock(reader, fms(), &runtimeDecoder{})
 	require.Error(t, err)
 }
 
@@ -28,7 +28,8 @@ type fieldMetadata struct {
 	fields []field
 }
 
-// only metadataID is used
+// metadataID is used to correlate events to their metadata, and providerName,
+// eventHeaderID (the event ID) and version are used to decode runtime events
 type metadataHeader struct {
 	metadataID    int32
 	providerName  string
@@ -91,8 +92,11 @@ func parseMetadataBlock(r network.MultiReader, fmm fieldMetadataMap) error {
 		return err
 	}
 
+	// the compressed event header carries state from one event to the next
+	// within a block
+	header := eventHeader{}
 	for r.Pos() < endpos {
-		fm, err := parseFieldMetadata(r)
+		fm, err := parseFieldMetadata(r, &header)
 		if err != nil {
 			return err
 		}
@@ -102,13 +106,14 @@ func parseMetadataBlock(r network.MultiReader, fmm fieldMetadataMap) error {
 	return nil
 }
 
-func parseFieldMetadata(r network.MultiReader) (m fieldMetadata, err error) {
-	ignored := eventHeader{}
-	err = parseEventHeader(r, &ignored)
+func parseFieldMetadata(r network.MultiReader, header *eventHeader) (m fieldMetadata, err error) {
+	err = parseEventHeader(r, header)
 	if err != nil {
 		return
 	}
 
+	start := r.Pos()
+
 	m.header, err = parseMetadataHeader(r)
 	if err != nil {
 		return
@@ -119,6 +124,8 @@ func parseFieldMetadata(r network.MultiReader) (m fieldMetadata, err error) {
 		return
 	}
 
+	// skip any optional metadata (e.g. opcodes) following the fields
+	err = skipPayload(r, start, header.payloadSize)
 	return
 }
 
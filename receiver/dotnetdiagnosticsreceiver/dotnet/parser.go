@@ -26,20 +26,23 @@ import (
 
 // Parser encapsulates all of the functionality to parse an IPC stream.
 type Parser struct {
-	r       network.MultiReader
-	consume func([]Metric)
-	logger  *zap.Logger
+	r             network.MultiReader
+	consume       func([]Metric)
+	consumeEvents func([]Event)
+	runtime       runtimeDecoder
+	logger        *zap.Logger
 }
 
 // MetricsConsumer is a function that accepts a slice of Metrics. Parser has a
 // member consumer function, used to send Metrics as they are created.
 type MetricsConsumer func([]Metric)
 
-// NewParser accepts an io.Reader, a MetricsConsumer, and logger, and returns a
-// Parser for processing an IPC stream.
-func NewParser(rdr io.Reader, mc MetricsConsumer, bw network.BlobWriter, logger *zap.Logger) *Parser {
+// NewParser accepts an io.Reader, a MetricsConsumer, an EventsConsumer, and
+// logger, and returns a Parser for processing an IPC stream. Either consumer
+// may be nil if the corresponding data is not needed.
+func NewParser(rdr io.Reader, mc MetricsConsumer, ec EventsConsumer, bw network.BlobWriter, logger *zap.Logger) *Parser {
 	r := network.NewMultiReader(rdr, bw)
-	return &Parser{r: r, consume: mc, logger: logger}
+	return &Parser{r: r, consume: mc, consumeEvents: ec, logger: logger}
 }
 
 // ParseIPC parses the IPC response from the initial request to a dotnet process.
@@ -89,7 +92,7 @@ func (p *Parser) parseBlock(fms fieldMetadataMap) error {
 
 	switch st.name {
 	case "Trace":
-		err = parseTraceMessage(p.r)
+		p.runtime.trace, err = parseTraceMessage(p.r)
 		if err != nil {
 			return err
 		}
@@ -105,11 +108,17 @@ func (p *Parser) parseBlock(fms fieldMetadataMap) error {
 		}
 	case "EventBlock":
 		var metrics []Metric
-		metrics, err = parseEventBlock(p.r, fms)
+		var events []Event
+		metrics, events, err = parseEventBlock(p.r, fms, &p.runtime)
 		if err != nil {
 			return err
 		}
-		p.consume(metrics)
+		if len(metrics) > 0 && p.consume != nil {
+			p.consume(metrics)
+		}
+		if len(events) > 0 && p.consumeEvents != nil {
+			p.consumeEvents(events)
+		}
 	case "SPBlock":
 		err = parseSPBlock(p.r)
 		if err != nil {
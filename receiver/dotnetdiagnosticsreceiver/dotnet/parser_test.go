@@ -18,7 +18,9 @@ import (
 	"context"
 	"path"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
@@ -40,6 +42,7 @@ func TestParser(t *testing.T) {
 	p := NewParser(
 		rw,
 		func(metrics []Metric) {},
+		func(events []Event) {},
 		&network.NopBlobWriter{},
 		zap.NewNop(),
 	)
@@ -53,7 +56,7 @@ func TestParser_TestData(t *testing.T) {
 	data, err := network.ReadBlobData(path.Join("..", "testdata"), 16)
 	require.NoError(t, err)
 	rw := network.NewBlobReader(data)
-	parser := NewParser(rw, func([]Metric) {}, &network.NopBlobWriter{}, zap.NewNop())
+	parser := NewParser(rw, func([]Metric) {}, nil, &network.NopBlobWriter{}, zap.NewNop())
 	err = parser.ParseIPC()
 	require.NoError(t, err)
 	err = parser.ParseNettrace()
@@ -110,7 +113,7 @@ func TestParser_ParseAll_Error(t *testing.T) {
 	data, err := network.ReadBlobData(path.Join("..", "testdata"), 16)
 	require.NoError(t, err)
 	rw := network.NewBlobReader(data)
-	parser := NewParser(rw, func([]Metric) {}, &network.NopBlobWriter{}, zap.NewNop())
+	parser := NewParser(rw, func([]Metric) {}, nil, &network.NopBlobWriter{}, zap.NewNop())
 	err = parser.ParseIPC()
 	require.NoError(t, err)
 	err = parser.ParseNettrace()
@@ -131,7 +134,7 @@ func TestParser_ParseAll_Cancel(t *testing.T) {
 	data, err := network.ReadBlobData(path.Join("..", "testdata"), 16)
 	require.NoError(t, err)
 	rw := network.NewBlobReader(data)
-	parser := NewParser(rw, func([]Metric) {}, &network.NopBlobWriter{}, zap.NewNop())
+	parser := NewParser(rw, func([]Metric) {}, nil, &network.NopBlobWriter{}, zap.NewNop())
 	err = parser.ParseIPC()
 	require.NoError(t, err)
 	err = parser.ParseNettrace()
@@ -148,3 +151,73 @@ func TestParser_ParseAll_Cancel(t *testing.T) {
 	rw.Gate() <- struct{}{}
 	require.NoError(t, <-errCh)
 }
+
+func TestParser_RuntimeTestData(t *testing.T) {
+	data, err := network.ReadBlobData(path.Join("..", "testdata", "runtime"), 25)
+	require.NoError(t, err)
+	rw := network.NewBlobReader(data)
+	var metrics []Metric
+	eventsByKind := map[EventKind][]Event{}
+	parser := NewParser(
+		rw,
+		func(ms []Metric) {
+			metrics = append(metrics, ms...)
+		},
+		func(es []Event) {
+			for _, e := range es {
+				eventsByKind[e.Kind] = append(eventsByKind[e.Kind], e)
+			}
+		},
+		&network.NopBlobWriter{},
+		zap.NewNop(),
+	)
+	err = parser.ParseIPC()
+	require.NoError(t, err)
+	err = parser.ParseNettrace()
+	require.NoError(t, err)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- parser.ParseAll(context.Background())
+	}()
+	select {
+	case <-rw.Gate():
+	case err = <-errCh:
+		require.NoError(t, err)
+		require.Fail(t, "ParseAll returned before the end of the data")
+	}
+
+	// System.Runtime sends events other than EventCounters, which must not
+	// become metrics
+	require.NotEmpty(t, metrics)
+	for _, m := range metrics {
+		require.NotEmpty(t, m.Name())
+	}
+
+	require.Equal(t, 7, len(eventsByKind[EventKindException]))
+	exception := eventsByKind[EventKindException][0]
+	assert.Equal(t, "System.InvalidOperationException", exception.ExceptionType)
+	assert.Equal(t, "iteration 22620", exception.ExceptionMessage)
+	assert.EqualValues(t, 0x80131509, exception.HResult)
+	assert.Equal(t, 2026, exception.Timestamp.Year())
+
+	require.Equal(t, 7, len(eventsByKind[EventKindGCPause]))
+	for _, e := range eventsByKind[EventKindGCPause] {
+		assert.True(t, e.Duration > 0 && e.Duration < time.Second)
+	}
+
+	require.Equal(t, 169, len(eventsByKind[EventKindAllocation]))
+	allocationKinds := map[string]int{}
+	for _, e := range eventsByKind[EventKindAllocation] {
+		assert.NotEmpty(t, e.TypeName)
+		assert.True(t, e.AllocationBytes > 0)
+		allocationKinds[e.AllocationKind]++
+	}
+	assert.Equal(t, 2, len(allocationKinds))
+	assert.True(t, allocationKinds["small"] > 0)
+	assert.True(t, allocationKinds["large"] > 0)
+
+	require.Equal(t, 44, len(eventsByKind[EventKindContention]))
+	for _, e := range eventsByKind[EventKindContention] {
+		assert.True(t, e.Duration > 0)
+	}
+}
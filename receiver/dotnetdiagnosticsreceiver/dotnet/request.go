@@ -35,12 +35,20 @@ import (
 type RequestWriter struct {
 	w           io.Writer
 	intervalSec int
+	// runtimeKeywords indicate which groups of runtime events to request. If zero,
+	// the runtime provider is not requested.
+	runtimeKeywords RuntimeKeywords
 	// providerNames (aka event sources) indicate which counter groups to get metrics for. e.g. "System.Runtime"
 	providerNames []string
 }
 
-func NewRequestWriter(w io.Writer, intervalSec int, providerNames ...string) *RequestWriter {
-	return &RequestWriter{w: w, intervalSec: intervalSec, providerNames: providerNames}
+func NewRequestWriter(w io.Writer, intervalSec int, runtimeKeywords RuntimeKeywords, providerNames ...string) *RequestWriter {
+	return &RequestWriter{
+		w:               w,
+		intervalSec:     intervalSec,
+		runtimeKeywords: runtimeKeywords,
+		providerNames:   providerNames,
+	}
 }
 
 func (w *RequestWriter) SendRequest() error {
@@ -59,7 +67,7 @@ const eventPipeCommand = 2
 const collectTracing2CommandID = 3
 
 func (w *RequestWriter) createRequest() []byte {
-	cfgReq := newConfigRequest(w.intervalSec, w.providerNames...)
+	cfgReq := newConfigRequest(w.intervalSec, w.runtimeKeywords, w.providerNames...)
 	payload := cfgReq.serialize()
 	hdr := &requestHeader{
 		commandSet: eventPipeCommand,
@@ -102,12 +110,16 @@ type serializationFormat uint32
 
 const netTrace = 1
 
-func newConfigRequest(intervalSec int, providerNames ...string) configRequest {
+func newConfigRequest(intervalSec int, runtimeKeywords RuntimeKeywords, providerNames ...string) configRequest {
+	providers := createProviders(intervalSec, providerNames...)
+	if runtimeKeywords != 0 {
+		providers = append(providers, createRuntimeProvider(runtimeKeywords))
+	}
 	return configRequest{
 		circularBufferSizeInMB: 10,
 		format:                 netTrace,
 		requestRundown:         false,
-		providers:              createProviders(intervalSec, providerNames...),
+		providers:              providers,
 	}
 }
 
@@ -152,6 +164,17 @@ func createProvider(name string, intervalSec int) provider {
 	}
 }
 
+// createRuntimeProvider creates a provider for the runtime's built-in events.
+// Verbose level is required to get GC allocation events.
+func createRuntimeProvider(keywords RuntimeKeywords) provider {
+	return provider{
+		name:       runtimeProviderName,
+		eventLevel: verboseEventLevel,
+		keywords:   int64(keywords),
+		args:       providerArgs{},
+	}
+}
+
 func (p provider) serialize(buf *bytes.Buffer) {
 	_ = binary.Write(buf, network.ByteOrder, p.keywords)
 	_ = binary.Write(buf, network.ByteOrder, p.eventLevel)
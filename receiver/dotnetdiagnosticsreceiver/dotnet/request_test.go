@@ -70,14 +70,25 @@ func TestRequestHeader_Serialize(t *testing.T) {
 }
 
 func TestSessionCfg(t *testing.T) {
-	req := newConfigRequest(42, "foo")
+	req := newConfigRequest(42, 0, "foo")
 	payload := req.serialize()
 	require.Equal(t, 95, len(payload))
 }
 
+func TestSessionCfg_Runtime(t *testing.T) {
+	req := newConfigRequest(42, RuntimeKeywordGC|RuntimeKeywordException, "foo")
+	require.Equal(t, 2, len(req.providers))
+	p := req.providers[1]
+	assert.Equal(t, runtimeProviderName, p.name)
+	assert.EqualValues(t, 0x8001, p.keywords)
+	assert.Equal(t, "", p.args.String())
+	payload := req.serialize()
+	require.Equal(t, 181, len(payload))
+}
+
 func TestRequestWriter_Send(t *testing.T) {
 	rw := &network.FakeRW{WriteErrIdx: -1}
-	w := NewRequestWriter(rw, 0, "")
+	w := NewRequestWriter(rw, 0, 0, "")
 	err := w.SendRequest()
 	require.NoError(t, err)
 	require.Equal(t, 107, len(rw.Writes))
@@ -0,0 +1,248 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dotnet
+
+import (
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/network"
+)
+
+// Event is a runtime event decoded from the Microsoft-Windows-DotNETRuntime
+// provider. Which of the payload fields are populated depends on the Kind.
+type Event struct {
+	Kind      EventKind
+	Timestamp time.Time
+	ThreadID  int64
+
+	// Duration is set for GCPause and Contention events.
+	Duration time.Duration
+
+	// AllocationBytes, AllocationKind, and TypeName are set for Allocation
+	// events. AllocationBytes is the number of bytes allocated since the
+	// previous allocation event, and TypeName is the type of the object whose
+	// allocation triggered the event.
+	AllocationBytes uint64
+	AllocationKind  string
+	TypeName        string
+
+	// ExceptionType, ExceptionMessage, and HResult are set for Exception events.
+	ExceptionType    string
+	ExceptionMessage string
+	HResult          uint32
+}
+
+type EventKind string
+
+const (
+	// EventKindGCPause is produced when the runtime resumes after being
+	// suspended for a garbage collection.
+	EventKindGCPause EventKind = "GCPause"
+	// EventKindAllocation is produced roughly every 100KB of allocations.
+	EventKindAllocation EventKind = "Allocation"
+	// EventKindContention is produced when a thread acquires a contended lock.
+	EventKindContention EventKind = "Contention"
+	// EventKindException is produced when an exception is thrown.
+	EventKindException EventKind = "Exception"
+)
+
+// EventsConsumer is a function that accepts a slice of Events. Parser has a
+// member consumer function, used to send Events as they are decoded.
+type EventsConsumer func([]Event)
+
+// RuntimeKeywords selects the groups of events requested from the runtime
+// provider. Keywords can be combined with a bitwise OR.
+// https://docs.microsoft.com/en-us/dotnet/fundamentals/diagnostics/runtime-events
+type RuntimeKeywords int64
+
+const (
+	RuntimeKeywordGC         RuntimeKeywords = 0x1
+	RuntimeKeywordContention RuntimeKeywords = 0x4000
+	RuntimeKeywordException  RuntimeKeywords = 0x8000
+)
+
+const runtimeProviderName = "Microsoft-Windows-DotNETRuntime"
+
+// runtime event IDs, from ClrEtwAll.man
+// https://github.com/dotnet/runtime/blob/main/src/coreclr/vm/ClrEtwAll.man
+const (
+	eventIDGCRestartEEEnd   = 3
+	eventIDGCSuspendEEBegin = 9
+	eventIDGCAllocationTick = 10
+	eventIDExceptionThrown  = 80
+	eventIDContentionStart  = 81
+	eventIDContentionStop   = 91
+)
+
+// suspend reasons from GCSuspendEEBegin that indicate a GC pause (as opposed to
+// e.g. a debugger suspension)
+const (
+	suspendReasonGC     = 1
+	suspendReasonGCPrep = 6
+)
+
+var allocationKinds = map[uint32]string{
+	0: "small",
+	1: "large",
+	2: "pinned",
+}
+
+// runtimeDecoder decodes runtime event payloads. Because pauses and contention
+// are reported as start and stop event pairs, it also keeps the timestamps of
+// start events until their corresponding stop events arrive.
+type runtimeDecoder struct {
+	trace            traceInfo
+	gcSuspendStart   int64
+	contentionStarts map[int64]int64
+}
+
+// decode decodes the payload of a runtime event, returning false if the event
+// is not one of the supported events or does not complete a start/stop pair.
+// The reader may be left before the end of the payload.
+func (d *runtimeDecoder) decode(r network.MultiReader, h *eventHeader, md metadataHeader) (e Event, ok bool, err error) {
+	e.Timestamp = d.trace.toTime(h.timestamp)
+	e.ThreadID = h.threadID
+	switch md.eventHeaderID {
+	case eventIDGCSuspendEEBegin:
+		var reason uint32
+		err = r.Read(&reason)
+		if err != nil {
+			return
+		}
+		d.gcSuspendStart = 0
+		if reason == suspendReasonGC || reason == suspendReasonGCPrep {
+			d.gcSuspendStart = h.timestamp
+		}
+	case eventIDGCRestartEEEnd:
+		if d.gcSuspendStart == 0 {
+			return
+		}
+		e.Kind = EventKindGCPause
+		e.Duration = d.trace.toDuration(h.timestamp - d.gcSuspendStart)
+		d.gcSuspendStart = 0
+		ok = true
+	case eventIDGCAllocationTick:
+		ok, err = d.decodeAllocation(r, md.version, &e)
+	case eventIDExceptionThrown:
+		ok, err = d.decodeException(r, md.version, &e)
+	case eventIDContentionStart:
+		if d.contentionStarts == nil {
+			d.contentionStarts = map[int64]int64{}
+		}
+		d.contentionStarts[h.threadID] = h.timestamp
+	case eventIDContentionStop:
+		ok, err = d.decodeContentionStop(r, h, md.version, &e)
+	}
+	return
+}
+
+// GCAllocationTick: AllocationAmount (uint32), AllocationKind (uint32),
+// ClrInstanceID (uint16, v1+), AllocationAmount64 (uint64, v2+), TypeID
+// (pointer, v2+), TypeName (string, v2+), ...
+func (d *runtimeDecoder) decodeAllocation(r network.MultiReader, version int32, e *Event) (bool, error) {
+	var amount, kind uint32
+	err := r.Read(&amount)
+	if err != nil {
+		return false, err
+	}
+	err = r.Read(&kind)
+	if err != nil {
+		return false, err
+	}
+	e.Kind = EventKindAllocation
+	e.AllocationBytes = uint64(amount)
+	e.AllocationKind = allocationKinds[kind]
+	if version < 2 {
+		return true, nil
+	}
+
+	var clrInstanceID uint16
+	err = r.Read(&clrInstanceID)
+	if err != nil {
+		return false, err
+	}
+	err = r.Read(&e.AllocationBytes)
+	if err != nil {
+		return false, err
+	}
+	err = r.Seek(int(d.trace.pointerSize))
+	if err != nil {
+		return false, err
+	}
+	e.TypeName, err = r.ReadUTF16()
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ExceptionThrown_V1: ExceptionType (string), ExceptionMessage (string),
+// ExceptionEIP (pointer), ExceptionHRESULT (uint32), ExceptionFlags (uint16),
+// ClrInstanceID (uint16)
+func (d *runtimeDecoder) decodeException(r network.MultiReader, version int32, e *Event) (bool, error) {
+	// version 0 has no payload
+	if version < 1 {
+		return false, nil
+	}
+	var err error
+	e.ExceptionType, err = r.ReadUTF16()
+	if err != nil {
+		return false, err
+	}
+	e.ExceptionMessage, err = r.ReadUTF16()
+	if err != nil {
+		return false, err
+	}
+	err = r.Seek(int(d.trace.pointerSize))
+	if err != nil {
+		return false, err
+	}
+	err = r.Read(&e.HResult)
+	if err != nil {
+		return false, err
+	}
+	e.Kind = EventKindException
+	return true, nil
+}
+
+// ContentionStop_V1: ContentionFlags (uint8), ClrInstanceID (uint16),
+// DurationNs (double). Version 0 has no duration, so it is computed from the
+// thread's ContentionStart event.
+func (d *runtimeDecoder) decodeContentionStop(r network.MultiReader, h *eventHeader, version int32, e *Event) (bool, error) {
+	start, found := d.contentionStarts[h.threadID]
+	delete(d.contentionStarts, h.threadID)
+	e.Kind = EventKindContention
+	if version < 1 {
+		e.Duration = d.trace.toDuration(h.timestamp - start)
+		return found, nil
+	}
+	var flags byte
+	err := r.Read(&flags)
+	if err != nil {
+		return false, err
+	}
+	var clrInstanceID uint16
+	err = r.Read(&clrInstanceID)
+	if err != nil {
+		return false, err
+	}
+	var durationNs float64
+	err = r.Read(&durationNs)
+	if err != nil {
+		return false, err
+	}
+	e.Duration = time.Duration(durationNs)
+	return true, nil
+}
@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dotnet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/network"
+)
+
+func TestRuntimeDecoder_GCPause(t *testing.T) {
+	d := testRuntimeDecoder()
+	_, ok := decodeTestEvent(t, d, 100, eventIDGCRestartEEEnd, 1, nil)
+	assert.False(t, ok, "restart without a suspend is ignored")
+
+	_, ok = decodeTestEvent(t, d, 1000, eventIDGCSuspendEEBegin, 1, uint32(suspendReasonGC))
+	assert.False(t, ok)
+	e, ok := decodeTestEvent(t, d, 3500, eventIDGCRestartEEEnd, 1, nil)
+	require.True(t, ok)
+	assert.Equal(t, EventKindGCPause, e.Kind)
+	assert.Equal(t, 2500*time.Microsecond, e.Duration)
+	assert.Equal(t, d.trace.syncTime.Add(3500*time.Microsecond), e.Timestamp)
+
+	// suspensions for reasons other than GC don't produce pauses
+	_, ok = decodeTestEvent(t, d, 4000, eventIDGCSuspendEEBegin, 1, uint32(5))
+	assert.False(t, ok)
+	_, ok = decodeTestEvent(t, d, 5000, eventIDGCRestartEEEnd, 1, nil)
+	assert.False(t, ok)
+}
+
+func TestRuntimeDecoder_ContentionV0(t *testing.T) {
+	d := testRuntimeDecoder()
+	_, ok := decodeTestEvent(t, d, 1000, eventIDContentionStart, 0, nil)
+	assert.False(t, ok)
+	e, ok := decodeTestEvent(t, d, 1750, eventIDContentionStop, 0, nil)
+	require.True(t, ok)
+	assert.Equal(t, EventKindContention, e.Kind)
+	assert.Equal(t, 750*time.Microsecond, e.Duration)
+
+	_, ok = decodeTestEvent(t, d, 2000, eventIDContentionStop, 0, nil)
+	assert.False(t, ok, "stop without a start is ignored")
+}
+
+func TestRuntimeDecoder_AllocationV1(t *testing.T) {
+	d := testRuntimeDecoder()
+	e, ok := decodeTestEvent(t, d, 1000, eventIDGCAllocationTick, 1, []uint32{100000, 1})
+	require.True(t, ok)
+	assert.Equal(t, EventKindAllocation, e.Kind)
+	assert.EqualValues(t, 100000, e.AllocationBytes)
+	assert.Equal(t, "large", e.AllocationKind)
+	assert.Empty(t, e.TypeName)
+}
+
+func TestRuntimeDecoder_ExceptionV0(t *testing.T) {
+	d := testRuntimeDecoder()
+	_, ok := decodeTestEvent(t, d, 1000, eventIDExceptionThrown, 0, nil)
+	assert.False(t, ok)
+}
+
+func TestRuntimeDecoder_Errors(t *testing.T) {
+	for _, id := range []int32{
+		eventIDGCSuspendEEBegin,
+		eventIDGCAllocationTick,
+		eventIDExceptionThrown,
+		eventIDContentionStop,
+	} {
+		d := testRuntimeDecoder()
+		rw := &network.FakeRW{ReadErrIdx: 0}
+		r := network.NewMultiReader(rw, &network.NopBlobWriter{})
+		_, _, err := d.decode(r, &eventHeader{}, metadataHeader{eventHeaderID: id, version: 4})
+		assert.Error(t, err)
+	}
+}
+
+func testRuntimeDecoder() *runtimeDecoder {
+	return &runtimeDecoder{trace: traceInfo{
+		syncTime:    time.Date(2021, 5, 24, 12, 0, 0, 0, time.UTC),
+		qpcFreq:     1000000,
+		pointerSize: 8,
+	}}
+}
+
+func decodeTestEvent(t *testing.T, d *runtimeDecoder, qpc int64, id int32, version int32, payload interface{}) (Event, bool) {
+	buf := &bytes.Buffer{}
+	if payload != nil {
+		require.NoError(t, binary.Write(buf, network.ByteOrder, payload))
+	}
+	r := network.NewMultiReader(network.NewBlobReader([][]byte{buf.Bytes()}), &network.NopBlobWriter{})
+	h := &eventHeader{timestamp: qpc, threadID: 1}
+	e, ok, err := d.decode(r, h, metadataHeader{eventHeaderID: id, version: version})
+	require.NoError(t, err)
+	return e, ok
+}
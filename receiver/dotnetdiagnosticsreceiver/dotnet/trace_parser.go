@@ -15,58 +15,97 @@
 package dotnet
 
 import (
+	"time"
+
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/network"
 )
 
-// parseTraceMessage parses a trace message, populating an eventSource struct. The
-// results are currently not used, but parsing this message is necessary for byte
-// alignment to process subsequent messages.
-// https://github.com/Microsoft/perfview/blob/main/src/TraceEvent/EventPipe/EventPipeFormat.md#the-first-object-the-trace-object
-func parseTraceMessage(r network.MultiReader) (err error) {
-	type eventSource struct {
-		syncTimeQPC             int64
-		qpcFreq                 int64
-		pointerSize             int32
-		processID               int32
-		numProcessors           int32
-		expectedCPUSamplingRate int32
-	}
+// traceInfo holds the clock and pointer size information extracted from the
+// trace message. It is used to convert event timestamps to wall-clock time and
+// to decode pointer-sized fields in runtime event payloads.
+type traceInfo struct {
+	syncTime                time.Time
+	syncTimeQPC             int64
+	qpcFreq                 int64
+	pointerSize             int32
+	processID               int32
+	numProcessors           int32
+	expectedCPUSamplingRate int32
+}
+
+// toTime converts a QPC (query performance counter) timestamp from an event
+// header to wall-clock time.
+func (ti traceInfo) toTime(qpc int64) time.Time {
+	return ti.syncTime.Add(ti.toDuration(qpc - ti.syncTimeQPC))
+}
 
-	es := eventSource{}
+// toDuration converts a number of QPC ticks to a time.Duration, taking care
+// not to overflow for large tick counts.
+func (ti traceInfo) toDuration(ticks int64) time.Duration {
+	if ti.qpcFreq <= 0 {
+		return 0
+	}
+	secs := ticks / ti.qpcFreq
+	rem := ticks % ti.qpcFreq
+	return time.Duration(secs)*time.Second + time.Duration(rem*int64(time.Second)/ti.qpcFreq)
+}
 
-	// skip date
-	const dateSize = 16
-	err = r.Seek(dateSize)
+// parseTraceMessage parses a trace message and returns the resulting traceInfo.
+// Parsing this message is also necessary for byte alignment to process
+// subsequent messages.
+// https://github.com/Microsoft/perfview/blob/main/src/TraceEvent/EventPipe/EventPipeFormat.md#the-first-object-the-trace-object
+func parseTraceMessage(r network.MultiReader) (ti traceInfo, err error) {
+	var st systemTime
+	err = r.Read(&st)
 	if err != nil {
 		return
 	}
+	ti.syncTime = st.toTime()
 
-	err = r.Read(&es.syncTimeQPC)
+	err = r.Read(&ti.syncTimeQPC)
 	if err != nil {
 		return
 	}
 
-	err = r.Read(&es.qpcFreq)
+	err = r.Read(&ti.qpcFreq)
 	if err != nil {
 		return
 	}
 
-	err = r.Read(&es.pointerSize)
+	err = r.Read(&ti.pointerSize)
 	if err != nil {
 		return
 	}
 
-	err = r.Read(&es.processID)
+	err = r.Read(&ti.processID)
 	if err != nil {
 		return
 	}
 
-	err = r.Read(&es.numProcessors)
+	err = r.Read(&ti.numProcessors)
 	if err != nil {
 		return
 	}
 
-	err = r.Read(&es.expectedCPUSamplingRate)
+	err = r.Read(&ti.expectedCPUSamplingRate)
 
 	return
 }
+
+// systemTime corresponds to the Windows SYSTEMTIME struct (year, month,
+// day of week, day, hour, minute, second, milliseconds) at the beginning of the
+// trace message.
+type systemTime [8]uint16
+
+func (st systemTime) toTime() time.Time {
+	return time.Date(
+		int(st[0]),
+		time.Month(st[1]),
+		int(st[3]),
+		int(st[4]),
+		int(st[5]),
+		int(st[6]),
+		int(st[7])*int(time.Millisecond),
+		time.UTC,
+	)
+}
@@ -17,6 +17,7 @@ package dotnet
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -30,9 +31,13 @@ func TestTraceParser(t *testing.T) {
 	reader := network.NewMultiReader(rw, &network.NopBlobWriter{})
 	err = reader.Seek(81)
 	require.NoError(t, err)
-	err = parseTraceMessage(reader)
+	ti, err := parseTraceMessage(reader)
 	require.NoError(t, err)
 	require.Equal(t, 129, reader.Pos())
+	require.EqualValues(t, 8, ti.pointerSize)
+	require.EqualValues(t, 1000000000, ti.qpcFreq)
+	require.Equal(t, ti.syncTime, ti.toTime(ti.syncTimeQPC))
+	require.Equal(t, ti.syncTime.Add(1500*time.Millisecond), ti.toTime(ti.syncTimeQPC+1500000000))
 }
 
 func TestTraceParser_Errors(t *testing.T) {
@@ -49,6 +54,6 @@ func testTraceParserReadError(t *testing.T, data [][]byte, i int) {
 	err := reader.Seek(81)
 	require.NoError(t, err)
 	rw.ErrOnRead(i)
-	err = parseTraceMessage(reader)
+	_, err = parseTraceMessage(reader)
 	require.Error(t, err)
 }
@@ -16,6 +16,7 @@ package dotnetdiagnosticsreceiver
 
 import (
 	"context"
+	"errors"
 	"io"
 	"math"
 	"net"
@@ -38,6 +39,7 @@ func NewFactory() component.ReceiverFactory {
 		typeStr,
 		createDefaultConfig,
 		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver),
 	)
 }
 
@@ -65,12 +67,41 @@ func createMetricsReceiver(
 		consumer,
 		mkConnectionSupplier(cfg.PID, net.Dial, filepath.Glob),
 		cfg.Counters,
+		cfg.runtimeKeywords("gc", "contention"),
 		sec,
 		params.Logger,
 		bw,
 	)
 }
 
+var errNoExceptionEvents = errors.New(`runtime_events must include "exceptions" to use this receiver in a logs pipeline`)
+
+func createLogsReceiver(
+	ctx context.Context,
+	params component.ReceiverCreateParams,
+	baseConfig config.Receiver,
+	consumer consumer.Logs,
+) (component.LogsReceiver, error) {
+	cfg := baseConfig.(*Config)
+	if cfg.runtimeKeywords("exceptions") == 0 {
+		return nil, errNoExceptionEvents
+	}
+	debugDir := cfg.LocalDebugDir
+	if debugDir != "" {
+		// the logs receiver has its own connection, so its stream is written
+		// separately from the metrics receiver's
+		debugDir = filepath.Join(debugDir, "logs")
+	}
+	bw := network.NewBlobWriter(debugDir, cfg.MaxLocalDebugFiles, params.Logger)
+	return NewLogsReceiver(
+		ctx,
+		consumer,
+		mkConnectionSupplier(cfg.PID, net.Dial, filepath.Glob),
+		params.Logger,
+		bw,
+	)
+}
+
 func mkConnectionSupplier(pid int, df network.DialFunc, gf network.GlobFunc) connectionSupplier {
 	return func() (io.ReadWriter, error) {
 		return network.Connect(pid, df, gf)
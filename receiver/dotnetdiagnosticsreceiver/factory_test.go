@@ -49,6 +49,19 @@ func TestNewFactory(t *testing.T) {
 	assert.NotNil(t, r)
 }
 
+func TestNewFactory_Logs(t *testing.T) {
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig()
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	_, err := f.CreateLogsReceiver(context.Background(), params, cfg, consumertest.NewNop())
+	assert.Equal(t, errNoExceptionEvents, err)
+
+	cfg.(*Config).RuntimeEvents = []string{"gc", "exceptions"}
+	r, err := f.CreateLogsReceiver(context.Background(), params, cfg, consumertest.NewNop())
+	assert.NoError(t, err)
+	assert.NotNil(t, r)
+}
+
 func TestMkConnectionSupplier(t *testing.T) {
 	connect := mkConnectionSupplier(0, func(network, address string) (net.Conn, error) {
 		return nil, nil
@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/dotnet"
+)
+
+const exceptionLogName = "dotnet.exception"
+
+func eventsToPdata(events []dotnet.Event) pdata.Logs {
+	ld := pdata.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	lrs := rl.InstrumentationLibraryLogs().AppendEmpty().Logs()
+	for _, e := range events {
+		if e.Kind != dotnet.EventKindException {
+			continue
+		}
+		exceptionToLogRecord(e, lrs.AppendEmpty())
+	}
+	return ld
+}
+
+// exceptionToLogRecord populates a log record from an exception event, using
+// the semantic conventions for exception attributes.
+// https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/trace/semantic_conventions/exceptions.md
+func exceptionToLogRecord(e dotnet.Event, lr pdata.LogRecord) {
+	lr.SetName(exceptionLogName)
+	lr.SetTimestamp(pdata.TimestampFromTime(e.Timestamp))
+	lr.SetSeverityNumber(pdata.SeverityNumberERROR)
+	lr.SetSeverityText("Error")
+	lr.Body().SetStringVal(fmt.Sprintf("%s: %s", e.ExceptionType, e.ExceptionMessage))
+	attrs := lr.Attributes()
+	attrs.InsertString("exception.type", e.ExceptionType)
+	attrs.InsertString("exception.message", e.ExceptionMessage)
+	attrs.InsertString("dotnet.exception.hresult", fmt.Sprintf("0x%08X", e.HResult))
+	attrs.InsertInt("thread.id", e.ThreadID)
+}
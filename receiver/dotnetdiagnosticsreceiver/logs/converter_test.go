@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/dotnet"
+)
+
+func TestEventsToPdata(t *testing.T) {
+	ts := time.Date(2021, 5, 24, 12, 0, 0, 0, time.UTC)
+	ld := eventsToPdata([]dotnet.Event{
+		{Kind: dotnet.EventKindGCPause, Duration: time.Millisecond},
+		{
+			Kind:             dotnet.EventKindException,
+			Timestamp:        ts,
+			ThreadID:         42,
+			ExceptionType:    "System.InvalidOperationException",
+			ExceptionMessage: "Operation is not valid",
+			HResult:          0x80131509,
+		},
+	})
+	require.Equal(t, 1, ld.LogRecordCount())
+	lr := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, "dotnet.exception", lr.Name())
+	assert.Equal(t, pdata.TimestampFromTime(ts), lr.Timestamp())
+	assert.Equal(t, pdata.SeverityNumberERROR, lr.SeverityNumber())
+	assert.Equal(t, "System.InvalidOperationException: Operation is not valid", lr.Body().StringVal())
+	assertAttr(t, lr, "exception.type", pdata.NewAttributeValueString("System.InvalidOperationException"))
+	assertAttr(t, lr, "exception.message", pdata.NewAttributeValueString("Operation is not valid"))
+	assertAttr(t, lr, "dotnet.exception.hresult", pdata.NewAttributeValueString("0x80131509"))
+	assertAttr(t, lr, "thread.id", pdata.NewAttributeValueInt(42))
+}
+
+func assertAttr(t *testing.T, lr pdata.LogRecord, k string, expected pdata.AttributeValue) {
+	v, ok := lr.Attributes().Get(k)
+	require.True(t, ok)
+	assert.True(t, expected.Equal(v))
+}
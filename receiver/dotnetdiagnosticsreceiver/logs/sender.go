@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/dotnet"
+)
+
+// Sender wraps a consumer.Logs, and has a Send method which conforms to
+// dotnet.EventsConsumer so it can be passed into a Parser.
+type Sender struct {
+	next   consumer.Logs
+	logger *zap.Logger
+}
+
+func NewSender(next consumer.Logs, logger *zap.Logger) *Sender {
+	return &Sender{next: next, logger: logger}
+}
+
+// Send accepts a slice of dotnet.Events, converts the exception events to
+// pdata.Logs, and sends them to the next pdata consumer. Other events are
+// ignored. Conforms to dotnet.EventsConsumer.
+func (s *Sender) Send(events []dotnet.Event) {
+	ld := eventsToPdata(events)
+	if ld.LogRecordCount() == 0 {
+		return
+	}
+	err := s.next.ConsumeLogs(context.Background(), ld)
+	if err != nil {
+		s.logger.Error(err.Error())
+	}
+}
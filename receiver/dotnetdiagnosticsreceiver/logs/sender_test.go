@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/dotnet"
+)
+
+func TestSend(t *testing.T) {
+	sink := &consumertest.LogsSink{}
+	s := NewSender(sink, zap.NewNop())
+	// events other than exceptions don't produce logs
+	s.Send([]dotnet.Event{{Kind: dotnet.EventKindContention}})
+	require.Equal(t, 0, sink.LogRecordsCount())
+	s.Send([]dotnet.Event{{Kind: dotnet.EventKindException}})
+	require.Equal(t, 1, sink.LogRecordsCount())
+}
+
+func TestSendError(t *testing.T) {
+	observedLogger, logs := observer.New(zapcore.WarnLevel)
+	s := NewSender(consumertest.NewErr(errors.New("")), zap.New(observedLogger))
+	s.Send([]dotnet.Event{{Kind: dotnet.EventKindException}})
+	require.Equal(t, 1, logs.Len())
+}
@@ -0,0 +1,200 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/dotnet"
+)
+
+// histogramDef describes a histogram metric built from runtime events.
+type histogramDef struct {
+	name        string
+	description string
+	unit        string
+	label       string
+	bounds      []float64
+}
+
+var durationBoundsMs = []float64{0.1, 0.5, 1, 5, 10, 50, 100, 500, 1000}
+
+var histogramDefs = map[dotnet.EventKind]histogramDef{
+	dotnet.EventKindGCPause: {
+		name:        "dotnet.gc.pause.duration",
+		description: "Time the runtime was suspended for garbage collection",
+		unit:        "ms",
+		bounds:      durationBoundsMs,
+	},
+	dotnet.EventKindAllocation: {
+		name:        "dotnet.gc.allocation.size",
+		description: "Bytes allocated between allocation tick events",
+		unit:        "By",
+		label:       "kind",
+		bounds:      []float64{16384, 65536, 131072, 262144, 1048576, 16777216},
+	},
+	dotnet.EventKindContention: {
+		name:        "dotnet.contention.duration",
+		description: "Time spent waiting to acquire a contended lock",
+		unit:        "ms",
+		bounds:      durationBoundsMs,
+	},
+}
+
+// eventValue returns the value recorded by a histogram for the passed-in
+// event, and the value of its label, if any.
+func eventValue(e dotnet.Event) (value float64, labelValue string) {
+	switch e.Kind {
+	case dotnet.EventKindAllocation:
+		return float64(e.AllocationBytes), e.AllocationKind
+	default:
+		return float64(e.Duration) / float64(time.Millisecond), ""
+	}
+}
+
+type histogramKey struct {
+	kind       dotnet.EventKind
+	labelValue string
+}
+
+type histogram struct {
+	count        uint64
+	sum          float64
+	bucketCounts []uint64
+}
+
+func (h *histogram) record(bounds []float64, v float64) {
+	if h.bucketCounts == nil {
+		h.bucketCounts = make([]uint64, len(bounds)+1)
+	}
+	h.count++
+	h.sum += v
+	h.bucketCounts[sort.SearchFloat64s(bounds, v)]++
+}
+
+// EventAggregator records runtime events in histograms and sends them to the
+// next consumer each time Flush is called. It has an Add method which conforms
+// to dotnet.EventsConsumer so it can be passed into a Parser.
+type EventAggregator struct {
+	next          consumer.Metrics
+	logger        *zap.Logger
+	mu            sync.Mutex
+	histograms    map[histogramKey]*histogram
+	prevFlushTime time.Time
+}
+
+func NewEventAggregator(next consumer.Metrics, logger *zap.Logger) *EventAggregator {
+	return &EventAggregator{
+		next:          next,
+		logger:        logger,
+		histograms:    map[histogramKey]*histogram{},
+		prevFlushTime: time.Now(),
+	}
+}
+
+// Add records the passed-in events. Events without a corresponding histogram
+// are ignored. Conforms to dotnet.EventsConsumer.
+func (a *EventAggregator) Add(events []dotnet.Event) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, e := range events {
+		def, ok := histogramDefs[e.Kind]
+		if !ok {
+			continue
+		}
+		v, labelValue := eventValue(e)
+		key := histogramKey{kind: e.Kind, labelValue: labelValue}
+		h, ok := a.histograms[key]
+		if !ok {
+			h = &histogram{}
+			a.histograms[key] = h
+		}
+		h.record(def.bounds, v)
+	}
+}
+
+// Flush converts the histograms recorded since the previous Flush to
+// pdata.Metrics with delta temporality, sends them to the next consumer, and
+// resets them. Nothing is sent if no events were recorded.
+func (a *EventAggregator) Flush() {
+	a.mu.Lock()
+	histograms := a.histograms
+	a.histograms = map[histogramKey]*histogram{}
+	now := time.Now()
+	startTime := a.prevFlushTime
+	a.prevFlushTime = now
+	a.mu.Unlock()
+
+	if len(histograms) == 0 {
+		return
+	}
+	pdm := histogramsToPdata(histograms, startTime, now)
+	err := a.next.ConsumeMetrics(context.Background(), pdm)
+	if err != nil {
+		a.logger.Error(err.Error())
+	}
+}
+
+func histogramsToPdata(histograms map[histogramKey]*histogram, startTime, now time.Time) pdata.Metrics {
+	pdm := pdata.NewMetrics()
+	rm := pdm.ResourceMetrics().AppendEmpty()
+	ms := rm.InstrumentationLibraryMetrics().AppendEmpty().Metrics()
+
+	// sorted so that the output is deterministic
+	keys := make([]histogramKey, 0, len(histograms))
+	for k := range histograms {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].kind != keys[j].kind {
+			return keys[i].kind < keys[j].kind
+		}
+		return keys[i].labelValue < keys[j].labelValue
+	})
+
+	metricsByKind := map[dotnet.EventKind]pdata.Metric{}
+	for _, k := range keys {
+		def := histogramDefs[k.kind]
+		m, ok := metricsByKind[k.kind]
+		if !ok {
+			m = ms.AppendEmpty()
+			m.SetName(def.name)
+			m.SetDescription(def.description)
+			m.SetUnit(def.unit)
+			m.SetDataType(pdata.MetricDataTypeHistogram)
+			m.Histogram().SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+			metricsByKind[k.kind] = m
+		}
+		h := histograms[k]
+		dp := m.Histogram().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(pdata.TimestampFromTime(startTime))
+		dp.SetTimestamp(pdata.TimestampFromTime(now))
+		dp.SetCount(h.count)
+		dp.SetSum(h.sum)
+		dp.SetBucketCounts(h.bucketCounts)
+		dp.SetExplicitBounds(def.bounds)
+		if def.label != "" {
+			dp.LabelsMap().Insert(def.label, k.labelValue)
+		}
+	}
+	return pdm
+}
@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/dotnet"
+)
+
+func TestEventAggregator(t *testing.T) {
+	sink := &consumertest.MetricsSink{}
+	a := NewEventAggregator(sink, zap.NewNop())
+	a.Add([]dotnet.Event{
+		{Kind: dotnet.EventKindGCPause, Duration: 300 * time.Microsecond},
+		{Kind: dotnet.EventKindGCPause, Duration: 20 * time.Millisecond},
+		{Kind: dotnet.EventKindAllocation, AllocationBytes: 100000, AllocationKind: "small"},
+		{Kind: dotnet.EventKindAllocation, AllocationBytes: 2000000, AllocationKind: "large"},
+		{Kind: dotnet.EventKindContention, Duration: time.Millisecond},
+		{Kind: dotnet.EventKindException, ExceptionType: "System.Exception"},
+	})
+	a.Flush()
+	require.Equal(t, 1, len(sink.AllMetrics()))
+	ms := sink.AllMetrics()[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 3, ms.Len())
+
+	contention := ms.At(1)
+	assert.Equal(t, "dotnet.contention.duration", contention.Name())
+
+	pause := ms.At(2)
+	assert.Equal(t, "dotnet.gc.pause.duration", pause.Name())
+	assert.Equal(t, "ms", pause.Unit())
+	assert.Equal(t, pdata.MetricDataTypeHistogram, pause.DataType())
+	assert.Equal(t, pdata.AggregationTemporalityDelta, pause.Histogram().AggregationTemporality())
+	dp := pause.Histogram().DataPoints().At(0)
+	assert.EqualValues(t, 2, dp.Count())
+	assert.InDelta(t, 20.3, dp.Sum(), 1e-9)
+	assert.Equal(t, []uint64{0, 1, 0, 0, 0, 1, 0, 0, 0, 0}, dp.BucketCounts())
+	assert.True(t, dp.StartTimestamp() <= dp.Timestamp())
+
+	alloc := ms.At(0)
+	assert.Equal(t, "dotnet.gc.allocation.size", alloc.Name())
+	assert.Equal(t, "By", alloc.Unit())
+	dps := alloc.Histogram().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	kind, _ := dps.At(0).LabelsMap().Get("kind")
+	assert.Equal(t, "large", kind)
+	assert.Equal(t, []uint64{0, 0, 0, 0, 0, 1, 0}, dps.At(0).BucketCounts())
+	kind, _ = dps.At(1).LabelsMap().Get("kind")
+	assert.Equal(t, "small", kind)
+	assert.Equal(t, []uint64{0, 0, 1, 0, 0, 0, 0}, dps.At(1).BucketCounts())
+
+	// histograms are reset after each flush, and nothing is sent if empty
+	a.Flush()
+	require.Equal(t, 1, len(sink.AllMetrics()))
+}
+
+func TestEventAggregator_SendError(t *testing.T) {
+	observedLogger, logs := observer.New(zapcore.WarnLevel)
+	a := NewEventAggregator(consumertest.NewErr(errors.New("")), zap.New(observedLogger))
+	a.Add([]dotnet.Event{{Kind: dotnet.EventKindGCPause}})
+	a.Flush()
+	require.Equal(t, 1, logs.Len())
+}
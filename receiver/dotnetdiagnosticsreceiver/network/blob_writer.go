@@ -75,7 +75,7 @@ func newBlobFileWriter(dir string, maxFiles int, logger *zap.Logger) *blobFileWr
 		logger:    logger,
 		dir:       dir,
 		maxFiles:  maxFiles,
-		mkdir:     os.Mkdir,
+		mkdir:     os.MkdirAll,
 		remove:    os.Remove,
 		writeFile: ioutil.WriteFile,
 	}
@@ -170,7 +170,7 @@ func (r *mReader) ReadUTF16() (s string, err error) {
 // ReadASCII reads an ASCII string of the given length from the underlying stream
 func (r *mReader) ReadASCII(strlen int) (string, error) {
 	b := make([]byte, strlen)
-	_, err := r.pr.Read(b)
+	_, err := io.ReadFull(r.pr, b)
 	if err != nil {
 		return "", err
 	}
@@ -193,7 +193,8 @@ func (r *mReader) AssertNextByteEquals(b byte) error {
 // Seek moves the current position forward by reading and throwing away the
 // specified number of bytes
 func (r *mReader) Seek(i int) error {
-	_, err := r.pr.Read(make([]byte, i))
+	// a single Read from a socket may return fewer bytes than requested
+	_, err := io.ReadFull(r.pr, make([]byte, i))
 	if err != nil {
 		return err
 	}
@@ -17,22 +17,29 @@ package dotnetdiagnosticsreceiver
 import (
 	"context"
 	"io"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/dotnet"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/logs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/metrics"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/network"
 )
 
 type receiver struct {
-	nextConsumer consumer.Metrics
-	connect      connectionSupplier
-	counters     []string
-	intervalSec  int
-	logger       *zap.Logger
+	connect         connectionSupplier
+	counters        []string
+	runtimeKeywords dotnet.RuntimeKeywords
+	intervalSec     int
+	logger          *zap.Logger
+
+	consumeMetrics dotnet.MetricsConsumer
+	consumeEvents  dotnet.EventsConsumer
+	// flush, if set, is called at each interval while the receiver is running
+	flush func()
 
 	bw     network.BlobWriter
 	cancel context.CancelFunc
@@ -40,24 +47,52 @@ type receiver struct {
 
 type connectionSupplier func() (io.ReadWriter, error)
 
-// NewReceiver creates a new receiver. connectionSupplier is swappable for
-// testing.
+// NewReceiver creates a new metrics receiver. If runtimeKeywords is nonzero,
+// the requested runtime events are aggregated into histograms and sent
+// every intervalSec. connectionSupplier is swappable for testing.
 func NewReceiver(
 	_ context.Context,
 	mc consumer.Metrics,
 	connect connectionSupplier,
 	counters []string,
+	runtimeKeywords dotnet.RuntimeKeywords,
 	intervalSec int,
 	logger *zap.Logger,
 	bw network.BlobWriter,
 ) (component.MetricsReceiver, error) {
+	r := &receiver{
+		connect:         connect,
+		counters:        counters,
+		runtimeKeywords: runtimeKeywords,
+		intervalSec:     intervalSec,
+		logger:          logger,
+		consumeMetrics:  metrics.NewSender(mc, logger).Send,
+		bw:              bw,
+	}
+	if runtimeKeywords != 0 {
+		aggregator := metrics.NewEventAggregator(mc, logger)
+		r.consumeEvents = aggregator.Add
+		r.flush = aggregator.Flush
+	}
+	return r, nil
+}
+
+// NewLogsReceiver creates a new logs receiver, which requests exception events
+// from the runtime and converts them to log records. connectionSupplier is
+// swappable for testing.
+func NewLogsReceiver(
+	_ context.Context,
+	lc consumer.Logs,
+	connect connectionSupplier,
+	logger *zap.Logger,
+	bw network.BlobWriter,
+) (component.LogsReceiver, error) {
 	return &receiver{
-		nextConsumer: mc,
-		connect:      connect,
-		counters:     counters,
-		intervalSec:  intervalSec,
-		logger:       logger,
-		bw:           bw,
+		connect:         connect,
+		runtimeKeywords: dotnet.RuntimeKeywordException,
+		logger:          logger,
+		consumeEvents:   logs.NewSender(lc, logger).Send,
+		bw:              bw,
 	}, nil
 }
 
@@ -67,7 +102,7 @@ func (r *receiver) Start(ctx context.Context, host component.Host) error {
 		return err
 	}
 
-	w := dotnet.NewRequestWriter(conn, r.intervalSec, r.counters...)
+	w := dotnet.NewRequestWriter(conn, r.intervalSec, r.runtimeKeywords, r.counters...)
 	err = w.SendRequest()
 	if err != nil {
 		return err
@@ -78,8 +113,7 @@ func (r *receiver) Start(ctx context.Context, host component.Host) error {
 		return err
 	}
 
-	sender := metrics.NewSender(r.nextConsumer, r.logger)
-	p := dotnet.NewParser(conn, sender.Send, r.bw, r.logger)
+	p := dotnet.NewParser(conn, r.consumeMetrics, r.consumeEvents, r.bw, r.logger)
 
 	err = p.ParseIPC()
 	if err != nil {
@@ -91,16 +125,37 @@ func (r *receiver) Start(ctx context.Context, host component.Host) error {
 		return err
 	}
 
+	ctx, r.cancel = context.WithCancel(context.Background())
 	go func() {
-		ctx, r.cancel = context.WithCancel(context.Background())
-		err = p.ParseAll(ctx)
+		err := p.ParseAll(ctx)
 		if err != nil {
 			r.logger.Error("parseAll error", zap.Error(err))
 		}
 	}()
+	if r.flush != nil {
+		go r.flushEvery(ctx, time.Duration(r.intervalSec)*time.Second)
+	}
 	return nil
 }
 
+// flushEvery calls flush at the passed-in interval until the context is
+// cancelled.
+func (r *receiver) flushEvery(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.flush()
+		}
+	}
+}
+
 func (r *receiver) Shutdown(context.Context) error {
 	if r.cancel != nil {
 		r.cancel()
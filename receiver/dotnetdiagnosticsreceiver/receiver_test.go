@@ -15,19 +15,25 @@
 package dotnetdiagnosticsreceiver
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"errors"
 	"io"
+	"path"
 	"testing"
 	"time"
+	"unicode/utf16"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/dotnet"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dotnetdiagnosticsreceiver/network"
 )
 
@@ -43,6 +49,7 @@ func TestReceiverBlobData(t *testing.T) {
 			return rw, nil
 		},
 		nil,
+		0,
 		1,
 		zap.NewNop(),
 		&network.NopBlobWriter{},
@@ -55,6 +62,92 @@ func TestReceiverBlobData(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestReceiverBlobData_RuntimeEvents(t *testing.T) {
+	data, err := network.ReadBlobData(path.Join("testdata", "runtime"), 25)
+	require.NoError(t, err)
+	rw := network.NewBlobReader(data)
+	ctx := context.Background()
+	sink := &consumertest.MetricsSink{}
+	r, err := NewReceiver(
+		ctx,
+		sink,
+		func() (io.ReadWriter, error) {
+			return rw, nil
+		},
+		[]string{"System.Runtime"},
+		dotnet.RuntimeKeywordGC|dotnet.RuntimeKeywordContention,
+		1,
+		zap.NewNop(),
+		&network.NopBlobWriter{},
+	)
+	require.NoError(t, err)
+	err = r.Start(ctx, componenttest.NewNopHost())
+	require.NoError(t, err)
+	<-rw.Gate()
+	r.(*receiver).flush()
+	err = r.Shutdown(ctx)
+	require.NoError(t, err)
+
+	histograms := map[string]uint64{}
+	for _, pdm := range sink.AllMetrics() {
+		ms := pdm.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+		for i := 0; i < ms.Len(); i++ {
+			m := ms.At(i)
+			if m.DataType() != pdata.MetricDataTypeHistogram {
+				continue
+			}
+			dps := m.Histogram().DataPoints()
+			for j := 0; j < dps.Len(); j++ {
+				histograms[m.Name()] += dps.At(j).Count()
+			}
+		}
+	}
+	assert.Equal(t, map[string]uint64{
+		"dotnet.gc.pause.duration":   7,
+		"dotnet.gc.allocation.size":  169,
+		"dotnet.contention.duration": 44,
+	}, histograms)
+}
+
+func TestLogsReceiverBlobData(t *testing.T) {
+	data, err := network.ReadBlobData(path.Join("testdata", "runtime"), 25)
+	require.NoError(t, err)
+	rw := network.NewBlobReader(data)
+	ctx := context.Background()
+	sink := &consumertest.LogsSink{}
+	r, err := NewLogsReceiver(
+		ctx,
+		sink,
+		func() (io.ReadWriter, error) {
+			return rw, nil
+		},
+		zap.NewNop(),
+		&network.NopBlobWriter{},
+	)
+	require.NoError(t, err)
+	err = r.Start(ctx, componenttest.NewNopHost())
+	require.NoError(t, err)
+	// the request includes the runtime provider with only the exception keyword
+	assert.True(t, bytes.Contains(rw.WriteBuf, utf16Bytes("Microsoft-Windows-DotNETRuntime")))
+	<-rw.Gate()
+	err = r.Shutdown(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, 7, sink.LogRecordsCount())
+	lr := sink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	v, ok := lr.Attributes().Get("exception.type")
+	require.True(t, ok)
+	assert.Equal(t, "System.InvalidOperationException", v.StringVal())
+}
+
+func utf16Bytes(s string) []byte {
+	buf := &bytes.Buffer{}
+	for _, c := range utf16.Encode([]rune(s)) {
+		_ = binary.Write(buf, network.ByteOrder, c)
+	}
+	return buf.Bytes()
+}
+
 func TestReceiverBlobData_ParsingError(t *testing.T) {
 	data, err := network.ReadBlobData("testdata", 16)
 	require.NoError(t, err)
@@ -70,6 +163,7 @@ func TestReceiverBlobData_ParsingError(t *testing.T) {
 			return rw, nil
 		},
 		nil,
+		0,
 		1,
 		zap.New(obs),
 		&network.NopBlobWriter{},
@@ -103,6 +197,7 @@ func testErrOnReceiverStart(t *testing.T, connect func() (io.ReadWriter, error),
 		consumertest.NewNop(),
 		connect,
 		nil,
+		0,
 		1,
 		zap.NewNop(),
 		&network.NopBlobWriter{},
@@ -130,6 +225,7 @@ func testReceiverReadErr(i int) error {
 			return rw, nil
 		},
 		nil,
+		0,
 		1,
 		zap.NewNop(),
 		&network.NopBlobWriter{},
@@ -14,7 +14,12 @@
 
 package fluentforwardreceiver
 
-import "go.opentelemetry.io/collector/config"
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtls"
+)
 
 // Config defines configuration for the SignalFx receiver.
 type Config struct {
@@ -24,4 +29,50 @@ type Config struct {
 	// of the form `<ip addr>:<port>` (TCP) or `unix://<socket_path>` (Unix
 	// domain socket).
 	ListenAddress string `mapstructure:"endpoint"`
+
+	// TLSSetting enables TLS on the listener if set.
+	TLSSetting *configtls.TLSServerSetting `mapstructure:"tls"`
+
+	// Security enables the handshake portion of the Forward protocol. Clients
+	// must authenticate with the shared key (and, optionally, a username and
+	// password) before sending any events.
+	Security *SecurityConfig `mapstructure:"security"`
+}
+
+// SecurityConfig configures the shared key handshake, see
+// https://github.com/fluent/fluentd/wiki/Forward-Protocol-Specification-v1#handshake-messages
+type SecurityConfig struct {
+	// SelfHostname is the hostname sent to clients in the HELO and PONG
+	// messages. Defaults to the OS hostname.
+	SelfHostname string `mapstructure:"self_hostname"`
+
+	// SharedKey is the key that must be shared between the client and this
+	// receiver.
+	SharedKey string `mapstructure:"shared_key"`
+
+	// UserAuth requires clients to also authenticate with one of the
+	// configured Users.
+	UserAuth bool `mapstructure:"user_auth"`
+
+	// Users are the accepted username/password pairs if UserAuth is enabled.
+	Users []UserConfig `mapstructure:"users"`
+}
+
+// UserConfig is a username/password pair for user authentication.
+type UserConfig struct {
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+func (c *Config) Validate() error {
+	if c.Security == nil {
+		return nil
+	}
+	if c.Security.SharedKey == "" {
+		return errors.New("security.shared_key must be set when security is enabled")
+	}
+	if c.Security.UserAuth && len(c.Security.Users) == 0 {
+		return errors.New("security.users must not be empty when security.user_auth is enabled")
+	}
+	return nil
 }
@@ -23,6 +23,7 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/config/configtls"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -38,9 +39,42 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 1)
+	assert.Equal(t, len(cfg.Receivers), 2)
 
 	r0 := cfg.Receivers[config.NewID("fluentforward")]
 	assert.Equal(t, r0, factory.CreateDefaultConfig())
 
+	r1 := cfg.Receivers[config.NewIDWithName("fluentforward", "secure")]
+	assert.Equal(t, r1, &Config{
+		ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName("fluentforward", "secure")),
+		ListenAddress:    "0.0.0.0:24224",
+		TLSSetting: &configtls.TLSServerSetting{
+			TLSSetting: configtls.TLSSetting{
+				CertFile: "/test.crt",
+				KeyFile:  "/test.key",
+			},
+		},
+		Security: &SecurityConfig{
+			SelfHostname: "collector.example.com",
+			SharedKey:    "secret",
+			UserAuth:     true,
+			Users: []UserConfig{
+				{Username: "alice", Password: "password1"},
+			},
+		},
+	})
+}
+
+func TestValidateConfig(t *testing.T) {
+	cfg := &Config{Security: &SecurityConfig{}}
+	assert.EqualError(t, cfg.Validate(), "security.shared_key must be set when security is enabled")
+
+	cfg.Security.SharedKey = "secret"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Security.UserAuth = true
+	assert.EqualError(t, cfg.Validate(), "security.users must not be empty when security.user_auth is enabled")
+
+	cfg.Security.Users = []UserConfig{{Username: "alice", Password: "password1"}}
+	assert.NoError(t, cfg.Validate())
 }
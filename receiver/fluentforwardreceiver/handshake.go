@@ -0,0 +1,237 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fluentforwardreceiver
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+// handshakeTimeout bounds how long a client has to authenticate after
+// connecting.
+const handshakeTimeout = 10 * time.Second
+
+const saltLength = 16
+
+var errAuthenticationFailed = errors.New("authentication failed")
+
+// handshake performs the HELO/PING/PONG exchange with a newly connected
+// client. See
+// https://github.com/fluent/fluentd/wiki/Forward-Protocol-Specification-v1#handshake-messages
+func (s *server) handshake(conn net.Conn, reader *msgp.Reader) error {
+	if err := conn.SetDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		return err
+	}
+
+	nonce, err := generateSalt()
+	if err != nil {
+		return err
+	}
+	var authSalt []byte
+	if s.security.UserAuth {
+		if authSalt, err = generateSalt(); err != nil {
+			return err
+		}
+	}
+
+	writer := msgp.NewWriter(conn)
+	if err := writeHelo(writer, nonce, authSalt); err != nil {
+		return fmt.Errorf("failed to send HELO: %v", err)
+	}
+
+	ping, err := readPing(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read PING: %v", err)
+	}
+
+	reason := s.checkPing(ping, nonce, authSalt)
+	if reason != "" {
+		// Like Fluentd, don't reveal our hostname or a digest of the shared key
+		// to a client that failed to authenticate.
+		if err := writePong(writer, false, reason, "", ""); err != nil {
+			return fmt.Errorf("failed to send PONG: %v", err)
+		}
+		return fmt.Errorf("%w for %s: %s", errAuthenticationFailed, ping.hostname, reason)
+	}
+	digest := sharedKeyDigest(ping.sharedKeySalt, s.selfHostname, nonce, s.security.SharedKey)
+	if err := writePong(writer, true, "", s.selfHostname, digest); err != nil {
+		return fmt.Errorf("failed to send PONG: %v", err)
+	}
+
+	return conn.SetDeadline(time.Time{})
+}
+
+// checkPing verifies the client credentials and returns the reason for
+// rejecting the client, or an empty string if it is accepted.
+func (s *server) checkPing(ping *pingMessage, nonce, authSalt []byte) string {
+	expected := sharedKeyDigest(ping.sharedKeySalt, ping.hostname, nonce, s.security.SharedKey)
+	if !digestEqual(expected, ping.sharedKeyDigest) {
+		return "shared_key mismatch"
+	}
+
+	if !s.security.UserAuth {
+		return ""
+	}
+	for _, u := range s.security.Users {
+		if u.Username != ping.username {
+			continue
+		}
+		if digestEqual(passwordDigest(authSalt, u.Username, u.Password), ping.passwordDigest) {
+			return ""
+		}
+	}
+	return "username/password mismatch"
+}
+
+type pingMessage struct {
+	hostname        string
+	sharedKeySalt   []byte
+	sharedKeyDigest string
+	username        string
+	passwordDigest  string
+}
+
+func writeHelo(writer *msgp.Writer, nonce, authSalt []byte) error {
+	if err := writer.WriteArrayHeader(2); err != nil {
+		return err
+	}
+	if err := writer.WriteString("HELO"); err != nil {
+		return err
+	}
+	if err := writer.WriteMapHeader(3); err != nil {
+		return err
+	}
+	if err := writer.WriteString("nonce"); err != nil {
+		return err
+	}
+	if err := writer.WriteBytes(nonce); err != nil {
+		return err
+	}
+	if err := writer.WriteString("auth"); err != nil {
+		return err
+	}
+	if err := writer.WriteBytes(authSalt); err != nil {
+		return err
+	}
+	if err := writer.WriteString("keepalive"); err != nil {
+		return err
+	}
+	if err := writer.WriteBool(true); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+func readPing(reader *msgp.Reader) (*pingMessage, error) {
+	size, err := reader.ReadArrayHeader()
+	if err != nil {
+		return nil, err
+	}
+	if size != 6 {
+		return nil, fmt.Errorf("expected 6 elements but got %d", size)
+	}
+
+	fields := make([]string, size)
+	for i := range fields {
+		if fields[i], err = readStringOrBytes(reader); err != nil {
+			return nil, err
+		}
+	}
+
+	if fields[0] != "PING" {
+		return nil, fmt.Errorf("unexpected message type %q", fields[0])
+	}
+
+	return &pingMessage{
+		hostname:        fields[1],
+		sharedKeySalt:   []byte(fields[2]),
+		sharedKeyDigest: fields[3],
+		username:        fields[4],
+		passwordDigest:  fields[5],
+	}, nil
+}
+
+func writePong(writer *msgp.Writer, ok bool, reason string, hostname string, digest string) error {
+	if err := writer.WriteArrayHeader(5); err != nil {
+		return err
+	}
+	if err := writer.WriteString("PONG"); err != nil {
+		return err
+	}
+	if err := writer.WriteBool(ok); err != nil {
+		return err
+	}
+	if err := writer.WriteString(reason); err != nil {
+		return err
+	}
+	if err := writer.WriteString(hostname); err != nil {
+		return err
+	}
+	if err := writer.WriteString(digest); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+// Clients are not consistent about whether salts and nonces are sent as str
+// or bin, so accept either.
+func readStringOrBytes(reader *msgp.Reader) (string, error) {
+	t, err := reader.NextType()
+	if err != nil {
+		return "", err
+	}
+	if t == msgp.BinType {
+		b, err := reader.ReadBytes(nil)
+		return string(b), err
+	}
+	return reader.ReadString()
+}
+
+func generateSalt() ([]byte, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+func sharedKeyDigest(salt []byte, hostname string, nonce []byte, sharedKey string) string {
+	h := sha512.New()
+	h.Write(salt)
+	h.Write([]byte(hostname))
+	h.Write(nonce)
+	h.Write([]byte(sharedKey))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func passwordDigest(authSalt []byte, username string, password string) string {
+	h := sha512.New()
+	h.Write(authSalt)
+	h.Write([]byte(username))
+	h.Write([]byte(password))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func digestEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,200 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fluentforwardreceiver
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinylib/msgp/msgp"
+)
+
+type pongMessage struct {
+	ok       bool
+	reason   string
+	hostname string
+	digest   string
+}
+
+// clientHandshake plays the client side of the handshake the way Fluentd's
+// forward output does.
+func clientHandshake(t *testing.T, conn net.Conn, sharedKey, username, password string) *pongMessage {
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+	reader := msgp.NewReader(conn)
+
+	size, err := reader.ReadArrayHeader()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, size)
+	msgType, err := reader.ReadString()
+	require.NoError(t, err)
+	require.Equal(t, "HELO", msgType)
+
+	options := map[string]interface{}{}
+	require.NoError(t, reader.ReadMapStrIntf(options))
+	nonce := options["nonce"].([]byte)
+	authSalt := options["auth"].([]byte)
+	require.Len(t, nonce, saltLength)
+	require.Equal(t, true, options["keepalive"])
+
+	salt := []byte("client-salt")
+	var b []byte
+	b = msgp.AppendArrayHeader(b, 6)
+	b = msgp.AppendString(b, "PING")
+	b = msgp.AppendString(b, "client.example.com")
+	b = msgp.AppendBytes(b, salt)
+	b = msgp.AppendString(b, sharedKeyDigest(salt, "client.example.com", nonce, sharedKey))
+	b = msgp.AppendString(b, username)
+	if username != "" {
+		b = msgp.AppendString(b, passwordDigest(authSalt, username, password))
+	} else {
+		b = msgp.AppendString(b, "")
+	}
+	_, err = conn.Write(b)
+	require.NoError(t, err)
+
+	size, err = reader.ReadArrayHeader()
+	require.NoError(t, err)
+	require.EqualValues(t, 5, size)
+	msgType, err = reader.ReadString()
+	require.NoError(t, err)
+	require.Equal(t, "PONG", msgType)
+
+	pong := &pongMessage{}
+	pong.ok, err = reader.ReadBool()
+	require.NoError(t, err)
+	pong.reason, err = reader.ReadString()
+	require.NoError(t, err)
+	pong.hostname, err = reader.ReadString()
+	require.NoError(t, err)
+	pong.digest, err = reader.ReadString()
+	require.NoError(t, err)
+
+	// The server proves it knows the shared key too.
+	if pong.ok {
+		require.Equal(t, sharedKeyDigest(salt, pong.hostname, nonce, sharedKey), pong.digest)
+	}
+
+	require.NoError(t, conn.SetDeadline(time.Time{}))
+	return pong
+}
+
+func TestHandshake(t *testing.T) {
+	connect, next, _, cancel := setupServerWithConfig(t, &Config{
+		ListenAddress: "127.0.0.1:0",
+		Security: &SecurityConfig{
+			SelfHostname: "collector.example.com",
+			SharedKey:    "secret",
+		},
+	})
+	defer cancel()
+
+	conn := connect()
+	pong := clientHandshake(t, conn, "secret", "", "")
+	require.True(t, pong.ok)
+	require.Equal(t, "collector.example.com", pong.hostname)
+
+	_, err := conn.Write(parseHexDump("testdata/message-event"))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool {
+		return len(next.AllLogs()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestHandshakeSharedKeyMismatch(t *testing.T) {
+	connect, next, logs, cancel := setupServerWithConfig(t, &Config{
+		ListenAddress: "127.0.0.1:0",
+		Security: &SecurityConfig{
+			SharedKey: "secret",
+		},
+	})
+	defer cancel()
+
+	conn := connect()
+	pong := clientHandshake(t, conn, "wrong", "", "")
+	require.False(t, pong.ok)
+	require.Equal(t, "shared_key mismatch", pong.reason)
+	// a failed PING must not get a digest of the shared key back
+	require.Empty(t, pong.hostname)
+	require.Empty(t, pong.digest)
+
+	waitForConnectionClose(t, conn)
+	require.Len(t, logs.FilterMessage("Client failed to authenticate").All(), 1)
+	require.Empty(t, next.AllLogs())
+}
+
+func TestHandshakeUserAuth(t *testing.T) {
+	connect, next, _, cancel := setupServerWithConfig(t, &Config{
+		ListenAddress: "127.0.0.1:0",
+		Security: &SecurityConfig{
+			SharedKey: "secret",
+			UserAuth:  true,
+			Users: []UserConfig{
+				{Username: "alice", Password: "password1"},
+				{Username: "bob", Password: "password2"},
+			},
+		},
+	})
+	defer cancel()
+
+	conn := connect()
+	pong := clientHandshake(t, conn, "secret", "bob", "password2")
+	require.True(t, pong.ok)
+
+	_, err := conn.Write(parseHexDump("testdata/message-event"))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool {
+		return len(next.AllLogs()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	for _, creds := range [][2]string{{"bob", "password1"}, {"carol", "password2"}, {"", ""}} {
+		conn = connect()
+		pong = clientHandshake(t, conn, "secret", creds[0], creds[1])
+		require.False(t, pong.ok)
+		require.Equal(t, "username/password mismatch", pong.reason)
+		require.Empty(t, pong.digest)
+		waitForConnectionClose(t, conn)
+	}
+}
+
+func TestHandshakeRequired(t *testing.T) {
+	connect, next, _, cancel := setupServerWithConfig(t, &Config{
+		ListenAddress: "127.0.0.1:0",
+		Security: &SecurityConfig{
+			SharedKey: "secret",
+		},
+	})
+	defer cancel()
+
+	// Skipping the handshake and sending an event straight away must not get
+	// the event accepted.
+	conn := connect()
+	_, err := conn.Write(parseHexDump("testdata/message-event"))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	buf := make([]byte, 4096)
+	for {
+		if _, err = conn.Read(buf); err != nil {
+			break
+		}
+	}
+	require.Empty(t, next.AllLogs())
+}
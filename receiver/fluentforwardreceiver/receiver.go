@@ -16,8 +16,13 @@ package fluentforwardreceiver
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"net"
+	"os"
 	"strings"
+	"syscall"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
@@ -42,7 +47,10 @@ func newFluentReceiver(logger *zap.Logger, conf *Config, next consumer.Logs) (co
 
 	collector := newCollector(eventCh, next, logger)
 
-	server := newServer(eventCh, logger)
+	server, err := newServer(eventCh, logger, conf.Security)
+	if err != nil {
+		return nil, err
+	}
 
 	return &fluentReceiver{
 		collector: collector,
@@ -64,7 +72,11 @@ func (r *fluentReceiver) Start(ctx context.Context, _ component.Host) error {
 	var udpListener net.PacketConn
 	var err error
 	if strings.HasPrefix(listenAddr, "/") || strings.HasPrefix(listenAddr, "unix://") {
-		listener, err = net.Listen("unix", strings.TrimPrefix(listenAddr, "unix://"))
+		socketPath := strings.TrimPrefix(listenAddr, "unix://")
+		if err = removeStaleSocket(socketPath); err != nil {
+			return err
+		}
+		listener, err = net.Listen("unix", socketPath)
 	} else {
 		listener, err = net.Listen("tcp", listenAddr)
 		if err == nil {
@@ -76,6 +88,18 @@ func (r *fluentReceiver) Start(ctx context.Context, _ component.Host) error {
 		return err
 	}
 
+	if r.conf.TLSSetting != nil {
+		tlsConfig, err := r.conf.TLSSetting.LoadTLSConfig()
+		if err != nil {
+			listener.Close()
+			if udpListener != nil {
+				udpListener.Close()
+			}
+			return err
+		}
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
 	r.listener = listener
 
 	r.server.Start(receiverCtx, listener)
@@ -88,6 +112,38 @@ func (r *fluentReceiver) Start(ctx context.Context, _ component.Host) error {
 }
 
 func (r *fluentReceiver) Shutdown(context.Context) error {
-	r.cancel()
+	if r.cancel != nil {
+		r.cancel()
+	}
+	if r.listener != nil {
+		// Closing a Unix listener also removes its socket file.
+		return r.listener.Close()
+	}
 	return nil
 }
+
+// removeStaleSocket removes a socket file left behind by a previous process
+// that did not shut down cleanly, otherwise listening on it would fail.
+// Anything other than a socket at that path is left alone, as is a socket
+// that accepts connections so another process is not silently taken over.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+	conn, err := net.Dial("unix", path)
+	if err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("socket %s: %w", path, syscall.EADDRINUSE)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return fmt.Errorf("failed to check if socket %s is stale: %w", path, err)
+	}
+	return os.Remove(path)
+}
@@ -16,6 +16,9 @@ package fluentforwardreceiver
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -23,11 +26,13 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/tinylib/msgp/msgp"
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.uber.org/zap"
@@ -35,22 +40,30 @@ import (
 )
 
 func setupServer(t *testing.T) (func() net.Conn, *consumertest.LogsSink, *observer.ObservedLogs, context.CancelFunc) {
+	return setupServerWithConfig(t, &Config{
+		ListenAddress: "127.0.0.1:0",
+	})
+}
+
+func setupServerWithConfig(t *testing.T, conf *Config) (func() net.Conn, *consumertest.LogsSink, *observer.ObservedLogs, context.CancelFunc) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	next := new(consumertest.LogsSink)
 	logCore, logObserver := observer.New(zap.DebugLevel)
 	logger := zap.New(logCore)
 
-	conf := &Config{
-		ListenAddress: "127.0.0.1:0",
-	}
-
 	receiver, err := newFluentReceiver(logger, conf, next)
 	require.NoError(t, err)
 	require.NoError(t, receiver.Start(ctx, nil))
 
 	connect := func() net.Conn {
-		conn, err := net.Dial("tcp", receiver.(*fluentReceiver).listener.Addr().String())
+		addr := receiver.(*fluentReceiver).listener.Addr().String()
+		if conf.TLSSetting != nil {
+			conn, err := tls.Dial("tcp", addr, clientTLSConfig(t))
+			require.Nil(t, err)
+			return conn
+		}
+		conn, err := net.Dial("tcp", addr)
 		require.Nil(t, err)
 		return conn
 	}
@@ -375,6 +388,133 @@ func TestUnixEndpoint(t *testing.T) {
 	}, 5*time.Second, 10*time.Millisecond)
 }
 
+func TestUnixEndpointStaleSocket(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "fluent-socket")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(tmpdir)
+
+	socketPath := filepath.Join(tmpdir, "fluent.sock")
+
+	// Leave a socket file behind, as a process that crashed would.
+	stale, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+	_, err = os.Stat(socketPath)
+	require.NoError(t, err)
+
+	next := new(consumertest.LogsSink)
+	receiver, err := newFluentReceiver(zap.NewNop(), &Config{ListenAddress: "unix://" + socketPath}, next)
+	require.NoError(t, err)
+	require.NoError(t, receiver.Start(context.Background(), nil))
+
+	conn, err := net.Dial("unix", socketPath)
+	require.NoError(t, err)
+
+	_, err = conn.Write(parseHexDump("testdata/message-event"))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool {
+		return len(next.AllLogs()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, receiver.Shutdown(context.Background()))
+
+	_, err = os.Stat(socketPath)
+	require.True(t, os.IsNotExist(err), "socket file should be removed on shutdown")
+}
+
+func TestUnixEndpointInUse(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "fluent-socket")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(tmpdir)
+
+	socketPath := filepath.Join(tmpdir, "fluent.sock")
+
+	// Another process is still listening on the socket.
+	active, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	defer active.Close()
+
+	receiver, err := newFluentReceiver(zap.NewNop(), &Config{ListenAddress: "unix://" + socketPath}, consumertest.NewNop())
+	require.NoError(t, err)
+	err = receiver.Start(context.Background(), nil)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, syscall.EADDRINUSE), err.Error())
+
+	// The socket still belongs to the other listener.
+	conn, err := net.Dial("unix", socketPath)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+}
+
+func TestUnixEndpointNotASocket(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "fluent-socket")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(tmpdir)
+
+	path := filepath.Join(tmpdir, "fluent.sock")
+	require.NoError(t, ioutil.WriteFile(path, []byte("not a socket"), 0600))
+
+	receiver, err := newFluentReceiver(zap.NewNop(), &Config{ListenAddress: "unix://" + path}, consumertest.NewNop())
+	require.NoError(t, err)
+	require.Error(t, receiver.Start(context.Background(), nil))
+
+	_, err = os.Stat(path)
+	require.NoError(t, err)
+}
+
+func TestTLS(t *testing.T) {
+	connect, next, _, cancel := setupServerWithConfig(t, &Config{
+		ListenAddress: "127.0.0.1:0",
+		TLSSetting: &configtls.TLSServerSetting{
+			TLSSetting: configtls.TLSSetting{
+				CertFile: "./testdata/server.crt",
+				KeyFile:  "./testdata/server.key",
+			},
+		},
+	})
+	defer cancel()
+
+	conn := connect()
+	_, err := conn.Write(parseHexDump("testdata/message-event"))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool {
+		return len(next.AllLogs()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestTLSInvalidCertificate(t *testing.T) {
+	receiver, err := newFluentReceiver(zap.NewNop(), &Config{
+		ListenAddress: "127.0.0.1:0",
+		TLSSetting: &configtls.TLSServerSetting{
+			TLSSetting: configtls.TLSSetting{
+				CertFile: "./testdata/nonexistent.crt",
+				KeyFile:  "./testdata/nonexistent.key",
+			},
+		},
+	}, consumertest.NewNop())
+	require.NoError(t, err)
+	require.Error(t, receiver.Start(context.Background(), nil))
+}
+
+func clientTLSConfig(t *testing.T) *tls.Config {
+	ca, err := ioutil.ReadFile("./testdata/ca.crt")
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(ca))
+	return &tls.Config{
+		RootCAs:    pool,
+		ServerName: "localhost",
+	}
+}
+
 func makeSampleEvent(tag string) []byte {
 	var b []byte
 
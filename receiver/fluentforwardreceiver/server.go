@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"os"
 	"time"
 
 	"github.com/tinylib/msgp/msgp"
@@ -35,15 +36,31 @@ import (
 const readBufferSize = 10 * 1024
 
 type server struct {
-	outCh  chan<- Event
-	logger *zap.Logger
+	outCh        chan<- Event
+	logger       *zap.Logger
+	security     *SecurityConfig
+	selfHostname string
 }
 
-func newServer(outCh chan<- Event, logger *zap.Logger) *server {
-	return &server{
-		outCh:  outCh,
-		logger: logger,
+func newServer(outCh chan<- Event, logger *zap.Logger, security *SecurityConfig) (*server, error) {
+	s := &server{
+		outCh:    outCh,
+		logger:   logger,
+		security: security,
 	}
+
+	if security != nil {
+		s.selfHostname = security.SelfHostname
+		if s.selfHostname == "" {
+			hostname, err := os.Hostname()
+			if err != nil {
+				return nil, fmt.Errorf("could not determine self_hostname: %v", err)
+			}
+			s.selfHostname = hostname
+		}
+	}
+
+	return s, nil
 }
 
 func (s *server) Start(ctx context.Context, listener net.Listener) {
@@ -91,6 +108,15 @@ func (s *server) handleConnections(ctx context.Context, listener net.Listener) {
 func (s *server) handleConn(ctx context.Context, conn net.Conn) error {
 	reader := msgp.NewReaderSize(conn, readBufferSize)
 
+	if s.security != nil {
+		if err := s.handshake(conn, reader); err != nil {
+			if errors.Is(err, errAuthenticationFailed) {
+				s.logger.Warn("Client failed to authenticate", zap.String("remoteAddr", conn.RemoteAddr().String()), zap.Error(err))
+			}
+			return err
+		}
+	}
+
 	for {
 		mode, err := DetermineNextEventMode(reader.R)
 		if err != nil {
@@ -16,8 +16,10 @@ package influxdbreceiver
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -73,8 +75,11 @@ func (r *metricsReceiver) Start(_ context.Context, host component.Host) error {
 	}
 
 	nr := mux.NewRouter()
-	nr.HandleFunc("/write", r.handleWrite)        // InfluxDB 1.x
-	nr.HandleFunc("/api/v2/write", r.handleWrite) // InfluxDB 2.x
+	nr.HandleFunc("/write", r.handleWrite)                                          // InfluxDB 1.x
+	nr.HandleFunc("/api/v2/write", r.handleWrite)                                   // InfluxDB 2.x
+	nr.HandleFunc("/ping", r.handlePing).Methods(http.MethodGet, http.MethodHead)   // InfluxDB 1.x and 2.x
+	nr.HandleFunc("/health", r.handleHealth).Methods(http.MethodGet)                // InfluxDB 2.x
+	nr.HandleFunc("/query", r.handleQuery).Methods(http.MethodGet, http.MethodPost) // InfluxDB 1.x
 
 	r.wg.Add(1)
 	r.server = r.httpServerSettings.ToServer(nr)
@@ -103,6 +108,95 @@ var precisions = map[string]lineprotocol.Precision{
 	lineprotocol.Microsecond.String(): lineprotocol.Microsecond,
 	lineprotocol.Millisecond.String(): lineprotocol.Millisecond,
 	lineprotocol.Second.String():      lineprotocol.Second,
+	// InfluxDB 2.x name for microsecond precision, the line protocol uses "µs".
+	"us": lineprotocol.Microsecond,
+	// InfluxDB 1.x names for nanosecond and microsecond precision.
+	"n": lineprotocol.Nanosecond,
+	"u": lineprotocol.Microsecond,
+	"µ": lineprotocol.Microsecond,
+}
+
+const (
+	// serverVersion is reported in the X-Influxdb-Version header and by the
+	// ping and health endpoints, some clients only log it.
+	serverVersion = "OpenTelemetry Collector"
+
+	// attributeBucket and attributeOrg are the resource attributes with the
+	// bucket, or database and retention policy, and the organization of the
+	// write request.
+	attributeBucket = "influxdb.bucket"
+	attributeOrg    = "influxdb.org"
+)
+
+// handlePing answers the ping used by clients to check the availability of
+// InfluxDB, see https://docs.influxdata.com/influxdb/v1.8/tools/api/#ping-http-endpoint.
+func (r *metricsReceiver) handlePing(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("X-Influxdb-Build", "OSS")
+	w.Header().Set("X-Influxdb-Version", serverVersion)
+	if verbose := req.URL.Query().Get("verbose"); verbose != "" && verbose != "false" {
+		writeJSON(w, http.StatusOK, map[string]string{"version": serverVersion})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleHealth answers the health check used by InfluxDB 2.x clients, see
+// https://docs.influxdata.com/influxdb/v2.0/api/#operation/GetHealth.
+func (r *metricsReceiver) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("X-Influxdb-Version", serverVersion)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"name":    "influxdb",
+		"message": "ready for writes",
+		"status":  "pass",
+		"checks":  []interface{}{},
+		"version": serverVersion,
+	})
+}
+
+// handleQuery only accepts the "CREATE DATABASE" statements that InfluxDB 1.x
+// clients, like Telegraf, issue before writing. Querying data is not supported.
+func (r *metricsReceiver) handleQuery(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("X-Influxdb-Version", serverVersion)
+	query := strings.TrimSpace(req.FormValue("q"))
+	if !strings.HasPrefix(strings.ToUpper(query), "CREATE DATABASE") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "only CREATE DATABASE statements are supported",
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"results": []interface{}{map[string]int{"statement_id": 0}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeResourceAttributes returns the resource attributes identifying where
+// the data of the write request would be stored by InfluxDB: the bucket and
+// organization for InfluxDB 2.x, or the database and retention policy for
+// InfluxDB 1.x, using the same "database/retention-policy" bucket name that
+// InfluxDB 2.x uses for compatibility.
+func writeResourceAttributes(req *http.Request) map[string]string {
+	query := req.URL.Query()
+	attrs := make(map[string]string, 2)
+	if bucket := query.Get("bucket"); bucket != "" {
+		attrs[attributeBucket] = bucket
+	} else if db := query.Get("db"); db != "" {
+		if rp := query.Get("rp"); rp != "" {
+			db += "/" + rp
+		}
+		attrs[attributeBucket] = db
+	}
+	if org := query.Get("org"); org != "" {
+		attrs[attributeOrg] = org
+	} else if orgID := query.Get("orgID"); orgID != "" {
+		attrs[attributeOrg] = orgID
+	}
+	return attrs
 }
 
 func (r *metricsReceiver) handleWrite(w http.ResponseWriter, req *http.Request) {
@@ -121,6 +215,7 @@ func (r *metricsReceiver) handleWrite(w http.ResponseWriter, req *http.Request)
 	}
 
 	batch := r.converter.NewBatch()
+	// Compressed bodies are already decompressed by the confighttp server.
 	lpDecoder := lineprotocol.NewDecoder(req.Body)
 
 	var k, vTag []byte
@@ -186,6 +281,14 @@ func (r *metricsReceiver) handleWrite(w http.ResponseWriter, req *http.Request)
 		_, _ = fmt.Fprintf(w, "failed to convert protobuf bytes to OTLP object")
 		return
 	}
+	if attrs := writeResourceAttributes(req); len(attrs) > 0 {
+		rms := md.ResourceMetrics()
+		for i := 0; i < rms.Len(); i++ {
+			for k, v := range attrs {
+				rms.At(i).Resource().Attributes().UpsertString(k, v)
+			}
+		}
+	}
 	if err = r.nextConsumer.ConsumeMetrics(req.Context(), md); err != nil {
 		if consumererror.IsPermanent(err) {
 			w.WriteHeader(http.StatusBadRequest)
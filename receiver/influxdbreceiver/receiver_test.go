@@ -0,0 +1,279 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbreceiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+)
+
+func newTestReceiver(t *testing.T) (*metricsReceiver, *consumertest.MetricsSink, string) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	sink := new(consumertest.MetricsSink)
+	r, err := newMetricsReceiver(cfg, newZapInfluxLogger(zap.NewNop()), sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, r.Shutdown(context.Background()))
+	})
+	return r, sink, "http://" + cfg.Endpoint
+}
+
+func TestEndpoints(t *testing.T) {
+	_, _, baseURL := newTestReceiver(t)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "ping",
+			method:       http.MethodGet,
+			path:         "/ping",
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:         "ping head",
+			method:       http.MethodHead,
+			path:         "/ping",
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:         "ping verbose",
+			method:       http.MethodGet,
+			path:         "/ping?verbose=true",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"version":"OpenTelemetry Collector"}`,
+		},
+		{
+			name:         "ping wrong method",
+			method:       http.MethodPost,
+			path:         "/ping",
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+		{
+			name:         "health",
+			method:       http.MethodGet,
+			path:         "/health",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"checks":[],"message":"ready for writes","name":"influxdb","status":"pass","version":"OpenTelemetry Collector"}`,
+		},
+		{
+			name:         "query create database",
+			method:       http.MethodGet,
+			path:         "/query?q=CREATE+DATABASE+%22telegraf%22",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"results":[{"statement_id":0}]}`,
+		},
+		{
+			name:         "query create database in form",
+			method:       http.MethodPost,
+			path:         "/query",
+			body:         "q=create database telegraf",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"results":[{"statement_id":0}]}`,
+		},
+		{
+			name:         "query select",
+			method:       http.MethodGet,
+			path:         "/query?q=SELECT+*+FROM+cpu",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `{"error":"only CREATE DATABASE statements are supported"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, baseURL+tt.path, strings.NewReader(tt.body))
+			require.NoError(t, err)
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			body, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedCode, resp.StatusCode)
+			if tt.expectedCode != http.StatusMethodNotAllowed {
+				assert.Equal(t, serverVersion, resp.Header.Get("X-Influxdb-Version"))
+			}
+			if tt.expectedBody != "" {
+				assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+				assert.JSONEq(t, tt.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestWritePrecision(t *testing.T) {
+	_, sink, baseURL := newTestReceiver(t)
+
+	tests := []struct {
+		precision    string
+		timestamp    string
+		expectedCode int
+		expectedTime time.Time
+	}{
+		{precision: "", timestamp: "1622505600000000001", expectedCode: http.StatusAccepted, expectedTime: time.Unix(1622505600, 1)},
+		{precision: "ns", timestamp: "1622505600000000001", expectedCode: http.StatusAccepted, expectedTime: time.Unix(1622505600, 1)},
+		{precision: "n", timestamp: "1622505600000000001", expectedCode: http.StatusAccepted, expectedTime: time.Unix(1622505600, 1)},
+		{precision: "us", timestamp: "1622505600000001", expectedCode: http.StatusAccepted, expectedTime: time.Unix(1622505600, 1000)},
+		{precision: "u", timestamp: "1622505600000001", expectedCode: http.StatusAccepted, expectedTime: time.Unix(1622505600, 1000)},
+		{precision: "µ", timestamp: "1622505600000001", expectedCode: http.StatusAccepted, expectedTime: time.Unix(1622505600, 1000)},
+		{precision: "µs", timestamp: "1622505600000001", expectedCode: http.StatusAccepted, expectedTime: time.Unix(1622505600, 1000)},
+		{precision: "ms", timestamp: "1622505600001", expectedCode: http.StatusAccepted, expectedTime: time.Unix(1622505600, 1000000)},
+		{precision: "s", timestamp: "1622505600", expectedCode: http.StatusAccepted, expectedTime: time.Unix(1622505600, 0)},
+		{precision: "h", timestamp: "451307", expectedCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run("precision "+tt.precision, func(t *testing.T) {
+			sink.Reset()
+			u := baseURL + "/write"
+			if tt.precision != "" {
+				u += "?precision=" + tt.precision
+			}
+			resp, err := http.Post(u, "text/plain", strings.NewReader("cpu_temperature,host=a gauge=87.3 "+tt.timestamp))
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, tt.expectedCode, resp.StatusCode)
+			if tt.expectedCode != http.StatusAccepted {
+				assert.Empty(t, sink.AllMetrics())
+				return
+			}
+			require.Len(t, sink.AllMetrics(), 1)
+			assert.Equal(t, tt.expectedTime.UnixNano(), int64(firstTimestamp(t, sink.AllMetrics()[0])))
+		})
+	}
+}
+
+func TestWriteGzip(t *testing.T) {
+	_, sink, baseURL := newTestReceiver(t)
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err := gw.Write([]byte("cpu_temperature,host=a gauge=87.3 1622505600000000000"))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	req, err := http.NewRequest(http.MethodPost, baseURL+"/api/v2/write?bucket=telegraf&org=otel", &buf)
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	require.Len(t, sink.AllMetrics(), 1)
+	attrs := sink.AllMetrics()[0].ResourceMetrics().At(0).Resource().Attributes()
+	bucket, ok := attrs.Get(attributeBucket)
+	require.True(t, ok)
+	assert.Equal(t, "telegraf", bucket.StringVal())
+	org, ok := attrs.Get(attributeOrg)
+	require.True(t, ok)
+	assert.Equal(t, "otel", org.StringVal())
+}
+
+func TestWriteResourceAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected map[string]string
+	}{
+		{
+			name:     "none",
+			query:    "",
+			expected: map[string]string{},
+		},
+		{
+			name:     "v2 bucket and org",
+			query:    "bucket=telegraf&org=otel",
+			expected: map[string]string{attributeBucket: "telegraf", attributeOrg: "otel"},
+		},
+		{
+			name:     "v2 org id",
+			query:    "bucket=telegraf&orgID=033a3f2c708aa000",
+			expected: map[string]string{attributeBucket: "telegraf", attributeOrg: "033a3f2c708aa000"},
+		},
+		{
+			name:     "v2 org takes precedence over org id",
+			query:    "bucket=telegraf&org=otel&orgID=033a3f2c708aa000",
+			expected: map[string]string{attributeBucket: "telegraf", attributeOrg: "otel"},
+		},
+		{
+			name:     "v1 database",
+			query:    "db=telegraf",
+			expected: map[string]string{attributeBucket: "telegraf"},
+		},
+		{
+			name:     "v1 database and retention policy",
+			query:    "db=telegraf&rp=autogen",
+			expected: map[string]string{attributeBucket: "telegraf/autogen"},
+		},
+		{
+			name:     "v1 retention policy without database",
+			query:    "rp=autogen",
+			expected: map[string]string{},
+		},
+		{
+			name:     "bucket takes precedence over database",
+			query:    "bucket=telegraf&db=other",
+			expected: map[string]string{attributeBucket: "telegraf"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/write?"+tt.query, nil)
+			assert.Equal(t, tt.expected, writeResourceAttributes(req))
+		})
+	}
+}
+
+// firstTimestamp returns the timestamp of the first data point of md.
+func firstTimestamp(t *testing.T, md pdata.Metrics) pdata.Timestamp {
+	m := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	switch m.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		return m.IntGauge().DataPoints().At(0).Timestamp()
+	case pdata.MetricDataTypeDoubleGauge:
+		return m.DoubleGauge().DataPoints().At(0).Timestamp()
+	case pdata.MetricDataTypeIntSum:
+		return m.IntSum().DataPoints().At(0).Timestamp()
+	case pdata.MetricDataTypeDoubleSum:
+		return m.DoubleSum().DataPoints().At(0).Timestamp()
+	}
+	t.Fatalf("unexpected metric data type %s", m.DataType())
+	return 0
+}
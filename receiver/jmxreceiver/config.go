@@ -30,7 +30,7 @@ type Config struct {
 	JARPath string `mapstructure:"jar_path"`
 	// The Service URL or host:port for the target coerced to one of form: service:jmx:rmi:///jndi/rmi://<host>:<port>/jmxrmi.
 	Endpoint string `mapstructure:"endpoint"`
-	// The target system for the metric gatherer whose built in groovy script to run.  Cannot be set with GroovyScript.
+	// The comma-separated target systems for the metric gatherer whose built in groovy scripts to run.  Cannot be set with GroovyScript.
 	TargetSystem string `mapstructure:"target_system"`
 	// The script for the metric gatherer to run on the configured interval.  Cannot be set with TargetSystem.
 	GroovyScript string `mapstructure:"groovy_script"`
@@ -61,6 +61,87 @@ type Config struct {
 	Realm string `mapstructure:"realm"`
 	// Map of property names to values to pass as system properties when running JMX Metric Gatherer
 	Properties map[string]string `mapstructure:"properties"`
+	// The targets to collect metrics from instead of the single one above.  Their unset fields default
+	// to the above ones, e.g. to the endpoint set by the receiver_creator.
+	Targets []TargetConfig `mapstructure:"targets"`
+}
+
+// TargetConfig is the configuration of a JMX target.  Targets sharing the same connection settings and
+// groovy script are collected by the same JMX Metric Gatherer process.
+type TargetConfig struct {
+	// The Service URL or host:port for the target coerced to one of form: service:jmx:rmi:///jndi/rmi://<host>:<port>/jmxrmi.
+	Endpoint string `mapstructure:"endpoint"`
+	// The comma-separated target systems for the metric gatherer whose built in groovy scripts to run.  Cannot be set with GroovyScript.
+	TargetSystem string `mapstructure:"target_system"`
+	// The script for the metric gatherer to run on the configured interval.  Cannot be set with TargetSystem.
+	GroovyScript string `mapstructure:"groovy_script"`
+	// The JMX username
+	Username string `mapstructure:"username"`
+	// The JMX password
+	Password string `mapstructure:"password"`
+	// The keystore path for SSL
+	KeystorePath string `mapstructure:"keystore_path"`
+	// The keystore password for SSL
+	KeystorePassword string `mapstructure:"keystore_password"`
+	// The keystore type for SSL
+	KeystoreType string `mapstructure:"keystore_type"`
+	// The truststore path for SSL
+	TruststorePath string `mapstructure:"truststore_path"`
+	// The truststore password for SSL
+	TruststorePassword string `mapstructure:"truststore_password"`
+	// The JMX remote profile.
+	RemoteProfile string `mapstructure:"remote_profile"`
+	// The SASL/DIGEST-MD5 realm
+	Realm string `mapstructure:"realm"`
+}
+
+// targets returns the configured targets, with their unset fields set to the receiver's ones, or the
+// receiver's own target if none is configured.
+func (c *Config) targets() []TargetConfig {
+	defaults := TargetConfig{
+		Endpoint:           c.Endpoint,
+		TargetSystem:       c.TargetSystem,
+		GroovyScript:       c.GroovyScript,
+		Username:           c.Username,
+		Password:           c.Password,
+		KeystorePath:       c.KeystorePath,
+		KeystorePassword:   c.KeystorePassword,
+		KeystoreType:       c.KeystoreType,
+		TruststorePath:     c.TruststorePath,
+		TruststorePassword: c.TruststorePassword,
+		RemoteProfile:      c.RemoteProfile,
+		Realm:              c.Realm,
+	}
+	if len(c.Targets) == 0 {
+		return []TargetConfig{defaults}
+	}
+
+	targets := make([]TargetConfig, 0, len(c.Targets))
+	for _, target := range c.Targets {
+		// The script to run is only inherited as a whole.
+		if target.TargetSystem == "" && target.GroovyScript == "" {
+			target.TargetSystem = defaults.TargetSystem
+			target.GroovyScript = defaults.GroovyScript
+		}
+		setDefault(&target.Endpoint, defaults.Endpoint)
+		setDefault(&target.Username, defaults.Username)
+		setDefault(&target.Password, defaults.Password)
+		setDefault(&target.KeystorePath, defaults.KeystorePath)
+		setDefault(&target.KeystorePassword, defaults.KeystorePassword)
+		setDefault(&target.KeystoreType, defaults.KeystoreType)
+		setDefault(&target.TruststorePath, defaults.TruststorePath)
+		setDefault(&target.TruststorePassword, defaults.TruststorePassword)
+		setDefault(&target.RemoteProfile, defaults.RemoteProfile)
+		setDefault(&target.Realm, defaults.Realm)
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+func setDefault(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
 }
 
 // We don't embed the existing OTLP Exporter config as most fields are unsupported
@@ -102,19 +183,24 @@ func (c *Config) parseProperties() []string {
 }
 
 func (c *Config) validate() error {
-	var missingFields []string
-	if c.Endpoint == "" {
-		missingFields = append(missingFields, "`endpoint`")
-	}
-	if c.TargetSystem == "" && c.GroovyScript == "" {
-		missingFields = append(missingFields, "`target_system` or `groovy_script`")
-	}
-	if missingFields != nil {
-		baseMsg := fmt.Sprintf("%v missing required field", c.ID())
-		if len(missingFields) > 1 {
-			baseMsg += "s"
+	for i, target := range c.targets() {
+		var missingFields []string
+		if target.Endpoint == "" {
+			missingFields = append(missingFields, "`endpoint`")
+		}
+		if target.TargetSystem == "" && target.GroovyScript == "" {
+			missingFields = append(missingFields, "`target_system` or `groovy_script`")
+		}
+		if missingFields != nil {
+			baseMsg := fmt.Sprintf("%v missing required field", c.ID())
+			if len(c.Targets) > 0 {
+				baseMsg = fmt.Sprintf("%v `targets[%d]` missing required field", c.ID(), i)
+			}
+			if len(missingFields) > 1 {
+				baseMsg += "s"
+			}
+			return fmt.Errorf("%v: %v", baseMsg, strings.Join(missingFields, ", "))
 		}
-		return fmt.Errorf("%v: %v", baseMsg, strings.Join(missingFields, ", "))
 	}
 
 	if c.CollectionInterval < 0 {
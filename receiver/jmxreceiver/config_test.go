@@ -39,7 +39,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 6)
+	assert.Equal(t, len(cfg.Receivers), 8)
 
 	r0 := cfg.Receivers[config.NewID(typeStr)].(*Config)
 	require.NoError(t, configcheck.ValidateConfig(r0))
@@ -165,4 +165,24 @@ func TestLoadConfig(t *testing.T) {
 	err = r5.validate()
 	require.Error(t, err)
 	assert.Equal(t, "jmx/invalidotlptimeout `otlp.timeout` must be positive: -100ms", err.Error())
+
+	r6 := cfg.Receivers[config.NewIDWithName(typeStr, "targets")].(*Config)
+	require.NoError(t, configcheck.ValidateConfig(r6))
+	require.NoError(t, r6.validate())
+	assert.Equal(t, []TargetConfig{{}, {TargetSystem: "kafka"}, {
+		Endpoint: "myotherendpoint:23456",
+		Username: "myotherusername",
+		Password: "myotherpassword",
+	}}, r6.Targets)
+	assert.Equal(t, []TargetConfig{
+		{Endpoint: "myendpoint:12345", TargetSystem: "jvm", Username: "myusername", Password: "mypassword"},
+		{Endpoint: "myendpoint:12345", TargetSystem: "kafka", Username: "myusername", Password: "mypassword"},
+		{Endpoint: "myotherendpoint:23456", TargetSystem: "jvm", Username: "myotherusername", Password: "myotherpassword"},
+	}, r6.targets())
+
+	r7 := cfg.Receivers[config.NewIDWithName(typeStr, "missingtargetfield")].(*Config)
+	require.NoError(t, configcheck.ValidateConfig(r7))
+	err = r7.validate()
+	require.Error(t, err)
+	assert.Equal(t, "jmx/missingtargetfield `targets[1]` missing required field: `endpoint`", err.Error())
 }
@@ -95,25 +95,6 @@ func cassandraContainer(t *testing.T) testcontainers.Container {
 	return cassandra
 }
 
-func getJavaStdout(receiver *jmxMetricReceiver) string {
-	msg := ""
-LOOP:
-	for i := 0; i < 70; i++ {
-		t := time.NewTimer(5 * time.Second)
-		select {
-		case m, ok := <-receiver.subprocess.Stdout:
-			if ok {
-				msg = msg + m + "\n"
-			} else {
-				break LOOP
-			}
-		case <-t.C:
-			break LOOP
-		}
-	}
-	return fmt.Sprintf("metrics not collected: %v\n", msg)
-}
-
 func getLogsOnFailure(t *testing.T, logObserver *observer.ObservedLogs) {
 	if !t.Failed() {
 		return
@@ -233,7 +214,7 @@ func (suite *JMXIntegrationSuite) TestJMXReceiverHappyPath() {
 		require.Equal(t, "myothervalue", anotherCustomLabel)
 
 		return true
-	}, 30*time.Second, 100*time.Millisecond, getJavaStdout(receiver))
+	}, 30*time.Second, 100*time.Millisecond, "metrics not collected")
 }
 
 func TestJMXReceiverInvalidOTLPEndpointIntegration(t *testing.T) {
@@ -19,16 +19,19 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/jmxreceiver/subprocess"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/subprocess"
 )
 
 var _ component.MetricsReceiver = (*jmxMetricReceiver)(nil)
@@ -36,7 +39,7 @@ var _ component.MetricsReceiver = (*jmxMetricReceiver)(nil)
 type jmxMetricReceiver struct {
 	logger       *zap.Logger
 	config       *Config
-	subprocess   *subprocess.Subprocess
+	subprocesses []*subprocess.Subprocess
 	params       component.ReceiverCreateParams
 	otlpReceiver component.MetricsReceiver
 	nextConsumer consumer.Metrics
@@ -63,36 +66,69 @@ func (jmx *jmxMetricReceiver) Start(ctx context.Context, host component.Host) (e
 		return err
 	}
 
-	javaConfig, err := jmx.buildJMXMetricGathererConfig()
+	javaConfigs, err := jmx.buildJMXMetricGathererConfigs()
 	if err != nil {
 		return err
 	}
 
-	subprocessConfig := subprocess.Config{
-		ExecutablePath: "java",
-		Args:           append(jmx.config.parseProperties(), "-Dorg.slf4j.simpleLogger.defaultLogLevel=info", "-jar", jmx.config.JARPath, "-config", "-"),
-		StdInContents:  javaConfig,
+	// The JMX Metric Gatherer accepts a single service URL, so one JRE is run per target connection.
+	for _, javaConfig := range javaConfigs {
+		subprocessConfig := subprocess.Config{
+			ExecutablePath: "java",
+			Args:           append(jmx.config.parseProperties(), "-Dorg.slf4j.simpleLogger.defaultLogLevel=info", "-jar", jmx.config.JARPath, "-config", "-"),
+			StdInContents:  javaConfig,
+			// Restart the JMX Metric Gatherer if the JVM crashes or exits, e.g. after failing to
+			// connect to a restarting MBean server.
+			RestartOnError: true,
+			LogLevel:       jmxMetricGathererLogLevel,
+		}
+		jmx.subprocesses = append(jmx.subprocesses, subprocess.NewSubprocess(&subprocessConfig, jmx.logger))
 	}
 
-	jmx.subprocess = subprocess.NewSubprocess(&subprocessConfig, jmx.logger)
-
 	err = jmx.otlpReceiver.Start(ctx, host)
 	if err != nil {
 		return err
 	}
-	go func() {
-		for range jmx.subprocess.Stdout {
-			// ensure stdout/stderr buffer is read from.
-			// these messages are already debug logged when captured.
+
+	subprocessCtx := obsreport.ReceiverContext(context.Background(), jmx.config.ID(), "")
+	for _, s := range jmx.subprocesses {
+		if err = s.Start(subprocessCtx); err != nil {
+			return err
 		}
-	}()
+	}
+	return nil
+}
+
+// slf4jLevelRe matches the level of the slf4j simple logger lines of the JMX Metric Gatherer,
+// e.g. "[main] INFO io.opentelemetry.contrib.jmxmetrics.JmxClient - Connecting to ...".
+var slf4jLevelRe = regexp.MustCompile(`^\[[^\]]*\] (TRACE|DEBUG|INFO|WARN|ERROR) `)
 
-	return jmx.subprocess.Start(context.Background())
+// jmxMetricGathererLogLevel maps the slf4j log levels of the JMX Metric Gatherer to the
+// collector ones. Other lines, e.g. stack traces, are logged at the default level.
+func jmxMetricGathererLogLevel(line string, isStderr bool) zapcore.Level {
+	match := slf4jLevelRe.FindStringSubmatch(line)
+	if match == nil {
+		return subprocess.DefaultLogLevel(line, isStderr)
+	}
+	switch match[1] {
+	case "TRACE", "DEBUG":
+		return zapcore.DebugLevel
+	case "INFO":
+		return zapcore.InfoLevel
+	case "WARN":
+		return zapcore.WarnLevel
+	}
+	return zapcore.ErrorLevel
 }
 
 func (jmx *jmxMetricReceiver) Shutdown(ctx context.Context) error {
 	jmx.logger.Debug("Shutting down JMX Receiver")
-	subprocessErr := jmx.subprocess.Shutdown(ctx)
+	var subprocessErr error
+	for _, s := range jmx.subprocesses {
+		if err := s.Shutdown(ctx); err != nil && subprocessErr == nil {
+			subprocessErr = err
+		}
+	}
 	otlpErr := jmx.otlpReceiver.Shutdown(ctx)
 	if subprocessErr != nil {
 		return subprocessErr
@@ -130,33 +166,111 @@ func (jmx *jmxMetricReceiver) buildOTLPReceiver() (component.MetricsReceiver, er
 	return factory.CreateMetricsReceiver(context.Background(), jmx.params, config, jmx.nextConsumer)
 }
 
-func (jmx *jmxMetricReceiver) buildJMXMetricGathererConfig() (string, error) {
-	failedToParse := `failed to parse Endpoint "%s": %w`
-	parsed, err := url.Parse(jmx.config.Endpoint)
+// buildJMXMetricGathererConfigs returns the config of each JMX Metric Gatherer process to run, one per
+// group of targets sharing the same connection settings and groovy script.
+func (jmx *jmxMetricReceiver) buildJMXMetricGathererConfigs() ([]string, error) {
+	targets, err := groupTargets(jmx.config.targets())
 	if err != nil {
-		return "", fmt.Errorf(failedToParse, jmx.config.Endpoint, err)
+		return nil, err
 	}
 
-	if !(parsed.Scheme == "service" && strings.HasPrefix(parsed.Opaque, "jmx:")) {
-		host, portStr, err := net.SplitHostPort(jmx.config.Endpoint)
+	javaConfigs := make([]string, 0, len(targets))
+	for _, target := range targets {
+		javaConfigs = append(javaConfigs, jmx.buildJMXMetricGathererConfig(target))
+	}
+	return javaConfigs, nil
+}
+
+// groupTargets coerces the endpoints of targets to service URLs and merges the target systems of the
+// targets sharing the same connection settings, in order of appearance.
+func groupTargets(targets []TargetConfig) ([]TargetConfig, error) {
+	var grouped []TargetConfig
+	groupIndexes := map[TargetConfig]int{}
+	for _, target := range targets {
+		serviceURL, err := toServiceURL(target.Endpoint)
 		if err != nil {
-			return "", fmt.Errorf(failedToParse, jmx.config.Endpoint, err)
+			return nil, err
 		}
-		port, err := strconv.ParseInt(portStr, 10, 0)
-		if err != nil {
-			return "", fmt.Errorf(failedToParse, jmx.config.Endpoint, err)
+		target.Endpoint = serviceURL
+		targetSystems := splitTargetSystems(target.TargetSystem)
+		if len(targetSystems) > 0 {
+			// The target systems take precedence over the groovy script.
+			target.GroovyScript = ""
+		}
+
+		key := target
+		key.TargetSystem = ""
+		i, ok := groupIndexes[key]
+		if !ok {
+			i = len(grouped)
+			groupIndexes[key] = i
+			grouped = append(grouped, key)
 		}
-		jmx.config.Endpoint = fmt.Sprintf("service:jmx:rmi:///jndi/rmi://%v:%d/jmxrmi", host, port)
+		grouped[i].TargetSystem = strings.Join(
+			mergeTargetSystems(splitTargetSystems(grouped[i].TargetSystem), targetSystems), ",",
+		)
 	}
+	return grouped, nil
+}
 
+func splitTargetSystems(targetSystem string) []string {
+	var targetSystems []string
+	for _, ts := range strings.Split(targetSystem, ",") {
+		if ts = strings.TrimSpace(ts); ts != "" {
+			targetSystems = append(targetSystems, ts)
+		}
+	}
+	return targetSystems
+}
+
+func mergeTargetSystems(targetSystems, others []string) []string {
+	for _, other := range others {
+		found := false
+		for _, ts := range targetSystems {
+			if ts == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			targetSystems = append(targetSystems, other)
+		}
+	}
+	return targetSystems
+}
+
+// toServiceURL returns endpoint if it is a JMX Service URL, or the RMI Service URL of endpoint in host:port form.
+func toServiceURL(endpoint string) (string, error) {
+	failedToParse := `failed to parse Endpoint "%s": %w`
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf(failedToParse, endpoint, err)
+	}
+
+	if parsed.Scheme == "service" && strings.HasPrefix(parsed.Opaque, "jmx:") {
+		return endpoint, nil
+	}
+
+	host, portStr, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return "", fmt.Errorf(failedToParse, endpoint, err)
+	}
+	port, err := strconv.ParseInt(portStr, 10, 0)
+	if err != nil {
+		return "", fmt.Errorf(failedToParse, endpoint, err)
+	}
+	return fmt.Sprintf("service:jmx:rmi:///jndi/rmi://%v:%d/jmxrmi", host, port), nil
+}
+
+func (jmx *jmxMetricReceiver) buildJMXMetricGathererConfig(target TargetConfig) string {
 	javaConfig := fmt.Sprintf(`otel.jmx.service.url = %v
 otel.jmx.interval.milliseconds = %v
-`, jmx.config.Endpoint, jmx.config.CollectionInterval.Milliseconds())
+`, target.Endpoint, jmx.config.CollectionInterval.Milliseconds())
 
-	if jmx.config.TargetSystem != "" {
-		javaConfig += fmt.Sprintf("otel.jmx.target.system = %v\n", jmx.config.TargetSystem)
-	} else if jmx.config.GroovyScript != "" {
-		javaConfig += fmt.Sprintf("otel.jmx.groovy.script = %v\n", jmx.config.GroovyScript)
+	if target.TargetSystem != "" {
+		javaConfig += fmt.Sprintf("otel.jmx.target.system = %v\n", target.TargetSystem)
+	} else if target.GroovyScript != "" {
+		javaConfig += fmt.Sprintf("otel.jmx.groovy.script = %v\n", target.GroovyScript)
 	}
 
 	endpoint := jmx.config.OTLPExporterConfig.Endpoint
@@ -173,13 +287,22 @@ otel.exporter.otlp.timeout = %v
 		javaConfig += fmt.Sprintf("otel.exporter.otlp.headers = %s\n", jmx.config.OTLPExporterConfig.headersToString())
 	}
 
-	if jmx.config.Username != "" {
-		javaConfig += fmt.Sprintf("otel.jmx.username = %v\n", jmx.config.Username)
+	properties := []struct{ name, value string }{
+		{"otel.jmx.username", target.Username},
+		{"otel.jmx.password", target.Password},
+		{"javax.net.ssl.keyStore", target.KeystorePath},
+		{"javax.net.ssl.keyStorePassword", target.KeystorePassword},
+		{"javax.net.ssl.keyStoreType", target.KeystoreType},
+		{"javax.net.ssl.trustStore", target.TruststorePath},
+		{"javax.net.ssl.trustStorePassword", target.TruststorePassword},
+		{"otel.jmx.remote.profile", target.RemoteProfile},
+		{"otel.jmx.realm", target.Realm},
 	}
-
-	if jmx.config.Password != "" {
-		javaConfig += fmt.Sprintf("otel.jmx.password = %v\n", jmx.config.Password)
+	for _, property := range properties {
+		if property.value != "" {
+			javaConfig += fmt.Sprintf("%v = %v\n", property.name, property.value)
+		}
 	}
 
-	return javaConfig, nil
+	return javaConfig
 }
@@ -47,12 +47,35 @@ func TestReceiver(t *testing.T) {
 	require.Nil(t, receiver.Shutdown(context.Background()))
 }
 
+func TestReceiverMultipleTargets(t *testing.T) {
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	config := &Config{
+		Endpoint:     "service:jmx:protocol:sap",
+		TargetSystem: "jvm",
+		OTLPExporterConfig: otlpExporterConfig{
+			Endpoint: fmt.Sprintf("localhost:%d", testutil.GetAvailablePort(t)),
+		},
+		Targets: []TargetConfig{
+			{},
+			{TargetSystem: "kafka"},
+			{Endpoint: "service:jmx:otherprotocol:sap"},
+		},
+	}
+
+	receiver := newJMXMetricReceiver(params, config, consumertest.NewNop())
+	require.NotNil(t, receiver)
+
+	require.Nil(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	require.Len(t, receiver.subprocesses, 2)
+	require.Nil(t, receiver.Shutdown(context.Background()))
+}
+
 func TestBuildJMXMetricGathererConfig(t *testing.T) {
 	tests := []struct {
-		name           string
-		config         Config
-		expectedConfig string
-		expectedError  string
+		name            string
+		config          Config
+		expectedConfigs []string
+		expectedError   string
 	}{
 		{
 			"uses target system",
@@ -68,13 +91,13 @@ func TestBuildJMXMetricGathererConfig(t *testing.T) {
 					},
 				},
 			},
-			`otel.jmx.service.url = service:jmx:rmi///jndi/rmi://myservice:12345/jmxrmi/
+			[]string{`otel.jmx.service.url = service:jmx:rmi///jndi/rmi://myservice:12345/jmxrmi/
 otel.jmx.interval.milliseconds = 123000
 otel.jmx.target.system = mytargetsystem
 otel.metrics.exporter = otlp
 otel.exporter.otlp.endpoint = http://myotlpendpoint
 otel.exporter.otlp.timeout = 234000
-`, "",
+`}, "",
 		},
 		{
 			"uses groovy script",
@@ -89,13 +112,13 @@ otel.exporter.otlp.timeout = 234000
 					},
 				},
 			},
-			`otel.jmx.service.url = service:jmx:rmi///jndi/rmi://myservice:12345/jmxrmi/
+			[]string{`otel.jmx.service.url = service:jmx:rmi///jndi/rmi://myservice:12345/jmxrmi/
 otel.jmx.interval.milliseconds = 123000
 otel.jmx.groovy.script = mygroovyscript
 otel.metrics.exporter = otlp
 otel.exporter.otlp.endpoint = http://myotlpendpoint
 otel.exporter.otlp.timeout = 234000
-`, "",
+`}, "",
 		},
 		{
 			"uses endpoint as service url",
@@ -115,14 +138,110 @@ otel.exporter.otlp.timeout = 234000
 					},
 				},
 			},
-			`otel.jmx.service.url = service:jmx:rmi:///jndi/rmi://myhost:12345/jmxrmi
+			[]string{`otel.jmx.service.url = service:jmx:rmi:///jndi/rmi://myhost:12345/jmxrmi
 otel.jmx.interval.milliseconds = 123000
 otel.jmx.target.system = mytargetsystem
 otel.metrics.exporter = otlp
 otel.exporter.otlp.endpoint = https://myotlpendpoint
 otel.exporter.otlp.timeout = 234000
 otel.exporter.otlp.headers = one=two,three=four
-`, "",
+`}, "",
+		},
+		{
+			"uses target credentials and ssl settings",
+			Config{
+				Endpoint:           "myhost:12345",
+				TargetSystem:       "mytargetsystem",
+				CollectionInterval: 123 * time.Second,
+				OTLPExporterConfig: otlpExporterConfig{
+					Endpoint: "myotlpendpoint",
+					TimeoutSettings: exporterhelper.TimeoutSettings{
+						Timeout: 234 * time.Second,
+					},
+				},
+				Username:           "myusername",
+				Password:           "mypassword",
+				KeystorePath:       "mykeystorepath",
+				KeystorePassword:   "mykeystorepassword",
+				KeystoreType:       "mykeystoretype",
+				TruststorePath:     "mytruststorepath",
+				TruststorePassword: "mytruststorepassword",
+				RemoteProfile:      "myremoteprofile",
+				Realm:              "myrealm",
+			},
+			[]string{`otel.jmx.service.url = service:jmx:rmi:///jndi/rmi://myhost:12345/jmxrmi
+otel.jmx.interval.milliseconds = 123000
+otel.jmx.target.system = mytargetsystem
+otel.metrics.exporter = otlp
+otel.exporter.otlp.endpoint = http://myotlpendpoint
+otel.exporter.otlp.timeout = 234000
+otel.jmx.username = myusername
+otel.jmx.password = mypassword
+javax.net.ssl.keyStore = mykeystorepath
+javax.net.ssl.keyStorePassword = mykeystorepassword
+javax.net.ssl.keyStoreType = mykeystoretype
+javax.net.ssl.trustStore = mytruststorepath
+javax.net.ssl.trustStorePassword = mytruststorepassword
+otel.jmx.remote.profile = myremoteprofile
+otel.jmx.realm = myrealm
+`}, "",
+		},
+		{
+			"groups targets by connection",
+			Config{
+				Endpoint:           "myhost:12345",
+				TargetSystem:       "jvm",
+				Username:           "myusername",
+				CollectionInterval: 123 * time.Second,
+				OTLPExporterConfig: otlpExporterConfig{
+					Endpoint: "myotlpendpoint",
+					TimeoutSettings: exporterhelper.TimeoutSettings{
+						Timeout: 234 * time.Second,
+					},
+				},
+				Targets: []TargetConfig{
+					{},
+					{Endpoint: "service:jmx:rmi:///jndi/rmi://myhost:12345/jmxrmi", TargetSystem: "kafka, jvm"},
+					{GroovyScript: "mygroovyscript"},
+					{Endpoint: "myotherhost:12345", Username: "myotherusername"},
+					{TargetSystem: "cassandra"},
+				},
+			},
+			[]string{`otel.jmx.service.url = service:jmx:rmi:///jndi/rmi://myhost:12345/jmxrmi
+otel.jmx.interval.milliseconds = 123000
+otel.jmx.target.system = jvm,kafka,cassandra
+otel.metrics.exporter = otlp
+otel.exporter.otlp.endpoint = http://myotlpendpoint
+otel.exporter.otlp.timeout = 234000
+otel.jmx.username = myusername
+`, `otel.jmx.service.url = service:jmx:rmi:///jndi/rmi://myhost:12345/jmxrmi
+otel.jmx.interval.milliseconds = 123000
+otel.jmx.groovy.script = mygroovyscript
+otel.metrics.exporter = otlp
+otel.exporter.otlp.endpoint = http://myotlpendpoint
+otel.exporter.otlp.timeout = 234000
+otel.jmx.username = myusername
+`, `otel.jmx.service.url = service:jmx:rmi:///jndi/rmi://myotherhost:12345/jmxrmi
+otel.jmx.interval.milliseconds = 123000
+otel.jmx.target.system = jvm
+otel.metrics.exporter = otlp
+otel.exporter.otlp.endpoint = http://myotlpendpoint
+otel.exporter.otlp.timeout = 234000
+otel.jmx.username = myotherusername
+`}, "",
+		},
+		{
+			"errors on invalid target endpoint",
+			Config{
+				Endpoint:           "myhost:12345",
+				TargetSystem:       "mytargetsystem",
+				CollectionInterval: 123 * time.Second,
+				Targets: []TargetConfig{
+					{},
+					{Endpoint: "myotherhostwithoutport"},
+				},
+			}, nil,
+			`failed to parse Endpoint "myotherhostwithoutport": address myotherhostwithoutport: missing port in address`,
 		},
 		{
 			"errors on portless endpoint",
@@ -137,7 +256,7 @@ otel.exporter.otlp.headers = one=two,three=four
 						Timeout: 234 * time.Second,
 					},
 				},
-			}, "",
+			}, nil,
 			`failed to parse Endpoint "myhostwithoutport": address myhostwithoutport: missing port in address`,
 		},
 		{
@@ -153,7 +272,7 @@ otel.exporter.otlp.headers = one=two,three=four
 						Timeout: 234 * time.Second,
 					},
 				},
-			}, "",
+			}, nil,
 			`failed to parse Endpoint "myhost:withoutvalidport": strconv.ParseInt: parsing "withoutvalidport": invalid syntax`,
 		},
 		{
@@ -169,7 +288,7 @@ otel.exporter.otlp.headers = one=two,three=four
 						Timeout: 234 * time.Second,
 					},
 				},
-			}, "",
+			}, nil,
 			`failed to parse Endpoint ":::": parse ":::": missing protocol scheme`,
 		},
 	}
@@ -178,14 +297,14 @@ otel.exporter.otlp.headers = one=two,three=four
 		t.Run(test.name, func(tt *testing.T) {
 			params := component.ReceiverCreateParams{Logger: zap.NewNop()}
 			receiver := newJMXMetricReceiver(params, &test.config, consumertest.NewNop())
-			jmxConfig, err := receiver.buildJMXMetricGathererConfig()
+			jmxConfigs, err := receiver.buildJMXMetricGathererConfigs()
 			if test.expectedError == "" {
 				require.NoError(t, err)
 			} else {
 				require.Error(t, err)
 				require.EqualError(t, err, test.expectedError)
 			}
-			require.Equal(t, test.expectedConfig, jmxConfig)
+			require.Equal(t, test.expectedConfigs, jmxConfigs)
 		})
 	}
 }
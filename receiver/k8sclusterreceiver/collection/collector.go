@@ -26,6 +26,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
@@ -48,11 +49,19 @@ const (
 	k8sKeyReplicationControllerUID = "k8s.replicationcontroller.uid"
 	k8sKeyHPAUID                   = "k8s.hpa.uid"
 	k8sKeyResourceQuotaUID         = "k8s.resourcequota.uid"
+	k8sKeyPersistentVolumeUID      = "k8s.persistentvolume.uid"
+	k8sKeyPersistentVolumeClaimUID = "k8s.persistentvolumeclaim.uid"
+	k8sKeyServiceUID               = "k8s.service.uid"
 
 	// Resource labels keys for Name.
 	k8sKeyReplicationControllerName = "k8s.replicationcontroller.name"
 	k8sKeyHPAName                   = "k8s.hpa.name"
 	k8sKeyResourceQuotaName         = "k8s.resourcequota.name"
+	k8sKeyPersistentVolumeName      = "k8s.persistentvolume.name"
+	k8sKeyPersistentVolumeClaimName = "k8s.persistentvolumeclaim.name"
+	k8sKeyServiceName               = "k8s.service.name"
+
+	k8sKeyServiceType = "k8s.service.type"
 
 	// Kubernetes resource kinds
 	k8sKindCronJob               = "CronJob"
@@ -62,6 +71,10 @@ const (
 	k8sKindReplicationController = "ReplicationController"
 	k8sKindReplicaSet            = "ReplicaSet"
 	k8sStatefulSet               = "StatefulSet"
+	k8sKindPersistentVolume      = "PersistentVolume"
+	k8sKindPersistentVolumeClaim = "PersistentVolumeClaim"
+	k8sKindService               = "Service"
+	k8sKindIngress               = "Ingress"
 )
 
 // DataCollector wraps around a metricsStore and a metadaStore exposing
@@ -131,6 +144,19 @@ func (dc *DataCollector) SyncMetrics(obj interface{}) {
 		rm = getMetricsForReplicationController(o)
 	case *corev1.ResourceQuota:
 		rm = getMetricsForResourceQuota(o)
+	case *corev1.PersistentVolume:
+		rm = getMetricsForPersistentVolume(o)
+	case *corev1.PersistentVolumeClaim:
+		rm = getMetricsForPersistentVolumeClaim(o)
+	case *corev1.Endpoints:
+		rm = getMetricsForEndpoints(o, dc.metadataStore.getServiceForEndpoints(o))
+	case *corev1.Service:
+		// The endpoint metrics of a service carry attributes of the service,
+		// so they have to be refreshed when the service changes.
+		if ep := dc.metadataStore.getEndpointsForService(o); ep != nil {
+			dc.UpdateMetricsStore(ep, getMetricsForEndpoints(ep, o))
+		}
+		return
 	case *appsv1.Deployment:
 		rm = getMetricsForDeployment(o)
 	case *appsv1.ReplicaSet:
@@ -166,6 +192,14 @@ func (dc *DataCollector) SyncMetadata(obj interface{}) map[metadata.ResourceID]*
 		km = getMetadataForNode(o)
 	case *corev1.ReplicationController:
 		km = getMetadataForReplicationController(o)
+	case *corev1.PersistentVolume:
+		km = getMetadataForPersistentVolume(o)
+	case *corev1.PersistentVolumeClaim:
+		km = getMetadataForPersistentVolumeClaim(o)
+	case *corev1.Service:
+		km = getMetadataForService(o)
+	case *networkingv1.Ingress:
+		km = getMetadataForIngress(o)
 	case *appsv1.Deployment:
 		km = getMetadataForDeployment(o)
 	case *appsv1.ReplicaSet:
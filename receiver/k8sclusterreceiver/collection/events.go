@@ -0,0 +1,124 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// Keys for K8s event attributes
+	k8sKeyEventName            = "k8s.event.name"
+	k8sKeyEventUID             = "k8s.event.uid"
+	k8sKeyEventReason          = "k8s.event.reason"
+	k8sKeyEventAction          = "k8s.event.action"
+	k8sKeyEventCount           = "k8s.event.count"
+	k8sKeyEventStartTime       = "k8s.event.start_time"
+	k8sKeyEventSourceComponent = "k8s.event.source.component"
+	k8sKeyEventSourceHost      = "k8s.event.source.host"
+	k8sKeyEventFieldPath       = "k8s.event.field_path"
+)
+
+// GetLogsForEvent converts a Kubernetes event into a log record. The resource
+// of the log record describes the object the event is about.
+func GetLogsForEvent(event *corev1.Event) pdata.Logs {
+	ld := pdata.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+
+	resourceAttrs := rl.Resource().Attributes()
+	obj := event.InvolvedObject
+	kind := strings.ToLower(obj.Kind)
+	resourceAttrs.InsertString(k8sKeyWorkLoadKind, obj.Kind)
+	resourceAttrs.InsertString(k8sKeyWorkLoadName, obj.Name)
+	if kind != "" {
+		resourceAttrs.InsertString(getOTelNameFromKind(kind), obj.Name)
+		if obj.UID != "" {
+			resourceAttrs.InsertString(getOTelUIDFromKind(kind), string(obj.UID))
+		}
+	}
+	if obj.Namespace != "" {
+		resourceAttrs.InsertString(conventions.AttributeK8sNamespace, obj.Namespace)
+	}
+	if event.ClusterName != "" {
+		resourceAttrs.InsertString(conventions.AttributeK8sCluster, event.ClusterName)
+	}
+
+	lr := rl.InstrumentationLibraryLogs().AppendEmpty().Logs().AppendEmpty()
+	lr.SetTimestamp(pdata.TimestampFromTime(EventTimestamp(event)))
+	lr.SetSeverityText(event.Type)
+	lr.SetSeverityNumber(eventSeverityNumbers[event.Type])
+	lr.Body().SetStringVal(event.Message)
+
+	attrs := lr.Attributes()
+	attrs.InsertString(k8sKeyEventName, event.Name)
+	attrs.InsertString(k8sKeyEventUID, string(event.UID))
+	attrs.InsertString(k8sKeyEventReason, event.Reason)
+	attrs.InsertInt(k8sKeyEventCount, int64(event.Count))
+	if event.Action != "" {
+		attrs.InsertString(k8sKeyEventAction, event.Action)
+	}
+	if !event.FirstTimestamp.IsZero() {
+		attrs.InsertString(k8sKeyEventStartTime, event.FirstTimestamp.UTC().Format(time.RFC3339))
+	}
+	if component := eventSourceComponent(event); component != "" {
+		attrs.InsertString(k8sKeyEventSourceComponent, component)
+	}
+	if event.Source.Host != "" {
+		attrs.InsertString(k8sKeyEventSourceHost, event.Source.Host)
+	}
+	if obj.FieldPath != "" {
+		attrs.InsertString(k8sKeyEventFieldPath, obj.FieldPath)
+	}
+
+	return ld
+}
+
+// EventTimestamp returns the last time the event occurred. Depending on the
+// API version and the component that reported it, events carry a different
+// subset of their timestamps.
+func EventTimestamp(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// eventSourceComponent returns the component that reported the event, which
+// is set in Source for core/v1 events and ReportingController for
+// events.k8s.io events.
+func eventSourceComponent(event *corev1.Event) string {
+	if event.Source.Component != "" {
+		return event.Source.Component
+	}
+	return event.ReportingController
+}
+
+var eventSeverityNumbers = map[string]pdata.SeverityNumber{
+	corev1.EventTypeNormal:  pdata.SeverityNumberINFO,
+	corev1.EventTypeWarning: pdata.SeverityNumberWARN,
+}
@@ -0,0 +1,143 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestEventLogs(t *testing.T) {
+	event := newEvent()
+
+	ld := GetLogsForEvent(event)
+	require.Equal(t, 1, ld.LogRecordCount())
+
+	rl := ld.ResourceLogs().At(0)
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		"k8s.workload.kind":  pdata.NewAttributeValueString("Pod"),
+		"k8s.workload.name":  pdata.NewAttributeValueString("test-pod"),
+		"k8s.pod.name":       pdata.NewAttributeValueString("test-pod"),
+		"k8s.pod.uid":        pdata.NewAttributeValueString("test-pod-uid"),
+		"k8s.namespace.name": pdata.NewAttributeValueString("test-namespace"),
+		"k8s.cluster.name":   pdata.NewAttributeValueString("test-cluster"),
+	}, rl.Resource().Attributes())
+
+	lr := rl.InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, pdata.TimestampFromTime(time.Date(2021, 5, 20, 10, 5, 0, 0, time.UTC)), lr.Timestamp())
+	assert.Equal(t, "Warning", lr.SeverityText())
+	assert.Equal(t, pdata.SeverityNumberWARN, lr.SeverityNumber())
+	assert.Equal(t, "0/3 nodes are available: 3 Insufficient memory.", lr.Body().StringVal())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		"k8s.event.name":             pdata.NewAttributeValueString("test-pod.1680a6f7b5c0a2b4"),
+		"k8s.event.uid":              pdata.NewAttributeValueString("test-event-uid"),
+		"k8s.event.reason":           pdata.NewAttributeValueString("FailedScheduling"),
+		"k8s.event.count":            pdata.NewAttributeValueInt(3),
+		"k8s.event.start_time":       pdata.NewAttributeValueString("2021-05-20T10:00:00Z"),
+		"k8s.event.source.component": pdata.NewAttributeValueString("default-scheduler"),
+	}, lr.Attributes())
+}
+
+func TestEventLogsNormal(t *testing.T) {
+	event := &corev1.Event{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "test-node.1680a6f7b5c0a2b4",
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind: "Node",
+			Name: "test-node",
+		},
+		Reason:              "NodeReady",
+		Message:             "Node test-node status is now: NodeReady",
+		Type:                corev1.EventTypeNormal,
+		ReportingController: "kubelet",
+		Source:              corev1.EventSource{Host: "test-node"},
+	}
+
+	ld := GetLogsForEvent(event)
+	rl := ld.ResourceLogs().At(0)
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		"k8s.workload.kind": pdata.NewAttributeValueString("Node"),
+		"k8s.workload.name": pdata.NewAttributeValueString("test-node"),
+		"k8s.node.name":     pdata.NewAttributeValueString("test-node"),
+	}, rl.Resource().Attributes())
+
+	lr := rl.InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, pdata.SeverityNumberINFO, lr.SeverityNumber())
+	v, ok := lr.Attributes().Get("k8s.event.source.component")
+	require.True(t, ok)
+	assert.Equal(t, "kubelet", v.StringVal())
+	v, ok = lr.Attributes().Get("k8s.event.source.host")
+	require.True(t, ok)
+	assert.Equal(t, "test-node", v.StringVal())
+}
+
+func TestEventTimestamp(t *testing.T) {
+	created := time.Date(2021, 5, 20, 10, 0, 0, 0, time.UTC)
+	first := created.Add(time.Minute)
+	eventTime := created.Add(2 * time.Minute)
+	series := created.Add(3 * time.Minute)
+	last := created.Add(4 * time.Minute)
+
+	event := &corev1.Event{ObjectMeta: v1.ObjectMeta{CreationTimestamp: v1.NewTime(created)}}
+	assert.Equal(t, created, EventTimestamp(event))
+
+	event.FirstTimestamp = v1.NewTime(first)
+	assert.Equal(t, first, EventTimestamp(event))
+
+	event.EventTime = v1.NewMicroTime(eventTime)
+	assert.Equal(t, eventTime, EventTimestamp(event))
+
+	event.Series = &corev1.EventSeries{LastObservedTime: v1.NewMicroTime(series)}
+	assert.Equal(t, series, EventTimestamp(event))
+
+	event.LastTimestamp = v1.NewTime(last)
+	assert.Equal(t, last, EventTimestamp(event))
+}
+
+func assertAttributes(t *testing.T, expected map[string]pdata.AttributeValue, actual pdata.AttributeMap) {
+	assert.EqualValues(t, pdata.NewAttributeMap().InitFromMap(expected).Sort(), actual.Sort())
+}
+
+func newEvent() *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "test-pod.1680a6f7b5c0a2b4",
+			Namespace:   "test-namespace",
+			UID:         types.UID("test-event-uid"),
+			ClusterName: "test-cluster",
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: "test-namespace",
+			Name:      "test-pod",
+			UID:       types.UID("test-pod-uid"),
+		},
+		Reason:         "FailedScheduling",
+		Message:        "0/3 nodes are available: 3 Insufficient memory.",
+		Type:           corev1.EventTypeWarning,
+		Count:          3,
+		FirstTimestamp: v1.NewTime(time.Date(2021, 5, 20, 10, 0, 0, 0, time.UTC)),
+		LastTimestamp:  v1.NewTime(time.Date(2021, 5, 20, 10, 5, 0, 0, time.UTC)),
+		Source:         corev1.EventSource{Component: "default-scheduler"},
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+
+	metadata "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/experimentalmetricmetadata"
+)
+
+const (
+	// Keys for ingress metadata.
+	ingressKeyClass           = "ingress_class"
+	ingressKeyHosts           = "hosts"
+	ingressKeyBackendServices = "backend_services"
+
+	// ingressClassAnnotation is the deprecated way of setting the class of an
+	// ingress, still used by many ingress controllers.
+	ingressClassAnnotation = "kubernetes.io/ingress.class"
+)
+
+func getMetadataForIngress(ing *networkingv1.Ingress) map[metadata.ResourceID]*KubernetesMetadata {
+	rm := getGenericMetadata(&ing.ObjectMeta, k8sKindIngress)
+
+	class := ing.Annotations[ingressClassAnnotation]
+	if ing.Spec.IngressClassName != nil {
+		class = *ing.Spec.IngressClassName
+	}
+	if class != "" {
+		rm.metadata[ingressKeyClass] = class
+	}
+
+	var hosts, services []string
+	addService := func(backend *networkingv1.IngressBackend) {
+		if backend != nil && backend.Service != nil {
+			services = appendUnique(services, backend.Service.Name)
+		}
+	}
+	addService(ing.Spec.DefaultBackend)
+	for _, rule := range ing.Spec.Rules {
+		if rule.Host != "" {
+			hosts = appendUnique(hosts, rule.Host)
+		}
+		if rule.HTTP == nil {
+			continue
+		}
+		for i := range rule.HTTP.Paths {
+			addService(&rule.HTTP.Paths[i].Backend)
+		}
+	}
+
+	if len(hosts) > 0 {
+		rm.metadata[ingressKeyHosts] = strings.Join(hosts, ",")
+	}
+	if len(services) > 0 {
+		rm.metadata[ingressKeyBackendServices] = strings.Join(services, ",")
+	}
+
+	return map[metadata.ResourceID]*KubernetesMetadata{metadata.ResourceID(ing.UID): rm}
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
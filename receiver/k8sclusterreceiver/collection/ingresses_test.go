@@ -0,0 +1,105 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestIngressMetadata(t *testing.T) {
+	ing := newIngress("1")
+
+	actualMetadata := getMetadataForIngress(ing)
+
+	require.Equal(t, 1, len(actualMetadata))
+	require.Equal(t,
+		KubernetesMetadata{
+			resourceIDKey: "k8s.ingress.uid",
+			resourceID:    "test-ingress-1-uid",
+			metadata: map[string]string{
+				"ingress.creation_timestamp": "0001-01-01T00:00:00Z",
+				"foo":                        "bar",
+				"k8s.workload.kind":          "Ingress",
+				"k8s.workload.name":          "test-ingress-1",
+				"ingress_class":              "nginx",
+				"hosts":                      "example.com,api.example.com",
+				"backend_services":           "default-backend,web,api",
+			},
+		},
+		*actualMetadata["test-ingress-1-uid"],
+	)
+}
+
+func TestIngressMetadataClassAnnotation(t *testing.T) {
+	ing := newIngress("1")
+	ing.Spec.IngressClassName = nil
+	ing.Annotations = map[string]string{"kubernetes.io/ingress.class": "traefik"}
+
+	actualMetadata := getMetadataForIngress(ing)
+	require.Equal(t, "traefik", actualMetadata["test-ingress-1-uid"].metadata["ingress_class"])
+}
+
+func newIngress(id string) *networkingv1.Ingress {
+	class := "nginx"
+	backend := func(name string) networkingv1.IngressBackend {
+		return networkingv1.IngressBackend{
+			Service: &networkingv1.IngressServiceBackend{Name: name},
+		}
+	}
+	defaultBackend := backend("default-backend")
+	return &networkingv1.Ingress{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "test-ingress-" + id,
+			Namespace:   "test-namespace",
+			UID:         types.UID("test-ingress-" + id + "-uid"),
+			ClusterName: "test-cluster",
+			Labels: map[string]string{
+				"foo": "bar",
+			},
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: &class,
+			DefaultBackend:   &defaultBackend,
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: "example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{Path: "/", Backend: backend("web")},
+								{Path: "/api", Backend: backend("api")},
+							},
+						},
+					},
+				},
+				{
+					Host: "api.example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{Path: "/", Backend: backend("api")},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
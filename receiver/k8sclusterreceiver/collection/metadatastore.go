@@ -20,6 +20,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/cache"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
 )
 
 // metadataStore keeps track of required caches exposed by informers.
@@ -27,18 +29,52 @@ import (
 // to correlate other Kubernetes objects with a Pod.
 type metadataStore struct {
 	services    cache.Store
+	endpoints   cache.Store
 	jobs        cache.Store
 	replicaSets cache.Store
 }
 
-// setupStore tracks metadata of services, jobs and replicasets.
+// setupStore tracks metadata of services, endpoints, jobs and replicasets.
 func (ms *metadataStore) setupStore(o runtime.Object, store cache.Store) {
 	switch o.(type) {
 	case *corev1.Service:
 		ms.services = store
+	case *corev1.Endpoints:
+		ms.endpoints = store
 	case *batchv1.Job:
 		ms.jobs = store
 	case *appsv1.ReplicaSet:
 		ms.replicaSets = store
 	}
 }
+
+// getServiceForEndpoints returns the service the endpoints belong to, which
+// has the same name and namespace, or nil if it isn't known.
+func (ms *metadataStore) getServiceForEndpoints(ep *corev1.Endpoints) *corev1.Service {
+	obj := getFromStore(ms.services, ep.Namespace, ep.Name)
+	if obj == nil {
+		return nil
+	}
+	return obj.(*corev1.Service)
+}
+
+// getEndpointsForService returns the endpoints of the service, or nil if
+// they aren't known.
+func (ms *metadataStore) getEndpointsForService(svc *corev1.Service) *corev1.Endpoints {
+	obj := getFromStore(ms.endpoints, svc.Namespace, svc.Name)
+	if obj == nil {
+		return nil
+	}
+	return obj.(*corev1.Endpoints)
+}
+
+func getFromStore(store cache.Store, namespace string, name string) interface{} {
+	if store == nil {
+		return nil
+	}
+	obj, exists, err := store.GetByKey(utils.GetIDForCache(namespace, name))
+	if err != nil || !exists {
+		return nil
+	}
+	return obj
+}
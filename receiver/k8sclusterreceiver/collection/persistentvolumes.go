@@ -0,0 +1,206 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/translator/conventions"
+	corev1 "k8s.io/api/core/v1"
+
+	metadata "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/experimentalmetricmetadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
+)
+
+const (
+	// Keys for persistent volume and persistent volume claim metadata.
+	persistentVolumeKeyStorageClass  = "storage_class"
+	persistentVolumeKeyReclaimPolicy = "reclaim_policy"
+	persistentVolumeKeyVolumeName    = "volume_name"
+)
+
+var persistentVolumePhaseMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s.persistentvolume.phase",
+	Description: "Current phase of the persistent volume (1 - Pending, 2 - Available, 3 - Bound, 4 - Released, 5 - Failed)",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var persistentVolumeCapacityMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s.persistentvolume.capacity",
+	Description: "Storage capacity of the persistent volume",
+	Unit:        "By",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var persistentVolumeClaimPhaseMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s.persistentvolumeclaim.phase",
+	Description: "Current phase of the persistent volume claim (1 - Pending, 2 - Bound, 3 - Lost)",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var persistentVolumeClaimRequestedStorageMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s.persistentvolumeclaim.requested_storage",
+	Description: "Storage requested by the persistent volume claim",
+	Unit:        "By",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var persistentVolumeClaimCapacityMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s.persistentvolumeclaim.capacity",
+	Description: "Storage capacity of the volume bound to the persistent volume claim",
+	Unit:        "By",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+func getMetricsForPersistentVolume(pv *corev1.PersistentVolume) []*resourceMetrics {
+	metrics := []*metricspb.Metric{
+		{
+			MetricDescriptor: persistentVolumePhaseMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(int64(persistentVolumePhaseToInt(pv.Status.Phase))),
+			},
+		},
+	}
+
+	if capacity, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: persistentVolumeCapacityMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(capacity.Value()),
+			},
+		})
+	}
+
+	return []*resourceMetrics{
+		{
+			resource: getResourceForPersistentVolume(pv),
+			metrics:  metrics,
+		},
+	}
+}
+
+func getResourceForPersistentVolume(pv *corev1.PersistentVolume) *resourcepb.Resource {
+	return &resourcepb.Resource{
+		Type: k8sType,
+		Labels: map[string]string{
+			k8sKeyPersistentVolumeUID:       string(pv.UID),
+			k8sKeyPersistentVolumeName:      pv.Name,
+			conventions.AttributeK8sCluster: pv.ClusterName,
+		},
+	}
+}
+
+func getMetadataForPersistentVolume(pv *corev1.PersistentVolume) map[metadata.ResourceID]*KubernetesMetadata {
+	rm := getGenericMetadata(&pv.ObjectMeta, k8sKindPersistentVolume)
+	if pv.Spec.StorageClassName != "" {
+		rm.metadata[persistentVolumeKeyStorageClass] = pv.Spec.StorageClassName
+	}
+	rm.metadata[persistentVolumeKeyReclaimPolicy] = string(pv.Spec.PersistentVolumeReclaimPolicy)
+	if ref := pv.Spec.ClaimRef; ref != nil {
+		rm.metadata[k8sKeyPersistentVolumeClaimName] = ref.Name
+		rm.metadata[k8sKeyPersistentVolumeClaimUID] = string(ref.UID)
+		rm.metadata[conventions.AttributeK8sNamespace] = ref.Namespace
+	}
+	return map[metadata.ResourceID]*KubernetesMetadata{metadata.ResourceID(pv.UID): rm}
+}
+
+func persistentVolumePhaseToInt(phase corev1.PersistentVolumePhase) int32 {
+	switch phase {
+	case corev1.VolumePending:
+		return 1
+	case corev1.VolumeAvailable:
+		return 2
+	case corev1.VolumeBound:
+		return 3
+	case corev1.VolumeReleased:
+		return 4
+	case corev1.VolumeFailed:
+		return 5
+	default:
+		return 1
+	}
+}
+
+func getMetricsForPersistentVolumeClaim(pvc *corev1.PersistentVolumeClaim) []*resourceMetrics {
+	metrics := []*metricspb.Metric{
+		{
+			MetricDescriptor: persistentVolumeClaimPhaseMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(int64(persistentVolumeClaimPhaseToInt(pvc.Status.Phase))),
+			},
+		},
+	}
+
+	if requested, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: persistentVolumeClaimRequestedStorageMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(requested.Value()),
+			},
+		})
+	}
+
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: persistentVolumeClaimCapacityMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(capacity.Value()),
+			},
+		})
+	}
+
+	return []*resourceMetrics{
+		{
+			resource: getResourceForPersistentVolumeClaim(pvc),
+			metrics:  metrics,
+		},
+	}
+}
+
+func getResourceForPersistentVolumeClaim(pvc *corev1.PersistentVolumeClaim) *resourcepb.Resource {
+	return &resourcepb.Resource{
+		Type: k8sType,
+		Labels: map[string]string{
+			k8sKeyPersistentVolumeClaimUID:    string(pvc.UID),
+			k8sKeyPersistentVolumeClaimName:   pvc.Name,
+			conventions.AttributeK8sNamespace: pvc.Namespace,
+			conventions.AttributeK8sCluster:   pvc.ClusterName,
+		},
+	}
+}
+
+func getMetadataForPersistentVolumeClaim(pvc *corev1.PersistentVolumeClaim) map[metadata.ResourceID]*KubernetesMetadata {
+	rm := getGenericMetadata(&pvc.ObjectMeta, k8sKindPersistentVolumeClaim)
+	if pvc.Spec.StorageClassName != nil {
+		rm.metadata[persistentVolumeKeyStorageClass] = *pvc.Spec.StorageClassName
+	}
+	if pvc.Spec.VolumeName != "" {
+		rm.metadata[persistentVolumeKeyVolumeName] = pvc.Spec.VolumeName
+	}
+	return map[metadata.ResourceID]*KubernetesMetadata{metadata.ResourceID(pvc.UID): rm}
+}
+
+func persistentVolumeClaimPhaseToInt(phase corev1.PersistentVolumeClaimPhase) int32 {
+	switch phase {
+	case corev1.ClaimPending:
+		return 1
+	case corev1.ClaimBound:
+		return 2
+	case corev1.ClaimLost:
+		return 3
+	default:
+		return 1
+	}
+}
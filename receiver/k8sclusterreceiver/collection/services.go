@@ -0,0 +1,104 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/translator/conventions"
+	corev1 "k8s.io/api/core/v1"
+
+	metadata "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/experimentalmetricmetadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
+)
+
+const (
+	// Keys for service metadata.
+	serviceKeyType      = "type"
+	serviceKeyClusterIP = "cluster_ip"
+)
+
+var serviceReadyEndpointsMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s.service.ready_endpoints",
+	Description: "The number of endpoint addresses of the service that are ready to serve traffic",
+	Unit:        "1",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var serviceNotReadyEndpointsMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s.service.not_ready_endpoints",
+	Description: "The number of endpoint addresses of the service that are not ready to serve traffic",
+	Unit:        "1",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+// getMetricsForEndpoints returns the endpoint metrics of the service the
+// endpoints belong to. svc may be nil if the service isn't known yet, in which
+// case the resource only has the name and namespace of the service.
+func getMetricsForEndpoints(ep *corev1.Endpoints, svc *corev1.Service) []*resourceMetrics {
+	var ready, notReady int64
+	for _, subset := range ep.Subsets {
+		ready += int64(len(subset.Addresses))
+		notReady += int64(len(subset.NotReadyAddresses))
+	}
+
+	metrics := []*metricspb.Metric{
+		{
+			MetricDescriptor: serviceReadyEndpointsMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(ready),
+			},
+		},
+		{
+			MetricDescriptor: serviceNotReadyEndpointsMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(notReady),
+			},
+		},
+	}
+
+	return []*resourceMetrics{
+		{
+			resource: getResourceForService(ep, svc),
+			metrics:  metrics,
+		},
+	}
+}
+
+func getResourceForService(ep *corev1.Endpoints, svc *corev1.Service) *resourcepb.Resource {
+	labels := map[string]string{
+		k8sKeyServiceName:                 ep.Name,
+		conventions.AttributeK8sNamespace: ep.Namespace,
+		conventions.AttributeK8sCluster:   ep.ClusterName,
+	}
+	if svc != nil {
+		labels[k8sKeyServiceUID] = string(svc.UID)
+		labels[k8sKeyServiceType] = string(svc.Spec.Type)
+	}
+
+	return &resourcepb.Resource{
+		Type:   k8sType,
+		Labels: labels,
+	}
+}
+
+func getMetadataForService(svc *corev1.Service) map[metadata.ResourceID]*KubernetesMetadata {
+	rm := getGenericMetadata(&svc.ObjectMeta, k8sKindService)
+	rm.metadata[serviceKeyType] = string(svc.Spec.Type)
+	if svc.Spec.ClusterIP != "" {
+		rm.metadata[serviceKeyClusterIP] = svc.Spec.ClusterIP
+	}
+	return map[metadata.ResourceID]*KubernetesMetadata{metadata.ResourceID(svc.UID): rm}
+}
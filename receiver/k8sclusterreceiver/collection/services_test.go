@@ -0,0 +1,153 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/testutils"
+)
+
+func TestEndpointsMetrics(t *testing.T) {
+	ep := newEndpoints("1")
+
+	actualResourceMetrics := getMetricsForEndpoints(ep, newService("1"))
+
+	require.Equal(t, 1, len(actualResourceMetrics))
+	require.Equal(t, 2, len(actualResourceMetrics[0].metrics))
+
+	testutils.AssertResource(t, actualResourceMetrics[0].resource, k8sType,
+		map[string]string{
+			"k8s.service.uid":    "test-service-1-uid",
+			"k8s.service.name":   "test-service-1",
+			"k8s.service.type":   "ClusterIP",
+			"k8s.namespace.name": "test-namespace",
+			"k8s.cluster.name":   "test-cluster",
+		},
+	)
+
+	testutils.AssertMetrics(t, actualResourceMetrics[0].metrics[0], "k8s.service.ready_endpoints",
+		metricspb.MetricDescriptor_GAUGE_INT64, 3)
+	testutils.AssertMetrics(t, actualResourceMetrics[0].metrics[1], "k8s.service.not_ready_endpoints",
+		metricspb.MetricDescriptor_GAUGE_INT64, 1)
+}
+
+func TestEndpointsMetricsUnknownService(t *testing.T) {
+	ep := newEndpoints("1")
+	ep.Subsets = nil
+
+	actualResourceMetrics := getMetricsForEndpoints(ep, nil)
+
+	require.Equal(t, 1, len(actualResourceMetrics))
+	testutils.AssertResource(t, actualResourceMetrics[0].resource, k8sType,
+		map[string]string{
+			"k8s.service.name":   "test-service-1",
+			"k8s.namespace.name": "test-namespace",
+			"k8s.cluster.name":   "test-cluster",
+		},
+	)
+	testutils.AssertMetrics(t, actualResourceMetrics[0].metrics[0], "k8s.service.ready_endpoints",
+		metricspb.MetricDescriptor_GAUGE_INT64, 0)
+}
+
+func TestServiceMetadata(t *testing.T) {
+	svc := newService("1")
+
+	actualMetadata := getMetadataForService(svc)
+
+	require.Equal(t, 1, len(actualMetadata))
+	require.Equal(t,
+		KubernetesMetadata{
+			resourceIDKey: "k8s.service.uid",
+			resourceID:    "test-service-1-uid",
+			metadata: map[string]string{
+				"service.creation_timestamp": "0001-01-01T00:00:00Z",
+				"foo":                        "bar",
+				"k8s.workload.kind":          "Service",
+				"k8s.workload.name":          "test-service-1",
+				"type":                       "ClusterIP",
+				"cluster_ip":                 "10.0.0.1",
+			},
+		},
+		*actualMetadata["test-service-1-uid"],
+	)
+}
+
+func TestSyncEndpointsAndServiceMetrics(t *testing.T) {
+	dc := NewDataCollector(zap.NewNop(), []string{})
+	services := &testutils.MockStore{Cache: map[string]interface{}{}}
+	endpoints := &testutils.MockStore{Cache: map[string]interface{}{}}
+	dc.SetupMetadataStore(&corev1.Service{}, services)
+	dc.SetupMetadataStore(&corev1.Endpoints{}, endpoints)
+
+	// The endpoints are synced before the service is known.
+	ep := newEndpoints("1")
+	endpoints.Cache["test-namespace/test-service-1"] = ep
+	dc.SyncMetrics(ep)
+	require.Equal(t, 1, len(dc.metricsStore.metricsCache))
+	require.NotContains(t, dc.metricsStore.metricsCache[ep.UID][0].Resource.Labels, "k8s.service.type")
+
+	// Syncing the service refreshes the endpoints metrics.
+	svc := newService("1")
+	services.Cache["test-namespace/test-service-1"] = svc
+	dc.SyncMetrics(svc)
+	require.Equal(t, 1, len(dc.metricsStore.metricsCache))
+	require.Equal(t, "ClusterIP", dc.metricsStore.metricsCache[ep.UID][0].Resource.Labels["k8s.service.type"])
+}
+
+func newService(id string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "test-service-" + id,
+			Namespace:   "test-namespace",
+			UID:         types.UID("test-service-" + id + "-uid"),
+			ClusterName: "test-cluster",
+			Labels: map[string]string{
+				"foo": "bar",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeClusterIP,
+			ClusterIP: "10.0.0.1",
+		},
+	}
+}
+
+func newEndpoints(id string) *corev1.Endpoints {
+	return &corev1.Endpoints{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "test-service-" + id,
+			Namespace:   "test-namespace",
+			UID:         types.UID("test-endpoints-" + id + "-uid"),
+			ClusterName: "test-cluster",
+		},
+		Subsets: []corev1.EndpointSubset{
+			{
+				Addresses:         []corev1.EndpointAddress{{IP: "10.1.0.1"}, {IP: "10.1.0.2"}},
+				NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.1.0.3"}},
+			},
+			{
+				Addresses: []corev1.EndpointAddress{{IP: "10.1.0.4"}},
+			},
+		},
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/collection"
+)
+
+var _ component.LogsReceiver = (*eventsReceiver)(nil)
+
+// eventsReceiver watches Kubernetes events and emits each one as a log record.
+type eventsReceiver struct {
+	client    kubernetes.Interface
+	config    *Config
+	logger    *zap.Logger
+	consumer  consumer.Logs
+	startTime time.Time
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
+// newEventsReceiver creates the Kubernetes events receiver with the given configuration.
+func newEventsReceiver(
+	logger *zap.Logger, config *Config, consumer consumer.Logs,
+	client kubernetes.Interface) (component.LogsReceiver, error) {
+	return &eventsReceiver{
+		client:   client,
+		config:   config,
+		logger:   logger,
+		consumer: consumer,
+	}, nil
+}
+
+func (er *eventsReceiver) Start(ctx context.Context, _ component.Host) error {
+	er.ctx, er.cancel = context.WithCancel(obsreport.ReceiverContext(ctx, er.config.ID(), transport))
+	er.startTime = time.Now()
+
+	factory := informers.NewSharedInformerFactoryWithOptions(er.client, 0)
+	factory.Core().V1().Events().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    er.onAdd,
+		UpdateFunc: er.onUpdate,
+	})
+
+	er.logger.Info("Starting to watch Kubernetes events.")
+	factory.Start(er.ctx.Done())
+	return nil
+}
+
+func (er *eventsReceiver) Shutdown(context.Context) error {
+	if er.cancel != nil {
+		er.cancel()
+	}
+	return nil
+}
+
+// onAdd is called for every event in the initial listing and whenever the
+// informer relists after losing its watch. Events that last occurred before
+// the receiver started were either already reported by a previous run of
+// the collector or are too old to be of interest, so only newer ones are
+// reported.
+func (er *eventsReceiver) onAdd(obj interface{}) {
+	event, ok := obj.(*corev1.Event)
+	if !ok {
+		return
+	}
+	if collection.EventTimestamp(event).Before(er.startTime) {
+		return
+	}
+	er.consumeEvent(event)
+}
+
+// onUpdate is called when an event occurs again, which increments its count,
+// and on informer resyncs, which don't change the resource version. Only the
+// former must be reported.
+func (er *eventsReceiver) onUpdate(oldObj, newObj interface{}) {
+	oldEvent, ok := oldObj.(*corev1.Event)
+	if !ok {
+		return
+	}
+	newEvent, ok := newObj.(*corev1.Event)
+	if !ok {
+		return
+	}
+	if oldEvent.ResourceVersion == newEvent.ResourceVersion {
+		return
+	}
+	er.consumeEvent(newEvent)
+}
+
+func (er *eventsReceiver) consumeEvent(event *corev1.Event) {
+	ld := collection.GetLogsForEvent(event)
+
+	c := obsreport.StartLogsReceiveOp(er.ctx, er.config.ID(), transport)
+	err := er.consumer.ConsumeLogs(c, ld)
+	if err != nil {
+		er.logger.Error("Failed to consume Kubernetes event", zap.String("event", event.Name), zap.Error(err))
+	}
+	obsreport.EndLogsReceiveOp(c, typeStr, ld.LogRecordCount(), err)
+}
@@ -0,0 +1,91 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEventsReceiver(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	sink := new(consumertest.LogsSink)
+
+	r, err := newEventsReceiver(zap.NewNop(), &Config{}, sink, client)
+	require.NoError(t, err)
+
+	// Events that occurred before the receiver started are not reported.
+	createEvent(t, client, "old-event", time.Now().Add(-time.Hour))
+
+	ctx := context.Background()
+	require.NoError(t, r.Start(ctx, componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(ctx)) }()
+
+	created := createEvent(t, client, "new-event", time.Now().Add(time.Second))
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 1
+	}, 10*time.Second, 100*time.Millisecond, "event not collected")
+
+	lr := sink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	name, ok := lr.Attributes().Get("k8s.event.name")
+	require.True(t, ok)
+	require.Equal(t, "new-event", name.StringVal())
+
+	er := r.(*eventsReceiver)
+
+	// Resyncs don't change the resource version and are not reported again.
+	er.onUpdate(created, created)
+	require.Equal(t, 1, sink.LogRecordsCount())
+
+	// The event occurring again is reported.
+	updated := created.DeepCopy()
+	updated.ResourceVersion = "2"
+	updated.Count = 2
+	er.onUpdate(created, updated)
+	require.Equal(t, 2, sink.LogRecordsCount())
+}
+
+func createEvent(t *testing.T, client *fake.Clientset, name string, timestamp time.Time) *corev1.Event {
+	event := &corev1.Event{
+		ObjectMeta: v1.ObjectMeta{
+			Name:            name,
+			Namespace:       "test-namespace",
+			ResourceVersion: "1",
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: "test-namespace",
+			Name:      "test-pod",
+		},
+		Reason:        "OOMKilling",
+		Message:       "Memory cgroup out of memory",
+		Type:          corev1.EventTypeWarning,
+		Count:         1,
+		LastTimestamp: v1.NewTime(timestamp),
+	}
+	created, err := client.CoreV1().Events(event.Namespace).Create(context.Background(), event, v1.CreateOptions{})
+	require.NoError(t, err)
+	return created
+}
@@ -59,10 +59,23 @@ func createMetricsReceiver(
 	return newReceiver(params.Logger, rCfg, consumer, k8sClient)
 }
 
+func createLogsReceiver(
+	_ context.Context, params component.ReceiverCreateParams, cfg config.Receiver,
+	consumer consumer.Logs) (component.LogsReceiver, error) {
+	rCfg := cfg.(*Config)
+
+	k8sClient, err := rCfg.getK8sClient()
+	if err != nil {
+		return nil, err
+	}
+	return newEventsReceiver(params.Logger, rCfg, consumer, k8sClient)
+}
+
 // NewFactory creates a factory for k8s_cluster receiver.
 func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
 }
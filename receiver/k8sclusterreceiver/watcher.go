@@ -28,6 +28,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
@@ -82,6 +83,11 @@ func (rw *resourceWatcher) prepareSharedInformerFactory() {
 	)
 	rw.setupInformers(&corev1.ResourceQuota{}, factory.Core().V1().ResourceQuotas().Informer())
 	rw.setupInformers(&corev1.Service{}, factory.Core().V1().Services().Informer())
+	rw.setupInformers(&corev1.Endpoints{}, factory.Core().V1().Endpoints().Informer())
+	rw.setupInformers(&corev1.PersistentVolume{}, factory.Core().V1().PersistentVolumes().Informer())
+	rw.setupInformers(&corev1.PersistentVolumeClaim{},
+		factory.Core().V1().PersistentVolumeClaims().Informer(),
+	)
 	rw.setupInformers(&appsv1.DaemonSet{}, factory.Apps().V1().DaemonSets().Informer())
 	rw.setupInformers(&appsv1.Deployment{}, factory.Apps().V1().Deployments().Informer())
 	rw.setupInformers(&appsv1.ReplicaSet{}, factory.Apps().V1().ReplicaSets().Informer())
@@ -92,9 +98,37 @@ func (rw *resourceWatcher) prepareSharedInformerFactory() {
 		factory.Autoscaling().V2beta1().HorizontalPodAutoscalers().Informer(),
 	)
 
+	// networking.k8s.io/v1 Ingresses are only served since Kubernetes 1.19.
+	// Watching them on older clusters would prevent the initial sync from
+	// ever completing.
+	if rw.isAPIAvailable(networkingv1.SchemeGroupVersion.String(), "ingresses") {
+		rw.setupInformers(&networkingv1.Ingress{}, factory.Networking().V1().Ingresses().Informer())
+	} else {
+		rw.logger.Info("Ingresses are not watched since the API server does not serve them",
+			zap.String("group_version", networkingv1.SchemeGroupVersion.String()))
+	}
+
 	rw.sharedInformerFactory = factory
 }
 
+// isAPIAvailable returns whether the API server serves the resource in the
+// given group version.
+func (rw *resourceWatcher) isAPIAvailable(groupVersion string, resource string) bool {
+	if rw.client == nil {
+		return false
+	}
+	resources, err := rw.client.Discovery().ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		return false
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == resource {
+			return true
+		}
+	}
+	return false
+}
+
 // startWatchingResources starts up all informers.
 func (rw *resourceWatcher) startWatchingResources(ctx context.Context) {
 	var cancel context.CancelFunc
@@ -21,6 +21,8 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestSetupMetadataExporters(t *testing.T) {
@@ -86,3 +88,18 @@ func TestSetupMetadataExporters(t *testing.T) {
 		})
 	}
 }
+
+func TestIsAPIAvailable(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	rw := &resourceWatcher{client: client, logger: zap.NewNop()}
+	require.False(t, rw.isAPIAvailable("networking.k8s.io/v1", "ingresses"))
+
+	client.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "networking.k8s.io/v1",
+			APIResources: []metav1.APIResource{{Name: "ingresses"}},
+		},
+	}
+	require.True(t, rw.isAPIAvailable("networking.k8s.io/v1", "ingresses"))
+	require.False(t, rw.isAPIAvailable("networking.k8s.io/v1", "ingressclasses"))
+}
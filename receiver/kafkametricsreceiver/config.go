@@ -15,6 +15,8 @@
 package kafkametricsreceiver
 
 import (
+	"fmt"
+
 	"go.opentelemetry.io/collector/exporter/kafkaexporter"
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
 )
@@ -41,6 +43,18 @@ type Config struct {
 	// Scrapers defines which metric data points to be captured from kafka
 	Scrapers []string `mapstructure:"scrapers"`
 
+	// LagSamples is the maximum number of sampled high-watermark offsets kept for each partition
+	// to estimate the consumer group time lag.
+	LagSamples int `mapstructure:"lag_samples"`
+
 	// ClientID is the id associated with the consumer that reads from topics in kafka.
 	ClientID string `mapstructure:"client_id"`
 }
+
+// Validate checks if the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	if cfg.LagSamples <= 0 {
+		return fmt.Errorf("lag_samples must be positive, got %d", cfg.LagSamples)
+	}
+	return nil
+}
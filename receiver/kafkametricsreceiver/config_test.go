@@ -15,6 +15,7 @@
 package kafkametricsreceiver
 
 import (
+	"fmt"
 	"path"
 	"testing"
 
@@ -36,7 +37,7 @@ func TestLoadConfig(t *testing.T) {
 	factories.Receivers[typeStr] = factory
 	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
 	require.NoError(t, err)
-	require.Equal(t, 1, len(cfg.Receivers))
+	require.Equal(t, 2, len(cfg.Receivers))
 
 	r := cfg.Receivers[config.NewID(typeStr)].(*Config)
 	assert.Equal(t, &Config{
@@ -54,7 +55,71 @@ func TestLoadConfig(t *testing.T) {
 				},
 			},
 		},
-		ClientID: defaultClientID,
-		Scrapers: []string{"brokers", "topics", "consumers"},
+		ClientID:   defaultClientID,
+		LagSamples: defaultLagSamples,
+		Scrapers:   []string{"brokers", "topics", "consumers"},
 	}, r)
+
+	r = cfg.Receivers[config.NewIDWithName(typeStr, "sasl")].(*Config)
+	assert.Equal(t, &Config{
+		ScraperControllerSettings: scraperhelper.ScraperControllerSettings{
+			ReceiverSettings:   config.NewReceiverSettings(config.NewIDWithName(typeStr, "sasl")),
+			CollectionInterval: scraperhelper.DefaultScraperControllerSettings(typeStr).CollectionInterval,
+		},
+		Brokers:                   []string{"10.10.10.10:9093"},
+		ProtocolVersion:           "2.0.0",
+		TopicMatch:                defaultTopicMatch,
+		GroupMatch:                defaultGroupMatch,
+		Authentication: kafkaexporter.Authentication{
+			SASL: &kafkaexporter.SASLConfig{
+				Username:  "user",
+				Password:  "pass",
+				Mechanism: "SCRAM-SHA-512",
+			},
+			TLS: &configtls.TLSClientSetting{
+				TLSSetting: configtls.TLSSetting{
+					CAFile: "ca.pem",
+				},
+			},
+		},
+		ClientID:   defaultClientID,
+		LagSamples: 120,
+		Scrapers:   []string{"consumers"},
+	}, r)
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		lagSamples int
+		expectErr  bool
+	}{
+		{name: "default", lagSamples: defaultLagSamples},
+		{name: "one sample", lagSamples: 1},
+		{name: "zero samples", lagSamples: 0, expectErr: true},
+		{name: "negative samples", lagSamples: -1, expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.LagSamples = tt.lagSamples
+			err := cfg.Validate()
+			if tt.expectErr {
+				assert.EqualError(t, err, fmt.Sprintf("lag_samples must be positive, got %d", tt.lagSamples))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLoadInvalidConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	_, err = configtest.LoadConfigFile(t, path.Join(".", "testdata", "config_invalid_lag_samples.yaml"), factories)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "lag_samples must be positive")
 }
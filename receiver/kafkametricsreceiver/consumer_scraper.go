@@ -40,6 +40,8 @@ type consumerScraper struct {
 	clusterAdmin sarama.ClusterAdmin
 	saramaConfig *sarama.Config
 	config       Config
+	// sampled high-watermark offsets of each partition in matched topics, kept across scrapes
+	offsetTimes map[string]map[int32]*offsetTimeTable
 }
 
 func (s *consumerScraper) Name() string {
@@ -123,6 +125,7 @@ func (s *consumerScraper) scrape(context.Context) (pdata.ResourceMetricsSlice, e
 			topicPartitionOffset[topic][p] = o
 		}
 	}
+	s.recordOffsetTimes(topicPartitionOffset, metrics.Timestamp)
 	consumerGroups, listErr := s.clusterAdmin.DescribeConsumerGroups(matchedGrpIds)
 	if listErr != nil {
 		return metrics.ResourceMetrics(), listErr
@@ -149,6 +152,8 @@ func (s *consumerScraper) scrape(context.Context) (pdata.ResourceMetricsSlice, e
 			if isConsumed {
 				var lagSum int64
 				var offsetSum int64
+				var lagTimeMax float64
+				hasLagTime := false
 				for partition, block := range partitions {
 					grpPartitionMetrics := grpTopicMetrics.WithLabels(map[string]string{metadata.L.Partition: string(partition)})
 					consumerOffset := block.Offset
@@ -164,9 +169,23 @@ func (s *consumerScraper) scrape(context.Context) (pdata.ResourceMetricsSlice, e
 						}
 					}
 					grpPartitionMetrics.AddGaugeDataPoint(metadata.M.KafkaConsumerGroupLag.Name(), consumerLag)
+					if block.Offset != -1 {
+						if table, ok := s.offsetTimes[topic][partition]; ok {
+							if lagTime, ok := table.lag(consumerOffset, metrics.Timestamp); ok {
+								grpPartitionMetrics.AddDGaugeDataPoint(metadata.M.KafkaConsumerGroupLagTime.Name(), lagTime.Seconds())
+								if !hasLagTime || lagTime.Seconds() > lagTimeMax {
+									lagTimeMax = lagTime.Seconds()
+								}
+								hasLagTime = true
+							}
+						}
+					}
 				}
 				grpTopicMetrics.AddGaugeDataPoint(metadata.M.KafkaConsumerGroupOffsetSum.Name(), offsetSum)
 				grpTopicMetrics.AddGaugeDataPoint(metadata.M.KafkaConsumerGroupLagSum.Name(), lagSum)
+				if hasLagTime {
+					grpTopicMetrics.AddDGaugeDataPoint(metadata.M.KafkaConsumerGroupLagTimeMax.Name(), lagTimeMax)
+				}
 			}
 		}
 	}
@@ -174,6 +193,24 @@ func (s *consumerScraper) scrape(context.Context) (pdata.ResourceMetricsSlice, e
 	return metrics.ResourceMetrics(), scrapeErrors.Combine()
 }
 
+// recordOffsetTimes samples the current high-watermark offsets of the matched topic partitions,
+// dropping the tables of partitions that are no longer matched.
+func (s *consumerScraper) recordOffsetTimes(topicPartitionOffset map[string]map[int32]int64, now time.Time) {
+	offsetTimes := map[string]map[int32]*offsetTimeTable{}
+	for topic, partitionOffsets := range topicPartitionOffset {
+		offsetTimes[topic] = map[int32]*offsetTimeTable{}
+		for partition, offset := range partitionOffsets {
+			table, ok := s.offsetTimes[topic][partition]
+			if !ok {
+				table = newOffsetTimeTable(s.config.LagSamples)
+			}
+			table.add(offset, now)
+			offsetTimes[topic][partition] = table
+		}
+	}
+	s.offsetTimes = offsetTimes
+}
+
 func createConsumerScraper(_ context.Context, cfg Config, saramaConfig *sarama.Config, logger *zap.Logger) (scraperhelper.ResourceMetricsScraper, error) {
 	groupFilter, err := regexp.Compile(cfg.GroupMatch)
 	if err != nil {
@@ -22,7 +22,10 @@ import (
 
 	"github.com/Shopify/sarama"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkametricsreceiver/internal/metadata"
 )
 
 func TestConsumerShutdown(t *testing.T) {
@@ -142,6 +145,43 @@ func TestConsumerScraper_scrape(t *testing.T) {
 	assert.NotNil(t, ms)
 }
 
+func TestConsumerScraper_scrape_lagTime(t *testing.T) {
+	filter := regexp.MustCompile(defaultGroupMatch)
+	client := newMockClient()
+	clusterAdmin := newMockClusterAdmin()
+	cs := consumerScraper{
+		client:       client,
+		logger:       zap.NewNop(),
+		clusterAdmin: clusterAdmin,
+		topicFilter:  filter,
+		groupFilter:  filter,
+		config:       Config{LagSamples: defaultLagSamples},
+	}
+
+	lagTimePoints := func() int {
+		ms, err := cs.scrape(context.Background())
+		require.NoError(t, err)
+		count := 0
+		metrics := ms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+		for i := 0; i < metrics.Len(); i++ {
+			m := metrics.At(i)
+			if m.Name() == metadata.M.KafkaConsumerGroupLagTime.Name() {
+				count += m.DoubleGauge().DataPoints().Len()
+				assert.Greater(t, m.DoubleGauge().DataPoints().At(0).Value(), 0.0)
+			}
+		}
+		return count
+	}
+
+	// the group is one message behind a single sampled offset
+	client.offset = 2
+	assert.Equal(t, 0, lagTimePoints())
+
+	client.offset = 4
+	assert.Equal(t, 1, lagTimePoints())
+	assert.Len(t, cs.offsetTimes[testTopic][testPartition].samples, 2)
+}
+
 func TestConsumerScraper_scrape_handlesListTopicError(t *testing.T) {
 	filter := regexp.MustCompile(defaultGroupMatch)
 	clusterAdmin := newMockClusterAdmin()
@@ -30,6 +30,7 @@ const (
 	defaultGroupMatch = ".*"
 	defaultTopicMatch = "^[^_].*$"
 	defaultClientID   = "otel-metrics-receiver"
+	defaultLagSamples = 60
 )
 
 // NewFactory creates kafkametrics receiver factory.
@@ -47,6 +48,7 @@ func createDefaultConfig() config.Receiver {
 		GroupMatch:                defaultGroupMatch,
 		TopicMatch:                defaultTopicMatch,
 		ClientID:                  defaultClientID,
+		LagSamples:                defaultLagSamples,
 	}
 }
 
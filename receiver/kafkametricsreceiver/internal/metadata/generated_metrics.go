@@ -58,6 +58,8 @@ type metricStruct struct {
 	KafkaBrokers                 MetricIntf
 	KafkaConsumerGroupLag        MetricIntf
 	KafkaConsumerGroupLagSum     MetricIntf
+	KafkaConsumerGroupLagTime    MetricIntf
+	KafkaConsumerGroupLagTimeMax MetricIntf
 	KafkaConsumerGroupMembers    MetricIntf
 	KafkaConsumerGroupOffset     MetricIntf
 	KafkaConsumerGroupOffsetSum  MetricIntf
@@ -74,6 +76,8 @@ func (m *metricStruct) Names() []string {
 		"kafka.brokers",
 		"kafka.consumer_group.lag",
 		"kafka.consumer_group.lag_sum",
+		"kafka.consumer_group.lag_time",
+		"kafka.consumer_group.lag_time_max",
 		"kafka.consumer_group.members",
 		"kafka.consumer_group.offset",
 		"kafka.consumer_group.offset_sum",
@@ -86,17 +90,19 @@ func (m *metricStruct) Names() []string {
 }
 
 var metricsByName = map[string]MetricIntf{
-	"kafka.brokers":                    Metrics.KafkaBrokers,
-	"kafka.consumer_group.lag":         Metrics.KafkaConsumerGroupLag,
-	"kafka.consumer_group.lag_sum":     Metrics.KafkaConsumerGroupLagSum,
-	"kafka.consumer_group.members":     Metrics.KafkaConsumerGroupMembers,
-	"kafka.consumer_group.offset":      Metrics.KafkaConsumerGroupOffset,
-	"kafka.consumer_group.offset_sum":  Metrics.KafkaConsumerGroupOffsetSum,
-	"kafka.partition.current_offset":   Metrics.KafkaPartitionCurrentOffset,
-	"kafka.partition.oldest_offset":    Metrics.KafkaPartitionOldestOffset,
-	"kafka.partition.replicas":         Metrics.KafkaPartitionReplicas,
-	"kafka.partition.replicas_in_sync": Metrics.KafkaPartitionReplicasInSync,
-	"kafka.topic.partitions":           Metrics.KafkaTopicPartitions,
+	"kafka.brokers":                     Metrics.KafkaBrokers,
+	"kafka.consumer_group.lag":          Metrics.KafkaConsumerGroupLag,
+	"kafka.consumer_group.lag_sum":      Metrics.KafkaConsumerGroupLagSum,
+	"kafka.consumer_group.lag_time":     Metrics.KafkaConsumerGroupLagTime,
+	"kafka.consumer_group.lag_time_max": Metrics.KafkaConsumerGroupLagTimeMax,
+	"kafka.consumer_group.members":      Metrics.KafkaConsumerGroupMembers,
+	"kafka.consumer_group.offset":       Metrics.KafkaConsumerGroupOffset,
+	"kafka.consumer_group.offset_sum":   Metrics.KafkaConsumerGroupOffsetSum,
+	"kafka.partition.current_offset":    Metrics.KafkaPartitionCurrentOffset,
+	"kafka.partition.oldest_offset":     Metrics.KafkaPartitionOldestOffset,
+	"kafka.partition.replicas":          Metrics.KafkaPartitionReplicas,
+	"kafka.partition.replicas_in_sync":  Metrics.KafkaPartitionReplicasInSync,
+	"kafka.topic.partitions":            Metrics.KafkaTopicPartitions,
 }
 
 func (m *metricStruct) ByName(n string) MetricIntf {
@@ -108,6 +114,8 @@ func (m *metricStruct) FactoriesByName() map[string]func(pdata.Metric) {
 		Metrics.KafkaBrokers.Name():                 Metrics.KafkaBrokers.Init,
 		Metrics.KafkaConsumerGroupLag.Name():        Metrics.KafkaConsumerGroupLag.Init,
 		Metrics.KafkaConsumerGroupLagSum.Name():     Metrics.KafkaConsumerGroupLagSum.Init,
+		Metrics.KafkaConsumerGroupLagTime.Name():    Metrics.KafkaConsumerGroupLagTime.Init,
+		Metrics.KafkaConsumerGroupLagTimeMax.Name(): Metrics.KafkaConsumerGroupLagTimeMax.Init,
 		Metrics.KafkaConsumerGroupMembers.Name():    Metrics.KafkaConsumerGroupMembers.Init,
 		Metrics.KafkaConsumerGroupOffset.Name():     Metrics.KafkaConsumerGroupOffset.Init,
 		Metrics.KafkaConsumerGroupOffsetSum.Name():  Metrics.KafkaConsumerGroupOffsetSum.Init,
@@ -149,6 +157,24 @@ var Metrics = &metricStruct{
 			metric.SetDataType(pdata.MetricDataTypeIntGauge)
 		},
 	},
+	&metricImpl{
+		"kafka.consumer_group.lag_time",
+		func(metric pdata.Metric) {
+			metric.SetName("kafka.consumer_group.lag_time")
+			metric.SetDescription("Estimated time since the message at the consumer group offset was produced at partition of topic")
+			metric.SetUnit("s")
+			metric.SetDataType(pdata.MetricDataTypeDoubleGauge)
+		},
+	},
+	&metricImpl{
+		"kafka.consumer_group.lag_time_max",
+		func(metric pdata.Metric) {
+			metric.SetName("kafka.consumer_group.lag_time_max")
+			metric.SetDescription("Maximum estimated consumer group time lag across all partitions of topic")
+			metric.SetUnit("s")
+			metric.SetDataType(pdata.MetricDataTypeDoubleGauge)
+		},
+	},
 	&metricImpl{
 		"kafka.consumer_group.members",
 		func(metric pdata.Metric) {
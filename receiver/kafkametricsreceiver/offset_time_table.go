@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkametricsreceiver
+
+import (
+	"time"
+)
+
+// offsetTimeSample is a partition high-watermark offset and the times it was first and last observed.
+// The messages from offset on were produced after lastSeen, the ones before it before time.
+type offsetTimeSample struct {
+	offset   int64
+	time     time.Time
+	lastSeen time.Time
+}
+
+// offsetTimeTable keeps a bounded history of sampled partition high-watermark offsets,
+// ordered by ascending offset and time, used to estimate when a given offset was produced.
+type offsetTimeTable struct {
+	maxSamples int
+	samples    []offsetTimeSample
+}
+
+func newOffsetTimeTable(maxSamples int) *offsetTimeTable {
+	return &offsetTimeTable{maxSamples: maxSamples}
+}
+
+// add records a high-watermark offset observed at ts. An unchanged offset only updates the
+// time it was last observed, and an offset lower than the latest sample (e.g. a recreated
+// topic) resets the table.
+func (t *offsetTimeTable) add(offset int64, ts time.Time) {
+	if n := len(t.samples); n > 0 {
+		last := &t.samples[n-1]
+		if offset == last.offset && !ts.Before(last.lastSeen) {
+			last.lastSeen = ts
+			return
+		}
+		if offset < last.offset || ts.Before(last.lastSeen) {
+			t.samples = t.samples[:0]
+		}
+	}
+	t.samples = append(t.samples, offsetTimeSample{offset: offset, time: ts, lastSeen: ts})
+	if t.maxSamples > 0 && len(t.samples) > t.maxSamples {
+		t.samples = t.samples[len(t.samples)-t.maxSamples:]
+	}
+}
+
+// lag estimates how long ago, relative to now, the message at the provided consumer offset
+// was produced. Offsets between two samples are linearly interpolated between the last time
+// the lower offset was seen and the first time the upper offset was seen, i.e. over the
+// interval in which the high watermark actually moved. Offsets older than the table are
+// extrapolated from the table's average production rate. The returned bool is false when
+// there are not enough samples to produce an estimate.
+func (t *offsetTimeTable) lag(offset int64, now time.Time) (time.Duration, bool) {
+	n := len(t.samples)
+	if n == 0 {
+		return 0, false
+	}
+	if offset >= t.samples[n-1].offset {
+		return 0, true
+	}
+	if n < 2 {
+		return 0, false
+	}
+
+	var produced time.Time
+	if first, last := t.samples[0], t.samples[n-1]; offset < first.offset {
+		rate := float64(last.time.Sub(first.time)) / float64(last.offset-first.offset)
+		produced = first.time.Add(-time.Duration(float64(first.offset-offset) * rate))
+	} else {
+		for i := 1; i < n; i++ {
+			if offset < t.samples[i].offset {
+				lower, upper := t.samples[i-1], t.samples[i]
+				rate := float64(upper.time.Sub(lower.lastSeen)) / float64(upper.offset-lower.offset)
+				produced = lower.lastSeen.Add(time.Duration(float64(offset-lower.offset) * rate))
+				break
+			}
+		}
+	}
+	if lag := now.Sub(produced); lag > 0 {
+		return lag, true
+	}
+	return 0, true
+}
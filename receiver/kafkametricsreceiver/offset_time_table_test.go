@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkametricsreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOffsetTimeTable_add(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	table := newOffsetTimeTable(3)
+
+	table.add(10, start)
+	// unchanged offsets keep their first observation time and update the last one
+	table.add(10, start.Add(time.Second))
+	assert.Equal(t, []offsetTimeSample{{10, start, start.Add(time.Second)}}, table.samples)
+
+	table.add(20, start.Add(2*time.Second))
+	table.add(30, start.Add(3*time.Second))
+	table.add(40, start.Add(4*time.Second))
+	assert.Equal(t, []offsetTimeSample{
+		{20, start.Add(2 * time.Second), start.Add(2 * time.Second)},
+		{30, start.Add(3 * time.Second), start.Add(3 * time.Second)},
+		{40, start.Add(4 * time.Second), start.Add(4 * time.Second)},
+	}, table.samples)
+
+	// a lower offset resets the table
+	table.add(5, start.Add(5*time.Second))
+	assert.Equal(t, []offsetTimeSample{{5, start.Add(5 * time.Second), start.Add(5 * time.Second)}}, table.samples)
+}
+
+func TestOffsetTimeTable_lag(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	now := start.Add(100 * time.Second)
+
+	table := newOffsetTimeTable(10)
+	_, ok := table.lag(10, now)
+	assert.False(t, ok)
+
+	table.add(100, start)
+	lag, ok := table.lag(100, now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), lag)
+	_, ok = table.lag(50, now)
+	assert.False(t, ok)
+
+	table.add(200, start.Add(10*time.Second))
+	table.add(400, start.Add(50*time.Second))
+
+	tests := []struct {
+		name   string
+		offset int64
+		lag    time.Duration
+	}{
+		{name: "caught up", offset: 400, lag: 0},
+		{name: "ahead of samples", offset: 500, lag: 0},
+		{name: "on sample", offset: 200, lag: 90 * time.Second},
+		{name: "interpolated", offset: 300, lag: 70 * time.Second},
+		{name: "extrapolated", offset: 40, lag: 110 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lag, ok := table.lag(tt.offset, now)
+			assert.True(t, ok)
+			assert.Equal(t, tt.lag, lag)
+		})
+	}
+
+	t.Run("quiet topic", func(t *testing.T) {
+		table := newOffsetTimeTable(100)
+		// The high watermark stays at 100 for an hour of one minute scrapes, then moves to 101.
+		for i := 0; i < 60; i++ {
+			table.add(100, start.Add(time.Duration(i)*time.Minute))
+		}
+		table.add(101, start.Add(time.Hour))
+		require.Len(t, table.samples, 2)
+
+		// Message 100 was produced during the last scrape interval, not an hour ago.
+		now := start.Add(time.Hour)
+		lag, ok := table.lag(100, now)
+		assert.True(t, ok)
+		assert.Equal(t, time.Minute, lag)
+
+		lag, ok = table.lag(101, now)
+		assert.True(t, ok)
+		assert.Equal(t, time.Duration(0), lag)
+	})
+}
@@ -59,6 +59,11 @@ type metricStruct struct {
 	MemcachedCurrentConnections MetricIntf
 	MemcachedGetHits            MetricIntf
 	MemcachedGetMisses          MetricIntf
+	MemcachedSlabChunkSize      MetricIntf
+	MemcachedSlabChunksUsed     MetricIntf
+	MemcachedSlabItemsCurrent   MetricIntf
+	MemcachedSlabItemsEvicted   MetricIntf
+	MemcachedSlabItemsOldestAge MetricIntf
 	MemcachedTotalConnections   MetricIntf
 }
 
@@ -69,16 +74,26 @@ func (m *metricStruct) Names() []string {
 		"memcached.current_connections",
 		"memcached.get_hits",
 		"memcached.get_misses",
+		"memcached.slab.chunk_size",
+		"memcached.slab.chunks.used",
+		"memcached.slab.items.current",
+		"memcached.slab.items.evicted",
+		"memcached.slab.items.oldest_age",
 		"memcached.total_connections",
 	}
 }
 
 var metricsByName = map[string]MetricIntf{
-	"memcached.bytes":               Metrics.MemcachedBytes,
-	"memcached.current_connections": Metrics.MemcachedCurrentConnections,
-	"memcached.get_hits":            Metrics.MemcachedGetHits,
-	"memcached.get_misses":          Metrics.MemcachedGetMisses,
-	"memcached.total_connections":   Metrics.MemcachedTotalConnections,
+	"memcached.bytes":                 Metrics.MemcachedBytes,
+	"memcached.current_connections":   Metrics.MemcachedCurrentConnections,
+	"memcached.get_hits":              Metrics.MemcachedGetHits,
+	"memcached.get_misses":            Metrics.MemcachedGetMisses,
+	"memcached.slab.chunk_size":       Metrics.MemcachedSlabChunkSize,
+	"memcached.slab.chunks.used":      Metrics.MemcachedSlabChunksUsed,
+	"memcached.slab.items.current":    Metrics.MemcachedSlabItemsCurrent,
+	"memcached.slab.items.evicted":    Metrics.MemcachedSlabItemsEvicted,
+	"memcached.slab.items.oldest_age": Metrics.MemcachedSlabItemsOldestAge,
+	"memcached.total_connections":     Metrics.MemcachedTotalConnections,
 }
 
 func (m *metricStruct) ByName(n string) MetricIntf {
@@ -91,6 +106,11 @@ func (m *metricStruct) FactoriesByName() map[string]func(pdata.Metric) {
 		Metrics.MemcachedCurrentConnections.Name(): Metrics.MemcachedCurrentConnections.Init,
 		Metrics.MemcachedGetHits.Name():            Metrics.MemcachedGetHits.Init,
 		Metrics.MemcachedGetMisses.Name():          Metrics.MemcachedGetMisses.Init,
+		Metrics.MemcachedSlabChunkSize.Name():      Metrics.MemcachedSlabChunkSize.Init,
+		Metrics.MemcachedSlabChunksUsed.Name():     Metrics.MemcachedSlabChunksUsed.Init,
+		Metrics.MemcachedSlabItemsCurrent.Name():   Metrics.MemcachedSlabItemsCurrent.Init,
+		Metrics.MemcachedSlabItemsEvicted.Name():   Metrics.MemcachedSlabItemsEvicted.Init,
+		Metrics.MemcachedSlabItemsOldestAge.Name(): Metrics.MemcachedSlabItemsOldestAge.Init,
 		Metrics.MemcachedTotalConnections.Name():   Metrics.MemcachedTotalConnections.Init,
 	}
 }
@@ -138,6 +158,53 @@ var Metrics = &metricStruct{
 			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
 		},
 	},
+	&metricImpl{
+		"memcached.slab.chunk_size",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.slab.chunk_size")
+			metric.SetDescription("Size of the chunks allocated in the slab class")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"memcached.slab.chunks.used",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.slab.chunks.used")
+			metric.SetDescription("Number of chunks allocated to items in the slab class")
+			metric.SetUnit("chunks")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"memcached.slab.items.current",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.slab.items.current")
+			metric.SetDescription("Number of items presently stored in the slab class")
+			metric.SetUnit("items")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"memcached.slab.items.evicted",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.slab.items.evicted")
+			metric.SetDescription("Number of items evicted from the slab class that had to be removed from the LRU before expiring")
+			metric.SetUnit("items")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"memcached.slab.items.oldest_age",
+		func(metric pdata.Metric) {
+			metric.SetName("memcached.slab.items.oldest_age")
+			metric.SetDescription("Age of the oldest item in the slab class")
+			metric.SetUnit("s")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
 	&metricImpl{
 		"memcached.total_connections",
 		func(metric pdata.Metric) {
@@ -157,7 +224,11 @@ var M = Metrics
 
 // Labels contains the possible metric labels that can be used.
 var Labels = struct {
-}{}
+	// Slab (Slab class ID.)
+	Slab string
+}{
+	"slab",
+}
 
 // L contains the possible metric labels that can be used. L is an alias for
 // Labels.
@@ -16,6 +16,7 @@ package memcachedreceiver
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/grobie/gomemcache/memcache"
@@ -87,6 +88,32 @@ func (r *memcachedScraper) scrape(_ context.Context) (pdata.ResourceMetricsSlice
 				metrics.AddSumDataPoint(metadata.M.MemcachedGetMisses.Name(), parseInt(v))
 			}
 		}
+
+		// Per slab class stats from "stats slabs" and "stats items".
+		for id, slab := range stats.Slabs {
+			slabMetrics := metrics.WithLabels(map[string]string{metadata.L.Slab: strconv.Itoa(id)})
+			for k, v := range slab {
+				switch k {
+				case "chunk_size":
+					slabMetrics.AddGaugeDataPoint(metadata.M.MemcachedSlabChunkSize.Name(), parseInt(v))
+				case "used_chunks":
+					slabMetrics.AddGaugeDataPoint(metadata.M.MemcachedSlabChunksUsed.Name(), parseInt(v))
+				}
+			}
+		}
+		for id, items := range stats.Items {
+			itemMetrics := metrics.WithLabels(map[string]string{metadata.L.Slab: strconv.Itoa(id)})
+			for k, v := range items {
+				switch k {
+				case "number":
+					itemMetrics.AddGaugeDataPoint(metadata.M.MemcachedSlabItemsCurrent.Name(), parseInt(v))
+				case "evicted":
+					itemMetrics.AddSumDataPoint(metadata.M.MemcachedSlabItemsEvicted.Name(), parseInt(v))
+				case "age":
+					itemMetrics.AddGaugeDataPoint(metadata.M.MemcachedSlabItemsOldestAge.Name(), parseInt(v))
+				}
+			}
+		}
 	}
 
 	return metrics.Metrics.ResourceMetrics(), nil
@@ -0,0 +1,140 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memcachedreceiver
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/memcachedreceiver/internal/metadata"
+)
+
+// fakeMemcached is a local TCP stand-in answering the stats commands with the
+// responses in testdata.
+type fakeMemcached struct {
+	listener  net.Listener
+	responses map[string][]byte
+}
+
+func newFakeMemcached(t *testing.T) *fakeMemcached {
+	responses := map[string][]byte{}
+	for cmd, file := range map[string]string{
+		"stats":       "stats.txt",
+		"stats slabs": "stats_slabs.txt",
+		"stats items": "stats_items.txt",
+	} {
+		b, err := ioutil.ReadFile(filepath.Join("testdata", file))
+		require.NoError(t, err)
+		responses[cmd] = b
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	m := &fakeMemcached{listener: l, responses: responses}
+	go m.serve()
+	t.Cleanup(func() { l.Close() })
+	return m
+}
+
+func (m *fakeMemcached) serve() {
+	for {
+		conn, err := m.listener.Accept()
+		if err != nil {
+			return
+		}
+		go m.handle(conn)
+	}
+}
+
+func (m *fakeMemcached) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		resp, ok := m.responses[strings.TrimSpace(line)]
+		if !ok {
+			resp = []byte("ERROR\r\n")
+		}
+		if _, err := conn.Write(resp); err != nil {
+			return
+		}
+	}
+}
+
+func TestScraper(t *testing.T) {
+	server := newFakeMemcached(t)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.listener.Addr().String()
+	ms := &memcachedScraper{logger: zap.NewNop(), config: cfg}
+
+	rms, err := ms.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, rms.Len())
+	metrics := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, len(metadata.M.Names()), metrics.Len())
+
+	byName := map[string]pdata.Metric{}
+	for i := 0; i < metrics.Len(); i++ {
+		byName[metrics.At(i).Name()] = metrics.At(i)
+	}
+
+	assert.Equal(t, int64(2105), byName["memcached.bytes"].IntGauge().DataPoints().At(0).Value())
+
+	assertSlabValues(t, map[string]int64{"1": 96, "5": 240}, byName["memcached.slab.chunk_size"].IntGauge().DataPoints())
+	assertSlabValues(t, map[string]int64{"1": 20, "5": 3}, byName["memcached.slab.chunks.used"].IntGauge().DataPoints())
+	assertSlabValues(t, map[string]int64{"1": 20, "5": 3}, byName["memcached.slab.items.current"].IntGauge().DataPoints())
+	assertSlabValues(t, map[string]int64{"1": 7, "5": 0}, byName["memcached.slab.items.evicted"].IntSum().DataPoints())
+	assertSlabValues(t, map[string]int64{"1": 1230, "5": 45}, byName["memcached.slab.items.oldest_age"].IntGauge().DataPoints())
+}
+
+func assertSlabValues(t *testing.T, expected map[string]int64, dps pdata.IntDataPointSlice) {
+	actual := map[string]int64{}
+	for i := 0; i < dps.Len(); i++ {
+		slab, ok := dps.At(i).LabelsMap().Get(metadata.L.Slab)
+		require.True(t, ok)
+		actual[slab] = dps.At(i).Value()
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestScraperError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	endpoint := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = endpoint
+	cfg.Timeout = time.Second
+	ms := &memcachedScraper{logger: zap.NewNop(), config: cfg}
+
+	_, err = ms.scrape(context.Background())
+	require.Error(t, err)
+}
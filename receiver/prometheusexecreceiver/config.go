@@ -30,6 +30,20 @@ type Config struct {
 	ScrapeInterval time.Duration `mapstructure:"scrape_interval,omitempty"`
 	// Port is the port assigned to the Receiver, and to the {{port}} template variables
 	Port int `mapstructure:"port"`
+	// PortRange is the range of ports a port is allocated from when Port isn't set, a random free port is allocated if unset
+	PortRange PortRange `mapstructure:"port_range"`
+	// MetricsPath is the path of the endpoint the exporter serves its metrics at
+	MetricsPath string `mapstructure:"metrics_path"`
+	// ReadinessTimeout is how long to wait for the exporter to serve its metrics before scraping it anyway, 0 disables the readiness probe
+	ReadinessTimeout time.Duration `mapstructure:"readiness_timeout"`
 	// SubprocessConfig is the configuration needed for the subprocess
 	SubprocessConfig subprocessmanager.SubprocessConfig `mapstructure:",squash"`
 }
+
+// PortRange is the config definition of an inclusive range of ports
+type PortRange struct {
+	// Start is the first port of the range
+	Start int `mapstructure:"start"`
+	// End is the last port of the range
+	End int `mapstructure:"end"`
+}
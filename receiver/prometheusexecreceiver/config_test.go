@@ -31,6 +31,8 @@ var (
 	wantReceiver2 = &Config{
 		ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName(typeStr, "test")),
 		ScrapeInterval:   60 * time.Second,
+		MetricsPath:      "/metrics",
+		ReadinessTimeout: 30 * time.Second,
 		Port:             9104,
 		SubprocessConfig: subprocessmanager.SubprocessConfig{
 			Command: "mysqld_exporter",
@@ -41,6 +43,8 @@ var (
 	wantReceiver3 = &Config{
 		ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName(typeStr, "test2")),
 		ScrapeInterval:   90 * time.Second,
+		MetricsPath:      "/metrics",
+		ReadinessTimeout: 30 * time.Second,
 		SubprocessConfig: subprocessmanager.SubprocessConfig{
 			Command: "postgres_exporter",
 			Env:     []subprocessmanager.EnvConfig{},
@@ -50,6 +54,8 @@ var (
 	wantReceiver4 = &Config{
 		ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName(typeStr, "end_to_end_test/1")),
 		ScrapeInterval:   100 * time.Millisecond,
+		MetricsPath:      "/metrics",
+		ReadinessTimeout: 30 * time.Second,
 		Port:             9999,
 		SubprocessConfig: subprocessmanager.SubprocessConfig{
 			Command: "go run ./testdata/end_to_end_metrics_test/test_prometheus_exporter.go {{port}}",
@@ -69,11 +75,29 @@ var (
 	wantReceiver5 = &Config{
 		ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName(typeStr, "end_to_end_test/2")),
 		ScrapeInterval:   100 * time.Millisecond,
+		MetricsPath:      "/metrics",
+		ReadinessTimeout: 30 * time.Second,
 		SubprocessConfig: subprocessmanager.SubprocessConfig{
 			Command: "go run ./testdata/end_to_end_metrics_test/test_prometheus_exporter.go {{port}}",
 			Env:     []subprocessmanager.EnvConfig{},
 		},
 	}
+
+	wantReceiver6 = &Config{
+		ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName(typeStr, "templated")),
+		ScrapeInterval:   60 * time.Second,
+		PortRange: PortRange{
+			Start: 9100,
+			End:   9199,
+		},
+		MetricsPath:      "/probe",
+		ReadinessTimeout: 10 * time.Second,
+		SubprocessConfig: subprocessmanager.SubprocessConfig{
+			Command: "redis_exporter",
+			Args:    []string{"--web.listen-address=:{{port}}", "--redis.addr=redis://localhost:6379"},
+			Env:     []subprocessmanager.EnvConfig{},
+		},
+	}
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -87,7 +111,7 @@ func TestLoadConfig(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 5)
+	assert.Equal(t, len(cfg.Receivers), 6)
 
 	receiver1 := cfg.Receivers[config.NewID(typeStr)]
 	assert.Equal(t, factory.CreateDefaultConfig(), receiver1)
@@ -103,4 +127,7 @@ func TestLoadConfig(t *testing.T) {
 
 	receiver5 := cfg.Receivers[config.NewIDWithName(typeStr, "end_to_end_test/2")]
 	assert.Equal(t, wantReceiver5, receiver5)
+
+	receiver6 := cfg.Receivers[config.NewIDWithName(typeStr, "templated")]
+	assert.Equal(t, wantReceiver6, receiver6)
 }
@@ -32,6 +32,7 @@ const (
 	typeStr = "prometheus_exec"
 
 	defaultCollectionInterval = 60 * time.Second
+	defaultReadinessTimeout   = 30 * time.Second
 )
 
 // NewFactory creates a factory for the prometheusexec receiver
@@ -47,6 +48,8 @@ func createDefaultConfig() config.Receiver {
 	return &Config{
 		ReceiverSettings: config.NewReceiverSettings(config.NewID(typeStr)),
 		ScrapeInterval:   defaultCollectionInterval,
+		MetricsPath:      defaultMetricsPath,
+		ReadinessTimeout: defaultReadinessTimeout,
 		SubprocessConfig: subprocessmanager.SubprocessConfig{
 			Env: []subprocessmanager.EnvConfig{},
 		},
@@ -0,0 +1,122 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusexecreceiver
+
+import (
+	"fmt"
+	"net"
+	"sync"
+)
+
+// maxRandomPortAttempts is the amount of random free ports tried before giving up on finding one not allocated to another exporter
+const maxRandomPortAttempts = 10
+
+// exporterPorts is the pool of the ports allocated to the exporters of all prometheus_exec receivers, so that they never share a port
+var exporterPorts = newPortPool()
+
+// portPool keeps track of allocated ports
+type portPool struct {
+	lock      sync.Mutex
+	allocated map[int]struct{}
+	// next is the port of each range to start looking for a free port from, so that a released port isn't reused right away
+	next map[PortRange]int
+}
+
+// newPortPool returns an empty portPool
+func newPortPool() *portPool {
+	return &portPool{
+		allocated: map[int]struct{}{},
+		next:      map[PortRange]int{},
+	}
+}
+
+// reserve allocates the given port, failing if it is already allocated
+func (pp *portPool) reserve(port int) error {
+	pp.lock.Lock()
+	defer pp.lock.Unlock()
+
+	if _, ok := pp.allocated[port]; ok {
+		return fmt.Errorf("port %d is already allocated to another prometheus_exec receiver", port)
+	}
+	pp.allocated[port] = struct{}{}
+	return nil
+}
+
+// allocate allocates the next free port of portRange, or a random free port if portRange is unset
+func (pp *portPool) allocate(portRange PortRange) (int, error) {
+	pp.lock.Lock()
+	defer pp.lock.Unlock()
+
+	if portRange.Start == 0 && portRange.End == 0 {
+		for i := 0; i < maxRandomPortAttempts; i++ {
+			port, err := generateRandomPort()
+			if err != nil {
+				return 0, err
+			}
+			if _, ok := pp.allocated[port]; !ok {
+				pp.allocated[port] = struct{}{}
+				return port, nil
+			}
+		}
+		return 0, fmt.Errorf("no free port found after %d attempts", maxRandomPortAttempts)
+	}
+
+	size := portRange.End - portRange.Start + 1
+	first := pp.next[portRange]
+	if first < portRange.Start || first > portRange.End {
+		first = portRange.Start
+	}
+	for i := 0; i < size; i++ {
+		port := portRange.Start + (first-portRange.Start+i)%size
+		if _, ok := pp.allocated[port]; ok {
+			continue
+		}
+		if !isPortFree(port) {
+			continue
+		}
+		pp.allocated[port] = struct{}{}
+		pp.next[portRange] = port + 1
+		return port, nil
+	}
+	return 0, fmt.Errorf("no free port in range %d-%d", portRange.Start, portRange.End)
+}
+
+// release frees the given port for another allocation
+func (pp *portPool) release(port int) {
+	pp.lock.Lock()
+	defer pp.lock.Unlock()
+
+	delete(pp.allocated, port)
+}
+
+// generateRandomPort will generate a random available port
+func generateRandomPort() (int, error) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// isPortFree returns whether nothing listens on the given port
+func isPortFree(port int) bool {
+	listener, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
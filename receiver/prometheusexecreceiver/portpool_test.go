@@ -0,0 +1,90 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusexecreceiver
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortPoolReserve(t *testing.T) {
+	pp := newPortPool()
+
+	require.NoError(t, pp.reserve(10500))
+	assert.EqualError(t, pp.reserve(10500), "port 10500 is already allocated to another prometheus_exec receiver")
+
+	pp.release(10500)
+	assert.NoError(t, pp.reserve(10500))
+}
+
+func TestPortPoolAllocateRandom(t *testing.T) {
+	pp := newPortPool()
+
+	ports := map[int]struct{}{}
+	for i := 0; i < 5; i++ {
+		port, err := pp.allocate(PortRange{})
+		require.NoError(t, err)
+		assert.NotContains(t, ports, port)
+		ports[port] = struct{}{}
+	}
+}
+
+func TestPortPoolAllocateRange(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	busyPort := listener.Addr().(*net.TCPAddr).Port
+
+	// The range starts with a port already listened on
+	portRange := PortRange{Start: busyPort, End: busyPort + 2}
+	pp := newPortPool()
+
+	first, err := pp.allocate(portRange)
+	require.NoError(t, err)
+	assert.Equal(t, busyPort+1, first)
+
+	second, err := pp.allocate(portRange)
+	require.NoError(t, err)
+	assert.Equal(t, busyPort+2, second)
+
+	_, err = pp.allocate(portRange)
+	assert.EqualError(t, err, fmt.Sprintf("no free port in range %d-%d", busyPort, busyPort+2))
+
+	// Released ports are allocated again once the range is exhausted
+	pp.release(first)
+	port, err := pp.allocate(portRange)
+	require.NoError(t, err)
+	assert.Equal(t, first, port)
+}
+
+func TestPortPoolAllocateRangeRoundRobin(t *testing.T) {
+	port, err := generateRandomPort()
+	require.NoError(t, err)
+	portRange := PortRange{Start: port, End: port + 1}
+	pp := newPortPool()
+
+	first, err := pp.allocate(portRange)
+	require.NoError(t, err)
+	pp.release(first)
+
+	// A released port isn't reused right away
+	second, err := pp.allocate(portRange)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+}
@@ -17,9 +17,9 @@ package prometheusexecreceiver
 import (
 	"context"
 	"fmt"
-	"math"
-	"math/rand"
-	"net"
+	"io"
+	"io/ioutil"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -30,27 +30,25 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/receiver/prometheusreceiver"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/subprocess"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusexecreceiver/subprocessmanager"
 )
 
 const (
 	// template for port in strings
 	portTemplate string = "{{port}}"
-	// healthyProcessTime is the default time a process needs to stay alive to be considered healthy
-	healthyProcessTime = 30 * time.Minute
-	// healthyCrashCount is the amount of times a process can crash (within the healthyProcessTime) before being considered unstable - it may be trying to find a port
-	healthyCrashCount int = 3
-	// delayMultiplier is the factor by which the delay scales
-	delayMultiplier float64 = 2.0
-	// initialDelay is the initial delay before a process is restarted
+	// initialDelay is the initial delay before a process is restarted, it is doubled on each consecutive crash
 	initialDelay = 1 * time.Second
 	// default path to scrape metrics at endpoint
 	defaultMetricsPath = "/metrics"
 	// default timeout for a scrape
 	defaultScrapeTimeout = 10 * time.Second
+	// readinessProbeInterval is the time between each request of the readiness probe
+	readinessProbeInterval = 250 * time.Millisecond
 )
 
 type prometheusExecReceiver struct {
@@ -68,13 +66,16 @@ type prometheusExecReceiver struct {
 	// Underlying receiver data
 	prometheusReceiver component.MetricsReceiver
 
-	// Shutdown channel
-	shutdownCh chan struct{}
-}
+	// Supervisor of the subprocess, started with the receiver
+	subprocess *subprocess.Subprocess
+	host       component.Host
 
-type runResult struct {
-	elapsed       time.Duration
-	subprocessErr error
+	// Data of the current run of the subprocess
+	currentPort int
+	cancelRun   context.CancelFunc
+	runDone     chan struct{}
+	// receiverStarted is only accessed by the readiness goroutine until runDone is closed
+	receiverStarted bool
 }
 
 // newPromExecReceiver returns a prometheusExecReceiver
@@ -82,6 +83,9 @@ func newPromExecReceiver(params component.ReceiverCreateParams, config *Config,
 	if config.SubprocessConfig.Command == "" {
 		return nil, fmt.Errorf("no command to execute entered in config file for %v", config.ID())
 	}
+	if err := validatePortRange(config.PortRange); err != nil {
+		return nil, fmt.Errorf("invalid port_range in config file for %v: %w", config.ID(), err)
+	}
 	subprocessConfig := getSubprocessConfig(config)
 	promReceiverConfig := getPromReceiverConfig(config)
 
@@ -95,6 +99,17 @@ func newPromExecReceiver(params component.ReceiverCreateParams, config *Config,
 	}, nil
 }
 
+// validatePortRange returns an error if portRange is partially set or isn't a valid range of ports
+func validatePortRange(portRange PortRange) error {
+	if portRange.Start == 0 && portRange.End == 0 {
+		return nil
+	}
+	if portRange.Start <= 0 || portRange.End > 65535 || portRange.Start > portRange.End {
+		return fmt.Errorf("%d-%d is not a valid range of ports", portRange.Start, portRange.End)
+	}
+	return nil
+}
+
 // getPromReceiverConfig returns the Prometheus receiver config
 func getPromReceiverConfig(cfg *Config) *prometheusreceiver.Config {
 	scrapeConfig := &promconfig.ScrapeConfig{}
@@ -102,7 +117,10 @@ func getPromReceiverConfig(cfg *Config) *prometheusreceiver.Config {
 	scrapeConfig.ScrapeInterval = model.Duration(cfg.ScrapeInterval)
 	scrapeConfig.ScrapeTimeout = model.Duration(defaultScrapeTimeout)
 	scrapeConfig.Scheme = "http"
-	scrapeConfig.MetricsPath = defaultMetricsPath
+	scrapeConfig.MetricsPath = cfg.MetricsPath
+	if scrapeConfig.MetricsPath == "" {
+		scrapeConfig.MetricsPath = defaultMetricsPath
+	}
 	jobName := cfg.ID().Name()
 	if jobName == "" {
 		// Fallback to type if no name
@@ -136,138 +154,176 @@ func getSubprocessConfig(cfg *Config) *subprocessmanager.SubprocessConfig {
 	subprocessConfig := &subprocessmanager.SubprocessConfig{}
 
 	subprocessConfig.Command = cfg.SubprocessConfig.Command
+	subprocessConfig.Args = cfg.SubprocessConfig.Args
 	subprocessConfig.Env = cfg.SubprocessConfig.Env
 
 	return subprocessConfig
 }
 
-// Start creates the configs and calls the function that handles the prometheus_exec receiver
+// Start reserves the configured port and starts the subprocess, which creates a new underlying Prometheus receiver before each (re)start of the process
 func (per *prometheusExecReceiver) Start(ctx context.Context, host component.Host) error {
-	// shutdown channel
-	per.shutdownCh = make(chan struct{})
+	per.host = host
 
-	go per.manageProcess(context.Background(), host)
+	if per.port != 0 {
+		if err := exporterPorts.reserve(per.port); err != nil {
+			return err
+		}
+	}
 
-	return nil
-}
+	restartDelay := initialDelay
+	per.subprocess = subprocess.NewSubprocess(&subprocess.Config{
+		RestartOnError: true,
+		RestartDelay:   &restartDelay,
+		BeforeStart:    per.beforeStart,
+		AfterExit:      per.afterExit,
+	}, per.params.Logger)
 
-// manageProcess is an infinite loop that handles starting and restarting Prometheus-receiver/subprocess pairs
-func (per *prometheusExecReceiver) manageProcess(ctx context.Context, host component.Host) {
-	var crashCount int
+	return per.subprocess.Start(obsreport.ReceiverContext(context.Background(), per.config.ID(), ""))
+}
 
-	for {
+// beforeStart allocates a port if none was specified, creates the underlying Prometheus receiver to start once the exporter is ready,
+// then sets the command and environment of the subprocess with the port filled in
+func (per *prometheusExecReceiver) beforeStart(conf *subprocess.Config) error {
+	per.currentPort = per.port
 
-		receiver, err := per.createAndStartReceiver(ctx, host)
+	// Allocate a port if none was specified
+	if per.currentPort == 0 {
+		var err error
+		per.currentPort, err = exporterPorts.allocate(per.config.PortRange)
 		if err != nil {
-			per.params.Logger.Error("createReceiver() error", zap.String("error", err.Error()))
-			return
+			return fmt.Errorf("could not allocate a port - killing this single process/receiver: %w", err)
 		}
+	}
 
-		elapsed := per.runProcess(ctx)
+	receiver, err := per.createReceiver(context.Background())
+	if err == nil {
+		per.subprocessConfig = per.fillPortPlaceholders(per.currentPort)
+		err = per.subprocessConfig.Apply(conf)
+	}
+	if err != nil {
+		per.releasePort()
+		return err
+	}
+	per.prometheusReceiver = receiver
 
-		err = receiver.Shutdown(ctx)
-		if err != nil {
-			per.params.Logger.Error("could not stop receiver associated to process, killing it", zap.String("error", err.Error()))
-			return
-		}
+	runCtx, cancel := context.WithCancel(context.Background())
+	per.cancelRun = cancel
+	per.runDone = make(chan struct{})
+	go per.startReceiverWhenReady(runCtx)
 
-		crashCount = per.computeCrashCount(elapsed, crashCount)
-		per.computeDelayAndSleep(elapsed, crashCount)
+	return nil
+}
 
-		// Exit loop if shutdown was signaled
-		select {
-		case <-per.shutdownCh:
-			return
-		default:
-		}
+// afterExit stops the underlying Prometheus receiver once its subprocess exited and releases its port
+func (per *prometheusExecReceiver) afterExit() {
+	if per.cancelRun == nil {
+		return
 	}
-}
 
-// createAndStartReceiver will create the underlying Prometheus receiver and generate a random port if one is needed, then start it
-func (per *prometheusExecReceiver) createAndStartReceiver(ctx context.Context, host component.Host) (component.MetricsReceiver, error) {
-	currentPort := per.port
+	per.cancelRun()
+	<-per.runDone
+	per.cancelRun = nil
 
-	// Generate a port if none was specified
-	if currentPort == 0 {
-		var err error
-		currentPort, err = generateRandomPort()
-		if err != nil {
-			return nil, fmt.Errorf("generateRandomPort() error - killing this single process/receiver: %w", err)
+	if per.receiverStarted {
+		if err := per.prometheusReceiver.Shutdown(context.Background()); err != nil {
+			per.params.Logger.Error("could not stop receiver associated to process", zap.Error(err))
 		}
+	}
+	per.prometheusReceiver = nil
+	per.receiverStarted = false
+	per.releasePort()
+}
 
-		staticConfig := per.promReceiverConfig.PrometheusConfig.ScrapeConfigs[0].ServiceDiscoveryConfigs[0].(*discovery.StaticConfig)
-		(*staticConfig)[0].Targets = []model.LabelSet{
-			{model.AddressLabel: model.LabelValue(fmt.Sprintf("localhost:%v", currentPort))},
-		}
+// releasePort releases the port allocated for the current run of the subprocess
+func (per *prometheusExecReceiver) releasePort() {
+	if per.port == 0 && per.currentPort != 0 {
+		exporterPorts.release(per.currentPort)
+	}
+	per.currentPort = 0
+}
+
+// createReceiver will create the underlying Prometheus receiver scraping the current port
+func (per *prometheusExecReceiver) createReceiver(ctx context.Context) (component.MetricsReceiver, error) {
+	staticConfig := per.promReceiverConfig.PrometheusConfig.ScrapeConfigs[0].ServiceDiscoveryConfigs[0].(*discovery.StaticConfig)
+	(*staticConfig)[0].Targets = []model.LabelSet{
+		{model.AddressLabel: model.LabelValue(fmt.Sprintf("localhost:%v", per.currentPort))},
 	}
 
-	// Create and start the underlying Prometheus receiver
 	factory := prometheusreceiver.NewFactory()
 	receiver, err := factory.CreateMetricsReceiver(ctx, per.params, per.promReceiverConfig, per.consumer)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create Prometheus receiver - killing this single process/receiver: %w", err)
 	}
-
-	per.subprocessConfig = per.fillPortPlaceholders(currentPort)
-
-	err = receiver.Start(ctx, host)
-	if err != nil {
-		return nil, fmt.Errorf("could not start receiver - killing this single process/receiver: %w", err)
-	}
-
 	return receiver, nil
 }
 
-// runProcess will run the process and return runtime, or handle a shutdown if one is triggered while the subprocess is running
-func (per *prometheusExecReceiver) runProcess(ctx context.Context) time.Duration {
-	childCtx, cancel := context.WithCancel(ctx)
-	run := make(chan runResult, 1)
-
-	go per.handleProcessResult(childCtx, run)
-
-	select {
-	case result := <-run:
-		// Log the error from the subprocess without returning it since we want to restart the process if it exited
-		if result.subprocessErr != nil {
-			per.params.Logger.Info("Subprocess error", zap.String("error", result.subprocessErr.Error()))
+// startReceiverWhenReady starts the underlying Prometheus receiver once the exporter serves its metrics, or after the readiness timeout
+func (per *prometheusExecReceiver) startReceiverWhenReady(ctx context.Context) {
+	defer close(per.runDone)
+
+	if per.config.ReadinessTimeout > 0 {
+		url := fmt.Sprintf("http://localhost:%d%s", per.currentPort, per.promReceiverConfig.PrometheusConfig.ScrapeConfigs[0].MetricsPath)
+		if err := waitUntilReady(ctx, url, per.config.ReadinessTimeout); err != nil {
+			if ctx.Err() != nil {
+				// The subprocess exited before being ready
+				return
+			}
+			per.params.Logger.Warn("exporter not ready, scraping it anyway", zap.String("url", url), zap.Error(err))
 		}
-		cancel()
-		return result.elapsed
+	}
 
-	case <-per.shutdownCh:
-		cancel()
-		return 0
+	if err := per.prometheusReceiver.Start(ctx, per.host); err != nil {
+		per.params.Logger.Error("could not start receiver associated to process", zap.Error(err))
+		return
 	}
+	per.receiverStarted = true
 }
 
-// handleProcessResult calls the process manager's run function and pipes the return value into the channel
-func (per *prometheusExecReceiver) handleProcessResult(childCtx context.Context, run chan<- runResult) {
-	elapsed, subprocessErr := per.subprocessConfig.Run(childCtx, per.params.Logger)
-	run <- runResult{elapsed, subprocessErr}
-}
+// waitUntilReady polls url until it is served successfully, the timeout elapses or ctx is done
+func waitUntilReady(ctx context.Context, url string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-// computeDelayAndSleep will compute how long the process should delay before restarting and handle a shutdown while this goroutine waits
-func (per *prometheusExecReceiver) computeDelayAndSleep(elapsed time.Duration, crashCount int) {
-	sleepTime := getDelay(elapsed, healthyProcessTime, crashCount, healthyCrashCount)
-	per.params.Logger.Info("Subprocess start delay", zap.String("time until process restarts", sleepTime.String()))
+	client := &http.Client{Timeout: defaultScrapeTimeout}
+	ticker := time.NewTicker(readinessProbeInterval)
+	defer ticker.Stop()
 
-	select {
-	case <-time.After(sleepTime):
-		return
+	var lastErr error
+	for {
+		err := probe(ctx, client, url)
+		if err == nil {
+			return nil
+		}
+		// Keep the error of the last complete probe rather than the one interrupted by the timeout
+		if ctx.Err() == nil || lastErr == nil {
+			lastErr = err
+		}
 
-	case <-per.shutdownCh:
-		return
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("readiness probe failed after %v: %w", timeout, lastErr)
+		case <-ticker.C:
+		}
 	}
 }
 
-// computeCrashCount will compute crashCount according to runtime
-func (per *prometheusExecReceiver) computeCrashCount(elapsed time.Duration, crashCount int) int {
-	if elapsed > healthyProcessTime {
-		return 1
+// probe requests url once, returning an error unless it is served successfully
+func probe(ctx context.Context, client *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
 	}
-	crashCount++
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
 
-	return crashCount
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %v", resp.Status)
+	}
+	return nil
 }
 
 // fillPortPlaceholders will check if any of the strings in the process data have the {{port}} placeholder, and replace it if necessary
@@ -278,6 +334,11 @@ func (per *prometheusExecReceiver) fillPortPlaceholders(newPort int) *subprocess
 
 	newConfig.Command = strings.ReplaceAll(per.config.SubprocessConfig.Command, portTemplate, port)
 
+	newConfig.Args = make([]string, len(per.config.SubprocessConfig.Args))
+	for i, arg := range per.config.SubprocessConfig.Args {
+		newConfig.Args[i] = strings.ReplaceAll(arg, portTemplate, port)
+	}
+
 	for i, env := range per.config.SubprocessConfig.Env {
 		newConfig.Env[i].Value = strings.ReplaceAll(env.Value, portTemplate, port)
 	}
@@ -285,29 +346,14 @@ func (per *prometheusExecReceiver) fillPortPlaceholders(newPort int) *subprocess
 	return &newConfig
 }
 
-// generateRandomPort will generate a random available port
-func generateRandomPort() (int, error) {
-	listener, err := net.Listen("tcp", "localhost:0")
-	if err != nil {
-		return 0, err
+// Shutdown stops the subprocess and the underlying Prometheus receiver.
+func (per *prometheusExecReceiver) Shutdown(ctx context.Context) error {
+	if per.subprocess == nil {
+		return nil
 	}
-	defer listener.Close()
-	return listener.Addr().(*net.TCPAddr).Port, nil
-}
-
-// getDelay will compute the delay for a given process according to its crash count and time alive using an exponential backoff algorithm
-func getDelay(elapsed time.Duration, healthyProcessDuration time.Duration, crashCount int, healthyCrashCount int) time.Duration {
-	// Return the initialDelay if the process is healthy (lasted longer than health duration) or has less or equal the allowed amount of crashes
-	if elapsed > healthyProcessDuration || crashCount <= healthyCrashCount {
-		return initialDelay
+	err := per.subprocess.Shutdown(ctx)
+	if per.port != 0 {
+		exporterPorts.release(per.port)
 	}
-
-	// Return initialDelay times 2 to the power of crashCount-healthyCrashCount (to offset for the allowed crashes) added to a random number
-	return initialDelay * time.Duration(math.Pow(delayMultiplier, float64(crashCount-healthyCrashCount)+rand.Float64()))
-}
-
-// Shutdown stops the underlying Prometheus receiver.
-func (per *prometheusExecReceiver) Shutdown(ctx context.Context) error {
-	close(per.shutdownCh)
-	return nil
+	return err
 }
@@ -16,6 +16,9 @@ package prometheusexecreceiver
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"path"
 	"testing"
 	"time"
@@ -33,6 +36,7 @@ import (
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/receiver/prometheusreceiver"
 	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusexecreceiver/subprocessmanager"
@@ -304,6 +308,7 @@ func TestFillPortPlaceholders(t *testing.T) {
 				config: &Config{
 					SubprocessConfig: subprocessmanager.SubprocessConfig{
 						Command: "apache_exporter --port:{{port}}",
+						Args:    []string{"--scrape_uri=http://localhost:{{port}}/server-status", "--insecure"},
 						Env: []subprocessmanager.EnvConfig{
 							{
 								Name:  "DATA_SOURCE_NAME",
@@ -330,6 +335,7 @@ func TestFillPortPlaceholders(t *testing.T) {
 			newPort: 10500,
 			want: &subprocessmanager.SubprocessConfig{
 				Command: "apache_exporter --port:10500",
+				Args:    []string{"--scrape_uri=http://localhost:10500/server-status", "--insecure"},
 				Env: []subprocessmanager.EnvConfig{
 					{
 						Name:  "DATA_SOURCE_NAME",
@@ -374,6 +380,7 @@ func TestFillPortPlaceholders(t *testing.T) {
 			newPort: 0,
 			want: &subprocessmanager.SubprocessConfig{
 				Command: "apache_exporter",
+				Args:    []string{},
 				Env: []subprocessmanager.EnvConfig{
 					{
 						Name:  "DATA_SOURCE_NAME",
@@ -418,6 +425,7 @@ func TestFillPortPlaceholders(t *testing.T) {
 			newPort: 10111,
 			want: &subprocessmanager.SubprocessConfig{
 				Command: "apache_exporter --port=10111",
+				Args:    []string{},
 				Env: []subprocessmanager.EnvConfig{
 					{
 						Name:  "DATA_SOURCE_NAME",
@@ -436,100 +444,99 @@ func TestFillPortPlaceholders(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			got := test.wrapper.fillPortPlaceholders(test.newPort)
 			assert.Equal(t, test.want.Command, got.Command)
+			assert.Equal(t, test.want.Args, got.Args)
 			assert.Equal(t, test.want.Env, got.Env)
 		})
 	}
 }
 
-func TestGetDelayAndComputeCrashCount(t *testing.T) {
-	var (
-		getDelayAndComputeCrashCountTests = []struct {
-			name               string
-			elapsed            time.Duration
-			healthyProcessTime time.Duration
-			crashCount         int
-			healthyCrashCount  int
-			wantDelay          time.Duration
-			wantCrashCount     int
-		}{
-			{
-				name:               "healthy process 1",
-				elapsed:            15 * time.Minute,
-				healthyProcessTime: 30 * time.Minute,
-				crashCount:         2,
-				healthyCrashCount:  3,
-				wantDelay:          1 * time.Second,
-				wantCrashCount:     3,
-			},
-			{
-				name:               "healthy process 2",
-				elapsed:            15 * time.Hour,
-				healthyProcessTime: 20 * time.Minute,
-				crashCount:         6,
-				healthyCrashCount:  2,
-				wantDelay:          1 * time.Second,
-				wantCrashCount:     1,
-			},
-			{
-				name:               "unhealthy process 1",
-				elapsed:            15 * time.Second,
-				healthyProcessTime: 45 * time.Minute,
-				crashCount:         4,
-				healthyCrashCount:  3,
-				wantCrashCount:     5,
-			},
-			{
-				name:               "unhealthy process 2",
-				elapsed:            15 * time.Second,
-				healthyProcessTime: 75 * time.Second,
-				crashCount:         5,
-				healthyCrashCount:  3,
-				wantCrashCount:     6,
-			},
-			{
-				name:               "unhealthy process 3",
-				elapsed:            15 * time.Second,
-				healthyProcessTime: 30 * time.Minute,
-				crashCount:         6,
-				healthyCrashCount:  3,
-				wantCrashCount:     7,
-			},
-			{
-				name:               "unhealthy process 4",
-				elapsed:            15 * time.Second,
-				healthyProcessTime: 10 * time.Minute,
-				crashCount:         7,
-				healthyCrashCount:  3,
-				wantCrashCount:     8,
-			},
-		}
-		previousResult time.Duration
-	)
-
-	// getDelay() test
-	t.Run("GetDelay test", func(t *testing.T) {
-		for _, test := range getDelayAndComputeCrashCountTests {
-			got := getDelay(test.elapsed, test.healthyProcessTime, test.crashCount, test.healthyCrashCount)
+func TestValidatePortRange(t *testing.T) {
+	validatePortRangeTests := []struct {
+		name      string
+		portRange PortRange
+		wantErr   bool
+	}{
+		{
+			name:      "unset",
+			portRange: PortRange{},
+		},
+		{
+			name:      "valid range",
+			portRange: PortRange{Start: 9100, End: 9199},
+		},
+		{
+			name:      "single port",
+			portRange: PortRange{Start: 9100, End: 9100},
+		},
+		{
+			name:      "missing end",
+			portRange: PortRange{Start: 9100},
+			wantErr:   true,
+		},
+		{
+			name:      "missing start",
+			portRange: PortRange{End: 9100},
+			wantErr:   true,
+		},
+		{
+			name:      "reversed range",
+			portRange: PortRange{Start: 9199, End: 9100},
+			wantErr:   true,
+		},
+		{
+			name:      "out of range",
+			portRange: PortRange{Start: 65500, End: 65600},
+			wantErr:   true,
+		},
+	}
 
-			if test.wantDelay > 0 {
-				assert.Equalf(t, test.wantDelay, got, "getDelay() '%v', got = %v, want %v", test.name, got, test.wantDelay)
-				continue
+	for _, test := range validatePortRangeTests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validatePortRange(test.portRange)
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
 			}
+		})
+	}
 
-			if previousResult > got {
-				t.Errorf("getDelay() '%v', got = %v, want something larger than the previous result %v", test.name, got, previousResult)
-			}
-			previousResult = got
+	_, err := newPromExecReceiver(component.ReceiverCreateParams{Logger: zap.NewNop()}, &Config{
+		ReceiverSettings: config.NewReceiverSettings(config.NewID(typeStr)),
+		PortRange:        PortRange{Start: 9100},
+		SubprocessConfig: subprocessmanager.SubprocessConfig{Command: "mysqld_exporter"},
+	}, nil)
+	assert.EqualError(t, err, "invalid port_range in config file for prometheus_exec: 9100-0 is not a valid range of ports")
+}
+
+func TestWaitUntilReady(t *testing.T) {
+	var ready atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" || !ready.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
 		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Run("not ready", func(t *testing.T) {
+		err := waitUntilReady(context.Background(), server.URL+"/metrics", 3*readinessProbeInterval)
+		assert.EqualError(t, err, fmt.Sprintf("readiness probe failed after %v: unexpected status 503 Service Unavailable", 3*readinessProbeInterval))
 	})
 
-	// computeCrashCount() test
-	per := &prometheusExecReceiver{}
+	t.Run("ready after a while", func(t *testing.T) {
+		time.AfterFunc(2*readinessProbeInterval, func() { ready.Store(true) })
+		assert.NoError(t, waitUntilReady(context.Background(), server.URL+"/metrics", 10*time.Second))
+	})
 
-	for _, test := range getDelayAndComputeCrashCountTests {
-		t.Run(test.name, func(t *testing.T) {
-			got := per.computeCrashCount(test.elapsed, test.crashCount)
-			assert.Equal(t, test.wantCrashCount, got)
-		})
-	}
+	t.Run("wrong path", func(t *testing.T) {
+		assert.Error(t, waitUntilReady(context.Background(), server.URL+"/probe", readinessProbeInterval))
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.Error(t, waitUntilReady(ctx, server.URL+"/probe", 10*time.Second))
+	})
 }
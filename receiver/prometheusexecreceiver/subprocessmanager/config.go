@@ -18,6 +18,8 @@ package subprocessmanager
 type SubprocessConfig struct {
 	// Command is the command to be run (binary + flags, separated by commas)
 	Command string `mapstructure:"exec"`
+	// Args is a list of arguments appended to the command, which aren't split on spaces nor unquoted
+	Args []string `mapstructure:"args"`
 	// Env is a list of env variables to pass to a specific command
 	Env []EnvConfig `mapstructure:"env"`
 }
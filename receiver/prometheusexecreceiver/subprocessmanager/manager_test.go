@@ -15,43 +15,41 @@
 package subprocessmanager
 
 import (
-	"context"
 	"reflect"
 	"testing"
-	"time"
 
-	"go.uber.org/zap"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/subprocess"
 )
 
-func TestFormatEnvSlice(t *testing.T) {
-	var formatEnvSliceTests = []struct {
+func TestFormatEnvMap(t *testing.T) {
+	var formatEnvMapTests = []struct {
 		name     string
-		envSlice *[]EnvConfig
-		want     []string
+		envSlice []EnvConfig
+		want     map[string]string
 		wantNil  bool
 	}{
 		{
 			name:     "empty slice",
-			envSlice: &[]EnvConfig{},
+			envSlice: []EnvConfig{},
 			want:     nil,
 			wantNil:  true,
 		},
 		{
 			name: "one entry",
-			envSlice: &[]EnvConfig{
+			envSlice: []EnvConfig{
 				{
 					Name:  "DATA_SOURCE",
 					Value: "password:username",
 				},
 			},
-			want: []string{
-				"DATA_SOURCE=password:username",
+			want: map[string]string{
+				"DATA_SOURCE": "password:username",
 			},
 			wantNil: false,
 		},
 		{
 			name: "three entries",
-			envSlice: &[]EnvConfig{
+			envSlice: []EnvConfig{
 				{
 					Name:  "DATA_SOURCE",
 					Value: "password:username",
@@ -65,40 +63,40 @@ func TestFormatEnvSlice(t *testing.T) {
 					Value: "doe",
 				},
 			},
-			want: []string{
-				"DATA_SOURCE=password:username",
-				"=",
-				"john=doe",
+			want: map[string]string{
+				"DATA_SOURCE": "password:username",
+				"":            "",
+				"john":        "doe",
 			},
 			wantNil: false,
 		},
 	}
 
-	for _, test := range formatEnvSliceTests {
+	for _, test := range formatEnvMapTests {
 		t.Run(test.name, func(t *testing.T) {
-			got := formatEnvSlice(test.envSlice)
+			got := formatEnvMap(test.envSlice)
 			if test.wantNil && got != nil {
-				t.Errorf("formatEnvSlice() got = %v, wantNil %v", got, test.wantNil)
+				t.Errorf("formatEnvMap() got = %v, wantNil %v", got, test.wantNil)
 				return
 			}
 			if !reflect.DeepEqual(got, test.want) {
-				t.Errorf("formatEnvSlice() got = %v, want %v", got, test.want)
+				t.Errorf("formatEnvMap() got = %v, want %v", got, test.want)
 			}
 		})
 	}
 }
 
-func TestRun(t *testing.T) {
-	var runTests = []struct {
-		name        string
-		process     *SubprocessConfig
-		wantElapsed time.Duration
-		wantErr     bool
+func TestApply(t *testing.T) {
+	var applyTests = []struct {
+		name    string
+		process *SubprocessConfig
+		want    *subprocess.Config
+		wantErr bool
 	}{
 		{
-			name: "normal process 1, error process exit",
+			name: "command with flags and env",
 			process: &SubprocessConfig{
-				Command: "go run testdata/test_crasher.go",
+				Command: "mysqld_exporter --web.listen-address=':9104' --log.level=\"debug info\"",
 				Env: []EnvConfig{
 					{
 						Name:  "DATA_SOURCE",
@@ -106,22 +104,38 @@ func TestRun(t *testing.T) {
 					},
 				},
 			},
-			wantElapsed: 4 * time.Millisecond,
-			wantErr:     false,
+			want: &subprocess.Config{
+				ExecutablePath: "mysqld_exporter",
+				Args:           []string{"--web.listen-address=:9104", "--log.level=debug info"},
+				EnvironmentVariables: map[string]string{
+					"DATA_SOURCE": "username:password@(url:port)/dbname",
+				},
+			},
+			wantErr: false,
 		},
 		{
-			name: "normal process 2, normal process exit",
+			name: "command with args",
 			process: &SubprocessConfig{
-				Command: "go version",
-				Env: []EnvConfig{
-					{
-						Name:  "DATA_SOURCE",
-						Value: "username:password@(url:port)/dbname",
-					},
-				},
+				Command: "redis_exporter --debug",
+				Args:    []string{"--redis.addr=redis://my host:6379", "--redis.password='secret'"},
+			},
+			want: &subprocess.Config{
+				ExecutablePath: "redis_exporter",
+				Args:           []string{"--debug", "--redis.addr=redis://my host:6379", "--redis.password='secret'"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "command without flags",
+			process: &SubprocessConfig{
+				Command: "go",
+				Env:     []EnvConfig{},
 			},
-			wantElapsed: 0 * time.Nanosecond,
-			wantErr:     false,
+			want: &subprocess.Config{
+				ExecutablePath: "go",
+				Args:           []string{},
+			},
+			wantErr: false,
 		},
 		{
 			name: "shellquote error",
@@ -129,21 +143,33 @@ func TestRun(t *testing.T) {
 				Command: "command flag='something",
 				Env:     []EnvConfig{},
 			},
-			wantElapsed: 0,
-			wantErr:     true,
+			wantErr: true,
+		},
+		{
+			name: "empty command",
+			process: &SubprocessConfig{
+				Command: "  ",
+			},
+			wantErr: true,
 		},
 	}
 
-	for _, test := range runTests {
+	for _, test := range applyTests {
 		t.Run(test.name, func(t *testing.T) {
-			logger, _ := zap.NewProduction()
-			got, err := test.process.Run(context.Background(), logger)
-			if test.wantErr && err == nil {
-				t.Errorf("Run() got = %v, wantErr %v", got, test.wantErr)
+			got := &subprocess.Config{}
+			err := test.process.Apply(got)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("Apply() got = %v, wantErr %v", got, test.wantErr)
+				}
 				return
 			}
-			if got < test.wantElapsed {
-				t.Errorf("Run() got = %v, want larger than %v", got, test.wantElapsed)
+			if err != nil {
+				t.Errorf("Apply() unexpected error: %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Apply() got = %v, want %v", got, test.want)
 			}
 		})
 	}
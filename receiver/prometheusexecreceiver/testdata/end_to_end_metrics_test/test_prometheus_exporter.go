@@ -30,10 +30,13 @@ func main() {
 	server()
 }
 
-// server serves one route "./metrics" and will shutdown the server as soon as it is scraped once, to allow for the next subprocess to be run
+// server serves one route "./metrics" and will shutdown the server as soon as it is scraped once by Prometheus, to allow for the next subprocess to be run
 func server() {
 	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
-		defer os.Exit(1)
+		// Only exit after Prometheus scrapes, not after readiness probes
+		if r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds") != "" {
+			defer os.Exit(1)
+		}
 		file, err := ioutil.TempFile("testdata", "metrics")
 		if err != nil {
 			log.Fatal(err)
@@ -47,6 +50,8 @@ func server() {
 			log.Fatal(err)
 		}
 		http.ServeFile(w, r, file.Name())
+		// Make sure the response is sent before exiting
+		w.(http.Flusher).Flush()
 		return
 	})
 
@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	"github.com/antonmedv/expr"
+	"github.com/spf13/cast"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
@@ -108,23 +109,48 @@ func expandMap(cfg map[string]interface{}, env observer.EndpointEnv) (map[string
 			continue
 		}
 
-		switch val := v.(type) {
-		case map[string]interface{}:
-			res, err := expandMap(val, env)
-			if err != nil {
-				return nil, err
-			}
-			resolved[k] = res
-		case string:
-			res, err := evalBackticksInConfigValue(val, env)
-			if err != nil {
-				return nil, fmt.Errorf("failed evaluating config expression for key %q: %v", k, err)
-			}
-			resolved[k] = res
-		default:
-			resolved[k] = v
+		res, err := expandValue(k, v, env)
+		if err != nil {
+			return nil, err
 		}
+		resolved[k] = res
 	}
 
 	return resolved, nil
 }
+
+// expandSlice recursively expands any expressions in backticks inside the elements of cfg,
+// e.g. a list of targets, returning a copy of the slice.
+func expandSlice(key string, cfg []interface{}, env observer.EndpointEnv) ([]interface{}, error) {
+	resolved := make([]interface{}, 0, len(cfg))
+	for i, v := range cfg {
+		res, err := expandValue(fmt.Sprintf("%s[%d]", key, i), v, env)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, res)
+	}
+
+	return resolved, nil
+}
+
+// expandValue expands any expressions in backticks inside the value v of key.
+func expandValue(key string, v interface{}, env observer.EndpointEnv) (interface{}, error) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return expandMap(val, env)
+	case map[interface{}]interface{}:
+		// Maps within lists aren't converted to string keyed maps when parsed.
+		return expandMap(cast.ToStringMap(val), env)
+	case []interface{}:
+		return expandSlice(key, val, env)
+	case string:
+		res, err := evalBackticksInConfigValue(val, env)
+		if err != nil {
+			return nil, fmt.Errorf("failed evaluating config expression for key %q: %v", key, err)
+		}
+		return res, nil
+	default:
+		return v, nil
+	}
+}
@@ -103,6 +103,40 @@ func Test_userConfigMap_resolve(t *testing.T) {
 				"endpoint": "localhost:6379",
 			}, false,
 		},
+		{
+			"list of maps", userConfigMap{
+				"targets": []interface{}{
+					map[interface{}]interface{}{
+						"endpoint":      "`endpoint`:9999",
+						"target_system": "jvm",
+					},
+					map[string]interface{}{
+						"target_system": "`name`",
+					},
+					"`endpoint`",
+				},
+			}, args{observer.EndpointEnv{"endpoint": "localhost", "name": "kafka"}}, map[string]interface{}{
+				"targets": []interface{}{
+					map[string]interface{}{
+						"endpoint":      "localhost:9999",
+						"target_system": "jvm",
+					},
+					map[string]interface{}{
+						"target_system": "kafka",
+					},
+					"localhost",
+				},
+			}, false,
+		},
+		{
+			"invalid expression in list", userConfigMap{
+				"targets": []interface{}{
+					map[string]interface{}{
+						"endpoint": "`endpoint",
+					},
+				},
+			}, args{observer.EndpointEnv{"endpoint": "localhost"}}, nil, true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -109,6 +109,11 @@ type nopWithEndpointReceiver struct {
 	consumer.Metrics
 }
 
+type nopWithEndpointLogsReceiver struct {
+	component.Component
+	consumer.Logs
+}
+
 func (*nopWithEndpointFactory) CreateDefaultConfig() config.Receiver {
 	return &nopWithEndpointConfig{
 		ReceiverSettings: config.NewReceiverSettings(config.NewID("nop")),
@@ -125,3 +130,14 @@ func (*nopWithEndpointFactory) CreateMetricsReceiver(
 		Metrics:   nextConsumer,
 	}, nil
 }
+
+func (*nopWithEndpointFactory) CreateLogsReceiver(
+	ctx context.Context,
+	_ component.ReceiverCreateParams,
+	_ config.Receiver,
+	nextConsumer consumer.Logs) (component.LogsReceiver, error) {
+	return &nopWithEndpointLogsReceiver{
+		Component: componenthelper.New(),
+		Logs:      nextConsumer,
+	}, nil
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
@@ -37,7 +38,9 @@ func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithLogs(createLogsReceiver),
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithTraces(createTracesReceiver))
 }
 
 func createDefaultConfig() config.Receiver {
@@ -54,16 +57,69 @@ func createDefaultConfig() config.Receiver {
 				conventions.AttributeK8sPodUID:    "`pod.uid`",
 				conventions.AttributeK8sNamespace: "`pod.namespace`",
 			},
+			observer.ContainerType: map[string]string{
+				conventions.AttributeContainerName:  "`name`",
+				conventions.AttributeContainerImage: "`image`",
+				conventions.AttributeContainerID:    "`container_id`",
+			},
+			observer.ECSTaskType: map[string]string{
+				"aws.ecs.task.arn":                 "`task_arn`",
+				"aws.ecs.task.family":              "`task_definition_family`",
+				"aws.ecs.task.revision":            "`task_definition_revision`",
+				conventions.AttributeContainerName: "`container_name`",
+			},
+			observer.K8sNodeType: map[string]string{
+				conventions.AttributeK8sNodeName: "`name`",
+				conventions.AttributeK8sNodeUID:  "`uid`",
+			},
+			observer.K8sServiceType: map[string]string{
+				"k8s.service.name":                "`name`",
+				"k8s.service.uid":                 "`uid`",
+				conventions.AttributeK8sNamespace: "`namespace`",
+			},
 		},
 		receiverTemplates: map[string]receiverTemplate{},
 	}
 }
 
+func createLogsReceiver(
+	ctx context.Context,
+	params component.ReceiverCreateParams,
+	cfg config.Receiver,
+	consumer consumer.Logs,
+) (component.LogsReceiver, error) {
+	if consumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	r := newReceiverCreator(params, cfg.(*Config))
+	r.nextLogsConsumer = consumer
+	return r, nil
+}
+
 func createMetricsReceiver(
 	ctx context.Context,
 	params component.ReceiverCreateParams,
 	cfg config.Receiver,
 	consumer consumer.Metrics,
 ) (component.MetricsReceiver, error) {
-	return newReceiverCreator(params, cfg.(*Config), consumer)
+	if consumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	r := newReceiverCreator(params, cfg.(*Config))
+	r.nextMetricsConsumer = consumer
+	return r, nil
+}
+
+func createTracesReceiver(
+	ctx context.Context,
+	params component.ReceiverCreateParams,
+	cfg config.Receiver,
+	consumer consumer.Traces,
+) (component.TracesReceiver, error) {
+	if consumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	r := newReceiverCreator(params, cfg.(*Config))
+	r.nextTracesConsumer = consumer
+	return r, nil
 }
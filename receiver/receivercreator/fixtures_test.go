@@ -59,6 +59,76 @@ var hostportEndpoint = observer.Endpoint{
 	},
 }
 
+var containerEndpoint = observer.Endpoint{
+	ID:     "container-1",
+	Target: "172.17.0.2:6379",
+	Details: &observer.Container{
+		Name:          "redis",
+		Image:         "redis",
+		Tag:           "6.2",
+		Port:          6379,
+		AlternatePort: 16379,
+		Command:       "redis-server",
+		ContainerID:   "abcdef",
+		Host:          "172.17.0.2",
+		Transport:     observer.ProtocolTCP,
+		Labels: map[string]string{
+			"region": "east-1",
+		},
+	},
+}
+
+var ecsTaskEndpoint = observer.Endpoint{
+	ID:     "ecs-task-1",
+	Target: "10.0.0.1:9113",
+	Details: &observer.ECSTask{
+		TaskARN:                "arn:aws:ecs:us-west-2:123456789012:task/cluster/abcdef",
+		TaskDefinitionFamily:   "nginx",
+		TaskDefinitionRevision: 3,
+		ContainerName:          "nginx-exporter",
+		Port:                   9113,
+		MetricsPath:            "/metrics",
+		Job:                    "nginx",
+		JobLabelName:           "prometheus_job",
+		DockerLabels: map[string]string{
+			"ECS_PROMETHEUS_EXPORTER_PORT": "9113",
+		},
+	},
+}
+
+var k8sNodeEndpoint = observer.Endpoint{
+	ID:     "k8s-node-1",
+	Target: "10.0.0.1",
+	Details: &observer.K8sNode{
+		Name:                "node-1",
+		UID:                 "uid-node-1",
+		Hostname:            "node-1",
+		InternalIP:          "10.0.0.1",
+		KubeletEndpointPort: 10250,
+		Labels: map[string]string{
+			"node-role.kubernetes.io/worker": "",
+		},
+	},
+}
+
+var k8sServiceEndpoint = observer.Endpoint{
+	ID:     "k8s-service-1",
+	Target: "10.96.0.10",
+	Details: &observer.K8sService{
+		Name:        "redis",
+		UID:         "uid-service-1",
+		Namespace:   "default",
+		ClusterIP:   "10.96.0.10",
+		ServiceType: "ClusterIP",
+		Ports: []observer.K8sServicePort{
+			{Name: "redis", Port: 6379, TargetPort: "6379", Transport: observer.ProtocolTCP},
+		},
+		Selector: map[string]string{
+			"app": "redis",
+		},
+	},
+}
+
 var unsupportedEndpoint = observer.Endpoint{
 	ID:      "endpoint-1",
 	Target:  "localhost:1234",
@@ -15,9 +15,11 @@
 package receivercreator
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 
+	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.uber.org/zap"
@@ -36,8 +38,11 @@ type observerHandler struct {
 	logger *zap.Logger
 	// receiversByEndpointID is a map of endpoint IDs to a receiver instance.
 	receiversByEndpointID receiverMap
-	// nextConsumer is the receiver_creator's own consumer
-	nextConsumer consumer.Metrics
+	// nextLogsConsumer, nextMetricsConsumer and nextTracesConsumer are the receiver_creator's
+	// own consumers. Only the one matching the receiver_creator's pipeline is set.
+	nextLogsConsumer    consumer.Logs
+	nextMetricsConsumer consumer.Metrics
+	nextTracesConsumer  consumer.Traces
 	// runner starts and stops receiver instances.
 	runner runner
 }
@@ -115,7 +120,9 @@ func (obs *observerHandler) OnAdd(added []observer.Endpoint) {
 				obs.config.ResourceAttributes,
 				env,
 				e,
-				obs.nextConsumer,
+				obs.nextLogsConsumer,
+				obs.nextMetricsConsumer,
+				obs.nextTracesConsumer,
 			)
 
 			if err != nil {
@@ -132,7 +139,10 @@ func (obs *observerHandler) OnAdd(added []observer.Endpoint) {
 				resourceEnhancer,
 			)
 
-			if err != nil {
+			if errors.Is(err, componenterror.ErrDataTypeIsNotSupported) {
+				obs.logger.Debug("receiver does not support the pipeline data type", zap.String("receiver", template.id.String()))
+				continue
+			} else if err != nil {
 				obs.logger.Error("failed to start receiver", zap.String("receiver", template.id.String()), zap.Error(err))
 				continue
 			}
@@ -20,8 +20,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/config"
-	"go.opentelemetry.io/collector/consumer"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
@@ -34,7 +34,7 @@ type mockRunner struct {
 func (run *mockRunner) start(
 	receiver receiverConfig,
 	discoveredConfig userConfigMap,
-	nextConsumer consumer.Metrics,
+	nextConsumer *resourceEnhancer,
 ) (component.Receiver, error) {
 	args := run.Called(receiver, discoveredConfig, nextConsumer)
 	return args.Get(0).(component.Receiver), args.Error(1)
@@ -77,6 +77,33 @@ func TestOnAdd(t *testing.T) {
 	assert.Equal(t, 1, handler.receiversByEndpointID.Size())
 }
 
+func TestOnAddUnsupportedDataType(t *testing.T) {
+	runner := &mockRunner{}
+	rcvrCfg := receiverConfig{id: config.NewIDWithName("name", "1"), config: userConfigMap{"foo": "bar"}}
+	cfg := createDefaultConfig().(*Config)
+	cfg.receiverTemplates = map[string]receiverTemplate{
+		"name/1": {rcvrCfg, "", newRuleOrPanic(`type == "port"`)},
+	}
+	handler := &observerHandler{
+		config:                cfg,
+		logger:                zap.NewNop(),
+		receiversByEndpointID: receiverMap{},
+		runner:                runner,
+	}
+
+	runner.On(
+		"start",
+		rcvrCfg,
+		userConfigMap{endpointConfigKey: "localhost:1234"},
+		mock.IsType(&resourceEnhancer{}),
+	).Return((*nopWithEndpointReceiver)(nil), componenterror.ErrDataTypeIsNotSupported)
+
+	handler.OnAdd([]observer.Endpoint{portEndpoint})
+
+	runner.AssertExpectations(t)
+	assert.Equal(t, 0, handler.receiversByEndpointID.Size())
+}
+
 func TestOnRemove(t *testing.T) {
 	runner := &mockRunner{}
 	rcvr := &nopWithEndpointReceiver{}
@@ -19,7 +19,6 @@ import (
 	"fmt"
 
 	"go.opentelemetry.io/collector/component"
-	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
 	"go.uber.org/zap"
@@ -27,28 +26,30 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
 
-var _ component.MetricsReceiver = (*receiverCreator)(nil)
+var (
+	_ component.LogsReceiver    = (*receiverCreator)(nil)
+	_ component.MetricsReceiver = (*receiverCreator)(nil)
+	_ component.TracesReceiver  = (*receiverCreator)(nil)
+)
 
-// receiverCreator implements consumer.Metrics.
+// receiverCreator starts receivers at runtime for a single pipeline data type. Only the
+// next consumer of the pipeline it was created for is set.
 type receiverCreator struct {
-	params          component.ReceiverCreateParams
-	cfg             *Config
-	nextConsumer    consumer.Metrics
-	observerHandler observerHandler
+	params              component.ReceiverCreateParams
+	cfg                 *Config
+	nextLogsConsumer    consumer.Logs
+	nextMetricsConsumer consumer.Metrics
+	nextTracesConsumer  consumer.Traces
+	observerHandler     observerHandler
+	observables         []observer.Observable
 }
 
 // newReceiverCreator creates the receiver_creator with the given parameters.
-func newReceiverCreator(params component.ReceiverCreateParams, cfg *Config, nextConsumer consumer.Metrics) (component.MetricsReceiver, error) {
-	if nextConsumer == nil {
-		return nil, componenterror.ErrNilNextConsumer
-	}
-
-	r := &receiverCreator{
-		params:       params,
-		cfg:          cfg,
-		nextConsumer: nextConsumer,
+func newReceiverCreator(params component.ReceiverCreateParams, cfg *Config) *receiverCreator {
+	return &receiverCreator{
+		params: params,
+		cfg:    cfg,
 	}
-	return r, nil
 }
 
 // loggingHost provides a safer version of host that logs errors instead of exiting the process.
@@ -70,7 +71,9 @@ func (rc *receiverCreator) Start(_ context.Context, host component.Host) error {
 		config:                rc.cfg,
 		logger:                rc.params.Logger,
 		receiversByEndpointID: receiverMap{},
-		nextConsumer:          rc.nextConsumer,
+		nextLogsConsumer:      rc.nextLogsConsumer,
+		nextMetricsConsumer:   rc.nextMetricsConsumer,
+		nextTracesConsumer:    rc.nextTracesConsumer,
 		runner: &receiverRunner{
 			params:      rc.params,
 			idNamespace: rc.cfg.ID(),
@@ -108,12 +111,19 @@ func (rc *receiverCreator) Start(_ context.Context, host component.Host) error {
 	// Start all configured watchers.
 	for _, observable := range observers {
 		observable.ListAndWatch(&rc.observerHandler)
+		rc.observables = append(rc.observables, observable)
 	}
 
 	return nil
 }
 
 // Shutdown stops the receiver_creator and all its receivers started at runtime.
+// Observers are unsubscribed from first so no receiver is started or stopped
+// while shutting down.
 func (rc *receiverCreator) Shutdown(context.Context) error {
+	for _, observable := range rc.observables {
+		observable.Unsubscribe(&rc.observerHandler)
+	}
+	rc.observables = nil
 	return rc.observerHandler.shutdown()
 }
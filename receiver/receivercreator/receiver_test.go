@@ -46,6 +46,8 @@ func TestCreateDefaultConfig(t *testing.T) {
 }
 
 type mockObserver struct {
+	sync.Mutex
+	listeners map[observer.Notify]struct{}
 }
 
 func (m *mockObserver) Start(ctx context.Context, host component.Host) error {
@@ -59,9 +61,27 @@ func (m *mockObserver) Shutdown(ctx context.Context) error {
 var _ component.Extension = (*mockObserver)(nil)
 
 func (m *mockObserver) ListAndWatch(notify observer.Notify) {
+	m.Lock()
+	defer m.Unlock()
+	if m.listeners == nil {
+		m.listeners = map[observer.Notify]struct{}{}
+	}
+	m.listeners[notify] = struct{}{}
 	notify.OnAdd([]observer.Endpoint{portEndpoint})
 }
 
+func (m *mockObserver) Unsubscribe(notify observer.Notify) {
+	m.Lock()
+	defer m.Unlock()
+	delete(m.listeners, notify)
+}
+
+func (m *mockObserver) listenerCount() int {
+	m.Lock()
+	defer m.Unlock()
+	return len(m.listeners)
+}
+
 var _ observer.Observable = (*mockObserver)(nil)
 
 func TestMockedEndToEnd(t *testing.T) {
@@ -127,6 +147,25 @@ func TestMockedEndToEnd(t *testing.T) {
 	assert.Len(t, mockConsumer.AllMetrics(), 1)
 }
 
+func TestShutdownUnsubscribes(t *testing.T) {
+	host, cfg := exampleCreatorFactory(t)
+	mockObs := &mockObserver{}
+	host.extensions = map[config.ComponentID]component.Extension{
+		config.NewID("mock_observer"): mockObs,
+	}
+	dynCfg := cfg.Receivers[config.NewIDWithName(typeStr, "1")]
+	factory := NewFactory()
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	rcvr, err := factory.CreateMetricsReceiver(context.Background(), params, dynCfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, rcvr.Start(context.Background(), host))
+	assert.Equal(t, 1, mockObs.listenerCount())
+
+	require.NoError(t, rcvr.Shutdown(context.Background()))
+	assert.Equal(t, 0, mockObs.listenerCount())
+}
+
 func TestLoggingHost(t *testing.T) {
 	core, obs := zapObserver.New(zap.ErrorLevel)
 	host := &loggingHost{
@@ -24,21 +24,29 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
 
-var _ consumer.Metrics = (*resourceEnhancer)(nil)
+var (
+	_ consumer.Logs    = (*resourceEnhancer)(nil)
+	_ consumer.Metrics = (*resourceEnhancer)(nil)
+	_ consumer.Traces  = (*resourceEnhancer)(nil)
+)
 
 // resourceEnhancer adds additional resource attribute entries
 // from the given endpoint environment. The added attributes vary based on the type
-// of the endpoint.
+// of the endpoint. Only the next consumer matching the receiver_creator's pipeline is set.
 type resourceEnhancer struct {
-	nextConsumer consumer.Metrics
-	attrs        map[string]string
+	nextLogs    consumer.Logs
+	nextMetrics consumer.Metrics
+	nextTraces  consumer.Traces
+	attrs       map[string]string
 }
 
 func newResourceEnhancer(
 	resources resourceAttributes,
 	env observer.EndpointEnv,
 	endpoint observer.Endpoint,
-	nextConsumer consumer.Metrics,
+	nextLogs consumer.Logs,
+	nextMetrics consumer.Metrics,
+	nextTraces consumer.Traces,
 ) (*resourceEnhancer, error) {
 	attrs := map[string]string{}
 
@@ -57,8 +65,10 @@ func newResourceEnhancer(
 	}
 
 	return &resourceEnhancer{
-		nextConsumer: nextConsumer,
-		attrs:        attrs,
+		nextLogs:    nextLogs,
+		nextMetrics: nextMetrics,
+		nextTraces:  nextTraces,
+		attrs:       attrs,
 	}, nil
 }
 
@@ -66,16 +76,36 @@ func (r *resourceEnhancer) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{MutatesData: true}
 }
 
+func (r *resourceEnhancer) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	rl := ld.ResourceLogs()
+	for i := 0; i < rl.Len(); i++ {
+		r.insertAttributes(rl.At(i).Resource())
+	}
+
+	return r.nextLogs.ConsumeLogs(ctx, ld)
+}
+
 func (r *resourceEnhancer) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
 	rm := md.ResourceMetrics()
 	for i := 0; i < rm.Len(); i++ {
-		rms := rm.At(i)
-		attrs := rms.Resource().Attributes()
+		r.insertAttributes(rm.At(i).Resource())
+	}
 
-		for attr, val := range r.attrs {
-			attrs.InsertString(attr, val)
-		}
+	return r.nextMetrics.ConsumeMetrics(ctx, md)
+}
+
+func (r *resourceEnhancer) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	rs := td.ResourceSpans()
+	for i := 0; i < rs.Len(); i++ {
+		r.insertAttributes(rs.At(i).Resource())
 	}
 
-	return r.nextConsumer.ConsumeMetrics(ctx, md)
+	return r.nextTraces.ConsumeTraces(ctx, td)
+}
+
+func (r *resourceEnhancer) insertAttributes(resource pdata.Resource) {
+	attrs := resource.Attributes()
+	for attr, val := range r.attrs {
+		attrs.InsertString(attr, val)
+	}
 }
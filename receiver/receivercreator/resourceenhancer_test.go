@@ -36,13 +36,25 @@ func Test_newResourceEnhancer(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	ecsTaskEnv, err := ecsTaskEndpoint.Env()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k8sNodeEnv, err := k8sNodeEndpoint.Env()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k8sServiceEnv, err := k8sServiceEndpoint.Env()
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	cfg := createDefaultConfig().(*Config)
 	type args struct {
-		resources    resourceAttributes
-		env          observer.EndpointEnv
-		endpoint     observer.Endpoint
-		nextConsumer consumer.Metrics
+		resources   resourceAttributes
+		env         observer.EndpointEnv
+		endpoint    observer.Endpoint
+		nextMetrics consumer.Metrics
 	}
 	tests := []struct {
 		name    string
@@ -53,13 +65,13 @@ func Test_newResourceEnhancer(t *testing.T) {
 		{
 			name: "pod endpoint",
 			args: args{
-				resources:    cfg.ResourceAttributes,
-				env:          podEnv,
-				endpoint:     podEndpoint,
-				nextConsumer: &consumertest.MetricsSink{},
+				resources:   cfg.ResourceAttributes,
+				env:         podEnv,
+				endpoint:    podEndpoint,
+				nextMetrics: &consumertest.MetricsSink{},
 			},
 			want: &resourceEnhancer{
-				nextConsumer: &consumertest.MetricsSink{},
+				nextMetrics: &consumertest.MetricsSink{},
 				attrs: map[string]string{
 					"k8s.pod.uid":        "uid-1",
 					"k8s.pod.name":       "pod-1",
@@ -71,13 +83,13 @@ func Test_newResourceEnhancer(t *testing.T) {
 		{
 			name: "port endpoint",
 			args: args{
-				resources:    cfg.ResourceAttributes,
-				env:          portEnv,
-				endpoint:     portEndpoint,
-				nextConsumer: &consumertest.MetricsSink{},
+				resources:   cfg.ResourceAttributes,
+				env:         portEnv,
+				endpoint:    portEndpoint,
+				nextMetrics: &consumertest.MetricsSink{},
 			},
 			want: &resourceEnhancer{
-				nextConsumer: &consumertest.MetricsSink{},
+				nextMetrics: &consumertest.MetricsSink{},
 				attrs: map[string]string{
 					"k8s.pod.uid":        "uid-1",
 					"k8s.pod.name":       "pod-1",
@@ -86,6 +98,60 @@ func Test_newResourceEnhancer(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "ecs task endpoint",
+			args: args{
+				resources:   cfg.ResourceAttributes,
+				env:         ecsTaskEnv,
+				endpoint:    ecsTaskEndpoint,
+				nextMetrics: &consumertest.MetricsSink{},
+			},
+			want: &resourceEnhancer{
+				nextMetrics: &consumertest.MetricsSink{},
+				attrs: map[string]string{
+					"aws.ecs.task.arn":      "arn:aws:ecs:us-west-2:123456789012:task/cluster/abcdef",
+					"aws.ecs.task.family":   "nginx",
+					"aws.ecs.task.revision": "3",
+					"container.name":        "nginx-exporter",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "k8s node endpoint",
+			args: args{
+				resources:   cfg.ResourceAttributes,
+				env:         k8sNodeEnv,
+				endpoint:    k8sNodeEndpoint,
+				nextMetrics: &consumertest.MetricsSink{},
+			},
+			want: &resourceEnhancer{
+				nextMetrics: &consumertest.MetricsSink{},
+				attrs: map[string]string{
+					"k8s.node.name": "node-1",
+					"k8s.node.uid":  "uid-node-1",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "k8s service endpoint",
+			args: args{
+				resources:   cfg.ResourceAttributes,
+				env:         k8sServiceEnv,
+				endpoint:    k8sServiceEndpoint,
+				nextMetrics: &consumertest.MetricsSink{},
+			},
+			want: &resourceEnhancer{
+				nextMetrics: &consumertest.MetricsSink{},
+				attrs: map[string]string{
+					"k8s.service.name":   "redis",
+					"k8s.service.uid":    "uid-service-1",
+					"k8s.namespace.name": "default",
+				},
+			},
+			wantErr: false,
+		},
 		{
 			// If the configured attribute value is empty it should not touch that
 			// attribute.
@@ -96,12 +162,12 @@ func Test_newResourceEnhancer(t *testing.T) {
 					res[observer.PodType]["k8s.pod.name"] = ""
 					return res
 				}(),
-				env:          podEnv,
-				endpoint:     podEndpoint,
-				nextConsumer: nil,
+				env:         podEnv,
+				endpoint:    podEndpoint,
+				nextMetrics: nil,
 			},
 			want: &resourceEnhancer{
-				nextConsumer: nil,
+				nextMetrics: nil,
 				attrs: map[string]string{
 					"k8s.pod.uid":        "uid-1",
 					"k8s.namespace.name": "default",
@@ -117,9 +183,9 @@ func Test_newResourceEnhancer(t *testing.T) {
 					res[observer.PodType]["k8s.pod.name"] = "`unbalanced"
 					return res
 				}(),
-				env:          podEnv,
-				endpoint:     podEndpoint,
-				nextConsumer: nil,
+				env:         podEnv,
+				endpoint:    podEndpoint,
+				nextMetrics: nil,
 			},
 			want:    nil,
 			wantErr: true,
@@ -127,7 +193,7 @@ func Test_newResourceEnhancer(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := newResourceEnhancer(tt.args.resources, tt.args.env, tt.args.endpoint, tt.args.nextConsumer)
+			got, err := newResourceEnhancer(tt.args.resources, tt.args.env, tt.args.endpoint, nil, tt.args.nextMetrics, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("newResourceEnhancer() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -186,8 +252,8 @@ func Test_resourceEnhancer_ConsumeMetrics(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &resourceEnhancer{
-				nextConsumer: tt.fields.nextConsumer,
-				attrs:        tt.fields.attrs,
+				nextMetrics: tt.fields.nextConsumer,
+				attrs:       tt.fields.attrs,
 			}
 			if err := r.ConsumeMetrics(tt.args.ctx, tt.args.md); (err != nil) != tt.wantErr {
 				t.Errorf("ConsumeMetrics() error = %v, wantErr %v", err, tt.wantErr)
@@ -202,3 +268,47 @@ func Test_resourceEnhancer_ConsumeMetrics(t *testing.T) {
 		})
 	}
 }
+
+func Test_resourceEnhancer_ConsumeLogs(t *testing.T) {
+	sink := &consumertest.LogsSink{}
+	r := &resourceEnhancer{
+		nextLogs: sink,
+		attrs: map[string]string{
+			"key1": "value1",
+		},
+	}
+
+	ld := pdata.NewLogs()
+	attrs := ld.ResourceLogs().AppendEmpty().Resource().Attributes()
+	attrs.InsertString("key1", "existing")
+	ld.ResourceLogs().AppendEmpty()
+	require.NoError(t, r.ConsumeLogs(context.Background(), ld))
+
+	logs := sink.AllLogs()
+	require.Len(t, logs, 1)
+	rl := logs[0].ResourceLogs()
+	require.Equal(t, 2, rl.Len())
+	val, _ := rl.At(0).Resource().Attributes().Get("key1")
+	require.Equal(t, "existing", val.StringVal())
+	val, _ = rl.At(1).Resource().Attributes().Get("key1")
+	require.Equal(t, "value1", val.StringVal())
+}
+
+func Test_resourceEnhancer_ConsumeTraces(t *testing.T) {
+	sink := &consumertest.TracesSink{}
+	r := &resourceEnhancer{
+		nextTraces: sink,
+		attrs: map[string]string{
+			"key1": "value1",
+		},
+	}
+
+	td := pdata.NewTraces()
+	td.ResourceSpans().AppendEmpty()
+	require.NoError(t, r.ConsumeTraces(context.Background(), td))
+
+	traces := sink.AllTraces()
+	require.Len(t, traces, 1)
+	val, _ := traces[0].ResourceSpans().At(0).Resource().Attributes().Get("key1")
+	require.Equal(t, "value1", val.StringVal())
+}
@@ -30,7 +30,7 @@ type rule struct {
 }
 
 // ruleRe is used to verify the rule starts type check.
-var ruleRe = regexp.MustCompile(`^type\s*==\s*("pod"|"port"|"hostport")`)
+var ruleRe = regexp.MustCompile(`^type\s*==\s*("pod"|"port"|"hostport"|"container"|"ecs_task"|"k8s\.node"|"k8s\.service")`)
 
 // newRule creates a new rule instance.
 func newRule(ruleStr string) (rule, error) {
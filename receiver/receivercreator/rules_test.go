@@ -48,6 +48,10 @@ func Test_ruleEval(t *testing.T) {
 		{"basic hostport", args{`type == "hostport" && port == 1234 && process_name == "splunk"`, hostportEndpoint}, true, false},
 		{"basic pod", args{`type == "pod" && labels["region"] == "west-1"`, podEndpoint}, true, false},
 		{"annotations", args{`type == "pod" && annotations["scrape"] == "true"`, podEndpoint}, true, false},
+		{"basic container", args{`type == "container" && image matches "redis" && labels["region"] == "east-1"`, containerEndpoint}, true, false},
+		{"basic ecs task", args{`type == "ecs_task" && task_definition_family == "nginx" && docker_labels["ECS_PROMETHEUS_EXPORTER_PORT"] == "9113"`, ecsTaskEndpoint}, true, false},
+		{"basic k8s node", args{`type == "k8s.node" && kubelet_endpoint_port == 10250 && "node-role.kubernetes.io/worker" in labels`, k8sNodeEndpoint}, true, false},
+		{"basic k8s service", args{`type == "k8s.service" && selector["app"] == "redis" && ports[0].port == 6379`, k8sServiceEndpoint}, true, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -85,6 +89,12 @@ func Test_newRule(t *testing.T) {
 		{"valid port", args{`type == "port" && port_name == "http"`}, false},
 		{"valid pod", args{`type=="pod" && port_name == "http"`}, false},
 		{"valid hostport", args{`type ==    "hostport" && port_name == "http"`}, false},
+		{"valid container", args{`type == "container" && port == 6379`}, false},
+		{"valid ecs task", args{`type == "ecs_task" && container_name == "nginx-exporter"`}, false},
+		{"valid k8s node", args{`type == "k8s.node" && kubelet_endpoint_port == 10250`}, false},
+		{"valid k8s service", args{`type == "k8s.service" && selector["app"] == "redis"`}, false},
+		{"invalid k8s node type", args{`type == "k8sxnode" && name == "node-1"`}, true},
+		{"invalid k8s service type", args{`type == "k8s-service" && name == "redis"`}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
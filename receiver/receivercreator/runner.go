@@ -18,17 +18,18 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/cast"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configloader"
-	"go.opentelemetry.io/collector/consumer"
 )
 
 // runner starts and stops receiver instances.
 type runner interface {
 	// start a receiver instance from its static config and discovered config.
-	start(receiver receiverConfig, discoveredConfig userConfigMap, nextConsumer consumer.Metrics) (component.Receiver, error)
+	start(receiver receiverConfig, discoveredConfig userConfigMap, nextConsumer *resourceEnhancer) (component.Receiver, error)
 	// shutdown a receiver.
 	shutdown(rcvr component.Receiver) error
 }
@@ -46,7 +47,7 @@ var _ runner = (*receiverRunner)(nil)
 func (run *receiverRunner) start(
 	receiver receiverConfig,
 	discoveredConfig userConfigMap,
-	nextConsumer consumer.Metrics,
+	nextConsumer *resourceEnhancer,
 ) (component.Receiver, error) {
 	factory := run.host.GetFactory(component.KindReceiver, receiver.id.Type())
 
@@ -91,6 +92,15 @@ func (run *receiverRunner) loadRuntimeReceiverConfig(
 		return nil, fmt.Errorf("failed to merge template config from config file: %v", err)
 	}
 
+	endpoint := cast.ToString(mergedConfig.Get(endpointConfigKey))
+	if discoveredEndpoint, ok := discoveredConfig[endpointConfigKey]; ok {
+		endpoint = cast.ToString(discoveredEndpoint)
+		// Receivers without an endpoint setting (e.g. filelog) would fail to load the discovered one.
+		if !hasEndpointConfig(factory) {
+			discoveredConfig = copyWithoutKey(discoveredConfig, endpointConfigKey)
+		}
+	}
+
 	// Merge in discoveredConfig containing values discovered at runtime.
 	if err := mergedConfig.MergeStringMap(discoveredConfig); err != nil {
 		return nil, fmt.Errorf("failed to merge template config from discovered runtime values: %v", err)
@@ -102,15 +112,45 @@ func (run *receiverRunner) loadRuntimeReceiverConfig(
 	}
 	// Sets dynamically created receiver to something like receiver_creator/1/redis{endpoint="localhost:6380"}.
 	// TODO: Need to make sure this is unique (just endpoint is probably not totally sufficient).
-	receiverConfig.SetIDName(fmt.Sprintf("%s/%s{endpoint=%q}", receiver.id.Name(), run.idNamespace, cast.ToString(mergedConfig.Get(endpointConfigKey))))
+	receiverConfig.SetIDName(fmt.Sprintf("%s/%s{endpoint=%q}", receiver.id.Name(), run.idNamespace, endpoint))
 	return receiverConfig, nil
 }
 
-// createRuntimeReceiver creates a receiver that is discovered at runtime.
+// hasEndpointConfig returns whether the receiver config created by factory has an endpoint setting.
+func hasEndpointConfig(factory component.ReceiverFactory) bool {
+	settings := map[string]interface{}{}
+	if err := mapstructure.Decode(factory.CreateDefaultConfig(), &settings); err != nil {
+		return false
+	}
+	_, ok := settings[endpointConfigKey]
+	return ok
+}
+
+func copyWithoutKey(m userConfigMap, key string) userConfigMap {
+	out := make(userConfigMap, len(m))
+	for k, v := range m {
+		if k != key {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// createRuntimeReceiver creates a receiver that is discovered at runtime for the pipeline
+// data type of nextConsumer. componenterror.ErrDataTypeIsNotSupported is returned if the
+// receiver doesn't support that data type.
 func (run *receiverRunner) createRuntimeReceiver(
 	factory component.ReceiverFactory,
 	cfg config.Receiver,
-	nextConsumer consumer.Metrics,
-) (component.MetricsReceiver, error) {
-	return factory.CreateMetricsReceiver(context.Background(), run.params, cfg, nextConsumer)
+	nextConsumer *resourceEnhancer,
+) (component.Receiver, error) {
+	switch {
+	case nextConsumer.nextLogs != nil:
+		return factory.CreateLogsReceiver(context.Background(), run.params, cfg, nextConsumer)
+	case nextConsumer.nextMetrics != nil:
+		return factory.CreateMetricsReceiver(context.Background(), run.params, cfg, nextConsumer)
+	case nextConsumer.nextTraces != nil:
+		return factory.CreateTracesReceiver(context.Background(), run.params, cfg, nextConsumer)
+	}
+	return nil, componenterror.ErrNilNextConsumer
 }
@@ -20,7 +20,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.uber.org/zap"
 )
 
@@ -42,9 +44,34 @@ func Test_loadAndCreateRuntimeReceiver(t *testing.T) {
 
 	// Test that metric receiver can be created from loaded config.
 	t.Run("test create receiver from loaded config", func(t *testing.T) {
-		recvr, err := run.createRuntimeReceiver(exampleFactory, loadedConfig, nil)
+		recvr, err := run.createRuntimeReceiver(exampleFactory, loadedConfig, &resourceEnhancer{nextMetrics: consumertest.NewNop()})
 		require.NoError(t, err)
 		assert.NotNil(t, recvr)
 		assert.IsType(t, &nopWithEndpointReceiver{}, recvr)
 	})
+
+	// Test that logs receiver can be created from loaded config.
+	t.Run("test create logs receiver from loaded config", func(t *testing.T) {
+		recvr, err := run.createRuntimeReceiver(exampleFactory, loadedConfig, &resourceEnhancer{nextLogs: consumertest.NewNop()})
+		require.NoError(t, err)
+		assert.NotNil(t, recvr)
+		assert.IsType(t, &nopWithEndpointLogsReceiver{}, recvr)
+	})
+}
+
+func Test_loadRuntimeReceiverConfigWithoutEndpoint(t *testing.T) {
+	run := &receiverRunner{params: component.ReceiverCreateParams{Logger: zap.NewNop()}, idNamespace: config.NewIDWithName(typeStr, "1")}
+	factory := componenttest.NewNopReceiverFactory()
+	template, err := newReceiverTemplate("nop/1", nil)
+	require.NoError(t, err)
+
+	assert.True(t, hasEndpointConfig(&nopWithEndpointFactory{}))
+	assert.False(t, hasEndpointConfig(factory))
+
+	// The discovered endpoint is only used to name receivers without an endpoint setting.
+	loadedConfig, err := run.loadRuntimeReceiverConfig(factory, template.receiverConfig, userConfigMap{
+		endpointConfigKey: "localhost:12345",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "nop/1/receiver_creator/1{endpoint=\"localhost:12345\"}", loadedConfig.ID().String())
 }
@@ -15,6 +15,9 @@
 package redisreceiver
 
 import (
+	"fmt"
+	"net"
+
 	"github.com/go-redis/redis/v7"
 )
 
@@ -22,6 +25,11 @@ import (
 type client interface {
 	// retrieves a string of key/value pairs of redis metadata
 	retrieveInfo() (string, error)
+	// retrieves the CLUSTER NODES description of a Redis Cluster, one node
+	// per line
+	retrieveClusterNodes() (string, error)
+	// closes the connections to the server
+	close() error
 	// line delimiter
 	// redis lines are delimited by \r\n, files (for testing) by \n
 	delimiter() string
@@ -46,7 +54,56 @@ func (c *redisClient) delimiter() string {
 	return "\r\n"
 }
 
-// Retrieve Redis INFO. We retrieve all of the 'sections'.
+// Retrieve Redis INFO. We retrieve all of the 'sections', including
+// commandstats and latencystats which are not part of the default set.
 func (c *redisClient) retrieveInfo() (string, error) {
-	return c.client.Info().Result()
+	return c.client.Info("all").Result()
+}
+
+// Retrieve the Redis Cluster topology as seen by this node.
+func (c *redisClient) retrieveClusterNodes() (string, error) {
+	return c.client.ClusterNodes().Result()
+}
+
+func (c *redisClient) close() error {
+	return c.client.Close()
+}
+
+// Interface for a Redis Sentinel client. Implementation can be faked for
+// testing.
+type sentinelClient interface {
+	// retrieves the "host:port" address of the current primary of the
+	// passed-in monitored primary name
+	retrievePrimaryAddr(primaryName string) (string, error)
+	// closes the connections to the sentinel
+	close() error
+}
+
+// Wraps a real Redis Sentinel client, implements `sentinelClient` interface.
+type redisSentinelClient struct {
+	client *redis.SentinelClient
+}
+
+var _ sentinelClient = (*redisSentinelClient)(nil)
+
+// Creates a new real Redis Sentinel client from the passed-in redis.Options.
+func newRedisSentinelClient(options *redis.Options) sentinelClient {
+	return &redisSentinelClient{
+		client: redis.NewSentinelClient(options),
+	}
+}
+
+func (c *redisSentinelClient) retrievePrimaryAddr(primaryName string) (string, error) {
+	addr, err := c.client.GetMasterAddrByName(primaryName).Result()
+	if err != nil {
+		return "", err
+	}
+	if len(addr) != 2 {
+		return "", fmt.Errorf("unexpected sentinel reply for primary %q: %v", primaryName, addr)
+	}
+	return net.JoinHostPort(addr[0], addr[1]), nil
+}
+
+func (c *redisSentinelClient) close() error {
+	return c.client.Close()
 }
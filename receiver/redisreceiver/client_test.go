@@ -32,6 +32,12 @@ func newFakeClient() *fakeClient {
 	return &fakeClient{}
 }
 
+// Creates a fake client regardless of the address, can be passed to
+// newRedisRunnable.
+func newFakeClientFunc(string) client {
+	return newFakeClient()
+}
+
 func (c fakeClient) delimiter() string {
 	if runtime.GOOS == "windows" {
 		return "\r\n"
@@ -44,6 +50,14 @@ func (fakeClient) retrieveInfo() (string, error) {
 	return readFile("info")
 }
 
+func (fakeClient) retrieveClusterNodes() (string, error) {
+	return readFile("cluster_nodes")
+}
+
+func (fakeClient) close() error {
+	return nil
+}
+
 func readFile(fname string) (string, error) {
 	file, err := ioutil.ReadFile(path.Join("testdata", fname+".txt"))
 	if err != nil {
@@ -58,3 +72,10 @@ func TestRetrieveInfo(t *testing.T) {
 	require.Nil(t, err)
 	require.True(t, strings.HasPrefix(res, "# Server"))
 }
+
+func TestRetrieveClusterNodes(t *testing.T) {
+	g := fakeClient{}
+	res, err := g.retrieveClusterNodes()
+	require.Nil(t, err)
+	require.Equal(t, 7, len(strings.Split(strings.TrimSpace(res), "\n")))
+}
@@ -0,0 +1,110 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Prefix of the keys in the Commandstats section of the INFO command,
+	// e.g. "cmdstat_get:calls=2,usec=17,usec_per_call=8.50"
+	commandStatsPrefix = "cmdstat_"
+	// Prefix of the keys in the Latencystats section of the INFO command,
+	// e.g. "latency_percentiles_usec_get:p50=1.003,p99=1.003,p99.9=1.003"
+	latencyStatsPrefix = "latency_percentiles_usec_"
+)
+
+// Holds fields returned by the Commandstats section of the INFO command.
+type commandStats struct {
+	cmd   string
+	calls int64
+	usec  int64
+}
+
+// Turns a commandstats value (the part after the colon
+// e.g. "calls=2,usec=17,usec_per_call=8.50") into a commandStats struct.
+// Fields other than calls and usec are ignored.
+func parseCommandStatsString(cmd string, str string) (*commandStats, error) {
+	cs := commandStats{cmd: cmd}
+	for _, pairStr := range strings.Split(str, ",") {
+		var field *int64
+		pair := strings.Split(pairStr, "=")
+		if len(pair) != 2 {
+			return nil, fmt.Errorf(
+				"unexpected commandstats pair '%s'",
+				pairStr,
+			)
+		}
+		switch pair[0] {
+		case "calls":
+			field = &cs.calls
+		case "usec":
+			field = &cs.usec
+		}
+		if field != nil {
+			val, err := strconv.ParseInt(pair[1], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			*field = val
+		}
+	}
+	return &cs, nil
+}
+
+// Holds the latency percentiles of a single command returned by the
+// Latencystats section of the INFO command (Redis 7 and later).
+type latencyStats struct {
+	cmd         string
+	percentiles map[string]float64
+}
+
+// Turns a latencystats value (the part after the colon
+// e.g. "p50=1.003,p99=1.003,p99.9=1.003") into a latencyStats struct.
+func parseLatencyStatsString(cmd string, str string) (*latencyStats, error) {
+	ls := latencyStats{cmd: cmd, percentiles: map[string]float64{}}
+	for _, pairStr := range strings.Split(str, ",") {
+		pair := strings.Split(pairStr, "=")
+		if len(pair) != 2 || !strings.HasPrefix(pair[0], "p") {
+			return nil, fmt.Errorf(
+				"unexpected latencystats pair '%s'",
+				pairStr,
+			)
+		}
+		val, err := strconv.ParseFloat(pair[1], 64)
+		if err != nil {
+			return nil, err
+		}
+		ls.percentiles[strings.TrimPrefix(pair[0], "p")] = val
+	}
+	return &ls, nil
+}
+
+// Returns the command names found in the info map under the passed-in key
+// prefix, sorted so that metrics are produced in a stable order.
+func (i info) commandsWithPrefix(prefix string) []string {
+	var cmds []string
+	for key := range i {
+		if strings.HasPrefix(key, prefix) {
+			cmds = append(cmds, strings.TrimPrefix(key, prefix))
+		}
+	}
+	sort.Strings(cmds)
+	return cmds
+}
@@ -0,0 +1,94 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommandStats(t *testing.T) {
+	cs, err := parseCommandStatsString("get", "calls=2,usec=17,usec_per_call=8.50,rejected_calls=0,failed_calls=0")
+	require.Nil(t, err)
+	require.Equal(t, "get", cs.cmd)
+	require.Equal(t, int64(2), cs.calls)
+	require.Equal(t, int64(17), cs.usec)
+}
+
+func TestParseMalformedCommandStats(t *testing.T) {
+	tests := []struct{ name, stats string }{
+		{"missing value", "calls=2,usec="},
+		{"missing equals", "calls=2,usec"},
+		{"non numeric value", "calls=two,usec=17"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseCommandStatsString("get", test.stats)
+			require.NotNil(t, err)
+		})
+	}
+}
+
+func TestParseLatencyStats(t *testing.T) {
+	ls, err := parseLatencyStatsString("set", "p50=1.003,p99=2.007,p99.9=4.015")
+	require.Nil(t, err)
+	require.Equal(t, "set", ls.cmd)
+	require.Equal(t, map[string]float64{"50": 1.003, "99": 2.007, "99.9": 4.015}, ls.percentiles)
+}
+
+func TestParseMalformedLatencyStats(t *testing.T) {
+	tests := []struct{ name, stats string }{
+		{"missing value", "p50=1.003,p99="},
+		{"missing equals", "p50=1.003,p99"},
+		{"unexpected key", "p50=1.003,avg=2.007"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseLatencyStatsString("set", test.stats)
+			require.NotNil(t, err)
+		})
+	}
+}
+
+func TestBuildCommandStatsMetrics(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	info, err := svc.info()
+	require.Nil(t, err)
+	ms, warnings := info.buildCommandStatsMetrics(testTimeBundle())
+	require.Nil(t, warnings)
+	// two commands with calls and usec metrics, and one command with three
+	// latency percentiles
+	require.Equal(t, 7, ms.Len())
+
+	calls := ms.At(0)
+	require.Equal(t, "redis/cmd/calls", calls.Name())
+	pt := calls.IntSum().DataPoints().At(0)
+	require.Equal(t, int64(2), pt.Value())
+	cmd, _ := pt.LabelsMap().Get("cmd")
+	require.Equal(t, "get", cmd)
+
+	usec := ms.At(1)
+	require.Equal(t, "redis/cmd/usec", usec.Name())
+	require.Equal(t, "us", usec.Unit())
+	require.Equal(t, int64(17), usec.IntSum().DataPoints().At(0).Value())
+
+	latency := ms.At(4)
+	require.Equal(t, "redis/cmd/latency", latency.Name())
+	lpt := latency.DoubleGauge().DataPoints().At(0)
+	require.Equal(t, 1.003, lpt.Value())
+	percentile, _ := lpt.LabelsMap().Get("percentile")
+	require.Equal(t, "50", percentile)
+}
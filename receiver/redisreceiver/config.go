@@ -15,9 +15,22 @@
 package redisreceiver
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+const (
+	// Scrape the server at Endpoint.
+	modeStandalone = "standalone"
+	// Read CLUSTER NODES from the server at Endpoint and scrape every
+	// primary and replica of the Redis Cluster.
+	modeCluster = "cluster"
+	// Ask the sentinels for the current primary and scrape it.
+	modeSentinel = "sentinel"
 )
 
 type Config struct {
@@ -33,7 +46,52 @@ type Config struct {
 
 	// TODO allow users to add additional resource key value pairs?
 
+	// Optional username. Use the specified Username to authenticate the
+	// current connection with one of the connections defined in the ACL
+	// list when connecting to a Redis 6.0 instance, or greater.
+	Username string `mapstructure:"username"`
+
 	// Optional password. Must match the password specified in the
-	// requirepass server configuration option.
+	// requirepass server configuration option, or the password of the ACL
+	// user when Username is set.
 	Password string `mapstructure:"password"`
+
+	// TLS settings used to connect to the Redis instance. TLS is disabled
+	// unless `insecure` is set to false or a CA file is configured.
+	TLS configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
+
+	// How the Redis servers to scrape are found: "standalone" (default),
+	// "cluster" or "sentinel". The topology is discovered again on every
+	// collection, so that failovers and resharding are picked up.
+	Mode string `mapstructure:"mode"`
+
+	// Sentinel settings, used when Mode is "sentinel".
+	Sentinel SentinelConfig `mapstructure:"sentinel"`
+}
+
+// SentinelConfig defines how to reach the sentinels monitoring the Redis
+// primary to scrape.
+type SentinelConfig struct {
+	// The name of the monitored primary, as configured in the sentinels.
+	PrimaryName string `mapstructure:"primary_name"`
+	// The sentinel endpoints, tried in order. Defaults to the receiver
+	// endpoint.
+	Endpoints []string `mapstructure:"endpoints"`
+	// Optional password of the sentinels, when different from the password
+	// of the Redis servers.
+	Password string `mapstructure:"password"`
+}
+
+// Validate checks the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	switch cfg.Mode {
+	case "", modeStandalone, modeCluster:
+	case modeSentinel:
+		if cfg.Sentinel.PrimaryName == "" {
+			return errors.New("sentinel.primary_name must be set when mode is sentinel")
+		}
+	default:
+		return fmt.Errorf("unknown mode %q, must be one of %q, %q or %q", cfg.Mode, modeStandalone, modeCluster, modeSentinel)
+	}
+	return nil
 }
@@ -0,0 +1,93 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(
+		t, path.Join(".", "testdata", "config.yaml"), factories,
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, len(cfg.Receivers), 4)
+
+	r0 := cfg.Receivers[config.NewID(typeStr)].(*Config)
+	defaultCfg := factory.CreateDefaultConfig().(*Config)
+	defaultCfg.Endpoint = "localhost:6379"
+	defaultCfg.ServiceName = "my-test-redis"
+	assert.Equal(t, defaultCfg, r0)
+
+	r1 := cfg.Receivers[config.NewIDWithName(typeStr, "tls")]
+	assert.Equal(t, &Config{
+		ReceiverSettings:   config.NewReceiverSettings(config.NewIDWithName(typeStr, "tls")),
+		Endpoint:           "redis.example.com:6380",
+		CollectionInterval: 30 * time.Second,
+		ServiceName:        "my-managed-redis",
+		Username:           "otel",
+		Password:           "secret",
+		TLS: configtls.TLSClientSetting{
+			TLSSetting: configtls.TLSSetting{
+				CAFile: "/ca.crt",
+			},
+			ServerName: "redis.example.com",
+		},
+		Mode: modeStandalone,
+	}, r1)
+
+	r2 := cfg.Receivers[config.NewIDWithName(typeStr, "cluster")].(*Config)
+	assert.Equal(t, modeCluster, r2.Mode)
+	assert.Equal(t, "redis-cluster:6379", r2.Endpoint)
+
+	r3 := cfg.Receivers[config.NewIDWithName(typeStr, "sentinel")].(*Config)
+	assert.Equal(t, modeSentinel, r3.Mode)
+	assert.Equal(t, SentinelConfig{
+		PrimaryName: "mymaster",
+		Endpoints:   []string{"sentinel-1:26379", "sentinel-2:26379"},
+		Password:    "sentinel-secret",
+	}, r3.Sentinel)
+}
+
+func TestValidateConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Mode = "replication"
+	assert.EqualError(t, cfg.Validate(), `unknown mode "replication", must be one of "standalone", "cluster" or "sentinel"`)
+
+	cfg.Mode = modeSentinel
+	assert.EqualError(t, cfg.Validate(), "sentinel.primary_name must be set when mode is sentinel")
+
+	cfg.Sentinel.PrimaryName = "mymaster"
+	assert.NoError(t, cfg.Validate())
+}
@@ -20,6 +20,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
@@ -40,6 +41,10 @@ func createDefaultConfig() config.Receiver {
 	return &Config{
 		ReceiverSettings:   config.NewReceiverSettings(config.NewID(typeStr)),
 		CollectionInterval: 10 * time.Second,
+		TLS: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+		Mode: modeStandalone,
 	}
 }
 
@@ -73,6 +73,33 @@ func (i info) buildKeyspaceMetrics(t *timeBundle) (outMS pdata.MetricSlice, warn
 	return outMS, warnings
 }
 
+// Builds per-command metrics from any 'commandstats' and 'latencystats'
+// metrics in Redis INFO: e.g. "cmdstat_get:calls=2,usec=17,usec_per_call=8.50"
+// and "latency_percentiles_usec_get:p50=1.003,p99=1.003". Returns metrics and
+// parsing errors, to be treated as warnings, if there were any.
+func (i info) buildCommandStatsMetrics(t *timeBundle) (outMS pdata.MetricSlice, warnings []error) {
+	outMS = pdata.NewMetricSlice()
+	for _, cmd := range i.commandsWithPrefix(commandStatsPrefix) {
+		cs, parsingError := parseCommandStatsString(cmd, i[commandStatsPrefix+cmd])
+		if parsingError != nil {
+			warnings = append(warnings, parsingError)
+			continue
+		}
+		ms := buildCommandStatsPair(cs, t)
+		ms.MoveAndAppendTo(outMS)
+	}
+	for _, cmd := range i.commandsWithPrefix(latencyStatsPrefix) {
+		ls, parsingError := parseLatencyStatsString(cmd, i[latencyStatsPrefix+cmd])
+		if parsingError != nil {
+			warnings = append(warnings, parsingError)
+			continue
+		}
+		ms := buildLatencyStatsMetrics(ls, t)
+		ms.MoveAndAppendTo(outMS)
+	}
+	return outMS, warnings
+}
+
 func (i info) getUptimeInSeconds() (int, error) {
 	const uptimeKey = "uptime_in_seconds"
 	uptimeStr, ok := i[uptimeKey]
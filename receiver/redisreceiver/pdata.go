@@ -15,6 +15,8 @@
 package redisreceiver
 
 import (
+	"sort"
+
 	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
@@ -67,6 +69,59 @@ func initKeyspaceTTLMetric(k *keyspace, t *timeBundle, dest pdata.Metric) {
 	initIntMetric(m, int64(k.avgTTL), t, dest)
 }
 
+func buildCommandStatsPair(c *commandStats, t *timeBundle) pdata.MetricSlice {
+	ms := pdata.NewMetricSlice()
+	ms.Resize(2)
+	initCommandCallsMetric(c, t, ms.At(0))
+	initCommandUsecMetric(c, t, ms.At(1))
+	return ms
+}
+
+func initCommandCallsMetric(c *commandStats, t *timeBundle, dest pdata.Metric) {
+	m := &redisMetric{
+		name:        "redis/cmd/calls",
+		desc:        "Number of calls of the command since Redis server start",
+		labels:      map[string]string{"cmd": c.cmd},
+		pdType:      pdata.MetricDataTypeIntSum,
+		isMonotonic: true,
+	}
+	initIntMetric(m, c.calls, t, dest)
+}
+
+func initCommandUsecMetric(c *commandStats, t *timeBundle, dest pdata.Metric) {
+	m := &redisMetric{
+		name:        "redis/cmd/usec",
+		units:       "us",
+		desc:        "Total CPU time consumed by the command since Redis server start",
+		labels:      map[string]string{"cmd": c.cmd},
+		pdType:      pdata.MetricDataTypeIntSum,
+		isMonotonic: true,
+	}
+	initIntMetric(m, c.usec, t, dest)
+}
+
+func buildLatencyStatsMetrics(l *latencyStats, t *timeBundle) pdata.MetricSlice {
+	percentiles := make([]string, 0, len(l.percentiles))
+	for p := range l.percentiles {
+		percentiles = append(percentiles, p)
+	}
+	sort.Strings(percentiles)
+
+	ms := pdata.NewMetricSlice()
+	ms.Resize(len(percentiles))
+	for i, p := range percentiles {
+		m := &redisMetric{
+			name:   "redis/cmd/latency",
+			units:  "us",
+			desc:   "Latency percentile of the command",
+			labels: map[string]string{"cmd": l.cmd, "percentile": p},
+			pdType: pdata.MetricDataTypeDoubleGauge,
+		}
+		initDoubleMetric(m, l.percentiles[p], t, ms.At(i))
+	}
+	return ms
+}
+
 func initIntMetric(m *redisMetric, value int64, t *timeBundle, dest pdata.Metric) {
 	redisMetricToPDM(m, dest)
 
@@ -30,6 +30,7 @@ type redisReceiver struct {
 	config         *Config
 	consumer       consumer.Metrics
 	intervalRunner *interval.Runner
+	redisRunnable  *redisRunnable
 }
 
 func newRedisReceiver(
@@ -46,12 +47,43 @@ func newRedisReceiver(
 
 // Set up and kick off the interval runner.
 func (r *redisReceiver) Start(ctx context.Context, host component.Host) error {
-	c := newRedisClient(&redis.Options{
-		Addr:     r.config.Endpoint,
-		Password: r.config.Password,
-	})
-	redisRunnable := newRedisRunnable(ctx, r.config.ID(), c, r.config.ServiceName, r.consumer, r.logger)
-	r.intervalRunner = interval.NewRunner(r.config.CollectionInterval, redisRunnable)
+	tlsConfig, err := r.config.TLS.LoadTLSConfig()
+	if err != nil {
+		return err
+	}
+	newClient := func(addr string) client {
+		return newRedisClient(&redis.Options{
+			Addr:      addr,
+			Username:  r.config.Username,
+			Password:  r.config.Password,
+			TLSConfig: tlsConfig,
+		})
+	}
+
+	var d discoverer
+	switch r.config.Mode {
+	case modeCluster:
+		d = &clusterDiscoverer{seed: newClient(r.config.Endpoint)}
+	case modeSentinel:
+		endpoints := r.config.Sentinel.Endpoints
+		if len(endpoints) == 0 {
+			endpoints = []string{r.config.Endpoint}
+		}
+		sd := &sentinelDiscoverer{primaryName: r.config.Sentinel.PrimaryName}
+		for _, endpoint := range endpoints {
+			sd.sentinels = append(sd.sentinels, newRedisSentinelClient(&redis.Options{
+				Addr:      endpoint,
+				Password:  r.config.Sentinel.Password,
+				TLSConfig: tlsConfig,
+			}))
+		}
+		d = sd
+	default:
+		d = &standaloneDiscoverer{endpoint: r.config.Endpoint}
+	}
+
+	r.redisRunnable = newRedisRunnable(ctx, r.config.ID(), d, newClient, r.config.ServiceName, r.consumer, r.logger)
+	r.intervalRunner = interval.NewRunner(r.config.CollectionInterval, r.redisRunnable)
 
 	go func() {
 		if err := r.intervalRunner.Start(); err != nil {
@@ -63,6 +95,9 @@ func (r *redisReceiver) Start(ctx context.Context, host component.Host) error {
 }
 
 func (r *redisReceiver) Shutdown(ctx context.Context) error {
+	if r.intervalRunner == nil {
+		return nil
+	}
 	r.intervalRunner.Stop()
-	return nil
+	return r.redisRunnable.shutdown()
 }
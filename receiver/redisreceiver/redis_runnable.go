@@ -16,10 +16,12 @@ package redisreceiver
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.uber.org/zap"
@@ -29,23 +31,36 @@ import (
 
 var _ interval.Runnable = (*redisRunnable)(nil)
 
-// Runs intermittently, fetching info from Redis, creating metrics/datapoints,
-// and feeding them to a metricsConsumer.
+// Runs intermittently, discovering the Redis servers to scrape, fetching info
+// from each of them, creating metrics/datapoints, and feeding them to a
+// metricsConsumer.
 type redisRunnable struct {
 	id              config.ComponentID
 	ctx             context.Context
 	metricsConsumer consumer.Metrics
-	redisSvc        *redisSvc
-	redisMetrics    []*redisMetric
-	logger          *zap.Logger
-	timeBundle      *timeBundle
-	serviceName     string
+	discoverer      discoverer
+	newClient       func(addr string) client
+	// guards nodes and discoverer between Run and shutdown
+	mu           sync.Mutex
+	nodes        map[string]*nodeScraper
+	redisMetrics []*redisMetric
+	logger       *zap.Logger
+	serviceName  string
+}
+
+// Holds the per-server state: each server has its own uptime and thus its own
+// timeBundle.
+type nodeScraper struct {
+	client     client
+	redisSvc   *redisSvc
+	timeBundle *timeBundle
 }
 
 func newRedisRunnable(
 	ctx context.Context,
 	id config.ComponentID,
-	client client,
+	discoverer discoverer,
+	newClient func(addr string) client,
 	serviceName string,
 	metricsConsumer consumer.Metrics,
 	logger *zap.Logger,
@@ -54,7 +69,9 @@ func newRedisRunnable(
 		id:              id,
 		ctx:             ctx,
 		serviceName:     serviceName,
-		redisSvc:        newRedisSvc(client),
+		discoverer:      discoverer,
+		newClient:       newClient,
+		nodes:           map[string]*nodeScraper{},
 		metricsConsumer: metricsConsumer,
 		logger:          logger,
 	}
@@ -67,41 +84,92 @@ func (r *redisRunnable) Setup() error {
 	return nil
 }
 
-// Run is called periodically, querying Redis and building Metrics to send to
-// the next consumer. First builds 'fixed' metrics (non-keyspace metrics)
-// defined at startup time. Then builds 'keyspace' metrics if there are any
-// keyspace lines returned by Redis. There should be one keyspace line per
-// active Redis database, of which there can be 16.
+// Run is called periodically, discovering the Redis servers to scrape, then
+// querying each of them and building Metrics to send to the next consumer.
+// Servers that can't be queried are logged and skipped; the collection only
+// fails if none of them could be queried.
 func (r *redisRunnable) Run() error {
 	const dataFormat = "redis"
 	const transport = "http" // todo verify this
 	ctx := obsreport.StartMetricsReceiveOp(r.ctx, r.id, transport)
 
-	inf, err := r.redisSvc.info()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	nodes, err := r.discoverer.discover()
 	if err != nil {
 		obsreport.EndMetricsReceiveOp(ctx, dataFormat, 0, err)
 		return nil
 	}
+	r.closeStaleNodes(nodes)
+
+	pdm := pdata.NewMetrics()
+	var errs []error
+	for _, node := range nodes {
+		if err = r.scrapeNode(node, pdm.ResourceMetrics()); err != nil {
+			r.logger.Warn(
+				"failed to scrape redis server",
+				zap.String("address", node.addr),
+				zap.Error(err),
+			)
+			errs = append(errs, err)
+		}
+	}
+	if len(nodes) > 0 && len(errs) == len(nodes) {
+		obsreport.EndMetricsReceiveOp(ctx, dataFormat, 0, consumererror.Combine(errs))
+		return nil
+	}
+
+	err = r.metricsConsumer.ConsumeMetrics(r.ctx, pdm)
+	_, numPoints := pdm.MetricAndDataPointCount()
+	obsreport.EndMetricsReceiveOp(ctx, dataFormat, numPoints, err)
+
+	return nil
+}
+
+// Queries a single Redis server and appends its metrics to rms. First builds
+// 'fixed' metrics (non-keyspace metrics) defined at startup time. Then builds
+// 'keyspace' metrics if there are any keyspace lines returned by Redis. There
+// should be one keyspace line per active Redis database, of which there can be
+// 16. Finally builds per-command metrics from the commandstats and
+// latencystats lines.
+func (r *redisRunnable) scrapeNode(node redisNode, rms pdata.ResourceMetricsSlice) error {
+	ns, ok := r.nodes[node.addr]
+	if !ok {
+		c := r.newClient(node.addr)
+		ns = &nodeScraper{client: c, redisSvc: newRedisSvc(c)}
+		r.nodes[node.addr] = ns
+	}
+
+	inf, err := ns.redisSvc.info()
+	if err != nil {
+		return err
+	}
 
 	uptime, err := inf.getUptimeInSeconds()
 	if err != nil {
-		obsreport.EndMetricsReceiveOp(ctx, dataFormat, 0, err)
-		return nil
+		return err
 	}
 
-	if r.timeBundle == nil {
-		r.timeBundle = newTimeBundle(time.Now(), uptime)
+	if ns.timeBundle == nil {
+		ns.timeBundle = newTimeBundle(time.Now(), uptime)
 	} else {
-		r.timeBundle.update(time.Now(), uptime)
+		ns.timeBundle.update(time.Now(), uptime)
 	}
 
-	pdm := pdata.NewMetrics()
-	rm := pdm.ResourceMetrics().AppendEmpty()
+	rm := rms.AppendEmpty()
 	resource := rm.Resource()
 	rattrs := resource.Attributes()
 	rattrs.InsertString("service.name", r.serviceName)
+	if node.role != "" {
+		rattrs.InsertString("redis.node.address", node.addr)
+		rattrs.InsertString("redis.node.role", node.role)
+	}
+	if node.slotRange != "" {
+		rattrs.InsertString("redis.cluster.slot_range", node.slotRange)
+	}
 	ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
-	fixedMS, warnings := inf.buildFixedMetrics(r.redisMetrics, r.timeBundle)
+	fixedMS, warnings := inf.buildFixedMetrics(r.redisMetrics, ns.timeBundle)
 	fixedMS.MoveAndAppendTo(ilm.Metrics())
 	if warnings != nil {
 		r.logger.Warn(
@@ -110,7 +178,7 @@ func (r *redisRunnable) Run() error {
 		)
 	}
 
-	keyspaceMS, warnings := inf.buildKeyspaceMetrics(r.timeBundle)
+	keyspaceMS, warnings := inf.buildKeyspaceMetrics(ns.timeBundle)
 	if warnings != nil {
 		r.logger.Warn(
 			"errors parsing keyspace string",
@@ -119,9 +187,50 @@ func (r *redisRunnable) Run() error {
 	}
 	keyspaceMS.MoveAndAppendTo(ilm.Metrics())
 
-	err = r.metricsConsumer.ConsumeMetrics(r.ctx, pdm)
-	_, numPoints := pdm.MetricAndDataPointCount()
-	obsreport.EndMetricsReceiveOp(ctx, dataFormat, numPoints, err)
+	commandStatsMS, warnings := inf.buildCommandStatsMetrics(ns.timeBundle)
+	if warnings != nil {
+		r.logger.Warn(
+			"errors parsing commandstats string",
+			zap.Errors("parsing errors", warnings),
+		)
+	}
+	commandStatsMS.MoveAndAppendTo(ilm.Metrics())
 
 	return nil
 }
+
+// Closes the clients of the servers that are no longer part of the topology,
+// e.g. after a sentinel failover or a cluster node removal.
+func (r *redisRunnable) closeStaleNodes(nodes []redisNode) {
+	current := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		current[node.addr] = true
+	}
+	for addr, ns := range r.nodes {
+		if current[addr] {
+			continue
+		}
+		if err := ns.client.close(); err != nil {
+			r.logger.Debug("failed to close redis client", zap.String("address", addr), zap.Error(err))
+		}
+		delete(r.nodes, addr)
+	}
+}
+
+// Closes all of the clients, including the ones used for discovery.
+func (r *redisRunnable) shutdown() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	for addr, ns := range r.nodes {
+		if err := ns.client.close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(r.nodes, addr)
+	}
+	if err := r.discoverer.close(); err != nil {
+		errs = append(errs, err)
+	}
+	return consumererror.Combine(errs)
+}
@@ -27,11 +27,13 @@ import (
 func TestRedisRunnable(t *testing.T) {
 	consumer := new(consumertest.MetricsSink)
 	logger, _ := zap.NewDevelopment()
-	runner := newRedisRunnable(context.Background(), config.NewID(typeStr), newFakeClient(), "", consumer, logger)
+	runner := newRedisRunnable(context.Background(), config.NewID(typeStr), &standaloneDiscoverer{endpoint: "localhost:6379"}, newFakeClientFunc, "", consumer, logger)
 	err := runner.Setup()
 	require.Nil(t, err)
 	err = runner.Run()
 	require.Nil(t, err)
 	// + 6 because there are two keyspace entries each of which has three metrics
-	require.Equal(t, len(getDefaultRedisMetrics())+6, consumer.MetricsCount())
+	// + 7 because there are two commandstats entries each of which has two
+	// metrics, and one latencystats entry with three percentiles
+	require.Equal(t, len(getDefaultRedisMetrics())+6+7, consumer.MetricsCount())
 }
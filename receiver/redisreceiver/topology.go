@@ -0,0 +1,205 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+const (
+	// Values of the redis.node.role resource attribute.
+	rolePrimary = "primary"
+	roleReplica = "replica"
+)
+
+// A Redis server to scrape, along with its place in the topology. Role and
+// slotRange are empty for a standalone server.
+type redisNode struct {
+	addr      string
+	role      string
+	slotRange string
+}
+
+// Interface for finding the Redis servers to scrape. Called on every
+// collection so that changes of the topology are picked up.
+type discoverer interface {
+	discover() ([]redisNode, error)
+	// closes any connection used for discovery
+	close() error
+}
+
+// Returns the configured endpoint as the only server to scrape.
+type standaloneDiscoverer struct {
+	endpoint string
+}
+
+var _ discoverer = (*standaloneDiscoverer)(nil)
+
+func (d *standaloneDiscoverer) discover() ([]redisNode, error) {
+	return []redisNode{{addr: d.endpoint}}, nil
+}
+
+func (d *standaloneDiscoverer) close() error {
+	return nil
+}
+
+// Reads CLUSTER NODES from a seed node and returns every connected primary
+// and replica of the Redis Cluster.
+type clusterDiscoverer struct {
+	seed client
+}
+
+var _ discoverer = (*clusterDiscoverer)(nil)
+
+func (d *clusterDiscoverer) discover() ([]redisNode, error) {
+	str, err := d.seed.retrieveClusterNodes()
+	if err != nil {
+		return nil, err
+	}
+	return parseClusterNodes(str)
+}
+
+func (d *clusterDiscoverer) close() error {
+	return d.seed.close()
+}
+
+// Asks the sentinels, in order, for the current primary of primaryName and
+// returns it as the only server to scrape.
+type sentinelDiscoverer struct {
+	primaryName string
+	sentinels   []sentinelClient
+}
+
+var _ discoverer = (*sentinelDiscoverer)(nil)
+
+func (d *sentinelDiscoverer) discover() ([]redisNode, error) {
+	var errs []error
+	for _, sentinel := range d.sentinels {
+		addr, err := sentinel.retrievePrimaryAddr(d.primaryName)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return []redisNode{{addr: addr, role: rolePrimary}}, nil
+	}
+	if len(errs) == 0 {
+		return nil, errors.New("no sentinel configured")
+	}
+	return nil, consumererror.Combine(errs)
+}
+
+func (d *sentinelDiscoverer) close() error {
+	var errs []error
+	for _, sentinel := range d.sentinels {
+		if err := sentinel.close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return consumererror.Combine(errs)
+}
+
+// Holds the fields of a CLUSTER NODES line we care about:
+// <id> <ip:port@cport[,hostname]> <flags> <primary> <ping-sent> <pong-recv> <config-epoch> <link-state> <slot> ... <slot>
+type clusterNode struct {
+	id        string
+	addr      string
+	flags     map[string]bool
+	primaryID string
+	slots     []string
+}
+
+// Turns the output of CLUSTER NODES into the list of nodes to scrape, sorted
+// by address. Nodes without an address, failing or still in handshake are
+// skipped. Replicas report the slot range of their primary.
+func parseClusterNodes(str string) ([]redisNode, error) {
+	var clusterNodes []*clusterNode
+	byID := map[string]*clusterNode{}
+	for _, line := range strings.Split(str, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		cn, err := parseClusterNodeLine(line)
+		if err != nil {
+			return nil, err
+		}
+		clusterNodes = append(clusterNodes, cn)
+		byID[cn.id] = cn
+	}
+
+	var nodes []redisNode
+	for _, cn := range clusterNodes {
+		if cn.addr == "" || cn.flags["noaddr"] || cn.flags["fail"] || cn.flags["handshake"] {
+			continue
+		}
+		switch {
+		case cn.flags["master"]:
+			nodes = append(nodes, redisNode{
+				addr:      cn.addr,
+				role:      rolePrimary,
+				slotRange: strings.Join(cn.slots, ","),
+			})
+		case cn.flags["slave"]:
+			node := redisNode{addr: cn.addr, role: roleReplica}
+			if primary, ok := byID[cn.primaryID]; ok {
+				node.slotRange = strings.Join(primary.slots, ",")
+			}
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].addr < nodes[j].addr
+	})
+	return nodes, nil
+}
+
+func parseClusterNodeLine(line string) (*clusterNode, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 8 {
+		return nil, fmt.Errorf("unexpected cluster nodes line '%s'", line)
+	}
+	cn := &clusterNode{
+		id:        fields[0],
+		flags:     map[string]bool{},
+		primaryID: fields[3],
+	}
+
+	// ip:port@cport, optionally followed by ",hostname" since Redis 7.0
+	addr := fields[1]
+	if i := strings.IndexAny(addr, "@,"); i >= 0 {
+		addr = addr[:i]
+	}
+	if !strings.HasPrefix(addr, ":") {
+		cn.addr = addr
+	}
+
+	for _, flag := range strings.Split(fields[2], ",") {
+		cn.flags[flag] = true
+	}
+
+	for _, slot := range fields[8:] {
+		// slots being imported or migrated, e.g. "[93->-292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f]"
+		if strings.HasPrefix(slot, "[") {
+			continue
+		}
+		cn.slots = append(cn.slots, slot)
+	}
+	return cn, nil
+}
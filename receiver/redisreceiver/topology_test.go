@@ -0,0 +1,149 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+)
+
+func TestParseClusterNodes(t *testing.T) {
+	str, err := readFile("cluster_nodes")
+	require.NoError(t, err)
+	nodes, err := parseClusterNodes(str)
+	require.NoError(t, err)
+	assert.Equal(t, []redisNode{
+		{addr: "127.0.0.1:30001", role: rolePrimary, slotRange: "0-92,94-5460"},
+		{addr: "127.0.0.1:30002", role: rolePrimary, slotRange: "5461-10922"},
+		{addr: "127.0.0.1:30003", role: rolePrimary, slotRange: "10923-16383"},
+		{addr: "127.0.0.1:30004", role: roleReplica, slotRange: "0-92,94-5460"},
+		{addr: "127.0.0.1:30005", role: roleReplica, slotRange: "5461-10922"},
+	}, nodes)
+}
+
+func TestParseMalformedClusterNodes(t *testing.T) {
+	_, err := parseClusterNodes("07c37dfeb235213a872192d90877d0cd55635b91 127.0.0.1:30004@31004 slave")
+	require.Error(t, err)
+}
+
+type fakeSentinelClient struct {
+	addr   string
+	err    error
+	closed bool
+}
+
+func (c *fakeSentinelClient) retrievePrimaryAddr(string) (string, error) {
+	return c.addr, c.err
+}
+
+func (c *fakeSentinelClient) close() error {
+	c.closed = true
+	return nil
+}
+
+func TestSentinelDiscoverer(t *testing.T) {
+	down := &fakeSentinelClient{err: errors.New("connection refused")}
+	up := &fakeSentinelClient{addr: "10.0.0.2:6379"}
+	d := &sentinelDiscoverer{primaryName: "mymaster", sentinels: []sentinelClient{down, up}}
+
+	nodes, err := d.discover()
+	require.NoError(t, err)
+	assert.Equal(t, []redisNode{{addr: "10.0.0.2:6379", role: rolePrimary}}, nodes)
+
+	require.NoError(t, d.close())
+	assert.True(t, down.closed)
+	assert.True(t, up.closed)
+
+	d = &sentinelDiscoverer{primaryName: "mymaster", sentinels: []sentinelClient{down}}
+	_, err = d.discover()
+	require.EqualError(t, err, "connection refused")
+}
+
+// A fake discoverer returning a fixed set of nodes.
+type fakeDiscoverer struct {
+	nodes []redisNode
+}
+
+func (d *fakeDiscoverer) discover() ([]redisNode, error) {
+	return d.nodes, nil
+}
+
+func (d *fakeDiscoverer) close() error {
+	return nil
+}
+
+// A fake client recording whether it was closed.
+type closeRecordingClient struct {
+	fakeClient
+	closed bool
+}
+
+func (c *closeRecordingClient) close() error {
+	c.closed = true
+	return nil
+}
+
+func TestRedisRunnableCluster(t *testing.T) {
+	consumer := new(consumertest.MetricsSink)
+	d := &clusterDiscoverer{seed: newFakeClient()}
+	runner := newRedisRunnable(context.Background(), config.NewID(typeStr), d, newFakeClientFunc, "my-cluster", consumer, zap.NewNop())
+	require.NoError(t, runner.Setup())
+	require.NoError(t, runner.Run())
+
+	require.Equal(t, 1, len(consumer.AllMetrics()))
+	rms := consumer.AllMetrics()[0].ResourceMetrics()
+	require.Equal(t, 5, rms.Len())
+
+	attrs := rms.At(3).Resource().Attributes()
+	addr, _ := attrs.Get("redis.node.address")
+	assert.Equal(t, "127.0.0.1:30004", addr.StringVal())
+	role, _ := attrs.Get("redis.node.role")
+	assert.Equal(t, roleReplica, role.StringVal())
+	slotRange, _ := attrs.Get("redis.cluster.slot_range")
+	assert.Equal(t, "0-92,94-5460", slotRange.StringVal())
+	serviceName, _ := attrs.Get("service.name")
+	assert.Equal(t, "my-cluster", serviceName.StringVal())
+}
+
+func TestRedisRunnableClosesStaleNodes(t *testing.T) {
+	clients := map[string]*closeRecordingClient{}
+	newClient := func(addr string) client {
+		c := &closeRecordingClient{}
+		clients[addr] = c
+		return c
+	}
+	d := &fakeDiscoverer{nodes: []redisNode{{addr: "10.0.0.1:6379", role: rolePrimary}}}
+	consumer := new(consumertest.MetricsSink)
+	runner := newRedisRunnable(context.Background(), config.NewID(typeStr), d, newClient, "", consumer, zap.NewNop())
+	require.NoError(t, runner.Setup())
+	require.NoError(t, runner.Run())
+
+	// failover to another primary
+	d.nodes = []redisNode{{addr: "10.0.0.2:6379", role: rolePrimary}}
+	require.NoError(t, runner.Run())
+	assert.True(t, clients["10.0.0.1:6379"].closed)
+	assert.False(t, clients["10.0.0.2:6379"].closed)
+	assert.Equal(t, 2, len(consumer.AllMetrics()))
+
+	require.NoError(t, runner.shutdown())
+	assert.True(t, clients["10.0.0.2:6379"].closed)
+}
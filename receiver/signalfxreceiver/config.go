@@ -15,6 +15,8 @@
 package signalfxreceiver
 
 import (
+	"fmt"
+
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/confighttp"
 
@@ -27,4 +29,21 @@ type Config struct {
 	confighttp.HTTPServerSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
 
 	splunk.AccessTokenPassthroughConfig `mapstructure:",squash"`
+
+	// CacheDimensionProperties indicates whether to cache the properties
+	// received on the dimension update endpoint and add them as resource
+	// attributes to datapoints carrying the matching dimension.
+	CacheDimensionProperties bool `mapstructure:"cache_dimension_properties"`
+
+	// DimensionCacheSize is the maximum number of dimensions whose properties
+	// are cached, the least recently updated dimension is evicted first.
+	DimensionCacheSize int `mapstructure:"dimension_cache_size"`
+}
+
+// Validate checks if the receiver configuration is valid.
+func (c *Config) Validate() error {
+	if c.CacheDimensionProperties && c.DimensionCacheSize <= 0 {
+		return fmt.Errorf("dimension_cache_size must be positive when cache_dimension_properties is enabled, got %d", c.DimensionCacheSize)
+	}
+	return nil
 }
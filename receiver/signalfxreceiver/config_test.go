@@ -57,6 +57,8 @@ func TestLoadConfig(t *testing.T) {
 			AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{
 				AccessTokenPassthrough: true,
 			},
+			CacheDimensionProperties: true,
+			DimensionCacheSize:       5000,
 		})
 
 	r2 := cfg.Receivers[config.NewIDWithName(typeStr, "tls")].(*Config)
@@ -75,5 +77,19 @@ func TestLoadConfig(t *testing.T) {
 			AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{
 				AccessTokenPassthrough: false,
 			},
+			DimensionCacheSize: defaultDimensionCacheSize,
 		})
 }
+
+func TestValidateConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.DimensionCacheSize = 0
+	// The size is only used when caching dimension properties.
+	assert.NoError(t, cfg.Validate())
+
+	cfg.CacheDimensionProperties = true
+	assert.Error(t, cfg.Validate())
+
+	cfg.DimensionCacheSize = 1
+	assert.NoError(t, cfg.Validate())
+}
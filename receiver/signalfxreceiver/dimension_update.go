@@ -0,0 +1,264 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"container/list"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const (
+	// Log record name and attributes used to represent dimension updates.
+	dimensionUpdateLogName         = "com.splunk.signalfx.dimension_update"
+	dimensionUpdateKeyKey          = "com.splunk.signalfx.dimension.key"
+	dimensionUpdateValueKey        = "com.splunk.signalfx.dimension.value"
+	dimensionUpdatePropertiesKey   = "com.splunk.signalfx.dimension.properties"
+	dimensionUpdateTagsKey         = "com.splunk.signalfx.dimension.tags"
+	dimensionUpdateTagsToRemoveKey = "com.splunk.signalfx.dimension.tags_to_remove"
+)
+
+// dimensionUpdate is the body of a request to the dimension endpoint. A PUT
+// replaces all properties and tags of the dimension while a PATCH only
+// changes the ones present, a nil property value removing the property.
+type dimensionUpdate struct {
+	Key              string             `json:"key"`
+	Value            string             `json:"value"`
+	CustomProperties map[string]*string `json:"customProperties"`
+	Tags             []string           `json:"tags"`
+	TagsToRemove     []string           `json:"tagsToRemove"`
+
+	replace bool
+}
+
+// parseDimensionUpdate unmarshals the JSON body of a dimension update. The
+// dimension key and value are taken from the request path when present.
+func parseDimensionUpdate(req *http.Request, key, value string, body []byte) (*dimensionUpdate, error) {
+	update := &dimensionUpdate{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, update); err != nil {
+			return nil, err
+		}
+	}
+	if key != "" {
+		update.Key = key
+	}
+	if value != "" {
+		update.Value = value
+	}
+	update.replace = req.Method == http.MethodPut
+	return update, nil
+}
+
+// fillLogRecord represents the dimension update as a log record.
+func (u *dimensionUpdate) fillLogRecord(lr pdata.LogRecord) {
+	lr.SetName(dimensionUpdateLogName)
+
+	attrs := lr.Attributes()
+	attrs.InsertString(dimensionUpdateKeyKey, u.Key)
+	attrs.InsertString(dimensionUpdateValueKey, u.Value)
+
+	if len(u.CustomProperties) > 0 {
+		propMapVal := pdata.NewAttributeValueMap()
+		propMap := propMapVal.MapVal()
+		propMap.EnsureCapacity(len(u.CustomProperties))
+		for k, v := range u.CustomProperties {
+			if v == nil {
+				// A null value removes the property.
+				propMap.InsertNull(k)
+				continue
+			}
+			propMap.InsertString(k, *v)
+		}
+		attrs.Insert(dimensionUpdatePropertiesKey, propMapVal)
+	}
+
+	if len(u.Tags) > 0 {
+		attrs.Insert(dimensionUpdateTagsKey, stringsToAttributeArray(u.Tags))
+	}
+	if len(u.TagsToRemove) > 0 {
+		attrs.Insert(dimensionUpdateTagsToRemoveKey, stringsToAttributeArray(u.TagsToRemove))
+	}
+}
+
+func stringsToAttributeArray(values []string) pdata.AttributeValue {
+	arrVal := pdata.NewAttributeValueArray()
+	arr := arrVal.ArrayVal()
+	for _, v := range values {
+		arr.AppendEmpty().SetStringVal(v)
+	}
+	return arrVal
+}
+
+type dimensionKey struct {
+	key   string
+	value string
+}
+
+// dimensionCache keeps the latest properties received for each dimension so
+// they can be added as resource attributes to datapoints with that dimension.
+// At most maxSize dimensions are kept, the least recently updated one is
+// evicted when a new dimension is added to a full cache.
+type dimensionCache struct {
+	sync.RWMutex
+	maxSize    int
+	properties map[dimensionKey]map[string]string
+	// updated orders the cached dimension keys from the most to the least
+	// recently updated, elements indexes them by key.
+	updated  *list.List
+	elements map[dimensionKey]*list.Element
+}
+
+func newDimensionCache(maxSize int) *dimensionCache {
+	return &dimensionCache{
+		maxSize:    maxSize,
+		properties: map[dimensionKey]map[string]string{},
+		updated:    list.New(),
+		elements:   map[dimensionKey]*list.Element{},
+	}
+}
+
+// update applies a dimension update to the cached properties.
+func (c *dimensionCache) update(u *dimensionUpdate) {
+	c.Lock()
+	defer c.Unlock()
+
+	dk := dimensionKey{key: u.Key, value: u.Value}
+	props := c.properties[dk]
+	if props == nil || u.replace {
+		props = map[string]string{}
+	}
+	for k, v := range u.CustomProperties {
+		if v == nil {
+			delete(props, k)
+			continue
+		}
+		props[k] = *v
+	}
+
+	if len(props) == 0 {
+		c.remove(dk)
+		return
+	}
+	c.properties[dk] = props
+	if e, ok := c.elements[dk]; ok {
+		c.updated.MoveToFront(e)
+		return
+	}
+	c.elements[dk] = c.updated.PushFront(dk)
+	if c.updated.Len() > c.maxSize {
+		c.remove(c.updated.Back().Value.(dimensionKey))
+	}
+}
+
+func (c *dimensionCache) remove(dk dimensionKey) {
+	delete(c.properties, dk)
+	if e, ok := c.elements[dk]; ok {
+		c.updated.Remove(e)
+		delete(c.elements, dk)
+	}
+}
+
+// apply moves every metric with a datapoint carrying a cached dimension into
+// a resource whose attributes are the cached properties of that dimension.
+// Metrics without cached dimensions are left in the original resource.
+func (c *dimensionCache) apply(md pdata.Metrics) pdata.Metrics {
+	c.RLock()
+	defer c.RUnlock()
+
+	if len(c.properties) == 0 {
+		return md
+	}
+
+	out := pdata.NewMetrics()
+	groups := map[string]pdata.MetricSlice{}
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				m := metrics.At(k)
+				groupKey, props := c.lookup(metricLabels(m))
+				dest, ok := groups[groupKey]
+				if !ok {
+					destRm := out.ResourceMetrics().AppendEmpty()
+					rm.Resource().CopyTo(destRm.Resource())
+					for pk, pv := range props {
+						destRm.Resource().Attributes().UpsertString(pk, pv)
+					}
+					dest = destRm.InstrumentationLibraryMetrics().AppendEmpty().Metrics()
+					groups[groupKey] = dest
+				}
+				m.CopyTo(dest.AppendEmpty())
+			}
+		}
+	}
+	return out
+}
+
+// lookup returns the merged cached properties of all the given labels along
+// with a key identifying the set of matched dimensions.
+func (c *dimensionCache) lookup(labels pdata.StringMap) (string, map[string]string) {
+	var matched []string
+	var props map[string]string
+	labels.Range(func(k string, v string) bool {
+		cached, ok := c.properties[dimensionKey{key: k, value: v}]
+		if !ok {
+			return true
+		}
+		if props == nil {
+			props = map[string]string{}
+		}
+		for pk, pv := range cached {
+			props[pk] = pv
+		}
+		matched = append(matched, k+"="+v)
+		return true
+	})
+	sort.Strings(matched)
+	return strings.Join(matched, ","), props
+}
+
+// metricLabels returns the labels of the first datapoint of the metric. The
+// SignalFx conversion produces a single datapoint per metric.
+func metricLabels(m pdata.Metric) pdata.StringMap {
+	switch m.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		if m.IntGauge().DataPoints().Len() > 0 {
+			return m.IntGauge().DataPoints().At(0).LabelsMap()
+		}
+	case pdata.MetricDataTypeIntSum:
+		if m.IntSum().DataPoints().Len() > 0 {
+			return m.IntSum().DataPoints().At(0).LabelsMap()
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		if m.DoubleGauge().DataPoints().Len() > 0 {
+			return m.DoubleGauge().DataPoints().At(0).LabelsMap()
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		if m.DoubleSum().DataPoints().Len() > 0 {
+			return m.DoubleSum().DataPoints().At(0).LabelsMap()
+		}
+	}
+	return pdata.NewStringMap()
+}
@@ -0,0 +1,131 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func Test_parseDimensionUpdate(t *testing.T) {
+	body := []byte(`{"customProperties": {"team": "a", "old": null}, "tags": ["t1"], "tagsToRemove": ["t2"]}`)
+	req := httptest.NewRequest("PATCH", "http://localhost/v2/dimension/host/h1/_/sfxagent", bytes.NewReader(body))
+
+	update, err := parseDimensionUpdate(req, "host", "h1", body)
+	require.NoError(t, err)
+	assert.Equal(t, "host", update.Key)
+	assert.Equal(t, "h1", update.Value)
+	assert.False(t, update.replace)
+	assert.Equal(t, map[string]*string{"team": strPtr("a"), "old": nil}, update.CustomProperties)
+
+	lr := pdata.NewLogRecord()
+	update.fillLogRecord(lr)
+	assert.Equal(t, dimensionUpdateLogName, lr.Name())
+
+	attrs := lr.Attributes()
+	key, _ := attrs.Get(dimensionUpdateKeyKey)
+	assert.Equal(t, "host", key.StringVal())
+	value, _ := attrs.Get(dimensionUpdateValueKey)
+	assert.Equal(t, "h1", value.StringVal())
+
+	props, ok := attrs.Get(dimensionUpdatePropertiesKey)
+	require.True(t, ok)
+	team, _ := props.MapVal().Get("team")
+	assert.Equal(t, "a", team.StringVal())
+	old, _ := props.MapVal().Get("old")
+	assert.Equal(t, pdata.AttributeValueTypeNull, old.Type())
+
+	tags, ok := attrs.Get(dimensionUpdateTagsKey)
+	require.True(t, ok)
+	require.Equal(t, 1, tags.ArrayVal().Len())
+	assert.Equal(t, "t1", tags.ArrayVal().At(0).StringVal())
+
+	_, err = parseDimensionUpdate(req, "host", "h1", []byte("{"))
+	assert.Error(t, err)
+}
+
+func Test_dimensionCache(t *testing.T) {
+	cache := newDimensionCache(defaultDimensionCacheSize)
+	cache.update(&dimensionUpdate{
+		Key:              "k0",
+		Value:            "v0",
+		CustomProperties: map[string]*string{"team": strPtr("a"), "env": strPtr("prod")},
+	})
+	cache.update(&dimensionUpdate{
+		Key:              "k0",
+		Value:            "v0",
+		CustomProperties: map[string]*string{"env": nil},
+	})
+
+	md, _ := signalFxV2ToMetrics(zap.NewNop(), []*sfxpb.DataPoint{
+		{
+			Metric:     "with_cached_dim",
+			Value:      sfxpb.Datum{IntValue: int64Ptr(1)},
+			Dimensions: buildNDimensions(2),
+		},
+		{
+			Metric: "without_cached_dim",
+			Value:  sfxpb.Datum{IntValue: int64Ptr(2)},
+		},
+	})
+
+	got := cache.apply(md)
+	require.Equal(t, 2, got.ResourceMetrics().Len())
+
+	rm0 := got.ResourceMetrics().At(0)
+	assert.Equal(t, 1, rm0.Resource().Attributes().Len())
+	team, _ := rm0.Resource().Attributes().Get("team")
+	assert.Equal(t, "a", team.StringVal())
+	assert.Equal(t, "with_cached_dim", rm0.InstrumentationLibraryMetrics().At(0).Metrics().At(0).Name())
+
+	rm1 := got.ResourceMetrics().At(1)
+	assert.Equal(t, 0, rm1.Resource().Attributes().Len())
+	assert.Equal(t, "without_cached_dim", rm1.InstrumentationLibraryMetrics().At(0).Metrics().At(0).Name())
+
+	// A PUT replaces all cached properties.
+	cache.update(&dimensionUpdate{Key: "k0", Value: "v0", replace: true})
+	assert.Empty(t, cache.properties)
+	assert.Zero(t, cache.updated.Len())
+	assert.Equal(t, md, cache.apply(md))
+}
+
+func Test_dimensionCacheEviction(t *testing.T) {
+	cache := newDimensionCache(2)
+	update := func(value string) {
+		cache.update(&dimensionUpdate{
+			Key:              "host",
+			Value:            value,
+			CustomProperties: map[string]*string{"team": strPtr(value)},
+		})
+	}
+	update("h0")
+	update("h1")
+	// Updating h0 again makes h1 the least recently updated dimension.
+	update("h0")
+	update("h2")
+
+	assert.Len(t, cache.properties, 2)
+	assert.Contains(t, cache.properties, dimensionKey{key: "host", value: "h0"})
+	assert.Contains(t, cache.properties, dimensionKey{key: "host", value: "h2"})
+	assert.Equal(t, 2, cache.updated.Len())
+	assert.Len(t, cache.elements, 2)
+}
@@ -36,6 +36,8 @@ const (
 
 	// Default endpoints to bind to.
 	defaultEndpoint = ":9943"
+
+	defaultDimensionCacheSize = 10000
 )
 
 // NewFactory creates a factory for SignalFx receiver.
@@ -44,7 +46,8 @@ func NewFactory() component.ReceiverFactory {
 		typeStr,
 		createDefaultConfig,
 		receiverhelper.WithMetrics(createMetricsReceiver),
-		receiverhelper.WithLogs(createLogsReceiver))
+		receiverhelper.WithLogs(createLogsReceiver),
+		receiverhelper.WithTraces(createTracesReceiver))
 }
 
 func createDefaultConfig() config.Receiver {
@@ -53,6 +56,7 @@ func createDefaultConfig() config.Receiver {
 		HTTPServerSettings: confighttp.HTTPServerSettings{
 			Endpoint: defaultEndpoint,
 		},
+		DimensionCacheSize: defaultDimensionCacheSize,
 	}
 }
 
@@ -140,5 +144,32 @@ func createLogsReceiver(
 	return r, nil
 }
 
+// createTracesReceiver creates a traces receiver based on provided config.
+func createTracesReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	cfg config.Receiver,
+	consumer consumer.Traces,
+) (component.TracesReceiver, error) {
+	rCfg := cfg.(*Config)
+
+	err := rCfg.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	receiverLock.Lock()
+	r := receivers[rCfg]
+	if r == nil {
+		r = newReceiver(params.Logger, *rCfg)
+		receivers[rCfg] = r
+	}
+	receiverLock.Unlock()
+
+	r.RegisterTracesConsumer(consumer)
+
+	return r, nil
+}
+
 var receiverLock sync.Mutex
 var receivers = map[*Config]*sfxReceiver{}
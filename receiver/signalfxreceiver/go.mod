@@ -4,9 +4,12 @@ go 1.15
 
 require (
 	github.com/gorilla/mux v1.8.0
+	github.com/jaegertracing/jaeger v1.22.0
 	github.com/open-telemetry/opentelemetry-collector-contrib/exporter/signalfxexporter v0.0.0-00010101000000-000000000000
 	github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk v0.0.0-00010101000000-000000000000
+	github.com/openzipkin/zipkin-go v0.2.5
 	github.com/signalfx/com_signalfx_metrics_protobuf v0.0.2
+	github.com/signalfx/sapm-proto v0.7.0
 	github.com/stretchr/testify v1.7.0
 	go.opencensus.io v0.23.0
 	go.opentelemetry.io/collector v0.27.1-0.20210524201935-86ea0a131fb2
@@ -22,12 +22,16 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 	"unsafe"
 
 	"github.com/gorilla/mux"
+	zipkinmodel "github.com/openzipkin/zipkin-go/model"
 	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	splunksapm "github.com/signalfx/sapm-proto/gen"
+	"github.com/signalfx/sapm-proto/sapmprotocol"
 	"go.opencensus.io/trace"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
@@ -35,6 +39,8 @@ import (
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/translator/conventions"
+	jaegertranslator "go.opentelemetry.io/collector/translator/trace/jaeger"
+	zipkintranslator "go.opentelemetry.io/collector/translator/trace/zipkin"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
@@ -45,7 +51,10 @@ const (
 
 	responseOK                      = "OK"
 	responseInvalidMethod           = "Only \"POST\" method is supported"
+	responseInvalidDimensionMethod  = "Only \"PUT\" and \"PATCH\" methods are supported"
 	responseInvalidContentType      = "\"Content-Type\" must be \"application/x-protobuf\""
+	responseInvalidV1ContentType    = "\"Content-Type\" must be \"application/x-protobuf\" or \"application/json\""
+	responseInvalidJSONContentType  = "\"Content-Type\" must be \"application/json\""
 	responseInvalidEncoding         = "\"Content-Encoding\" must be \"gzip\" or empty"
 	responseErrGzipReader           = "Error on gzip body"
 	responseErrReadBody             = "Failed to read message body"
@@ -53,9 +62,12 @@ const (
 	responseErrNextConsumer         = "Internal Server Error"
 	responseErrLogsNotConfigured    = "Log pipeline has not been configured to handle events"
 	responseErrMetricsNotConfigured = "Metric pipeline has not been configured to handle datapoints"
+	responseErrTracesNotConfigured  = "Trace pipeline has not been configured to handle spans"
+	responseErrDimensionsDisabled   = "Log pipeline or dimension properties cache must be configured to handle dimension updates"
 
 	// Centralizing some HTTP and related string constants.
 	protobufContentType       = "application/x-protobuf"
+	jsonContentType           = "application/json"
 	gzipEncoding              = "gzip"
 	httpContentTypeHeader     = "Content-Type"
 	httpContentEncodingHeader = "Content-Encoding"
@@ -66,7 +78,10 @@ var (
 
 	okRespBody               = initJSONResponse(responseOK)
 	invalidMethodRespBody    = initJSONResponse(responseInvalidMethod)
+	invalidDimMethodRespBody = initJSONResponse(responseInvalidDimensionMethod)
 	invalidContentRespBody   = initJSONResponse(responseInvalidContentType)
+	invalidV1ContentRespBody = initJSONResponse(responseInvalidV1ContentType)
+	invalidJSONContentBody   = initJSONResponse(responseInvalidJSONContentType)
 	invalidEncodingRespBody  = initJSONResponse(responseInvalidEncoding)
 	errGzipReaderRespBody    = initJSONResponse(responseErrGzipReader)
 	errReadBodyRespBody      = initJSONResponse(responseErrReadBody)
@@ -74,8 +89,44 @@ var (
 	errNextConsumerRespBody  = initJSONResponse(responseErrNextConsumer)
 	errLogsNotConfigured     = initJSONResponse(responseErrLogsNotConfigured)
 	errMetricsNotConfigured  = initJSONResponse(responseErrMetricsNotConfigured)
+	errTracesNotConfigured   = initJSONResponse(responseErrTracesNotConfigured)
+	errDimensionsDisabled    = initJSONResponse(responseErrDimensionsDisabled)
+
+	protobufRequestFormat = requestFormat{
+		methods:            []string{http.MethodPost},
+		contentTypes:       []string{protobufContentType},
+		invalidMethodResp:  invalidMethodRespBody,
+		invalidContentResp: invalidContentRespBody,
+	}
+	v1DatapointRequestFormat = requestFormat{
+		methods:            []string{http.MethodPost},
+		contentTypes:       []string{protobufContentType, jsonContentType},
+		invalidMethodResp:  invalidMethodRespBody,
+		invalidContentResp: invalidV1ContentRespBody,
+	}
+	zipkinRequestFormat = requestFormat{
+		methods:            []string{http.MethodPost},
+		contentTypes:       []string{jsonContentType},
+		invalidMethodResp:  invalidMethodRespBody,
+		invalidContentResp: invalidJSONContentBody,
+	}
+	dimensionRequestFormat = requestFormat{
+		methods:            []string{http.MethodPut, http.MethodPatch},
+		contentTypes:       []string{jsonContentType},
+		invalidMethodResp:  invalidDimMethodRespBody,
+		invalidContentResp: invalidJSONContentBody,
+	}
 )
 
+// requestFormat holds the methods and content types accepted by an endpoint
+// along with the responses sent when a request doesn't match them.
+type requestFormat struct {
+	methods            []string
+	contentTypes       []string
+	invalidMethodResp  []byte
+	invalidContentResp []byte
+}
+
 // sfxReceiver implements the component.MetricsReceiver for SignalFx metric protocol.
 type sfxReceiver struct {
 	sync.Mutex
@@ -83,10 +134,18 @@ type sfxReceiver struct {
 	config          *Config
 	metricsConsumer consumer.Metrics
 	logsConsumer    consumer.Logs
+	tracesConsumer  consumer.Traces
 	server          *http.Server
+	dimensionCache  *dimensionCache
+
+	// sapmResponse is the marshaled empty SAPM response returned to
+	// successful SAPM requests.
+	sapmResponse []byte
 }
 
 var _ component.MetricsReceiver = (*sfxReceiver)(nil)
+var _ component.LogsReceiver = (*sfxReceiver)(nil)
+var _ component.TracesReceiver = (*sfxReceiver)(nil)
 
 // New creates the SignalFx receiver with the given configuration.
 func newReceiver(
@@ -97,6 +156,11 @@ func newReceiver(
 		logger: logger,
 		config: &config,
 	}
+	if config.CacheDimensionProperties {
+		r.dimensionCache = newDimensionCache(config.DimensionCacheSize)
+	}
+	// An empty message always marshals successfully.
+	r.sapmResponse, _ = (&splunksapm.PostSpansResponse{}).Marshal()
 
 	return r
 }
@@ -115,6 +179,13 @@ func (r *sfxReceiver) RegisterLogsConsumer(lc consumer.Logs) {
 	r.logsConsumer = lc
 }
 
+func (r *sfxReceiver) RegisterTracesConsumer(tc consumer.Traces) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.tracesConsumer = tc
+}
+
 // Start tells the receiver to start its processing.
 // By convention the consumer of the received data is set when the receiver
 // instance is created.
@@ -122,7 +193,7 @@ func (r *sfxReceiver) Start(_ context.Context, host component.Host) error {
 	r.Lock()
 	defer r.Unlock()
 
-	if r.metricsConsumer == nil && r.logsConsumer == nil {
+	if r.metricsConsumer == nil && r.logsConsumer == nil && r.tracesConsumer == nil {
 		return componenterror.ErrNilNextConsumer
 	}
 
@@ -133,8 +204,15 @@ func (r *sfxReceiver) Start(_ context.Context, host component.Host) error {
 	}
 
 	mx := mux.NewRouter()
+	mx.HandleFunc("/v1/datapoint", r.handleV1DatapointReq)
 	mx.HandleFunc("/v2/datapoint", r.handleDatapointReq)
+	mx.HandleFunc("/v1/event", r.handleEventReq)
 	mx.HandleFunc("/v2/event", r.handleEventReq)
+	mx.HandleFunc("/v1/trace", r.handleZipkinTraceReq)
+	mx.HandleFunc(sapmprotocol.TraceEndpointV2, r.handleSAPMTraceReq)
+	mx.HandleFunc("/v2/dimension", r.handleDimensionReq)
+	mx.HandleFunc("/v2/dimension/{key}/{value}", r.handleDimensionReq)
+	mx.HandleFunc("/v2/dimension/{key}/{value}/_/sfxagent", r.handleDimensionReq)
 
 	r.server = r.config.HTTPServerSettings.ToServer(mx)
 
@@ -160,21 +238,29 @@ func (r *sfxReceiver) Shutdown(context.Context) error {
 	return r.server.Close()
 }
 
-func (r *sfxReceiver) readBody(ctx context.Context, resp http.ResponseWriter, req *http.Request) ([]byte, bool) {
-	if req.Method != http.MethodPost {
-		r.failRequest(ctx, resp, http.StatusBadRequest, invalidMethodRespBody, nil)
-		return nil, false
+// readBody validates the request against the given format and returns its
+// decompressed body along with the content type it was sent with.
+func (r *sfxReceiver) readBody(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	req *http.Request,
+	format requestFormat,
+) ([]byte, string, bool) {
+	if !containsString(format.methods, req.Method) {
+		r.failRequest(ctx, resp, http.StatusBadRequest, format.invalidMethodResp, nil)
+		return nil, "", false
 	}
 
-	if req.Header.Get(httpContentTypeHeader) != protobufContentType {
-		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidContentRespBody, nil)
-		return nil, false
+	contentType := mediaType(req.Header.Get(httpContentTypeHeader))
+	if !containsString(format.contentTypes, contentType) {
+		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, format.invalidContentResp, nil)
+		return nil, "", false
 	}
 
 	encoding := req.Header.Get(httpContentEncodingHeader)
 	if encoding != "" && encoding != gzipEncoding {
 		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidEncodingRespBody, nil)
-		return nil, false
+		return nil, "", false
 	}
 
 	bodyReader := req.Body
@@ -183,16 +269,23 @@ func (r *sfxReceiver) readBody(ctx context.Context, resp http.ResponseWriter, re
 		bodyReader, err = gzip.NewReader(bodyReader)
 		if err != nil {
 			r.failRequest(ctx, resp, http.StatusBadRequest, errGzipReaderRespBody, err)
-			return nil, false
+			return nil, "", false
 		}
 	}
 
 	body, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
 		r.failRequest(ctx, resp, http.StatusBadRequest, errReadBodyRespBody, err)
-		return nil, false
+		return nil, "", false
 	}
-	return body, true
+	return body, contentType, true
+}
+
+func (r *sfxReceiver) transport() string {
+	if r.config.TLSSetting != nil {
+		return "https"
+	}
+	return "http"
 }
 
 func (r *sfxReceiver) writeResponse(ctx context.Context, resp http.ResponseWriter, err error) {
@@ -206,11 +299,7 @@ func (r *sfxReceiver) writeResponse(ctx context.Context, resp http.ResponseWrite
 }
 
 func (r *sfxReceiver) handleDatapointReq(resp http.ResponseWriter, req *http.Request) {
-	transport := "http"
-	if r.config.TLSSetting != nil {
-		transport = "https"
-	}
-
+	transport := r.transport()
 	ctx := obsreport.ReceiverContext(req.Context(), r.config.ID(), transport)
 	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.ID(), transport)
 
@@ -219,7 +308,7 @@ func (r *sfxReceiver) handleDatapointReq(resp http.ResponseWriter, req *http.Req
 		return
 	}
 
-	body, ok := r.readBody(ctx, resp, req)
+	body, _, ok := r.readBody(ctx, resp, req, protobufRequestFormat)
 	if !ok {
 		return
 	}
@@ -230,13 +319,58 @@ func (r *sfxReceiver) handleDatapointReq(resp http.ResponseWriter, req *http.Req
 		return
 	}
 
-	if len(msg.Datapoints) == 0 {
+	r.consumeDatapoints(ctx, resp, req, msg.Datapoints)
+}
+
+// handleV1DatapointReq handles datapoints sent in the legacy v1 format, either
+// as varint length prefixed protobuf data points or as JSON objects.
+func (r *sfxReceiver) handleV1DatapointReq(resp http.ResponseWriter, req *http.Request) {
+	transport := r.transport()
+	ctx := obsreport.ReceiverContext(req.Context(), r.config.ID(), transport)
+	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.ID(), transport)
+
+	if r.metricsConsumer == nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errMetricsNotConfigured, nil)
+		return
+	}
+
+	body, contentType, ok := r.readBody(ctx, resp, req, v1DatapointRequestFormat)
+	if !ok {
+		return
+	}
+
+	var sfxDataPoints []*sfxpb.DataPoint
+	var err error
+	if contentType == jsonContentType {
+		sfxDataPoints, err = signalFxV1JSONToDatapoints(body)
+	} else {
+		sfxDataPoints, err = signalFxV1ProtobufToDatapoints(body)
+	}
+	if err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+		return
+	}
+
+	r.consumeDatapoints(ctx, resp, req, sfxDataPoints)
+}
+
+func (r *sfxReceiver) consumeDatapoints(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	req *http.Request,
+	sfxDataPoints []*sfxpb.DataPoint,
+) {
+	if len(sfxDataPoints) == 0 {
 		obsreport.EndMetricsReceiveOp(ctx, typeStr, 0, nil)
 		resp.Write(okRespBody)
 		return
 	}
 
-	md, _ := signalFxV2ToMetrics(r.logger, msg.Datapoints)
+	md, _ := signalFxV2ToMetrics(r.logger, sfxDataPoints)
+
+	if r.dimensionCache != nil {
+		md = r.dimensionCache.apply(md)
+	}
 
 	if r.config.AccessTokenPassthrough {
 		if accessToken := req.Header.Get(splunk.SFxAccessTokenHeader); accessToken != "" {
@@ -252,18 +386,14 @@ func (r *sfxReceiver) handleDatapointReq(resp http.ResponseWriter, req *http.Req
 	obsreport.EndMetricsReceiveOp(
 		ctx,
 		typeStr,
-		len(msg.Datapoints),
+		len(sfxDataPoints),
 		err)
 
 	r.writeResponse(ctx, resp, err)
 }
 
 func (r *sfxReceiver) handleEventReq(resp http.ResponseWriter, req *http.Request) {
-	transport := "http"
-	if r.config.TLSSetting != nil {
-		transport = "https"
-	}
-
+	transport := r.transport()
 	ctx := obsreport.ReceiverContext(req.Context(), r.config.ID(), transport)
 	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.ID(), transport)
 
@@ -272,7 +402,7 @@ func (r *sfxReceiver) handleEventReq(resp http.ResponseWriter, req *http.Request
 		return
 	}
 
-	body, ok := r.readBody(ctx, resp, req)
+	body, _, ok := r.readBody(ctx, resp, req, protobufRequestFormat)
 	if !ok {
 		return
 	}
@@ -310,6 +440,164 @@ func (r *sfxReceiver) handleEventReq(resp http.ResponseWriter, req *http.Request
 	r.writeResponse(ctx, resp, err)
 }
 
+// handleZipkinTraceReq handles spans sent in the Zipkin v2 JSON format, as
+// done by the SignalFx Smart Agent.
+func (r *sfxReceiver) handleZipkinTraceReq(resp http.ResponseWriter, req *http.Request) {
+	transport := r.transport()
+	ctx := obsreport.ReceiverContext(req.Context(), r.config.ID(), transport)
+	ctx = obsreport.StartTraceDataReceiveOp(ctx, r.config.ID(), transport)
+
+	if r.tracesConsumer == nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errTracesNotConfigured, nil)
+		return
+	}
+
+	body, _, ok := r.readBody(ctx, resp, req, zipkinRequestFormat)
+	if !ok {
+		return
+	}
+
+	var zipkinSpans []*zipkinmodel.SpanModel
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &zipkinSpans); err != nil {
+			r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+			return
+		}
+	}
+
+	if len(zipkinSpans) == 0 {
+		obsreport.EndTraceDataReceiveOp(ctx, typeStr, 0, nil)
+		resp.Write(okRespBody)
+		return
+	}
+
+	td, err := zipkintranslator.V2SpansToInternalTraces(zipkinSpans, false)
+	if err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+		return
+	}
+
+	err = r.consumeTraces(ctx, req, td)
+	r.writeResponse(ctx, resp, err)
+}
+
+// handleSAPMTraceReq handles spans sent in the SAPM format.
+func (r *sfxReceiver) handleSAPMTraceReq(resp http.ResponseWriter, req *http.Request) {
+	transport := r.transport()
+	ctx := obsreport.ReceiverContext(req.Context(), r.config.ID(), transport)
+	ctx = obsreport.StartTraceDataReceiveOp(ctx, r.config.ID(), transport)
+
+	if r.tracesConsumer == nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errTracesNotConfigured, nil)
+		return
+	}
+
+	if req.Method != http.MethodPost {
+		r.failRequest(ctx, resp, http.StatusBadRequest, invalidMethodRespBody, nil)
+		return
+	}
+
+	sapm, err := sapmprotocol.ParseTraceV2Request(req)
+	if err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+		return
+	}
+
+	td := jaegertranslator.ProtoBatchesToInternalTraces(sapm.Batches)
+	if td.SpanCount() == 0 {
+		obsreport.EndTraceDataReceiveOp(ctx, typeStr, 0, nil)
+		r.writeSAPMResponse(resp)
+		return
+	}
+
+	if err = r.consumeTraces(ctx, req, td); err != nil {
+		r.failRequest(ctx, resp, http.StatusInternalServerError, errNextConsumerRespBody, err)
+		return
+	}
+	r.writeSAPMResponse(resp)
+}
+
+// consumeTraces passes the spans to the next consumer, leaving it up to the
+// caller to write the response.
+func (r *sfxReceiver) consumeTraces(ctx context.Context, req *http.Request, td pdata.Traces) error {
+	if r.config.AccessTokenPassthrough {
+		if accessToken := req.Header.Get(splunk.SFxAccessTokenHeader); accessToken != "" {
+			rSpans := td.ResourceSpans()
+			for i := 0; i < rSpans.Len(); i++ {
+				rSpans.At(i).Resource().Attributes().UpsertString(splunk.SFxAccessTokenLabel, accessToken)
+			}
+		}
+	}
+
+	numSpans := td.SpanCount()
+	err := r.tracesConsumer.ConsumeTraces(ctx, td)
+	obsreport.EndTraceDataReceiveOp(ctx, typeStr, numSpans, err)
+
+	return err
+}
+
+func (r *sfxReceiver) writeSAPMResponse(resp http.ResponseWriter) {
+	resp.Header().Set(sapmprotocol.ContentTypeHeaderName, sapmprotocol.ContentTypeHeaderValue)
+	resp.WriteHeader(http.StatusOK)
+	resp.Write(r.sapmResponse)
+}
+
+// handleDimensionReq handles dimension property and tag updates. Updates are
+// sent as log records to the logs pipeline, if any, and kept in the dimension
+// properties cache when enabled.
+func (r *sfxReceiver) handleDimensionReq(resp http.ResponseWriter, req *http.Request) {
+	transport := r.transport()
+	ctx := obsreport.ReceiverContext(req.Context(), r.config.ID(), transport)
+	ctx = obsreport.StartLogsReceiveOp(ctx, r.config.ID(), transport)
+
+	if r.logsConsumer == nil && r.dimensionCache == nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errDimensionsDisabled, nil)
+		return
+	}
+
+	body, _, ok := r.readBody(ctx, resp, req, dimensionRequestFormat)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(req)
+	update, err := parseDimensionUpdate(req, vars["key"], vars["value"], body)
+	if err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+		return
+	}
+	if update.Key == "" || update.Value == "" {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, errors.New("dimension key and value are required"))
+		return
+	}
+
+	if r.dimensionCache != nil {
+		r.dimensionCache.update(update)
+	}
+
+	if r.logsConsumer == nil {
+		obsreport.EndLogsReceiveOp(ctx, typeStr, 0, nil)
+		r.writeResponse(ctx, resp, nil)
+		return
+	}
+
+	ld := pdata.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	ill := rl.InstrumentationLibraryLogs().AppendEmpty()
+	update.fillLogRecord(ill.Logs().AppendEmpty())
+
+	if r.config.AccessTokenPassthrough {
+		if accessToken := req.Header.Get(splunk.SFxAccessTokenHeader); accessToken != "" {
+			rl.Resource().Attributes().InsertString(splunk.SFxAccessTokenLabel, accessToken)
+		}
+	}
+
+	err = r.logsConsumer.ConsumeLogs(ctx, ld)
+	obsreport.EndLogsReceiveOp(ctx, typeStr, 1, err)
+
+	r.writeResponse(ctx, resp, err)
+}
+
 func (r *sfxReceiver) failRequest(
 	ctx context.Context,
 	resp http.ResponseWriter,
@@ -364,3 +652,20 @@ func initJSONResponse(s string) []byte {
 	}
 	return respBody
 }
+
+// mediaType strips any parameters from a Content-Type header value.
+func mediaType(contentType string) string {
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return strings.TrimSpace(contentType)
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
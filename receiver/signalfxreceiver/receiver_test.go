@@ -30,7 +30,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gorilla/mux"
+	jaegerpb "github.com/jaegertracing/jaeger/model"
 	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	splunksapm "github.com/signalfx/sapm-proto/gen"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
@@ -46,6 +49,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/signalfxexporter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
 )
 
 func Test_signalfxeceiver_New(t *testing.T) {
@@ -772,6 +776,245 @@ func Test_sfxReceiver_EventAccessTokenPassthrough(t *testing.T) {
 	}
 }
 
+func Test_sfxReceiver_handleV1DatapointReq(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+
+	tests := []struct {
+		name        string
+		body        []byte
+		contentType string
+		wantStatus  int
+		wantBody    string
+		wantPoints  int
+	}{
+		{
+			name:        "protobuf",
+			body:        buildV1ProtobufBody(t, buildSFxDatapointMsg(time.Now().Unix()*1e3, 13, 3).Datapoints...),
+			contentType: "application/x-protobuf",
+			wantStatus:  http.StatusOK,
+			wantBody:    responseOK,
+			wantPoints:  1,
+		},
+		{
+			name:        "json",
+			body:        []byte(`{"source": "s", "metric": "m1", "value": 1}{"metric": "m2", "value": 2.5}`),
+			contentType: "application/json; charset=utf-8",
+			wantStatus:  http.StatusOK,
+			wantBody:    responseOK,
+			wantPoints:  2,
+		},
+		{
+			name:        "bad_json",
+			body:        []byte(`{"metric": `),
+			contentType: "application/json",
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    responseErrUnmarshalBody,
+		},
+		{
+			name:        "incorrect_content_type",
+			contentType: "text/plain",
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantBody:    responseInvalidV1ContentType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			rcv := newReceiver(zap.NewNop(), *config)
+			rcv.RegisterMetricsConsumer(sink)
+
+			req := httptest.NewRequest("POST", "http://localhost/v1/datapoint", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			w := httptest.NewRecorder()
+			rcv.handleV1DatapointReq(w, req)
+
+			resp := w.Result()
+			respBytes, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(t, err)
+
+			var bodyStr string
+			assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantBody, bodyStr)
+			assert.Equal(t, tt.wantPoints, sink.MetricsCount())
+		})
+	}
+}
+
+func Test_sfxReceiver_handleZipkinTraceReq(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+	config.AccessTokenPassthrough = true
+
+	body := []byte(`[{
+		"traceId": "0123456789abcdef0123456789abcdef",
+		"id": "0123456789abcdef",
+		"name": "get",
+		"kind": "SERVER",
+		"timestamp": 1574092046000000,
+		"duration": 1000,
+		"localEndpoint": {"serviceName": "frontend"}
+	}]`)
+
+	sink := new(consumertest.TracesSink)
+	rcv := newReceiver(zap.NewNop(), *config)
+
+	req := httptest.NewRequest("POST", "http://localhost/v1/trace", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	rcv.handleZipkinTraceReq(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+	rcv.RegisterTracesConsumer(sink)
+	req = httptest.NewRequest("POST", "http://localhost/v1/trace", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-sf-token", "myToken")
+	w = httptest.NewRecorder()
+	rcv.handleZipkinTraceReq(w, req)
+
+	resp := w.Result()
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var bodyStr string
+	require.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, responseOK, bodyStr)
+
+	require.Equal(t, 1, sink.SpansCount())
+	rs := sink.AllTraces()[0].ResourceSpans().At(0)
+	token, ok := rs.Resource().Attributes().Get(splunk.SFxAccessTokenLabel)
+	require.True(t, ok)
+	assert.Equal(t, "myToken", token.StringVal())
+	assert.Equal(t, "get", rs.InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+}
+
+func Test_sfxReceiver_handleSAPMTraceReq(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+
+	sapmReq := &splunksapm.PostSpansRequest{
+		Batches: []*jaegerpb.Batch{
+			{
+				Process: &jaegerpb.Process{ServiceName: "frontend"},
+				Spans: []*jaegerpb.Span{
+					{
+						TraceID:       jaegerpb.NewTraceID(1, 2),
+						SpanID:        jaegerpb.NewSpanID(3),
+						OperationName: "get",
+						StartTime:     time.Unix(1574092046, 0),
+						Duration:      time.Second,
+					},
+				},
+			},
+		},
+	}
+	msgBytes, err := sapmReq.Marshal()
+	require.NoError(t, err)
+
+	sink := new(consumertest.TracesSink)
+	rcv := newReceiver(zap.NewNop(), *config)
+	rcv.RegisterTracesConsumer(sink)
+
+	req := httptest.NewRequest("POST", "http://localhost/v2/trace", bytes.NewReader(msgBytes))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	w := httptest.NewRecorder()
+	rcv.handleSAPMTraceReq(w, req)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-protobuf", resp.Header.Get("Content-Type"))
+	require.Equal(t, 1, sink.SpansCount())
+
+	req = httptest.NewRequest("POST", "http://localhost/v2/trace", bytes.NewReader(msgBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	rcv.handleSAPMTraceReq(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	assert.Equal(t, 1, sink.SpansCount())
+}
+
+func Test_sfxReceiver_handleDimensionReq(t *testing.T) {
+	body := []byte(`{"customProperties": {"team": "a"}, "tags": ["t1"]}`)
+	newReq := func(method string) *http.Request {
+		req := httptest.NewRequest(method, "http://localhost/v2/dimension/k0/v0/_/sfxagent", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return mux.SetURLVars(req, map[string]string{"key": "k0", "value": "v0"})
+	}
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		logs       bool
+		cache      bool
+		wantStatus int
+		wantBody   string
+		wantLogs   int
+	}{
+		{
+			name:       "not_configured",
+			req:        newReq("PATCH"),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   responseErrDimensionsDisabled,
+		},
+		{
+			name:       "incorrect_method",
+			req:        newReq("POST"),
+			logs:       true,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   responseInvalidDimensionMethod,
+		},
+		{
+			name:       "logs",
+			req:        newReq("PATCH"),
+			logs:       true,
+			wantStatus: http.StatusOK,
+			wantBody:   responseOK,
+			wantLogs:   1,
+		},
+		{
+			name:       "cache_only",
+			req:        newReq("PUT"),
+			cache:      true,
+			wantStatus: http.StatusOK,
+			wantBody:   responseOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.Endpoint = "localhost:0" // Actually not creating the endpoint
+			config.CacheDimensionProperties = tt.cache
+
+			sink := new(consumertest.LogsSink)
+			rcv := newReceiver(zap.NewNop(), *config)
+			if tt.logs {
+				rcv.RegisterLogsConsumer(sink)
+			}
+
+			w := httptest.NewRecorder()
+			rcv.handleDimensionReq(w, tt.req)
+
+			resp := w.Result()
+			respBytes, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(t, err)
+
+			var bodyStr string
+			assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantBody, bodyStr)
+			assert.Equal(t, tt.wantLogs, sink.LogRecordsCount())
+
+			if tt.cache && tt.wantStatus == http.StatusOK {
+				assert.Equal(t, map[string]string{"team": "a"}, rcv.dimensionCache.properties[dimensionKey{key: "k0", value: "v0"}])
+			}
+		})
+	}
+}
+
 func buildSFxDatapointMsg(time int64, value int64, dimensions uint) *sfxpb.DataPointUploadMessage {
 	return &sfxpb.DataPointUploadMessage{
 		Datapoints: []*sfxpb.DataPoint{
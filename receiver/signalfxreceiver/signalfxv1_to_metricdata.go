@@ -0,0 +1,121 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+)
+
+// sfxSourceDimension is the dimension the SignalFx ingest API uses to carry
+// the "source" field of v1 datapoints.
+const sfxSourceDimension = "sf_source"
+
+var (
+	errSFxV1InvalidLength = errors.New("invalid length prefix in v1 datapoint message")
+	errSFxV1Truncated     = errors.New("truncated v1 datapoint message")
+)
+
+// jsonDatapointV1 is a datapoint in the SignalFx v1 JSON format.
+type jsonDatapointV1 struct {
+	Source string      `json:"source"`
+	Metric string      `json:"metric"`
+	Value  json.Number `json:"value"`
+}
+
+// signalFxV1ProtobufToDatapoints decodes a stream of varint length prefixed
+// SignalFx proto data points, as sent to the v1 datapoint endpoint.
+func signalFxV1ProtobufToDatapoints(body []byte) ([]*sfxpb.DataPoint, error) {
+	var sfxDataPoints []*sfxpb.DataPoint
+	for len(body) > 0 {
+		size, n := binary.Uvarint(body)
+		if n <= 0 {
+			return nil, errSFxV1InvalidLength
+		}
+		body = body[n:]
+		if uint64(len(body)) < size {
+			return nil, errSFxV1Truncated
+		}
+
+		sfxDataPoint := &sfxpb.DataPoint{}
+		if err := sfxDataPoint.Unmarshal(body[:size]); err != nil {
+			return nil, err
+		}
+		body = body[size:]
+
+		addSourceDimension(sfxDataPoint, sfxDataPoint.Source)
+		sfxDataPoints = append(sfxDataPoints, sfxDataPoint)
+	}
+	return sfxDataPoints, nil
+}
+
+// signalFxV1JSONToDatapoints decodes a stream of SignalFx v1 JSON data
+// points. The v1 JSON format doesn't carry a metric type so all data points
+// are reported as gauges.
+func signalFxV1JSONToDatapoints(body []byte) ([]*sfxpb.DataPoint, error) {
+	var sfxDataPoints []*sfxpb.DataPoint
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	for {
+		var jsonDataPoint jsonDatapointV1
+		if err := dec.Decode(&jsonDataPoint); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		if jsonDataPoint.Metric == "" {
+			continue
+		}
+
+		sfxDataPoint := &sfxpb.DataPoint{
+			Metric:     jsonDataPoint.Metric,
+			MetricType: sfxMetricTypePtr(sfxpb.MetricType_GAUGE),
+		}
+		if intVal, err := jsonDataPoint.Value.Int64(); err == nil {
+			sfxDataPoint.Value.IntValue = &intVal
+		} else {
+			doubleVal, err := jsonDataPoint.Value.Float64()
+			if err != nil {
+				return nil, err
+			}
+			sfxDataPoint.Value.DoubleValue = &doubleVal
+		}
+
+		addSourceDimension(sfxDataPoint, jsonDataPoint.Source)
+		sfxDataPoints = append(sfxDataPoints, sfxDataPoint)
+	}
+	return sfxDataPoints, nil
+}
+
+func addSourceDimension(sfxDataPoint *sfxpb.DataPoint, source string) {
+	if source == "" {
+		return
+	}
+	sfxDataPoint.Dimensions = append(sfxDataPoint.Dimensions, &sfxpb.Dimension{
+		Key:   sfxSourceDimension,
+		Value: source,
+	})
+}
+
+func sfxMetricTypePtr(t sfxpb.MetricType) *sfxpb.MetricType {
+	return &t
+}
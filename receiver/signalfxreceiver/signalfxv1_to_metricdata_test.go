@@ -0,0 +1,103 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"encoding/binary"
+	"testing"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_signalFxV1ProtobufToDatapoints(t *testing.T) {
+	dps := []*sfxpb.DataPoint{
+		{
+			Source:     "host1",
+			Metric:     "gauge",
+			Value:      sfxpb.Datum{IntValue: int64Ptr(13)},
+			MetricType: sfxTypePtr(sfxpb.MetricType_GAUGE),
+		},
+		{
+			Metric:     "counter",
+			Value:      sfxpb.Datum{DoubleValue: float64Ptr(1.5)},
+			MetricType: sfxTypePtr(sfxpb.MetricType_COUNTER),
+			Dimensions: buildNDimensions(1),
+		},
+	}
+
+	body := buildV1ProtobufBody(t, dps...)
+
+	got, err := signalFxV1ProtobufToDatapoints(body)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, "gauge", got[0].Metric)
+	assert.Equal(t, int64(13), *got[0].Value.IntValue)
+	assert.Equal(t, []*sfxpb.Dimension{{Key: sfxSourceDimension, Value: "host1"}}, got[0].Dimensions)
+
+	assert.Equal(t, "counter", got[1].Metric)
+	assert.Equal(t, 1.5, *got[1].Value.DoubleValue)
+	assert.Equal(t, sfxpb.MetricType_COUNTER, got[1].GetMetricType())
+	assert.Equal(t, buildNDimensions(1), got[1].Dimensions)
+}
+
+func Test_signalFxV1ProtobufToDatapoints_Invalid(t *testing.T) {
+	body := buildV1ProtobufBody(t, &sfxpb.DataPoint{Metric: "gauge"})
+
+	_, err := signalFxV1ProtobufToDatapoints(body[:len(body)-1])
+	assert.Equal(t, errSFxV1Truncated, err)
+
+	_, err = signalFxV1ProtobufToDatapoints([]byte{0xff})
+	assert.Equal(t, errSFxV1InvalidLength, err)
+}
+
+func Test_signalFxV1JSONToDatapoints(t *testing.T) {
+	body := []byte(`{"source": "host1", "metric": "int_gauge", "value": 3}
+{"metric": "double_gauge", "value": 3.25}
+{"source": "host1", "value": 1}`)
+
+	got, err := signalFxV1JSONToDatapoints(body)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, "int_gauge", got[0].Metric)
+	assert.Equal(t, sfxpb.MetricType_GAUGE, got[0].GetMetricType())
+	assert.Equal(t, int64(3), *got[0].Value.IntValue)
+	assert.Nil(t, got[0].Value.DoubleValue)
+	assert.Equal(t, []*sfxpb.Dimension{{Key: sfxSourceDimension, Value: "host1"}}, got[0].Dimensions)
+
+	assert.Equal(t, "double_gauge", got[1].Metric)
+	assert.Equal(t, 3.25, *got[1].Value.DoubleValue)
+	assert.Nil(t, got[1].Value.IntValue)
+	assert.Empty(t, got[1].Dimensions)
+
+	_, err = signalFxV1JSONToDatapoints([]byte(`{"metric": "bad", "value": "x"}`))
+	assert.Error(t, err)
+}
+
+func buildV1ProtobufBody(t *testing.T, dps ...*sfxpb.DataPoint) []byte {
+	var body []byte
+	sizeBuf := make([]byte, binary.MaxVarintLen64)
+	for _, dp := range dps {
+		b, err := dp.Marshal()
+		require.NoError(t, err)
+		n := binary.PutUvarint(sizeBuf, uint64(len(b)))
+		body = append(body, sizeBuf[:n]...)
+		body = append(body, b...)
+	}
+	return body
+}
@@ -40,4 +40,8 @@ func TestCreateReceiver(t *testing.T) {
 	tReceiver, err := createMetricsReceiver(context.Background(), params, cfg, consumertest.NewNop())
 	assert.NoError(t, err)
 	assert.NotNil(t, tReceiver, "receiver creation failed")
+
+	traceReceiver, err := createTracesReceiver(context.Background(), params, cfg, consumertest.NewNop())
+	assert.NoError(t, err)
+	assert.Same(t, tReceiver, traceReceiver, "metrics and traces receivers must be the same instance")
 }
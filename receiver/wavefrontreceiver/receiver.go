@@ -0,0 +1,236 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/transport"
+)
+
+const transportTCP = "tcp"
+
+var errEmptyEndpoint = errors.New("empty endpoint")
+
+// wavefrontReceiver implements a component.MetricsReceiver and a
+// component.TracesReceiver for the Wavefront proxy format. Each received text
+// line is a metric, a histogram distribution, a span or span logs, told apart
+// the same way the Wavefront proxy does. The lines are received by the TCP
+// server of the Carbon receiver, which passes them to ConsumeLine.
+type wavefrontReceiver struct {
+	sync.Mutex
+	logger *zap.Logger
+	config *Config
+	parser *WavefrontParser
+	server transport.Server
+	done   chan struct{}
+
+	metricsConsumer consumer.Metrics
+	tracesConsumer  consumer.Traces
+}
+
+var _ component.MetricsReceiver = (*wavefrontReceiver)(nil)
+var _ component.TracesReceiver = (*wavefrontReceiver)(nil)
+var _ protocol.Parser = (*wavefrontReceiver)(nil)
+var _ transport.LineConsumer = (*wavefrontReceiver)(nil)
+
+// newReceiver creates the Wavefront receiver with the given configuration.
+func newReceiver(logger *zap.Logger, config Config) (*wavefrontReceiver, error) {
+	if config.Endpoint == "" {
+		return nil, errEmptyEndpoint
+	}
+	return &wavefrontReceiver{
+		logger: logger,
+		config: &config,
+		parser: &WavefrontParser{
+			ExtractCollectdTags: config.ExtractCollectdTags,
+		},
+	}, nil
+}
+
+func (r *wavefrontReceiver) registerMetricsConsumer(mc consumer.Metrics) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.metricsConsumer = mc
+}
+
+func (r *wavefrontReceiver) registerTracesConsumer(tc consumer.Traces) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.tracesConsumer = tc
+}
+
+// Start tells the receiver to start its processing.
+func (r *wavefrontReceiver) Start(_ context.Context, host component.Host) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.metricsConsumer == nil && r.tracesConsumer == nil {
+		return componenterror.ErrNilNextConsumer
+	}
+
+	server, err := transport.NewTCPServer(r.config.Endpoint, r.config.TCPIdleTimeout)
+	if err != nil {
+		return err
+	}
+	r.server = server
+	r.done = make(chan struct{})
+
+	go func() {
+		err := server.ListenAndServe(r, r.metricsConsumer, &serverReporter{logger: r.logger})
+		select {
+		case <-r.done:
+			// The server was closed by Shutdown.
+		default:
+			host.ReportFatalError(err)
+		}
+	}()
+	return nil
+}
+
+// Shutdown tells the receiver that should stop reception,
+// giving it a chance to perform any necessary clean-up.
+func (r *wavefrontReceiver) Shutdown(context.Context) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.server == nil {
+		return nil
+	}
+	close(r.done)
+	return r.server.Close()
+}
+
+// Parse parses a metric line. The TCP server passes every line to ConsumeLine
+// instead, Parse only completes the protocol.Parser interface.
+func (r *wavefrontReceiver) Parse(line string) (*metricspb.Metric, error) {
+	return r.parser.Parse(line)
+}
+
+// ConsumeLine parses the line and passes the result to the next consumer of
+// its data type. Only errors from the next consumer are returned, translation
+// errors are just logged.
+func (r *wavefrontReceiver) ConsumeLine(line string) error {
+	switch {
+	case isHistogramLine(line):
+		return r.consumeMetrics(line, r.parser.ParseHistogram)
+	case isSpanLogsLine(line):
+		return r.consumeTraces(line, parseSpanLogs)
+	case isSpanLine(line):
+		return r.consumeTraces(line, func(line string) (pdata.Traces, error) {
+			td, hasSpanLogs, err := parseSpan(line)
+			if err == nil && hasSpanLogs {
+				// The span is sent again along with its logs.
+				return pdata.NewTraces(), nil
+			}
+			return td, err
+		})
+	default:
+		return r.consumeMetrics(line, func(line string) (pdata.Metrics, error) {
+			metric, err := r.parser.Parse(line)
+			if err != nil {
+				return pdata.Metrics{}, err
+			}
+			return internaldata.OCToMetrics(nil, nil, []*metricspb.Metric{metric}), nil
+		})
+	}
+}
+
+func (r *wavefrontReceiver) consumeMetrics(line string, parse func(string) (pdata.Metrics, error)) error {
+	if r.metricsConsumer == nil {
+		r.logger.Debug("Wavefront receiver dropped metric, no metrics pipeline configured")
+		return nil
+	}
+
+	ctx := obsreport.ReceiverContext(context.Background(), r.config.ID(), transportTCP)
+	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.ID(), transportTCP)
+
+	md, err := parse(line)
+	if err != nil {
+		r.logger.Debug("Wavefront translation error", zap.Error(err))
+		obsreport.EndMetricsReceiveOp(ctx, typeStr, 1, err)
+		return nil
+	}
+
+	err = r.metricsConsumer.ConsumeMetrics(ctx, md)
+	obsreport.EndMetricsReceiveOp(ctx, typeStr, 1, err)
+	if err != nil {
+		r.logger.Debug("Wavefront receiver failed to push metrics into pipeline", zap.Error(err))
+	}
+	return err
+}
+
+func (r *wavefrontReceiver) consumeTraces(line string, parse func(string) (pdata.Traces, error)) error {
+	if r.tracesConsumer == nil {
+		r.logger.Debug("Wavefront receiver dropped span, no traces pipeline configured")
+		return nil
+	}
+
+	ctx := obsreport.ReceiverContext(context.Background(), r.config.ID(), transportTCP)
+	ctx = obsreport.StartTraceDataReceiveOp(ctx, r.config.ID(), transportTCP)
+
+	td, err := parse(line)
+	if err != nil {
+		r.logger.Debug("Wavefront translation error", zap.Error(err))
+		obsreport.EndTraceDataReceiveOp(ctx, typeStr, 1, err)
+		return nil
+	}
+	if td.SpanCount() == 0 {
+		obsreport.EndTraceDataReceiveOp(ctx, typeStr, 0, nil)
+		return nil
+	}
+
+	err = r.tracesConsumer.ConsumeTraces(ctx, td)
+	obsreport.EndTraceDataReceiveOp(ctx, typeStr, td.SpanCount(), err)
+	if err != nil {
+		r.logger.Debug("Wavefront receiver failed to push spans into pipeline", zap.Error(err))
+	}
+	return err
+}
+
+// serverReporter is the transport.Reporter of the TCP server. The received
+// lines are reported by ConsumeLine, so only the debug messages of the server
+// are logged.
+type serverReporter struct {
+	logger *zap.Logger
+}
+
+var _ transport.Reporter = (*serverReporter)(nil)
+
+func (r *serverReporter) OnDataReceived(ctx context.Context) context.Context {
+	return ctx
+}
+
+func (r *serverReporter) OnTranslationError(context.Context, error) {}
+
+func (r *serverReporter) OnMetricsProcessed(context.Context, int, error) {}
+
+func (r *serverReporter) OnDebugf(template string, args ...interface{}) {
+	r.logger.Sugar().Debugf(template, args...)
+}
@@ -152,3 +152,51 @@ func Test_wavefrontreceiver_EndToEnd(t *testing.T) {
 		sink.Reset()
 	}
 }
+
+func Test_wavefrontreceiver_EndToEnd_HistogramsAndSpans(t *testing.T) {
+	rCfg := createDefaultConfig().(*Config)
+	rCfg.TCPIdleTimeout = time.Second
+
+	addr := testutil.GetAvailableLocalAddress(t)
+	rCfg.Endpoint = addr
+	metricsSink := new(consumertest.MetricsSink)
+	tracesSink := new(consumertest.TracesSink)
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	mRcvr, err := createMetricsReceiver(context.Background(), params, rCfg, metricsSink)
+	require.NoError(t, err)
+	tRcvr, err := createTracesReceiver(context.Background(), params, rCfg, tracesSink)
+	require.NoError(t, err)
+	require.Same(t, mRcvr, tRcvr)
+
+	require.NoError(t, mRcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer mRcvr.Shutdown(context.Background())
+
+	msg := "!M 1582231120 #2 1.5 #1 3 request.latency source=e2e\n" +
+		"op0 source=e2e traceId=7b3bf470-9456-11e8-9eb6-529269fb1459 spanId=0313bafe-9457-11e8-9eb6-529269fb1459 1582231120000 5\n" +
+		"op1 source=e2e traceId=7b3bf470-9456-11e8-9eb6-529269fb1459 spanId=2f64e538-9457-11e8-9eb6-529269fb1459 _spanLogs=true 1582231120000 5\n" +
+		`{"traceId": "7b3bf470-9456-11e8-9eb6-529269fb1459", "spanId": "2f64e538-9457-11e8-9eb6-529269fb1459", ` +
+		`"logs": [{"timestamp": 1582231120000001, "fields": {"event": "error"}}], ` +
+		`"span": "op1 source=e2e traceId=7b3bf470-9456-11e8-9eb6-529269fb1459 spanId=2f64e538-9457-11e8-9eb6-529269fb1459 _spanLogs=true 1582231120000 5"}` + "\n"
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	_, err = fmt.Fprint(conn, msg)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	assert.Eventually(t, func() bool {
+		return metricsSink.MetricsCount() == 1 && tracesSink.SpansCount() == 2
+	}, 10*time.Second, 5*time.Millisecond)
+
+	m := metricsSink.AllMetrics()[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "request.latency", m.Name())
+	assert.Equal(t, uint64(3), m.Histogram().DataPoints().At(0).Count())
+
+	traces := tracesSink.AllTraces()
+	require.Len(t, traces, 2)
+	span0 := traces[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	assert.Equal(t, "op0", span0.Name())
+	span1 := traces[1].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	assert.Equal(t, "op1", span1.Name())
+	assert.Equal(t, 1, span1.Events().Len())
+}
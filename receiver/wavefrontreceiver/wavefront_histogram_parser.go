@@ -0,0 +1,153 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// histogramGranularities maps the prefix of a Wavefront histogram line to the
+// aggregation interval of the distribution.
+var histogramGranularities = map[string]time.Duration{
+	"!M": time.Minute,
+	"!H": time.Hour,
+	"!D": 24 * time.Hour,
+}
+
+// isHistogramLine returns true if the line is a histogram distribution in the
+// Wavefront format.
+func isHistogramLine(line string) bool {
+	if len(line) < 3 || line[2] != ' ' {
+		return false
+	}
+	_, ok := histogramGranularities[line[:2]]
+	return ok
+}
+
+// ParseHistogram receives the string with a Wavefront histogram distribution
+// and transforms it to a delta pdata Histogram, see
+// https://docs.wavefront.com/wavefront_data_format.html#histogram-data-format-syntax.
+//
+// Each line received represents a Wavefront distribution in the following format:
+//
+// 	"{!M | !H | !D} [<timestamp>] {#<count> <mean>}+ <metricName> source=<source> [pointTags]"
+//
+// Each centroid becomes a bucket whose upper bound is the centroid mean. The
+// timestamp, when present, marks the start of the aggregation interval.
+func (wp *WavefrontParser) ParseHistogram(line string) (pdata.Metrics, error) {
+	if !isHistogramLine(line) {
+		return pdata.Metrics{}, fmt.Errorf("invalid wavefront histogram [%s]", line)
+	}
+	granularity := histogramGranularities[line[:2]]
+	rest := strings.TrimLeft(line[3:], " ")
+
+	// The timestamp is optional.
+	startTime := time.Now().Truncate(granularity)
+	if !strings.HasPrefix(rest, "#") {
+		parts := strings.SplitN(rest, " ", 2)
+		unixTime, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil || len(parts) < 2 {
+			return pdata.Metrics{}, fmt.Errorf("invalid timestamp for wavefront histogram [%s]", line)
+		}
+		startTime = time.Unix(unixTime, 0)
+		rest = strings.TrimLeft(parts[1], " ")
+	}
+
+	centroids := map[float64]uint64{}
+	for strings.HasPrefix(rest, "#") {
+		parts := strings.SplitN(rest, " ", 3)
+		if len(parts) < 3 {
+			return pdata.Metrics{}, fmt.Errorf("invalid wavefront histogram [%s]", line)
+		}
+		count, err := strconv.ParseUint(parts[0][1:], 10, 64)
+		if err != nil {
+			return pdata.Metrics{}, fmt.Errorf("invalid count in wavefront histogram [%s]: %v", line, err)
+		}
+		mean, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return pdata.Metrics{}, fmt.Errorf("invalid mean in wavefront histogram [%s]: %v", line, err)
+		}
+		centroids[mean] += count
+		rest = strings.TrimLeft(parts[2], " ")
+	}
+	if len(centroids) == 0 {
+		return pdata.Metrics{}, fmt.Errorf("no centroids in wavefront histogram [%s]", line)
+	}
+
+	parts := strings.SplitN(rest, " ", 2)
+	metricName := unDoubleQuote(parts[0])
+	if metricName == "" {
+		return pdata.Metrics{}, fmt.Errorf("empty name for wavefront histogram [%s]", line)
+	}
+
+	var tags string
+	if len(parts) == 2 {
+		tags = parts[1]
+	}
+	labelKeys, labelValues, err := buildLabels(tags)
+	if err != nil {
+		return pdata.Metrics{}, fmt.Errorf("invalid wavefront histogram [%s]: %v", line, err)
+	}
+	if wp.ExtractCollectdTags {
+		metricName, labelKeys, labelValues = wp.injectCollectDLabels(metricName, labelKeys, labelValues)
+	}
+
+	md := pdata.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName(metricName)
+	m.SetDataType(pdata.MetricDataTypeHistogram)
+	m.Histogram().SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+
+	dp := m.Histogram().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(pdata.TimestampFromTime(startTime))
+	dp.SetTimestamp(pdata.TimestampFromTime(startTime.Add(granularity)))
+	for i := range labelKeys {
+		dp.LabelsMap().Upsert(labelKeys[i].Key, labelValues[i].Value)
+	}
+	fillHistogramBuckets(centroids, dp)
+
+	return md, nil
+}
+
+// fillHistogramBuckets sets the buckets of the data point using the centroid
+// means as explicit bounds. The overflow bucket is always empty.
+func fillHistogramBuckets(centroids map[float64]uint64, dp pdata.HistogramDataPoint) {
+	bounds := make([]float64, 0, len(centroids))
+	for mean := range centroids {
+		bounds = append(bounds, mean)
+	}
+	sort.Float64s(bounds)
+
+	var count uint64
+	var sum float64
+	bucketCounts := make([]uint64, len(bounds)+1)
+	for i, bound := range bounds {
+		c := centroids[bound]
+		bucketCounts[i] = c
+		count += c
+		sum += float64(c) * bound
+	}
+
+	dp.SetCount(count)
+	dp.SetSum(sum)
+	dp.SetExplicitBounds(bounds)
+	dp.SetBucketCounts(bucketCounts)
+}
@@ -0,0 +1,144 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func Test_wavefrontParser_ParseHistogram(t *testing.T) {
+	buildHistogram := func(name string, start time.Time, granularity time.Duration, labels map[string]string, bounds []float64, counts []uint64, count uint64, sum float64) pdata.Metrics {
+		md := pdata.NewMetrics()
+		m := md.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName(name)
+		m.SetDataType(pdata.MetricDataTypeHistogram)
+		m.Histogram().SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+		dp := m.Histogram().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(pdata.TimestampFromTime(start))
+		dp.SetTimestamp(pdata.TimestampFromTime(start.Add(granularity)))
+		for k, v := range labels {
+			dp.LabelsMap().Upsert(k, v)
+		}
+		dp.SetExplicitBounds(bounds)
+		dp.SetBucketCounts(counts)
+		dp.SetCount(count)
+		dp.SetSum(sum)
+		return md
+	}
+
+	tests := []struct {
+		line    string
+		want    pdata.Metrics
+		wantErr bool
+	}{
+		{
+			line: "!M 1533529977 #20 30.0 #10 5.1 request.latency source=appServer1 region=us-west",
+			want: buildHistogram(
+				"request.latency",
+				time.Unix(1533529977, 0),
+				time.Minute,
+				map[string]string{"source": "appServer1", "region": "us-west"},
+				[]float64{5.1, 30.0},
+				[]uint64{10, 20, 0},
+				30,
+				20*30.0+10*5.1,
+			),
+		},
+		{
+			line: "!H 1533529977 #1 1 #2 1 #3 2 request.latency",
+			want: buildHistogram(
+				"request.latency",
+				time.Unix(1533529977, 0),
+				time.Hour,
+				nil,
+				[]float64{1, 2},
+				[]uint64{3, 3, 0},
+				6,
+				9,
+			),
+		},
+		{
+			line: "!D 1533529977 #5 -1.5 \"quoted.name\" source=s",
+			want: buildHistogram(
+				"quoted.name",
+				time.Unix(1533529977, 0),
+				24*time.Hour,
+				map[string]string{"source": "s"},
+				[]float64{-1.5},
+				[]uint64{5, 0},
+				5,
+				-7.5,
+			),
+		},
+		{
+			line:    "!M 1533529977 request.latency source=s",
+			wantErr: true,
+		},
+		{
+			line:    "!M 1533529977 #x 1 request.latency",
+			wantErr: true,
+		},
+		{
+			line:    "!M 1533529977 #1 y request.latency",
+			wantErr: true,
+		},
+		{
+			line:    "!M bad #1 1 request.latency",
+			wantErr: true,
+		},
+		{
+			line:    "!X 1533529977 #1 1 request.latency",
+			wantErr: true,
+		},
+	}
+
+	p := WavefrontParser{}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := p.ParseHistogram(tt.line)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			sortLabels(tt.want)
+			sortLabels(got)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_wavefrontParser_ParseHistogram_NoTimestamp(t *testing.T) {
+	p := WavefrontParser{}
+	md, err := p.ParseHistogram("!M #1 1 request.latency source=s")
+	require.NoError(t, err)
+
+	dp := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).Histogram().DataPoints().At(0)
+	start := dp.StartTimestamp().AsTime()
+	assert.Equal(t, start.Truncate(time.Minute), start)
+	assert.Equal(t, time.Minute, dp.Timestamp().AsTime().Sub(start))
+}
+
+func sortLabels(md pdata.Metrics) {
+	dps := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).Histogram().DataPoints()
+	for i := 0; i < dps.Len(); i++ {
+		dps.At(i).LabelsMap().Sort()
+	}
+}
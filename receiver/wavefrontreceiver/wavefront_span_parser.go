@@ -0,0 +1,267 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// Span tags with a special meaning in the Wavefront span format, see
+// https://docs.wavefront.com/trace_data_details.html#span-tags.
+const (
+	spanTagTraceID     = "traceId"
+	spanTagSpanID      = "spanId"
+	spanTagParent      = "parent"
+	spanTagFollowsFrom = "followsFrom"
+	spanTagSource      = "source"
+	spanTagService     = "service"
+	spanTagSpanKind    = "span.kind"
+	spanTagError       = "error"
+	spanTagSpanLogs    = "_spanLogs"
+)
+
+// resourceSpanTags are the span tags moved to the resource of the span, any
+// other tag becomes a span attribute.
+var resourceSpanTags = map[string]string{
+	spanTagSource:  conventions.AttributeHostName,
+	spanTagService: conventions.AttributeServiceName,
+	"application":  "application",
+	"cluster":      "cluster",
+	"shard":        "shard",
+}
+
+var spanKinds = map[string]pdata.SpanKind{
+	"client":   pdata.SpanKindClient,
+	"server":   pdata.SpanKindServer,
+	"producer": pdata.SpanKindProducer,
+	"consumer": pdata.SpanKindConsumer,
+	"internal": pdata.SpanKindInternal,
+}
+
+var errSpanLogsWithoutSpan = errors.New("wavefront span logs without span")
+
+// isSpanLine returns true if the line is a span in the Wavefront format. Span
+// lines are told apart from metric lines by having a tag, instead of a value,
+// right after the name.
+func isSpanLine(line string) bool {
+	parts := strings.SplitN(line, " ", 3)
+	return len(parts) == 3 && strings.IndexByte(parts[1], '=') > 0
+}
+
+// isSpanLogsLine returns true if the line is span logs in the Wavefront JSON
+// format.
+func isSpanLogsLine(line string) bool {
+	return strings.HasPrefix(line, "{")
+}
+
+// parseSpan receives the string with a Wavefront span and transforms it to
+// the collector trace format, see
+// https://docs.wavefront.com/trace_data_details.html#span-format.
+//
+// Each line received represents a Wavefront span in the following format:
+//
+// 	"<operationName> source=<source> <spanTags> <start_milliseconds> <duration_milliseconds>"
+//
+// The returned bool reports whether the span has span logs sent separately.
+func parseSpan(line string) (pdata.Traces, bool, error) {
+	rest, durationStr := splitLastField(line)
+	rest, startStr := splitLastField(rest)
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil {
+		return pdata.Traces{}, false, fmt.Errorf("invalid start for wavefront span [%s]: %v", line, err)
+	}
+	duration, err := strconv.ParseInt(durationStr, 10, 64)
+	if err != nil {
+		return pdata.Traces{}, false, fmt.Errorf("invalid duration for wavefront span [%s]: %v", line, err)
+	}
+
+	parts := strings.SplitN(rest, " ", 2)
+	if len(parts) < 2 {
+		return pdata.Traces{}, false, fmt.Errorf("invalid wavefront span [%s]", line)
+	}
+	name := unDoubleQuote(parts[0])
+	if name == "" {
+		return pdata.Traces{}, false, fmt.Errorf("empty name for wavefront span [%s]", line)
+	}
+	keys, values, err := buildLabels(parts[1])
+	if err != nil {
+		return pdata.Traces{}, false, fmt.Errorf("invalid wavefront span [%s]: %v", line, err)
+	}
+
+	td := pdata.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	span := rs.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName(name)
+	startTime := time.Unix(0, 0).Add(time.Duration(start) * time.Millisecond)
+	span.SetStartTimestamp(pdata.TimestampFromTime(startTime))
+	span.SetEndTimestamp(pdata.TimestampFromTime(startTime.Add(time.Duration(duration) * time.Millisecond)))
+
+	var hasTraceID, hasSpanID, hasSpanLogs bool
+	for i := range keys {
+		key, value := keys[i].Key, values[i].Value
+		switch key {
+		case spanTagTraceID:
+			traceID, err := parseUUID(value)
+			if err != nil {
+				return pdata.Traces{}, false, fmt.Errorf("invalid trace ID for wavefront span [%s]: %v", line, err)
+			}
+			span.SetTraceID(pdata.NewTraceID(traceID))
+			hasTraceID = true
+		case spanTagSpanID:
+			spanID, err := parseSpanID(value)
+			if err != nil {
+				return pdata.Traces{}, false, fmt.Errorf("invalid span ID for wavefront span [%s]: %v", line, err)
+			}
+			span.SetSpanID(spanID)
+			hasSpanID = true
+		case spanTagParent, spanTagFollowsFrom:
+			spanID, err := parseSpanID(value)
+			if err != nil {
+				return pdata.Traces{}, false, fmt.Errorf("invalid %s for wavefront span [%s]: %v", key, line, err)
+			}
+			// Only the first parent is the parent of the span, other
+			// references are recorded as links.
+			if key == spanTagParent && span.ParentSpanID().IsEmpty() {
+				span.SetParentSpanID(spanID)
+				continue
+			}
+			link := span.Links().AppendEmpty()
+			link.SetSpanID(spanID)
+		case spanTagSpanKind:
+			span.SetKind(spanKinds[strings.ToLower(value)])
+			span.Attributes().UpsertString(key, value)
+		case spanTagError:
+			if strings.EqualFold(value, "true") {
+				span.Status().SetCode(pdata.StatusCodeError)
+			}
+			span.Attributes().UpsertString(key, value)
+		case spanTagSpanLogs:
+			hasSpanLogs = strings.EqualFold(value, "true")
+		default:
+			if resourceKey, ok := resourceSpanTags[key]; ok {
+				rs.Resource().Attributes().UpsertString(resourceKey, value)
+				continue
+			}
+			span.Attributes().UpsertString(key, value)
+		}
+	}
+	if !hasTraceID || !hasSpanID {
+		return pdata.Traces{}, false, fmt.Errorf("missing trace or span ID for wavefront span [%s]", line)
+	}
+
+	// Links always belong to the same trace as the span.
+	for i := 0; i < span.Links().Len(); i++ {
+		span.Links().At(i).SetTraceID(span.TraceID())
+	}
+
+	return td, hasSpanLogs, nil
+}
+
+// spanLogs are the logs of a span in the Wavefront JSON format, see
+// https://docs.wavefront.com/trace_data_details.html#span-logs.
+type spanLogs struct {
+	TraceID string    `json:"traceId"`
+	SpanID  string    `json:"spanId"`
+	Logs    []spanLog `json:"logs"`
+	// Span is the line of the span the logs belong to.
+	Span string `json:"span"`
+}
+
+type spanLog struct {
+	// Timestamp is in microseconds.
+	Timestamp int64             `json:"timestamp"`
+	Fields    map[string]string `json:"fields"`
+}
+
+// parseSpanLogs receives the string with Wavefront span logs and transforms it
+// to a span carrying the logs as span events. Wavefront SDKs send the line of
+// the span along with its logs.
+func parseSpanLogs(line string) (pdata.Traces, error) {
+	var sl spanLogs
+	if err := json.Unmarshal([]byte(line), &sl); err != nil {
+		return pdata.Traces{}, fmt.Errorf("invalid wavefront span logs [%s]: %v", line, err)
+	}
+	if sl.Span == "" {
+		return pdata.Traces{}, errSpanLogsWithoutSpan
+	}
+
+	td, _, err := parseSpan(sl.Span)
+	if err != nil {
+		return pdata.Traces{}, err
+	}
+
+	span := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	for _, log := range sl.Logs {
+		event := span.Events().AppendEmpty()
+		event.SetTimestamp(pdata.TimestampFromTime(time.Unix(0, 0).Add(time.Duration(log.Timestamp) * time.Microsecond)))
+		// Follows the OpenTracing convention of naming logs after their
+		// "event" field.
+		name := log.Fields["event"]
+		if name == "" {
+			name = "log"
+		}
+		event.SetName(name)
+		for k, v := range log.Fields {
+			event.Attributes().UpsertString(k, v)
+		}
+	}
+	return td, nil
+}
+
+// splitLastField splits s at its last space.
+func splitLastField(s string) (string, string) {
+	s = strings.TrimRight(s, " ")
+	i := strings.LastIndexByte(s, ' ')
+	if i < 0 {
+		return "", s
+	}
+	return s[:i], s[i+1:]
+}
+
+// parseUUID parses a UUID, as used by Wavefront for trace and span IDs.
+func parseUUID(s string) ([16]byte, error) {
+	var id [16]byte
+	b, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil {
+		return id, err
+	}
+	if len(b) != len(id) {
+		return id, fmt.Errorf("invalid UUID %q", s)
+	}
+	copy(id[:], b)
+	return id, nil
+}
+
+// parseSpanID parses a Wavefront span ID. Wavefront span IDs are UUIDs so
+// only their lower 8 bytes are kept, which is where Wavefront SDKs and
+// exporters put 8 byte span IDs.
+func parseSpanID(s string) (pdata.SpanID, error) {
+	uuid, err := parseUUID(s)
+	if err != nil {
+		return pdata.SpanID{}, err
+	}
+	var id [8]byte
+	copy(id[:], uuid[8:])
+	return pdata.NewSpanID(id), nil
+}
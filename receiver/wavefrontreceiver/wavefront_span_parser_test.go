@@ -0,0 +1,138 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+const (
+	testTraceID  = "7b3bf470-9456-11e8-9eb6-529269fb1459"
+	testSpanID   = "0313bafe-9457-11e8-9eb6-529269fb1459"
+	testParentID = "2f64e538-9457-11e8-9eb6-529269fb1459"
+	testOtherID  = "5fb8fd40-9457-11e8-9eb6-529269fb1459"
+)
+
+func Test_isSpanLine(t *testing.T) {
+	assert.True(t, isSpanLine("getAllUsers source=localhost traceId=x spanId=y 1552949776000 343"))
+	assert.False(t, isSpanLine("system.cpu 1.5 source=localhost"))
+	assert.False(t, isSpanLine("system.cpu 1.5"))
+	assert.True(t, isSpanLogsLine(`{"traceId": "x"}`))
+	assert.False(t, isSpanLogsLine("system.cpu 1.5"))
+}
+
+func Test_parseSpan(t *testing.T) {
+	line := "getAllUsers source=localhost traceId=" + testTraceID + " spanId=" + testSpanID +
+		" parent=" + testParentID + " followsFrom=" + testOtherID +
+		" application=Wavefront service=auth cluster=us-west-2 shard=secondary http.method=GET span.kind=server error=true" +
+		" 1552949776000 343"
+
+	td, hasSpanLogs, err := parseSpan(line)
+	require.NoError(t, err)
+	assert.False(t, hasSpanLogs)
+	require.Equal(t, 1, td.SpanCount())
+
+	rs := td.ResourceSpans().At(0)
+	wantResource := pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		conventions.AttributeHostName:    pdata.NewAttributeValueString("localhost"),
+		conventions.AttributeServiceName: pdata.NewAttributeValueString("auth"),
+		"application":                    pdata.NewAttributeValueString("Wavefront"),
+		"cluster":                        pdata.NewAttributeValueString("us-west-2"),
+		"shard":                          pdata.NewAttributeValueString("secondary"),
+	})
+	assert.Equal(t, wantResource.Sort(), rs.Resource().Attributes().Sort())
+
+	span := rs.InstrumentationLibrarySpans().At(0).Spans().At(0)
+	assert.Equal(t, "getAllUsers", span.Name())
+	assert.Equal(t, "7b3bf470945611e89eb6529269fb1459", span.TraceID().HexString())
+	assert.Equal(t, "9eb6529269fb1459", span.SpanID().HexString())
+	assert.Equal(t, "9eb6529269fb1459", span.ParentSpanID().HexString())
+	assert.Equal(t, pdata.SpanKindServer, span.Kind())
+	assert.Equal(t, pdata.StatusCodeError, span.Status().Code())
+
+	start := time.Unix(1552949776, 0)
+	assert.Equal(t, pdata.TimestampFromTime(start), span.StartTimestamp())
+	assert.Equal(t, pdata.TimestampFromTime(start.Add(343*time.Millisecond)), span.EndTimestamp())
+
+	require.Equal(t, 1, span.Links().Len())
+	assert.Equal(t, span.TraceID(), span.Links().At(0).TraceID())
+
+	method, ok := span.Attributes().Get("http.method")
+	require.True(t, ok)
+	assert.Equal(t, "GET", method.StringVal())
+	_, ok = span.Attributes().Get("source")
+	assert.False(t, ok)
+}
+
+func Test_parseSpan_SpanLogsFlag(t *testing.T) {
+	_, hasSpanLogs, err := parseSpan("op traceId=" + testTraceID + " spanId=" + testSpanID + " _spanLogs=true 1552949776000 1")
+	require.NoError(t, err)
+	assert.True(t, hasSpanLogs)
+}
+
+func Test_parseSpan_Invalid(t *testing.T) {
+	tests := []string{
+		"op traceId=" + testTraceID + " spanId=" + testSpanID + " x 1",
+		"op traceId=" + testTraceID + " spanId=" + testSpanID + " 1 x",
+		"op spanId=" + testSpanID + " 1552949776000 1",
+		"op traceId=" + testTraceID + " 1552949776000 1",
+		"op traceId=bad spanId=" + testSpanID + " 1552949776000 1",
+		"op traceId=" + testTraceID + " spanId=abcd 1552949776000 1",
+		"op traceId=" + testTraceID + " spanId=" + testSpanID + " parent=bad 1552949776000 1",
+		`"" traceId=` + testTraceID + " spanId=" + testSpanID + " 1552949776000 1",
+		"1552949776000 1",
+	}
+	for _, line := range tests {
+		t.Run(line, func(t *testing.T) {
+			_, _, err := parseSpan(line)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_parseSpanLogs(t *testing.T) {
+	line := `{"traceId": "` + testTraceID + `", "spanId": "` + testSpanID + `", ` +
+		`"logs": [{"timestamp": 1552949776000123, "fields": {"event": "error", "message": "boom"}}, ` +
+		`{"timestamp": 1552949776000456, "fields": {"message": "retry"}}], ` +
+		`"span": "op source=localhost traceId=` + testTraceID + ` spanId=` + testSpanID + ` _spanLogs=true 1552949776000 1"}`
+
+	td, err := parseSpanLogs(line)
+	require.NoError(t, err)
+	require.Equal(t, 1, td.SpanCount())
+
+	span := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	_, ok := span.Attributes().Get(spanTagSpanLogs)
+	assert.False(t, ok)
+
+	events := span.Events()
+	require.Equal(t, 2, events.Len())
+	assert.Equal(t, "error", events.At(0).Name())
+	assert.Equal(t, pdata.TimestampFromTime(time.Unix(1552949776, 123000)), events.At(0).Timestamp())
+	message, _ := events.At(0).Attributes().Get("message")
+	assert.Equal(t, "boom", message.StringVal())
+	assert.Equal(t, "log", events.At(1).Name())
+
+	_, err = parseSpanLogs(`{"traceId": "` + testTraceID + `"}`)
+	assert.Equal(t, errSpanLogsWithoutSpan, err)
+
+	_, err = parseSpanLogs("{")
+	assert.Error(t, err)
+}
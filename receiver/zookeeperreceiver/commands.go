@@ -0,0 +1,286 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zookeeperreceiver
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/zookeeperreceiver/internal/metadata"
+)
+
+var (
+	// e.g. "3 connections watching 7 paths"
+	wchsFormatRE = regexp.MustCompile(`^(\d+) connections watching (\d+) paths`)
+	// e.g. " /127.0.0.1:51000[1](queued=0,recved=8,sent=8,sid=0x100000a1b2c0001,...)"
+	consFormatRE = regexp.MustCompile(`^/(.+):\d+\[\d+\]\((.*)\)$`)
+)
+
+// Parses the response of one of the four-letter words sent besides "mntr".
+func parseCommandResponse(cmd string, scanner *bufio.Scanner) ([]stat, error) {
+	var lines []string
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	switch cmd {
+	case ruokCommand:
+		return ruokStats(len(lines) == 1 && lines[0] == "imok"), nil
+	case isroCommand:
+		if len(lines) != 1 || (lines[0] != "ro" && lines[0] != "rw") {
+			return nil, fmt.Errorf("unexpected %s response %q", cmd, strings.Join(lines, "\n"))
+		}
+		return readOnlyStats(lines[0] == "ro"), nil
+	case wchsCommand:
+		for _, line := range lines {
+			parts := wchsFormatRE.FindStringSubmatch(line)
+			if len(parts) != 3 {
+				continue
+			}
+			connections, _ := strconv.ParseInt(parts[1], 10, 64)
+			paths, _ := strconv.ParseInt(parts[2], 10, 64)
+			return watchStats(connections, paths), nil
+		}
+		return nil, fmt.Errorf("unexpected %s response %q", cmd, strings.Join(lines, "\n"))
+	case consCommand:
+		var conns []clientConnection
+		for _, line := range lines {
+			parts := consFormatRE.FindStringSubmatch(line)
+			if len(parts) != 3 {
+				return nil, fmt.Errorf("unexpected %s line %q", cmd, line)
+			}
+			conn := clientConnection{host: parts[1]}
+			for _, pair := range strings.Split(parts[2], ",") {
+				if strings.HasPrefix(pair, "queued=") {
+					conn.queued, _ = strconv.ParseInt(strings.TrimPrefix(pair, "queued="), 10, 64)
+				}
+			}
+			conns = append(conns, conn)
+		}
+		return clientConnectionStats(conns), nil
+	}
+	return nil, fmt.Errorf("unsupported command %q", cmd)
+}
+
+func ruokStats(ok bool) []stat {
+	return []stat{{metric: metadata.Metrics.ZookeeperRuok.New(), val: boolToInt64(ok)}}
+}
+
+func readOnlyStats(readOnly bool) []stat {
+	return []stat{{metric: metadata.Metrics.ZookeeperReadOnly.New(), val: boolToInt64(readOnly)}}
+}
+
+func watchStats(connections, paths int64) []stat {
+	return []stat{
+		{metric: metadata.Metrics.ZookeeperWatchesConnections.New(), val: connections},
+		{metric: metadata.Metrics.ZookeeperWatchesPaths.New(), val: paths},
+	}
+}
+
+// A connection of a client to the server, as listed by "cons".
+type clientConnection struct {
+	host   string
+	queued int64
+}
+
+// Aggregates the connections per client host, as client ports are ephemeral.
+func clientConnectionStats(conns []clientConnection) []stat {
+	counts := map[string]int64{}
+	queued := map[string]int64{}
+	for _, conn := range conns {
+		counts[conn.host]++
+		queued[conn.host] += conn.queued
+	}
+	hosts := make([]string, 0, len(counts))
+	for host := range counts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	stats := make([]stat, 0, 2*len(hosts))
+	for _, host := range hosts {
+		labels := map[string]string{metadata.Labels.ClientAddress: host}
+		stats = append(stats,
+			stat{metric: metadata.Metrics.ZookeeperClientConnections.New(), val: counts[host], labels: labels},
+			stat{metric: metadata.Metrics.ZookeeperClientOutstandingRequests.New(), val: queued[host], labels: labels},
+		)
+	}
+	return stats
+}
+
+func boolToInt64(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Reads the metrics of a server through the AdminServer HTTP API. "monitor"
+// returns the same keys as "mntr", without the "zk_" prefix.
+func (z *zookeeperMetricsScraper) scrapeAdminServer(ctx context.Context, t target) (pdata.ResourceMetricsSlice, error) {
+	var monitor map[string]interface{}
+	if err := z.getAdminCommand(ctx, t.adminServerURL, "monitor", &monitor); err != nil {
+		z.logger.Error("failed to query admin server",
+			zap.String("endpoint", t.adminServerURL),
+			zap.String("command", "monitor"),
+			zap.Error(err),
+		)
+		return pdata.NewResourceMetricsSlice(), err
+	}
+	stats, attributes := getAdminMetricsAndAttributes(monitor)
+
+	for _, cmd := range z.config.Commands {
+		cmdStats, err := z.adminCommandStats(ctx, t.adminServerURL, cmd)
+		if err != nil {
+			z.logger.Warn("failed to query admin server",
+				zap.String("endpoint", t.adminServerURL),
+				zap.String("command", adminServerCommands[cmd]),
+				zap.Error(err),
+			)
+			if cmd != ruokCommand {
+				continue
+			}
+			// A server that can't be reached isn't ok.
+			cmdStats = ruokStats(false)
+		}
+		stats = append(stats, cmdStats...)
+	}
+
+	return z.buildResourceMetrics(t, stats, attributes), nil
+}
+
+func getAdminMetricsAndAttributes(monitor map[string]interface{}) ([]stat, map[string]string) {
+	attributes := make(map[string]string, 2)
+	stats := make([]stat, 0, metricsLen)
+
+	keys := make([]string, 0, len(monitor))
+	for key := range monitor {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		metricKey := "zk_" + key
+		switch value := monitor[key].(type) {
+		case string:
+			switch metricKey {
+			case zkVersionKey:
+				// e.g. "3.6.2--803c7f1a12f85978cb049af5e4ef23bd8b688715, built on 09/04/2020 12:44 GMT"
+				attributes[metadata.Labels.ZkVersion] = strings.SplitN(value, ",", 2)[0]
+			case serverStateKey:
+				attributes[metadata.Labels.ServerState] = value
+			}
+		case json.Number:
+			metricDescriptor := getOTLPMetricDescriptor(metricKey)
+			if metricDescriptor.Name() == "" {
+				continue
+			}
+			int64Val, err := value.Int64()
+			if err != nil {
+				// Averages are floating point numbers since ZooKeeper 3.6.
+				float64Val, err := value.Float64()
+				if err != nil {
+					continue
+				}
+				int64Val = int64(float64Val)
+			}
+			stats = append(stats, stat{metric: metricDescriptor, val: int64Val})
+		}
+	}
+	return stats, attributes
+}
+
+func (z *zookeeperMetricsScraper) adminCommandStats(ctx context.Context, baseURL string, cmd string) ([]stat, error) {
+	switch cmd {
+	case ruokCommand:
+		var resp struct{}
+		if err := z.getAdminCommand(ctx, baseURL, adminServerCommands[cmd], &resp); err != nil {
+			return nil, err
+		}
+		return ruokStats(true), nil
+	case isroCommand:
+		var resp struct {
+			ReadOnly bool `json:"read_only"`
+		}
+		if err := z.getAdminCommand(ctx, baseURL, adminServerCommands[cmd], &resp); err != nil {
+			return nil, err
+		}
+		return readOnlyStats(resp.ReadOnly), nil
+	case wchsCommand:
+		var resp struct {
+			NumConnections int64 `json:"num_connections"`
+			NumPaths       int64 `json:"num_paths"`
+		}
+		if err := z.getAdminCommand(ctx, baseURL, adminServerCommands[cmd], &resp); err != nil {
+			return nil, err
+		}
+		return watchStats(resp.NumConnections, resp.NumPaths), nil
+	case consCommand:
+		type connection struct {
+			RemoteSocketAddress string `json:"remote_socket_address"`
+			OutstandingRequests int64  `json:"outstanding_requests"`
+		}
+		var resp struct {
+			Connections       []connection `json:"connections"`
+			SecureConnections []connection `json:"secure_connections"`
+		}
+		if err := z.getAdminCommand(ctx, baseURL, adminServerCommands[cmd], &resp); err != nil {
+			return nil, err
+		}
+		var conns []clientConnection
+		for _, c := range append(resp.Connections, resp.SecureConnections...) {
+			host, _, err := net.SplitHostPort(strings.TrimPrefix(c.RemoteSocketAddress, "/"))
+			if err != nil {
+				host = c.RemoteSocketAddress
+			}
+			conns = append(conns, clientConnection{host: host, queued: c.OutstandingRequests})
+		}
+		return clientConnectionStats(conns), nil
+	}
+	return nil, fmt.Errorf("unsupported command %q", cmd)
+}
+
+// Runs an AdminServer command and decodes its JSON response into out.
+func (z *zookeeperMetricsScraper) getAdminCommand(ctx context.Context, baseURL string, cmd string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/commands/"+cmd, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := z.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+	return decoder.Decode(out)
+}
@@ -0,0 +1,337 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zookeeperreceiver
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+)
+
+func TestParseCommandResponse(t *testing.T) {
+	tests := []struct {
+		cmd      string
+		response string
+		expected map[string]int64
+		wantErr  bool
+	}{
+		{cmd: ruokCommand, response: "imok", expected: map[string]int64{"zookeeper.ruok": 1}},
+		{cmd: ruokCommand, response: "", expected: map[string]int64{"zookeeper.ruok": 0}},
+		{cmd: isroCommand, response: "ro\n", expected: map[string]int64{"zookeeper.read_only": 1}},
+		{cmd: isroCommand, response: "rw\n", expected: map[string]int64{"zookeeper.read_only": 0}},
+		{cmd: isroCommand, response: "isro is not executed because it is not in the whitelist.\n", wantErr: true},
+		{
+			cmd:      wchsCommand,
+			response: "2 connections watching 3 paths\nTotal watches:4\n",
+			expected: map[string]int64{"zookeeper.watches.connections": 2, "zookeeper.watches.paths": 3},
+		},
+		{cmd: wchsCommand, response: "wchs is not executed because it is not in the whitelist.\n", wantErr: true},
+		{cmd: consCommand, response: "cons is not executed because it is not in the whitelist.\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cmd+"/"+strings.TrimSpace(tt.response), func(t *testing.T) {
+			stats, err := parseCommandResponse(tt.cmd, bufio.NewScanner(strings.NewReader(tt.response)))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			actual := map[string]int64{}
+			for _, s := range stats {
+				actual[s.metric.Name()] = s.val
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestParseConsResponse(t *testing.T) {
+	f, err := os.Open(path.Join("testdata", "cons"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	stats, err := parseCommandResponse(consCommand, bufio.NewScanner(f))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{
+		"zookeeper.client.connections/10.0.0.5":          2,
+		"zookeeper.client.outstanding_requests/10.0.0.5": 3,
+		"zookeeper.client.connections/10.0.0.7":          1,
+		"zookeeper.client.outstanding_requests/10.0.0.7": 0,
+	}, statsByNameAndClient(stats))
+}
+
+func statsByNameAndClient(stats []stat) map[string]int64 {
+	out := map[string]int64{}
+	for _, s := range stats {
+		out[s.metric.Name()+"/"+s.labels["client.address"]] = s.val
+	}
+	return out
+}
+
+func TestParseConnectString(t *testing.T) {
+	endpoints, err := parseConnectString("zk1:2181, zk2:2182,zk3/chroot/path")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zk1:2181", "zk2:2182", "zk3:2181"}, endpoints)
+
+	_, err = parseConnectString("/chroot")
+	require.EqualError(t, err, "connect_string must contain at least one server")
+}
+
+func TestNewScraperInvalidCommand(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Commands = []string{"stat"}
+	_, err := newZookeeperMetricsScraper(zap.NewNop(), cfg)
+	require.EqualError(t, err, `unsupported command "stat", must be one of "ruok", "isro", "wchs" or "cons"`)
+}
+
+// Serves every four-letter word with the testdata file of the same name, on
+// as many connections as needed.
+func mockZKEnsembleMember(t *testing.T) string {
+	listener, err := net.Listen("tcp", testutil.GetAvailableLocalAddress(t))
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			cmd, err := bufio.NewReader(conn).ReadString('\n')
+			if err == nil {
+				filename := strings.TrimSpace(cmd)
+				if filename == mntrCommand {
+					filename = "mntr-3.5.5"
+				}
+				out, _ := ioutil.ReadFile(path.Join("testdata", filename))
+				conn.Write(out)
+			}
+			conn.Close()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestScrapeCommandsAndConnectString(t *testing.T) {
+	member1 := mockZKEnsembleMember(t)
+	member2 := mockZKEnsembleMember(t)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.ConnectString = member1 + "," + member2
+	cfg.Commands = []string{ruokCommand, isroCommand, wchsCommand, consCommand}
+	z, err := newZookeeperMetricsScraper(zap.NewNop(), cfg)
+	require.NoError(t, err)
+
+	got, err := z.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, got.Len())
+
+	for i, member := range []string{member1, member2} {
+		attrs := got.At(i).Resource().Attributes()
+		endpoint, ok := attrs.Get("server.endpoint")
+		require.True(t, ok)
+		assert.Equal(t, member, endpoint.StringVal())
+		state, _ := attrs.Get("server.state")
+		assert.Equal(t, "leader", state.StringVal())
+
+		values := metricValues(got.At(i))
+		assert.Equal(t, int64(5), values["zookeeper.znodes"])
+		assert.Equal(t, int64(1), values["zookeeper.ruok"])
+		assert.Equal(t, int64(0), values["zookeeper.read_only"])
+		assert.Equal(t, int64(2), values["zookeeper.watches.connections"])
+		assert.Equal(t, int64(3), values["zookeeper.watches.paths"])
+		assert.Equal(t, int64(2), values["zookeeper.client.connections/10.0.0.5"])
+		assert.Equal(t, int64(3), values["zookeeper.client.outstanding_requests/10.0.0.5"])
+		assert.Equal(t, int64(1), values["zookeeper.client.connections/10.0.0.7"])
+	}
+	require.NoError(t, z.shutdown(context.Background()))
+}
+
+func TestScrapeConnectStringPartialFailure(t *testing.T) {
+	member := mockZKEnsembleMember(t)
+	down := testutil.GetAvailableLocalAddress(t)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.ConnectString = member + "," + down
+	z, err := newZookeeperMetricsScraper(zap.NewNop(), cfg)
+	require.NoError(t, err)
+
+	got, err := z.scrape(context.Background())
+	require.Error(t, err)
+	require.True(t, scrapererror.IsPartialScrapeError(err))
+	require.Equal(t, 1, got.Len())
+	require.NoError(t, z.shutdown(context.Background()))
+}
+
+func TestScrapeRuokUnreachable(t *testing.T) {
+	cfg := &Config{
+		TCPAddr:  confignet.TCPAddr{Endpoint: testutil.GetAvailableLocalAddress(t)},
+		Timeout:  defaultTimeout,
+		Commands: []string{ruokCommand},
+	}
+	ms := mockedServer{ready: make(chan bool, 1)}
+	go ms.mockZKServer(t, cfg.Endpoint, "mntr-3.4.14")
+	<-ms.ready
+
+	z, err := newZookeeperMetricsScraper(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	got, err := z.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, got.Len())
+	// The mocked server only accepts the mntr connection.
+	assert.Equal(t, int64(0), metricValues(got.At(0))["zookeeper.ruok"])
+	require.NoError(t, z.shutdown(context.Background()))
+}
+
+func mockAdminServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cmd := strings.TrimPrefix(r.URL.Path, "/commands/")
+		out, err := ioutil.ReadFile(path.Join("testdata", "admin", cmd+".json"))
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(out)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestScrapeAdminServer(t *testing.T) {
+	server := mockAdminServer(t)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Commands = []string{ruokCommand, isroCommand, wchsCommand, consCommand}
+	cfg.AdminServer = &AdminServerConfig{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: server.URL},
+	}
+	z, err := newZookeeperMetricsScraper(zap.NewNop(), cfg)
+	require.NoError(t, err)
+
+	got, err := z.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, got.Len())
+
+	attrs := got.At(0).Resource().Attributes()
+	require.Equal(t, 2, attrs.Len())
+	version, _ := attrs.Get("zk.version")
+	assert.Equal(t, "3.6.2--803c7f1a12f85978cb049af5e4ef23bd8b688715", version.StringVal())
+	state, _ := attrs.Get("server.state")
+	assert.Equal(t, "standalone", state.StringVal())
+
+	values := metricValues(got.At(0))
+	assert.Equal(t, map[string]int64{
+		"zookeeper.approximate_date_size":                44,
+		"zookeeper.connections_alive":                    2,
+		"zookeeper.ephemeral_nodes":                      0,
+		"zookeeper.latency.avg":                          0,
+		"zookeeper.latency.max":                          3,
+		"zookeeper.latency.min":                          0,
+		"zookeeper.max_file_descriptors":                 1048576,
+		"zookeeper.open_file_descriptors":                67,
+		"zookeeper.outstanding_requests":                 0,
+		"zookeeper.packets.received":                     21,
+		"zookeeper.packets.sent":                         20,
+		"zookeeper.watches":                              4,
+		"zookeeper.znodes":                               5,
+		"zookeeper.ruok":                                 1,
+		"zookeeper.read_only":                            0,
+		"zookeeper.watches.connections":                  2,
+		"zookeeper.watches.paths":                        3,
+		"zookeeper.client.connections/10.0.0.5":          2,
+		"zookeeper.client.outstanding_requests/10.0.0.5": 3,
+		"zookeeper.client.connections/10.0.0.7":          1,
+		"zookeeper.client.outstanding_requests/10.0.0.7": 0,
+	}, values)
+	require.NoError(t, z.shutdown(context.Background()))
+}
+
+func TestScrapeAdminServerUnavailable(t *testing.T) {
+	server := mockAdminServer(t)
+	server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.AdminServer = &AdminServerConfig{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: server.URL},
+	}
+	z, err := newZookeeperMetricsScraper(zap.NewNop(), cfg)
+	require.NoError(t, err)
+
+	got, err := z.scrape(context.Background())
+	require.Error(t, err)
+	require.Equal(t, 0, got.Len())
+	require.NoError(t, z.shutdown(context.Background()))
+}
+
+func TestAdminServerURL(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ConnectString = "zk1:2181,zk2:2181"
+	cfg.AdminServer = &AdminServerConfig{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: "https://localhost:9090/"},
+	}
+	z, err := newZookeeperMetricsScraper(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []target{
+		{endpoint: "zk1:2181", adminServerURL: "https://zk1:9090"},
+		{endpoint: "zk2:2181", adminServerURL: "https://zk2:9090"},
+	}, z.targets)
+
+	cfg.AdminServer.Endpoint = "http://localhost"
+	z, err = newZookeeperMetricsScraper(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "http://zk1:8080", z.targets[0].adminServerURL)
+}
+
+// Returns the values of the data points of rm by metric name, suffixed with
+// the client address for per client metrics.
+func metricValues(rm pdata.ResourceMetrics) map[string]int64 {
+	out := map[string]int64{}
+	ms := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		var dps pdata.IntDataPointSlice
+		switch m.DataType() {
+		case pdata.MetricDataTypeIntGauge:
+			dps = m.IntGauge().DataPoints()
+		case pdata.MetricDataTypeIntSum:
+			dps = m.IntSum().DataPoints()
+		}
+		for j := 0; j < dps.Len(); j++ {
+			key := m.Name()
+			if client, ok := dps.At(j).LabelsMap().Get("client.address"); ok {
+				key += "/" + client
+			}
+			out[key] = dps.At(j).Value()
+		}
+	}
+	return out
+}
@@ -17,6 +17,7 @@ package zookeeperreceiver
 import (
 	"time"
 
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
 )
@@ -27,4 +28,25 @@ type Config struct {
 
 	// Timeout within which requests should be completed.
 	Timeout time.Duration `mapstructure:"timeout"`
+
+	// Commands to send besides "mntr": any of "ruok", "isro", "wchs" and
+	// "cons". Each four-letter word needs to be whitelisted on the server.
+	Commands []string `mapstructure:"commands"`
+
+	// ConnectString lists the members of the ensemble to scrape, in the
+	// ZooKeeper client connect string format, e.g.
+	// "zk1:2181,zk2:2181,zk3:2181/chroot". Overrides Endpoint when set.
+	ConnectString string `mapstructure:"connect_string"`
+
+	// AdminServer reads the metrics from the AdminServer HTTP API
+	// (ZooKeeper 3.5+) instead of four-letter words when set.
+	AdminServer *AdminServerConfig `mapstructure:"admin_server"`
+}
+
+// AdminServerConfig defines how to reach the ZooKeeper AdminServer.
+type AdminServerConfig struct {
+	// Endpoint is the base URL of the AdminServer, e.g.
+	// "http://localhost:8080". When scraping a connect string, the scheme
+	// and port of this URL are used with the host of each member.
+	confighttp.HTTPClientSettings `mapstructure:",squash"`
 }
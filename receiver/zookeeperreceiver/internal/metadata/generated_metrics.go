@@ -55,29 +55,37 @@ func (m *metricImpl) Init(metric pdata.Metric) {
 }
 
 type metricStruct struct {
-	ZookeeperApproximateDateSize   MetricIntf
-	ZookeeperConnectionsAlive      MetricIntf
-	ZookeeperEphemeralNodes        MetricIntf
-	ZookeeperFollowers             MetricIntf
-	ZookeeperFsyncThresholdExceeds MetricIntf
-	ZookeeperLatencyAvg            MetricIntf
-	ZookeeperLatencyMax            MetricIntf
-	ZookeeperLatencyMin            MetricIntf
-	ZookeeperMaxFileDescriptors    MetricIntf
-	ZookeeperOpenFileDescriptors   MetricIntf
-	ZookeeperOutstandingRequests   MetricIntf
-	ZookeeperPacketsReceived       MetricIntf
-	ZookeeperPacketsSent           MetricIntf
-	ZookeeperPendingSyncs          MetricIntf
-	ZookeeperSyncedFollowers       MetricIntf
-	ZookeeperWatches               MetricIntf
-	ZookeeperZnodes                MetricIntf
+	ZookeeperApproximateDateSize       MetricIntf
+	ZookeeperClientConnections         MetricIntf
+	ZookeeperClientOutstandingRequests MetricIntf
+	ZookeeperConnectionsAlive          MetricIntf
+	ZookeeperEphemeralNodes            MetricIntf
+	ZookeeperFollowers                 MetricIntf
+	ZookeeperFsyncThresholdExceeds     MetricIntf
+	ZookeeperLatencyAvg                MetricIntf
+	ZookeeperLatencyMax                MetricIntf
+	ZookeeperLatencyMin                MetricIntf
+	ZookeeperMaxFileDescriptors        MetricIntf
+	ZookeeperOpenFileDescriptors       MetricIntf
+	ZookeeperOutstandingRequests       MetricIntf
+	ZookeeperPacketsReceived           MetricIntf
+	ZookeeperPacketsSent               MetricIntf
+	ZookeeperPendingSyncs              MetricIntf
+	ZookeeperReadOnly                  MetricIntf
+	ZookeeperRuok                      MetricIntf
+	ZookeeperSyncedFollowers           MetricIntf
+	ZookeeperWatches                   MetricIntf
+	ZookeeperWatchesConnections        MetricIntf
+	ZookeeperWatchesPaths              MetricIntf
+	ZookeeperZnodes                    MetricIntf
 }
 
 // Names returns a list of all the metric name strings.
 func (m *metricStruct) Names() []string {
 	return []string{
 		"zookeeper.approximate_date_size",
+		"zookeeper.client.connections",
+		"zookeeper.client.outstanding_requests",
 		"zookeeper.connections_alive",
 		"zookeeper.ephemeral_nodes",
 		"zookeeper.followers",
@@ -91,30 +99,40 @@ func (m *metricStruct) Names() []string {
 		"zookeeper.packets.received",
 		"zookeeper.packets.sent",
 		"zookeeper.pending_syncs",
+		"zookeeper.read_only",
+		"zookeeper.ruok",
 		"zookeeper.synced_followers",
 		"zookeeper.watches",
+		"zookeeper.watches.connections",
+		"zookeeper.watches.paths",
 		"zookeeper.znodes",
 	}
 }
 
 var metricsByName = map[string]MetricIntf{
-	"zookeeper.approximate_date_size":   Metrics.ZookeeperApproximateDateSize,
-	"zookeeper.connections_alive":       Metrics.ZookeeperConnectionsAlive,
-	"zookeeper.ephemeral_nodes":         Metrics.ZookeeperEphemeralNodes,
-	"zookeeper.followers":               Metrics.ZookeeperFollowers,
-	"zookeeper.fsync_threshold_exceeds": Metrics.ZookeeperFsyncThresholdExceeds,
-	"zookeeper.latency.avg":             Metrics.ZookeeperLatencyAvg,
-	"zookeeper.latency.max":             Metrics.ZookeeperLatencyMax,
-	"zookeeper.latency.min":             Metrics.ZookeeperLatencyMin,
-	"zookeeper.max_file_descriptors":    Metrics.ZookeeperMaxFileDescriptors,
-	"zookeeper.open_file_descriptors":   Metrics.ZookeeperOpenFileDescriptors,
-	"zookeeper.outstanding_requests":    Metrics.ZookeeperOutstandingRequests,
-	"zookeeper.packets.received":        Metrics.ZookeeperPacketsReceived,
-	"zookeeper.packets.sent":            Metrics.ZookeeperPacketsSent,
-	"zookeeper.pending_syncs":           Metrics.ZookeeperPendingSyncs,
-	"zookeeper.synced_followers":        Metrics.ZookeeperSyncedFollowers,
-	"zookeeper.watches":                 Metrics.ZookeeperWatches,
-	"zookeeper.znodes":                  Metrics.ZookeeperZnodes,
+	"zookeeper.approximate_date_size":       Metrics.ZookeeperApproximateDateSize,
+	"zookeeper.client.connections":          Metrics.ZookeeperClientConnections,
+	"zookeeper.client.outstanding_requests": Metrics.ZookeeperClientOutstandingRequests,
+	"zookeeper.connections_alive":           Metrics.ZookeeperConnectionsAlive,
+	"zookeeper.ephemeral_nodes":             Metrics.ZookeeperEphemeralNodes,
+	"zookeeper.followers":                   Metrics.ZookeeperFollowers,
+	"zookeeper.fsync_threshold_exceeds":     Metrics.ZookeeperFsyncThresholdExceeds,
+	"zookeeper.latency.avg":                 Metrics.ZookeeperLatencyAvg,
+	"zookeeper.latency.max":                 Metrics.ZookeeperLatencyMax,
+	"zookeeper.latency.min":                 Metrics.ZookeeperLatencyMin,
+	"zookeeper.max_file_descriptors":        Metrics.ZookeeperMaxFileDescriptors,
+	"zookeeper.open_file_descriptors":       Metrics.ZookeeperOpenFileDescriptors,
+	"zookeeper.outstanding_requests":        Metrics.ZookeeperOutstandingRequests,
+	"zookeeper.packets.received":            Metrics.ZookeeperPacketsReceived,
+	"zookeeper.packets.sent":                Metrics.ZookeeperPacketsSent,
+	"zookeeper.pending_syncs":               Metrics.ZookeeperPendingSyncs,
+	"zookeeper.read_only":                   Metrics.ZookeeperReadOnly,
+	"zookeeper.ruok":                        Metrics.ZookeeperRuok,
+	"zookeeper.synced_followers":            Metrics.ZookeeperSyncedFollowers,
+	"zookeeper.watches":                     Metrics.ZookeeperWatches,
+	"zookeeper.watches.connections":         Metrics.ZookeeperWatchesConnections,
+	"zookeeper.watches.paths":               Metrics.ZookeeperWatchesPaths,
+	"zookeeper.znodes":                      Metrics.ZookeeperZnodes,
 }
 
 func (m *metricStruct) ByName(n string) MetricIntf {
@@ -123,23 +141,29 @@ func (m *metricStruct) ByName(n string) MetricIntf {
 
 func (m *metricStruct) FactoriesByName() map[string]func(pdata.Metric) {
 	return map[string]func(pdata.Metric){
-		Metrics.ZookeeperApproximateDateSize.Name():   Metrics.ZookeeperApproximateDateSize.Init,
-		Metrics.ZookeeperConnectionsAlive.Name():      Metrics.ZookeeperConnectionsAlive.Init,
-		Metrics.ZookeeperEphemeralNodes.Name():        Metrics.ZookeeperEphemeralNodes.Init,
-		Metrics.ZookeeperFollowers.Name():             Metrics.ZookeeperFollowers.Init,
-		Metrics.ZookeeperFsyncThresholdExceeds.Name(): Metrics.ZookeeperFsyncThresholdExceeds.Init,
-		Metrics.ZookeeperLatencyAvg.Name():            Metrics.ZookeeperLatencyAvg.Init,
-		Metrics.ZookeeperLatencyMax.Name():            Metrics.ZookeeperLatencyMax.Init,
-		Metrics.ZookeeperLatencyMin.Name():            Metrics.ZookeeperLatencyMin.Init,
-		Metrics.ZookeeperMaxFileDescriptors.Name():    Metrics.ZookeeperMaxFileDescriptors.Init,
-		Metrics.ZookeeperOpenFileDescriptors.Name():   Metrics.ZookeeperOpenFileDescriptors.Init,
-		Metrics.ZookeeperOutstandingRequests.Name():   Metrics.ZookeeperOutstandingRequests.Init,
-		Metrics.ZookeeperPacketsReceived.Name():       Metrics.ZookeeperPacketsReceived.Init,
-		Metrics.ZookeeperPacketsSent.Name():           Metrics.ZookeeperPacketsSent.Init,
-		Metrics.ZookeeperPendingSyncs.Name():          Metrics.ZookeeperPendingSyncs.Init,
-		Metrics.ZookeeperSyncedFollowers.Name():       Metrics.ZookeeperSyncedFollowers.Init,
-		Metrics.ZookeeperWatches.Name():               Metrics.ZookeeperWatches.Init,
-		Metrics.ZookeeperZnodes.Name():                Metrics.ZookeeperZnodes.Init,
+		Metrics.ZookeeperApproximateDateSize.Name():       Metrics.ZookeeperApproximateDateSize.Init,
+		Metrics.ZookeeperClientConnections.Name():         Metrics.ZookeeperClientConnections.Init,
+		Metrics.ZookeeperClientOutstandingRequests.Name(): Metrics.ZookeeperClientOutstandingRequests.Init,
+		Metrics.ZookeeperConnectionsAlive.Name():          Metrics.ZookeeperConnectionsAlive.Init,
+		Metrics.ZookeeperEphemeralNodes.Name():            Metrics.ZookeeperEphemeralNodes.Init,
+		Metrics.ZookeeperFollowers.Name():                 Metrics.ZookeeperFollowers.Init,
+		Metrics.ZookeeperFsyncThresholdExceeds.Name():     Metrics.ZookeeperFsyncThresholdExceeds.Init,
+		Metrics.ZookeeperLatencyAvg.Name():                Metrics.ZookeeperLatencyAvg.Init,
+		Metrics.ZookeeperLatencyMax.Name():                Metrics.ZookeeperLatencyMax.Init,
+		Metrics.ZookeeperLatencyMin.Name():                Metrics.ZookeeperLatencyMin.Init,
+		Metrics.ZookeeperMaxFileDescriptors.Name():        Metrics.ZookeeperMaxFileDescriptors.Init,
+		Metrics.ZookeeperOpenFileDescriptors.Name():       Metrics.ZookeeperOpenFileDescriptors.Init,
+		Metrics.ZookeeperOutstandingRequests.Name():       Metrics.ZookeeperOutstandingRequests.Init,
+		Metrics.ZookeeperPacketsReceived.Name():           Metrics.ZookeeperPacketsReceived.Init,
+		Metrics.ZookeeperPacketsSent.Name():               Metrics.ZookeeperPacketsSent.Init,
+		Metrics.ZookeeperPendingSyncs.Name():              Metrics.ZookeeperPendingSyncs.Init,
+		Metrics.ZookeeperReadOnly.Name():                  Metrics.ZookeeperReadOnly.Init,
+		Metrics.ZookeeperRuok.Name():                      Metrics.ZookeeperRuok.Init,
+		Metrics.ZookeeperSyncedFollowers.Name():           Metrics.ZookeeperSyncedFollowers.Init,
+		Metrics.ZookeeperWatches.Name():                   Metrics.ZookeeperWatches.Init,
+		Metrics.ZookeeperWatchesConnections.Name():        Metrics.ZookeeperWatchesConnections.Init,
+		Metrics.ZookeeperWatchesPaths.Name():              Metrics.ZookeeperWatchesPaths.Init,
+		Metrics.ZookeeperZnodes.Name():                    Metrics.ZookeeperZnodes.Init,
 	}
 }
 
@@ -155,6 +179,24 @@ var Metrics = &metricStruct{
 			metric.SetDataType(pdata.MetricDataTypeIntGauge)
 		},
 	},
+	&metricImpl{
+		"zookeeper.client.connections",
+		func(metric pdata.Metric) {
+			metric.SetName("zookeeper.client.connections")
+			metric.SetDescription("Number of connections from a client host to a ZooKeeper server.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"zookeeper.client.outstanding_requests",
+		func(metric pdata.Metric) {
+			metric.SetName("zookeeper.client.outstanding_requests")
+			metric.SetDescription("Number of queued requests of the connections from a client host to a ZooKeeper server.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
 	&metricImpl{
 		"zookeeper.connections_alive",
 		func(metric pdata.Metric) {
@@ -278,6 +320,24 @@ var Metrics = &metricStruct{
 			metric.SetDataType(pdata.MetricDataTypeIntGauge)
 		},
 	},
+	&metricImpl{
+		"zookeeper.read_only",
+		func(metric pdata.Metric) {
+			metric.SetName("zookeeper.read_only")
+			metric.SetDescription("Whether the server is in read-only mode, 1 if so and 0 otherwise.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"zookeeper.ruok",
+		func(metric pdata.Metric) {
+			metric.SetName("zookeeper.ruok")
+			metric.SetDescription("Whether the server is running in a non-error state, 1 if it answered the ruok command and 0 otherwise.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
 	&metricImpl{
 		"zookeeper.synced_followers",
 		func(metric pdata.Metric) {
@@ -296,6 +356,24 @@ var Metrics = &metricStruct{
 			metric.SetDataType(pdata.MetricDataTypeIntGauge)
 		},
 	},
+	&metricImpl{
+		"zookeeper.watches.connections",
+		func(metric pdata.Metric) {
+			metric.SetName("zookeeper.watches.connections")
+			metric.SetDescription("Number of connections with watches set on a ZooKeeper server.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"zookeeper.watches.paths",
+		func(metric pdata.Metric) {
+			metric.SetName("zookeeper.watches.paths")
+			metric.SetDescription("Number of z-node paths watched on a ZooKeeper server.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
 	&metricImpl{
 		"zookeeper.znodes",
 		func(metric pdata.Metric) {
@@ -313,11 +391,17 @@ var M = Metrics
 
 // Labels contains the possible metric labels that can be used.
 var Labels = struct {
+	// ClientAddress (Address of the host the client connections come from.)
+	ClientAddress string
+	// ServerEndpoint (Endpoint of the ensemble member, when scraping every member of a connect string.)
+	ServerEndpoint string
 	// ServerState (State of the Zookeeper server (leader, standalone or follower).)
 	ServerState string
 	// ZkVersion (Zookeeper version of the instance.)
 	ZkVersion string
 }{
+	"client.address",
+	"server.endpoint",
 	"server.state",
 	"zk.version",
 }
@@ -20,12 +20,17 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/consumer/simple"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/zookeeperreceiver/internal/metadata"
@@ -35,12 +40,37 @@ var zookeeperFormatRE = regexp.MustCompile(`(^zk_\w+)\s+([\w\.\-]+)`)
 
 const (
 	mntrCommand = "mntr"
+	ruokCommand = "ruok"
+	isroCommand = "isro"
+	wchsCommand = "wchs"
+	consCommand = "cons"
+
+	defaultClientPort      = "2181"
+	defaultAdminServerPort = "8080"
 )
 
+// Commands that can be sent besides "mntr", mapped to the name of the
+// equivalent AdminServer command.
+var adminServerCommands = map[string]string{
+	ruokCommand: "ruok",
+	isroCommand: "is_read_only",
+	wchsCommand: "watch_summary",
+	consCommand: "connections",
+}
+
+// A ZooKeeper server to scrape. adminServerURL is only set when the metrics
+// are read from the AdminServer.
+type target struct {
+	endpoint       string
+	adminServerURL string
+}
+
 type zookeeperMetricsScraper struct {
-	logger *zap.Logger
-	config *Config
-	cancel context.CancelFunc
+	logger     *zap.Logger
+	config     *Config
+	cancel     context.CancelFunc
+	targets    []target
+	httpClient *http.Client
 
 	// For mocking.
 	closeConnection       func(net.Conn) error
@@ -53,26 +83,113 @@ func (z *zookeeperMetricsScraper) Name() string {
 }
 
 func newZookeeperMetricsScraper(logger *zap.Logger, config *Config) (*zookeeperMetricsScraper, error) {
-	_, _, err := net.SplitHostPort(config.TCPAddr.Endpoint)
-	if err != nil {
-		return nil, err
+	endpoints := []string{config.TCPAddr.Endpoint}
+	if config.ConnectString != "" {
+		var err error
+		endpoints, err = parseConnectString(config.ConnectString)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, endpoint := range endpoints {
+		_, _, err := net.SplitHostPort(endpoint)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if config.Timeout <= 0 {
 		return nil, errors.New("timeout must be a positive duration")
 	}
 
-	return &zookeeperMetricsScraper{
+	for _, cmd := range config.Commands {
+		if _, ok := adminServerCommands[cmd]; !ok {
+			return nil, fmt.Errorf("unsupported command %q, must be one of %q, %q, %q or %q",
+				cmd, ruokCommand, isroCommand, wchsCommand, consCommand)
+		}
+	}
+
+	z := &zookeeperMetricsScraper{
 		logger:                logger,
 		config:                config,
 		closeConnection:       closeConnection,
 		setConnectionDeadline: setConnectionDeadline,
 		sendCmd:               sendCmd,
-	}, nil
+	}
+
+	for _, endpoint := range endpoints {
+		t := target{endpoint: endpoint}
+		if config.AdminServer != nil {
+			var err error
+			t.adminServerURL, err = z.adminServerURL(endpoint)
+			if err != nil {
+				return nil, err
+			}
+		}
+		z.targets = append(z.targets, t)
+	}
+
+	if config.AdminServer != nil {
+		var err error
+		z.httpClient, err = config.AdminServer.ToClient()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return z, nil
+}
+
+// Turns a ZooKeeper client connect string, e.g. "zk1:2181,zk2:2181/chroot",
+// into the list of "host:port" endpoints of the ensemble members.
+func parseConnectString(connectString string) ([]string, error) {
+	if i := strings.Index(connectString, "/"); i >= 0 {
+		connectString = connectString[:i]
+	}
+	var endpoints []string
+	for _, server := range strings.Split(connectString, ",") {
+		server = strings.TrimSpace(server)
+		if server == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, defaultClientPort)
+		}
+		endpoints = append(endpoints, server)
+	}
+	if len(endpoints) == 0 {
+		return nil, errors.New("connect_string must contain at least one server")
+	}
+	return endpoints, nil
+}
+
+// Returns the AdminServer base URL of the passed-in endpoint. When scraping a
+// connect string, the host of the ensemble member replaces the one of the
+// configured AdminServer endpoint.
+func (z *zookeeperMetricsScraper) adminServerURL(endpoint string) (string, error) {
+	if z.config.ConnectString == "" {
+		return strings.TrimSuffix(z.config.AdminServer.Endpoint, "/"), nil
+	}
+	u, err := url.Parse(z.config.AdminServer.Endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid admin_server endpoint: %w", err)
+	}
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return "", err
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultAdminServerPort
+	}
+	u.Host = net.JoinHostPort(host, port)
+	return strings.TrimSuffix(u.String(), "/"), nil
 }
 
 func (z *zookeeperMetricsScraper) shutdown(_ context.Context) error {
-	z.cancel()
+	if z.cancel != nil {
+		z.cancel()
+	}
 	return nil
 }
 
@@ -80,13 +197,91 @@ func (z *zookeeperMetricsScraper) scrape(ctx context.Context) (pdata.ResourceMet
 	var ctxWithTimeout context.Context
 	ctxWithTimeout, z.cancel = context.WithTimeout(ctx, z.config.Timeout)
 
-	conn, err := z.config.Dial()
+	rms := pdata.NewResourceMetricsSlice()
+	var errs []error
+	for _, t := range z.targets {
+		var targetRMS pdata.ResourceMetricsSlice
+		var err error
+		if t.adminServerURL != "" {
+			targetRMS, err = z.scrapeAdminServer(ctxWithTimeout, t)
+		} else {
+			targetRMS, err = z.scrapeFourLetterWords(ctxWithTimeout, t)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		targetRMS.MoveAndAppendTo(rms)
+	}
+
+	if len(errs) == len(z.targets) {
+		return pdata.NewResourceMetricsSlice(), errs[0]
+	}
+	if len(errs) > 0 {
+		// Some of the ensemble members could be scraped.
+		scrapeErrors := scrapererror.ScrapeErrors{}
+		for _, err := range errs {
+			scrapeErrors.AddPartial(1, err)
+		}
+		return rms, scrapeErrors.Combine()
+	}
+	return rms, nil
+}
+
+type stat struct {
+	metric pdata.Metric
+	val    int64
+	labels map[string]string
+}
+
+// Reads the metrics of a server through four-letter words, opening one
+// connection per command as the server closes it after answering.
+func (z *zookeeperMetricsScraper) scrapeFourLetterWords(ctx context.Context, t target) (pdata.ResourceMetricsSlice, error) {
+	var stats []stat
+	var attributes map[string]string
+	err := z.runCommand(ctx, t.endpoint, mntrCommand, func(scanner *bufio.Scanner) {
+		stats, attributes = z.getMetricsAndAttributes(scanner)
+	})
+	if err != nil {
+		return pdata.NewResourceMetricsSlice(), err
+	}
+
+	for _, cmd := range z.config.Commands {
+		var cmdStats []stat
+		var parseErr error
+		err = z.runCommand(ctx, t.endpoint, cmd, func(scanner *bufio.Scanner) {
+			cmdStats, parseErr = parseCommandResponse(cmd, scanner)
+		})
+		switch {
+		case err != nil && cmd == ruokCommand:
+			// A server that can't be reached isn't ok.
+			cmdStats = []stat{{metric: metadata.Metrics.ZookeeperRuok.New(), val: 0}}
+		case err != nil:
+			continue
+		case parseErr != nil:
+			z.logger.Warn("unexpected response",
+				zap.String("command", cmd),
+				zap.Error(parseErr),
+			)
+			continue
+		}
+		stats = append(stats, cmdStats...)
+	}
+
+	return z.buildResourceMetrics(t, stats, attributes), nil
+}
+
+// Sends cmd over a new connection to endpoint and hands the response over to
+// handle.
+func (z *zookeeperMetricsScraper) runCommand(ctx context.Context, endpoint string, cmd string, handle func(*bufio.Scanner)) error {
+	addr := confignet.TCPAddr{Endpoint: endpoint}
+	conn, err := addr.Dial()
 	if err != nil {
 		z.logger.Error("failed to establish connection",
-			zap.String("endpoint", z.config.Endpoint),
+			zap.String("endpoint", endpoint),
 			zap.Error(err),
 		)
-		return pdata.NewResourceMetricsSlice(), err
+		return err
 	}
 	defer func() {
 		if closeErr := z.closeConnection(conn); closeErr != nil {
@@ -94,32 +289,29 @@ func (z *zookeeperMetricsScraper) scrape(ctx context.Context) (pdata.ResourceMet
 		}
 	}()
 
-	deadline, ok := ctxWithTimeout.Deadline()
+	deadline, ok := ctx.Deadline()
 	if ok {
 		if err := z.setConnectionDeadline(conn, deadline); err != nil {
 			z.logger.Warn("failed to set deadline on connection", zap.Error(err))
 		}
 	}
 
-	return z.getResourceMetrics(conn)
-}
-
-type stat struct {
-	metric pdata.Metric
-	val    int64
-}
-
-func (z *zookeeperMetricsScraper) getResourceMetrics(conn net.Conn) (pdata.ResourceMetricsSlice, error) {
-	scanner, err := z.sendCmd(conn, mntrCommand)
+	scanner, err := z.sendCmd(conn, cmd)
 	if err != nil {
 		z.logger.Error("failed to send command",
 			zap.Error(err),
-			zap.String("command", mntrCommand),
+			zap.String("command", cmd),
 		)
-		return pdata.NewResourceMetricsSlice(), err
+		return err
 	}
+	handle(scanner)
+	return nil
+}
 
-	stats, attributes := z.getMetricsAndAttributes(scanner)
+func (z *zookeeperMetricsScraper) buildResourceMetrics(t target, stats []stat, attributes map[string]string) pdata.ResourceMetricsSlice {
+	if z.config.ConnectString != "" {
+		attributes[metadata.Labels.ServerEndpoint] = t.endpoint
+	}
 	metrics := simple.Metrics{
 		Metrics:                    pdata.NewMetrics(),
 		Timestamp:                  time.Now(),
@@ -129,15 +321,19 @@ func (z *zookeeperMetricsScraper) getResourceMetrics(conn net.Conn) (pdata.Resou
 	}
 
 	for _, stat := range stats {
+		mb := &metrics
+		if len(stat.labels) > 0 {
+			mb = metrics.WithLabels(stat.labels)
+		}
 		// Currently the receiver only deals with one metric type.
 		switch stat.metric.DataType() {
 		case pdata.MetricDataTypeIntGauge:
-			metrics.AddGaugeDataPoint(stat.metric.Name(), stat.val)
+			mb.AddGaugeDataPoint(stat.metric.Name(), stat.val)
 		case pdata.MetricDataTypeIntSum:
-			metrics.AddSumDataPoint(stat.metric.Name(), stat.val)
+			mb.AddSumDataPoint(stat.metric.Name(), stat.val)
 		}
 	}
-	return metrics.ResourceMetrics(), nil
+	return metrics.ResourceMetrics()
 }
 
 func (z *zookeeperMetricsScraper) getMetricsAndAttributes(scanner *bufio.Scanner) ([]stat, map[string]string) {